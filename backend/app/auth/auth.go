@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"os"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo"
+	"github.com/labstack/echo/middleware"
+)
+
+const (
+	// 認証済みユーザーのトークンを格納するコンテキストのキー
+	contextKey = "user"
+	// トークンの有効期限
+	tokenTTL = 24 * time.Hour
+)
+
+type (
+	// Claims JWTに含めるクレームを表す
+	Claims struct {
+		UserID int64 `json:"user_id"`
+		jwt.StandardClaims
+	}
+)
+
+// GenerateToken ユーザーIDを含む署名済みトークンを発行
+func GenerateToken(userId int64) (string, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID: userId,
+		StandardClaims: jwt.StandardClaims{
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(tokenTTL).Unix(),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret())
+}
+
+// Middleware Authorizationヘッダのトークンを検証し、ユーザーをコンテキストに格納する
+func Middleware() echo.MiddlewareFunc {
+	return middleware.JWTWithConfig(middleware.JWTConfig{
+		SigningKey: secret(),
+		Claims:     &Claims{},
+		ContextKey: contextKey,
+	})
+}
+
+// UserID コンテキストから認証済みユーザーのIDを取得。未認証の場合は0を返却
+func UserID(c echo.Context) int64 {
+	token, ok := c.Get(contextKey).(*jwt.Token)
+	if !ok {
+		return 0
+	}
+	claims, ok := token.Claims.(*Claims)
+	if !ok {
+		return 0
+	}
+	return claims.UserID
+}
+
+// secret 署名に利用する鍵を環境変数から読み込み
+func secret() []byte {
+	s := os.Getenv("JWT_SECRET")
+	if s == "" {
+		panic("JWT_SECRET environment variable is not set")
+	}
+	return []byte(s)
+}
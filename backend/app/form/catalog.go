@@ -0,0 +1,48 @@
+package form
+
+type (
+	// ListCatalog 種目カタログの検索フォームを表す
+	ListCatalog struct {
+		Query     string `json:"q" form:"q" query:"q" description:"名前・別名の部分一致"`
+		Muscle    string `json:"muscle" form:"muscle" query:"muscle" description:"対象の部位(主・補助のいずれか)"`
+		Equipment string `json:"equipment" form:"equipment" query:"equipment" description:"器具"`
+	}
+
+	CreateCatalogEntry struct {
+		NameJa           string         `json:"name_ja" form:"name_ja" valid:"required" description:"日本語名"`
+		NameEn           string         `json:"name_en" form:"name_en" valid:"required" description:"英語名"`
+		PrimaryMuscle    string         `json:"primary_muscle" form:"primary_muscle" valid:"required" description:"主に使う部位"`
+		SecondaryMuscles []string       `json:"secondary_muscles" form:"secondary_muscles" description:"補助的に使う部位"`
+		Equipment        string         `json:"equipment" form:"equipment" description:"器具"`
+		Modality         string         `json:"modality" form:"modality" valid:"in(weighted|bodyweight|assisted|timed|distance)" description:"記録方法(weighted, bodyweight, assisted, timed, distance)。未指定の場合はweighted"`
+		Aliases          []CatalogAlias `json:"aliases" form:"aliases" description:"別名"`
+	}
+
+	CatalogAlias struct {
+		Alias  string `json:"alias" form:"alias" valid:"required" description:"別名"`
+		Locale string `json:"locale" form:"locale" valid:"in(ja|en)" description:"言語(ja, en)"`
+	}
+
+	// 更新系のフォームは未指定の項目を区別するためポインタで受け取る
+	UpdateCatalogEntry struct {
+		NameJa           *string         `json:"name_ja" form:"name_ja" description:"日本語名"`
+		NameEn           *string         `json:"name_en" form:"name_en" description:"英語名"`
+		PrimaryMuscle    *string         `json:"primary_muscle" form:"primary_muscle" description:"主に使う部位"`
+		SecondaryMuscles *[]string       `json:"secondary_muscles" form:"secondary_muscles" description:"補助的に使う部位"`
+		Equipment        *string         `json:"equipment" form:"equipment" description:"器具"`
+		Modality         *string         `json:"modality" form:"modality" description:"記録方法(weighted, bodyweight, assisted, timed, distance)"`
+		Aliases          *[]CatalogAlias `json:"aliases" form:"aliases" description:"別名(指定した場合は置き換え)"`
+	}
+)
+
+func NewListCatalog() *ListCatalog {
+	return &ListCatalog{}
+}
+
+func NewCreateCatalogEntry() *CreateCatalogEntry {
+	return &CreateCatalogEntry{}
+}
+
+func NewUpdateCatalogEntry() *UpdateCatalogEntry {
+	return &UpdateCatalogEntry{}
+}
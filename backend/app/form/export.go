@@ -0,0 +1,15 @@
+package form
+
+type (
+	// ExportWorkouts セット単位のトレーニング履歴のエクスポートの検索フォームを表す
+	ExportWorkouts struct {
+		From     string `json:"from" form:"from" query:"from" description:"エクスポートする期間の開始日"`
+		To       string `json:"to" form:"to" query:"to" description:"エクスポートする期間の終了日"`
+		Exercise string `json:"exercise" form:"exercise" query:"exercise" description:"エクスポートする種目(カタログID、または名前・別名)。未指定の場合は全種目"`
+		Unit     string `json:"unit" form:"unit" query:"unit" valid:"in(kg|lb)" description:"出力する重量の単位(kg, lb)。未指定の場合はユーザーの設定"`
+	}
+)
+
+func NewExportWorkouts() *ExportWorkouts {
+	return &ExportWorkouts{}
+}
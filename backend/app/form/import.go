@@ -0,0 +1,15 @@
+package form
+
+type (
+	// ImportWorkouts 他のアプリの記録の取り込みのフォームを表す。CSVはmultipartのfileで送る
+	ImportWorkouts struct {
+		Format  string `json:"format" form:"format" query:"format" valid:"in(strong|hevy|fitnotes)" description:"取り込むCSVの形式(strong, hevy, fitnotes)。未指定の場合はヘッダから判別"`
+		Unit    string `json:"unit" form:"unit" query:"unit" valid:"in(kg|lb)" description:"重量の単位を記録しない形式での単位(kg, lb)。未指定の場合はユーザーの設定"`
+		DryRun  bool   `json:"dry_run" form:"dry_run" query:"dry_run" description:"trueの場合は保存せずに取り込む内容を返却"`
+		Mapping string `json:"mapping" form:"mapping" description:"取り込み元の種目名から種目名・別名・カタログIDへの対応づけ(JSONのオブジェクト)"`
+	}
+)
+
+func NewImportWorkouts() *ImportWorkouts {
+	return &ImportWorkouts{}
+}
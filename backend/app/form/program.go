@@ -0,0 +1,90 @@
+package form
+
+type (
+	// SaveProgram プログラムの作成フォームを表す
+	SaveProgram struct {
+		Name  string       `json:"name" form:"name" valid:"required,runelength(1|100)" description:"プログラム名"`
+		Weeks int64        `json:"weeks" form:"weeks" valid:"required" description:"週数"`
+		Days  []ProgramDay `json:"days" form:"days" description:"予定の日の一覧。予定のない日は休養日とする"`
+	}
+
+	ProgramDay struct {
+		Week      int64             `json:"week" form:"week" valid:"required" description:"週(1始まり)"`
+		Day       int64             `json:"day" form:"day" valid:"required" description:"週の開始日から数えた日(1〜7)"`
+		Name      string            `json:"name" form:"name" valid:"runelength(0|100)" description:"予定の日の名前"`
+		Exercises []ProgramExercise `json:"exercises" form:"exercises" description:"種目一覧(並び順)"`
+	}
+
+	ProgramExercise struct {
+		CatalogID    int64        `json:"catalog_id" form:"catalog_id" description:"種目カタログID"`
+		ExerciseName string       `json:"exercise_name" form:"exercise_name" description:"エクササイズ名(カタログID未指定の場合は必須)"`
+		Modality     string       `json:"modality" form:"modality" valid:"in(weighted|bodyweight|assisted|timed|distance)" description:"記録方法。未指定の場合はカタログの記録方法"`
+		Sets         []ProgramSet `json:"sets" form:"sets" description:"予定のセット一覧"`
+	}
+
+	// ProgramSet 重量はトレーニングマックスに対する割合で指定する
+	ProgramSet struct {
+		SetNumber  int64    `json:"set_number" form:"set_number" valid:"required" description:"セット数"`
+		SetType    string   `json:"set_type" form:"set_type" valid:"in(warmup|working|drop|failure|amrap)" description:"セットの種類。未指定の場合はworking"`
+		Percentage *float64 `json:"percentage" form:"percentage" description:"トレーニングマックスに対する重量の割合(%)。未指定の場合は重量を指定しない"`
+		Reps       int64    `json:"reps" form:"reps" description:"回数"`
+	}
+
+	// EnrollProgram プログラムへの参加フォームを表す
+	EnrollProgram struct {
+		StartDate     string        `json:"start_date" form:"start_date" valid:"required" description:"開始日。1週目の1日目とする"`
+		TrainingMaxes []TrainingMax `json:"training_maxes" form:"training_maxes" description:"割合で重量を指定する種目ごとのトレーニングマックス"`
+	}
+
+	TrainingMax struct {
+		CatalogID    int64   `json:"catalog_id" form:"catalog_id" description:"種目カタログID"`
+		ExerciseName string  `json:"exercise_name" form:"exercise_name" description:"エクササイズ名(カタログID未指定の場合は必須)"`
+		Weight       float64 `json:"weight" form:"weight" valid:"required" description:"トレーニングマックスの重量"`
+		Unit         string  `json:"unit" form:"unit" valid:"in(kg|lb)" description:"重量の単位(kg, lb)。未指定の場合はユーザーの設定"`
+	}
+
+	// GetPlannedSession 指定の日の予定の取得フォームを表す
+	GetPlannedSession struct {
+		Date string `json:"date" form:"date" query:"date" valid:"required" description:"予定を取得する日付"`
+		Unit string `json:"unit" form:"unit" query:"unit" valid:"in(kg|lb)" description:"表示する重量の単位(kg, lb)。未指定の場合はユーザーの設定"`
+	}
+
+	// CreatePlannedSession 指定の日の予定から下書きのセッションを作成する
+	CreatePlannedSession struct {
+		Date string `json:"date" form:"date" valid:"required" description:"ワークアウトの日付"`
+	}
+
+	// LinkProgramDay 記録したセッションをプログラムの予定の日に紐づける
+	LinkProgramDay struct {
+		Date string `json:"date" form:"date" description:"紐づける予定の日付。未指定の場合はセッションの日付"`
+	}
+
+	// GetAdherence プログラムの実施状況の取得フォームを表す
+	GetAdherence struct {
+		AsOf string `json:"as_of" form:"as_of" query:"as_of" description:"集計の基準日。未指定の場合は今日"`
+	}
+)
+
+func NewSaveProgram() *SaveProgram {
+	return &SaveProgram{}
+}
+
+func NewEnrollProgram() *EnrollProgram {
+	return &EnrollProgram{}
+}
+
+func NewGetPlannedSession() *GetPlannedSession {
+	return &GetPlannedSession{}
+}
+
+func NewCreatePlannedSession() *CreatePlannedSession {
+	return &CreatePlannedSession{}
+}
+
+func NewLinkProgramDay() *LinkProgramDay {
+	return &LinkProgramDay{}
+}
+
+func NewGetAdherence() *GetAdherence {
+	return &GetAdherence{}
+}
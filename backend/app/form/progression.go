@@ -0,0 +1,31 @@
+package form
+
+type (
+	// SaveProgressionRule 種目ごとの進め方の保存フォームを表す。同じ種目の進め方は置き換える
+	SaveProgressionRule struct {
+		CatalogID        int64    `json:"catalog_id" form:"catalog_id" description:"種目カタログID"`
+		ExerciseName     string   `json:"exercise_name" form:"exercise_name" description:"エクササイズ名(カタログID未指定の場合は必須)"`
+		Strategy         string   `json:"strategy" form:"strategy" valid:"required,in(linear|double|rpe)" description:"進め方(linear, double, rpe)"`
+		Increment        float64  `json:"increment" form:"increment" valid:"required" description:"1回に上げる重量"`
+		Unit             string   `json:"unit" form:"unit" valid:"in(kg|lb)" description:"重量の単位(kg, lb)。未指定の場合はユーザーの設定"`
+		Sets             int64    `json:"sets" form:"sets" description:"メインセットのセット数。未指定の場合は3"`
+		MinReps          int64    `json:"min_reps" form:"min_reps" valid:"required" description:"目標の回数(doubleの場合は回数の範囲の下限)"`
+		MaxReps          int64    `json:"max_reps" form:"max_reps" description:"doubleの場合の回数の範囲の上限。未指定の場合はmin_repsと同じ"`
+		TargetRPE        *float64 `json:"target_rpe" form:"target_rpe" description:"rpeの場合の目標のRPE(1〜10、0.5刻み)。未指定の場合は8"`
+		DeloadAfter      *int64   `json:"deload_after" form:"deload_after" description:"連続で失敗したらディロードするセッション数。0の場合はディロードしない。未指定の場合は3"`
+		DeloadPercentage *float64 `json:"deload_percentage" form:"deload_percentage" description:"ディロードで下げる重量の割合(%)。未指定の場合は10"`
+	}
+
+	// GetProgressionSuggestion 次のセッションの提案の取得フォームを表す
+	GetProgressionSuggestion struct {
+		Unit string `json:"unit" form:"unit" query:"unit" valid:"in(kg|lb)" description:"表示する重量の単位(kg, lb)。未指定の場合はユーザーの設定"`
+	}
+)
+
+func NewSaveProgressionRule() *SaveProgressionRule {
+	return &SaveProgressionRule{}
+}
+
+func NewGetProgressionSuggestion() *GetProgressionSuggestion {
+	return &GetProgressionSuggestion{}
+}
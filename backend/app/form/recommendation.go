@@ -1,12 +1,42 @@
 package form
 
 type ProposeTrainingMenu struct {
-	TrainingGoal    string   `json:"training_goal" form:"training_goal"`       // 例: "筋肥大", "ダイエット", ...
-	TargetParts     []string `json:"target_parts" form:"target_parts"`         // 例: ["胸", "背中", "脚"] ...
-	ExperienceLevel string   `json:"experience_level" form:"experience_level"` // 例: "初心者", "中級者", "上級者"
-	AvailableTime   int      `json:"available_time" form:"available_time"`
+	TrainingGoal    string   `json:"training_goal" form:"training_goal" valid:"required"` // 例: "筋肥大", "ダイエット", ...
+	TargetParts     []string `json:"target_parts" form:"target_parts"`                    // 例: ["胸", "背中", "脚"] ...
+	ExperienceLevel string   `json:"experience_level" form:"experience_level"`            // 例: "初心者", "中級者", "上級者"
+	AvailableTime   int      `json:"available_time" form:"available_time" valid:"range(0|300)"`
 }
 
 func NewProposeTrainingMenu() *ProposeTrainingMenu {
 	return &ProposeTrainingMenu{}
 }
+
+type (
+	// CreateWorkoutFromRecommendation 提案したメニューから予定のセッションを作成する
+	// 保存した提案のIDか、メニューの内容のどちらかを指定する
+	CreateWorkoutFromRecommendation struct {
+		Date             string        `json:"date" form:"date" valid:"required" description:"ワークアウトの日付"`
+		RecommendationID int64         `json:"recommendation_id" form:"recommendation_id" description:"保存した提案のID"`
+		Menu             *TrainingMenu `json:"menu" form:"menu" description:"提案したメニューの内容(IDを指定しない場合は必須)"`
+	}
+
+	// TrainingMenu 提案したメニューの内容を表す
+	TrainingMenu struct {
+		Title     string         `json:"title" form:"title"`
+		Exercises []MenuExercise `json:"exercises" form:"exercises"`
+		Notes     string         `json:"notes" form:"notes"`
+	}
+
+	// MenuExercise 提案したメニューの種目を表す
+	MenuExercise struct {
+		Name        string `json:"name" form:"name" valid:"required"`
+		Sets        int    `json:"sets" form:"sets"`
+		Reps        int    `json:"reps" form:"reps"`
+		RestSeconds int    `json:"rest_seconds" form:"rest_seconds"`
+		Notes       string `json:"notes" form:"notes"`
+	}
+)
+
+func NewCreateWorkoutFromRecommendation() *CreateWorkoutFromRecommendation {
+	return &CreateWorkoutFromRecommendation{}
+}
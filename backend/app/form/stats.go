@@ -0,0 +1,43 @@
+package form
+
+type (
+	// ExerciseProgress 種目ごとの推移の検索フォームを表す
+	ExerciseProgress struct {
+		From    string `json:"from" form:"from" query:"from" description:"集計期間の開始日"`
+		To      string `json:"to" form:"to" query:"to" description:"集計期間の終了日"`
+		Bucket  string `json:"bucket" form:"bucket" query:"bucket" valid:"in(day|week|month)" description:"集計単位(day, week, month)"`
+		Formula string `json:"formula" form:"formula" query:"formula" valid:"in(epley|brzycki|lombardi)" description:"推定1RMの計算式(epley, brzycki, lombardi)"`
+		Unit    string `json:"unit" form:"unit" query:"unit" valid:"in(kg|lb)" description:"表示する重量の単位(kg, lb)。未指定の場合はユーザーの設定"`
+	}
+)
+
+func NewExerciseProgress() *ExerciseProgress {
+	return &ExerciseProgress{}
+}
+
+type (
+	// WeeklyMuscleVolume 部位ごとの週間ボリュームの検索フォームを表す
+	WeeklyMuscleVolume struct {
+		From     string `json:"from" form:"from" query:"from" description:"集計期間の開始日"`
+		To       string `json:"to" form:"to" query:"to" description:"集計期間の終了日"`
+		TimeZone string `json:"tz" form:"tz" query:"tz" description:"週の区切りに用いるタイムゾーン(例: Asia/Tokyo)"`
+		Unit     string `json:"unit" form:"unit" query:"unit" valid:"in(kg|lb)" description:"表示する重量の単位(kg, lb)。未指定の場合はユーザーの設定"`
+	}
+)
+
+func NewWeeklyMuscleVolume() *WeeklyMuscleVolume {
+	return &WeeklyMuscleVolume{}
+}
+
+type (
+	// WeeklyTargetCompletion 目標セットの週ごとの達成率の検索フォームを表す
+	WeeklyTargetCompletion struct {
+		From     string `json:"from" form:"from" query:"from" description:"集計期間の開始日"`
+		To       string `json:"to" form:"to" query:"to" description:"集計期間の終了日"`
+		TimeZone string `json:"tz" form:"tz" query:"tz" description:"週の区切りに用いるタイムゾーン(例: Asia/Tokyo)"`
+	}
+)
+
+func NewWeeklyTargetCompletion() *WeeklyTargetCompletion {
+	return &WeeklyTargetCompletion{}
+}
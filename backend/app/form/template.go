@@ -0,0 +1,60 @@
+package form
+
+type (
+	// GetTemplate テンプレートの取得フォームを表す
+	GetTemplate struct {
+		Unit string `json:"unit" form:"unit" query:"unit" valid:"in(kg|lb)" description:"表示する重量の単位(kg, lb)。未指定の場合はユーザーの設定"`
+	}
+
+	// SaveTemplate テンプレートの作成・更新フォームを表す。更新時は種目・目標セットごと置き換える
+	SaveTemplate struct {
+		Name      string             `json:"name" form:"name" valid:"required,runelength(1|100)" description:"テンプレート名"`
+		Exercises []TemplateExercise `json:"exercises" form:"exercises" description:"種目一覧(並び順)"`
+	}
+
+	TemplateExercise struct {
+		CatalogID    int64         `json:"catalog_id" form:"catalog_id" description:"種目カタログID"`
+		ExerciseName string        `json:"exercise_name" form:"exercise_name" description:"エクササイズ名(カタログID未指定の場合は必須)"`
+		Modality     string        `json:"modality" form:"modality" valid:"in(weighted|bodyweight|assisted|timed|distance)" description:"記録方法。未指定の場合はカタログの記録方法"`
+		Sets         []TemplateSet `json:"sets" form:"sets" description:"目標セット一覧"`
+	}
+
+	// TemplateSet 目標セットは重量・回数などの一部のみの指定を許す
+	TemplateSet struct {
+		SetNumber       int64    `json:"set_number" form:"set_number" valid:"required" description:"セット数"`
+		SetType         string   `json:"set_type" form:"set_type" valid:"in(warmup|working|drop|failure|amrap)" description:"セットの種類。未指定の場合はworking"`
+		Weight          float64  `json:"weight" form:"weight" description:"重量"`
+		Unit            string   `json:"unit" form:"unit" valid:"in(kg|lb)" description:"重量の単位(kg, lb)。未指定の場合はユーザーの設定"`
+		Reps            int64    `json:"reps" form:"reps" description:"回数"`
+		DurationSeconds *int64   `json:"duration_seconds" form:"duration_seconds" description:"時間(秒)"`
+		DistanceMeters  *float64 `json:"distance_meters" form:"distance_meters" description:"距離(m)"`
+		RPE             *float64 `json:"rpe" form:"rpe" description:"目標のRPE(1〜10、0.5刻み)"`
+	}
+
+	// CreateTemplateFromWorkout 実施したセッションの種目・セットからテンプレートを作成する
+	CreateTemplateFromWorkout struct {
+		Name string `json:"name" form:"name" valid:"required,runelength(1|100)" description:"テンプレート名"`
+	}
+
+	// InstantiateTemplate テンプレートから下書きのセッションを作成する
+	InstantiateTemplate struct {
+		Date   string `json:"date" form:"date" valid:"required" description:"ワークアウトの日付"`
+		Source string `json:"source" form:"source" valid:"in(template|last_session)" description:"種目の作成元(template, last_session)。last_sessionはテンプレートから作成した直近のセッションを繰り返す"`
+	}
+)
+
+func NewGetTemplate() *GetTemplate {
+	return &GetTemplate{}
+}
+
+func NewSaveTemplate() *SaveTemplate {
+	return &SaveTemplate{}
+}
+
+func NewCreateTemplateFromWorkout() *CreateTemplateFromWorkout {
+	return &CreateTemplateFromWorkout{}
+}
+
+func NewInstantiateTemplate() *InstantiateTemplate {
+	return &InstantiateTemplate{}
+}
@@ -0,0 +1,33 @@
+package form
+
+type (
+	// Signup ユーザー登録のフォームを表す
+	Signup struct {
+		Email    string `json:"email" form:"email" valid:"required,email" description:"メールアドレス"`
+		Password string `json:"password" form:"password" valid:"required,length(8|72)" description:"パスワード"`
+		Name     string `json:"name" form:"name" valid:"length(0|255)" description:"表示名"`
+	}
+
+	// Login ログインのフォームを表す
+	Login struct {
+		Email    string `json:"email" form:"email" valid:"required,email" description:"メールアドレス"`
+		Password string `json:"password" form:"password" valid:"required" description:"パスワード"`
+	}
+
+	// UpdatePreferences ユーザー設定の更新フォームを表す
+	UpdatePreferences struct {
+		WeightUnit string `json:"weight_unit" form:"weight_unit" valid:"required,in(kg|lb)" description:"重量の単位(kg, lb)"`
+	}
+)
+
+func NewSignup() *Signup {
+	return &Signup{}
+}
+
+func NewLogin() *Login {
+	return &Login{}
+}
+
+func NewUpdatePreferences() *UpdatePreferences {
+	return &UpdatePreferences{}
+}
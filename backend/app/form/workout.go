@@ -3,25 +3,113 @@ package form
 type (
 	// Workout ワークアウトのフォームを表す
 	ListWorkout struct {
-		ID   int64  `json:"id" form:"id" query:"id" description:"検索したいセッションID"`
-		Date string `json:"date" form:"date" query:"date" description:"検索したい日付"`
+		ID           int64  `json:"id" form:"id" query:"id" description:"検索したいセッションID"`
+		Date         string `json:"date" form:"date" query:"date" description:"検索したい日付"`
+		From         string `json:"from" form:"from" query:"from" description:"検索したい期間の開始日"`
+		To           string `json:"to" form:"to" query:"to" description:"検索したい期間の終了日"`
+		ExerciseName string `json:"exercise_name" form:"exercise_name" query:"exercise_name" description:"含まれるエクササイズ名"`
+		Sort         string `json:"sort" form:"sort" query:"sort" valid:"in(asc|desc)" description:"日付の並び順(asc, desc)"`
+		Limit        uint64 `json:"limit" form:"limit" query:"limit" valid:"range(0|100)" description:"取得件数"`
+		Cursor       string `json:"cursor" form:"cursor" query:"cursor" description:"次ページのカーソル"`
+		Formula      string `json:"formula" form:"formula" query:"formula" valid:"in(epley|brzycki|lombardi)" description:"推定1RMの計算式(epley, brzycki, lombardi)"`
+		Unit         string `json:"unit" form:"unit" query:"unit" valid:"in(kg|lb)" description:"表示する重量の単位(kg, lb)。未指定の場合はユーザーの設定"`
+	}
+
+	GetWorkout struct {
+		Formula string `json:"formula" form:"formula" query:"formula" valid:"in(epley|brzycki|lombardi)" description:"推定1RMの計算式(epley, brzycki, lombardi)"`
+		Unit    string `json:"unit" form:"unit" query:"unit" valid:"in(kg|lb)" description:"表示する重量の単位(kg, lb)。未指定の場合はユーザーの設定"`
 	}
 
 	CreateWorkoutSession struct {
-		Date   string `json:"date" form:"date" query:"date" valid:"required" description:"ワークアウトの日付"`
-		UserID int64  `json:"user_id" form:"user_id" query:"user_id" valid:"required" description:"ユーザーID"`
+		Date string `json:"date" form:"date" query:"date" valid:"required" description:"ワークアウトの日付"`
 	}
 
 	CreateExercise struct {
 		// SessionID    int64  `json:"session_id" form:"session_id" query:"session_id" valid:"required" description:"ワークアウトセッションID"`
-		ExerciseName string `json:"exercise_name" form:"exercise_name" query:"exercise_name" valid:"required" description:"エクササイズ名"`
+		CatalogID    int64  `json:"catalog_id" form:"catalog_id" query:"catalog_id" description:"種目カタログID"`
+		ExerciseName string `json:"exercise_name" form:"exercise_name" query:"exercise_name" description:"エクササイズ名(カタログID未指定の場合は必須)"`
+		Modality     string `json:"modality" form:"modality" query:"modality" valid:"in(weighted|bodyweight|assisted|timed|distance)" description:"記録方法(weighted, bodyweight, assisted, timed, distance)。未指定の場合はカタログの記録方法"`
 	}
 
+	// CreateSet 重量・回数・時間・距離の要否はエクササイズの記録方法に応じてサービスで検証する
 	CreateSet struct {
 		// ExerciseID int64   `json:"exercise_id" form:"exercise_id" query:"exercise_id" valid:"required" description:"エクササイズID"`
 		SetNumber int64   `json:"set_number" form:"set_number" query:"set_number" valid:"required" description:"セット数"`
-		Weight    float64 `json:"weight" form:"weight" query:"weight" valid:"required" description:"重量"`
-		Reps      int64   `json:"reps" form:"reps" query:"reps" valid:"required" description:"回数"`
+		Weight    float64 `json:"weight" form:"weight" query:"weight" description:"重量(自重種目・時間の種目は加重、アシスト種目はアシスト重量)"`
+		Unit      string  `json:"unit" form:"unit" query:"unit" valid:"in(kg|lb)" description:"重量の単位(kg, lb)。未指定の場合はユーザーの設定"`
+		Reps      int64   `json:"reps" form:"reps" query:"reps" description:"回数"`
+		// 時間の種目・有酸素種目で記録する
+		DurationSeconds *int64   `json:"duration_seconds" form:"duration_seconds" query:"duration_seconds" description:"時間(秒)"`
+		DistanceMeters  *float64 `json:"distance_meters" form:"distance_meters" query:"distance_meters" description:"距離(m)"`
+		// 以降は任意の項目
+		SetType     string   `json:"set_type" form:"set_type" query:"set_type" valid:"in(warmup|working|drop|failure|amrap)" description:"セットの種類(warmup, working, drop, failure, amrap)。未指定の場合はworking"`
+		RPE         *float64 `json:"rpe" form:"rpe" query:"rpe" description:"主観的運動強度(1〜10、0.5刻み)。RIRとどちらか一方を指定する"`
+		RIR         *int64   `json:"rir" form:"rir" query:"rir" description:"余力の回数(0〜10)"`
+		RestSeconds *int64   `json:"rest_seconds" form:"rest_seconds" query:"rest_seconds" description:"セット前の休憩時間(秒)"`
+		Tempo       string   `json:"tempo" form:"tempo" query:"tempo" description:"テンポ(例: 31X0, 3-1-X-0)"`
+		PerformedAt string   `json:"performed_at" form:"performed_at" query:"performed_at" description:"実施日時(RFC3339)"`
+		Notes       string   `json:"notes" form:"notes" query:"notes" valid:"runelength(0|1000)" description:"メモ"`
+	}
+
+	// CreateWorkoutLog セッションをエクササイズ・セットごとまとめて記録する
+	CreateWorkoutLog struct {
+		Date      string                     `json:"date" form:"date" query:"date" valid:"required" description:"ワークアウトの日付"`
+		Exercises []CreateWorkoutLogExercise `json:"exercises" form:"exercises" description:"エクササイズ一覧"`
+	}
+
+	CreateWorkoutLogExercise struct {
+		CatalogID    int64       `json:"catalog_id" form:"catalog_id" description:"種目カタログID"`
+		ExerciseName string      `json:"exercise_name" form:"exercise_name" description:"エクササイズ名(カタログID未指定の場合は必須)"`
+		Modality     string      `json:"modality" form:"modality" valid:"in(weighted|bodyweight|assisted|timed|distance)" description:"記録方法。未指定の場合はカタログの記録方法"`
+		Sets         []CreateSet `json:"sets" form:"sets" description:"セット一覧"`
+	}
+
+	// 更新系のフォームは未指定の項目を区別するためポインタで受け取る
+	UpdateWorkoutSession struct {
+		Date   *string `json:"date" form:"date" query:"date" description:"ワークアウトの日付"`
+		Notes  *string `json:"notes" form:"notes" query:"notes" description:"メモ"`
+		Rating *int64  `json:"rating" form:"rating" query:"rating" description:"評価(1〜5)。0を指定すると消去する"`
+	}
+
+	// StartWorkoutSession 開始日時を省略した場合は現在日時とする
+	StartWorkoutSession struct {
+		StartedAt string `json:"started_at" form:"started_at" query:"started_at" description:"開始日時(RFC3339)"`
+	}
+
+	// FinishWorkoutSession 終了日時を省略した場合は現在日時とする
+	FinishWorkoutSession struct {
+		FinishedAt string  `json:"finished_at" form:"finished_at" query:"finished_at" description:"終了日時(RFC3339)"`
+		Notes      *string `json:"notes" form:"notes" query:"notes" description:"メモ"`
+		Rating     *int64  `json:"rating" form:"rating" query:"rating" description:"評価(1〜5)"`
+	}
+
+	UpdateExercise struct {
+		CatalogID    *int64  `json:"catalog_id" form:"catalog_id" query:"catalog_id" description:"種目カタログID"`
+		ExerciseName *string `json:"exercise_name" form:"exercise_name" query:"exercise_name" description:"エクササイズ名"`
+		Modality     *string `json:"modality" form:"modality" query:"modality" description:"記録方法(weighted, bodyweight, assisted, timed, distance)"`
+	}
+
+	UpdateSet struct {
+		SetNumber *int64   `json:"set_number" form:"set_number" query:"set_number" description:"セット数"`
+		Weight    *float64 `json:"weight" form:"weight" query:"weight" description:"重量"`
+		Unit      string   `json:"unit" form:"unit" query:"unit" valid:"in(kg|lb)" description:"重量の単位(kg, lb)。未指定の場合はユーザーの設定"`
+		Reps      *int64   `json:"reps" form:"reps" query:"reps" description:"回数"`
+		// 時間・距離は0を指定すると消去する
+		DurationSeconds *int64   `json:"duration_seconds" form:"duration_seconds" query:"duration_seconds" description:"時間(秒)"`
+		DistanceMeters  *float64 `json:"distance_meters" form:"distance_meters" query:"distance_meters" description:"距離(m)"`
+		// RPEとRIRはどちらか一方のみを記録するため、一方を指定するともう一方は消去する
+		SetType     *string  `json:"set_type" form:"set_type" query:"set_type" description:"セットの種類(warmup, working, drop, failure, amrap)"`
+		RPE         *float64 `json:"rpe" form:"rpe" query:"rpe" description:"主観的運動強度(1〜10、0.5刻み)"`
+		RIR         *int64   `json:"rir" form:"rir" query:"rir" description:"余力の回数(0〜10)"`
+		RestSeconds *int64   `json:"rest_seconds" form:"rest_seconds" query:"rest_seconds" description:"セット前の休憩時間(秒)"`
+		Tempo       *string  `json:"tempo" form:"tempo" query:"tempo" description:"テンポ(例: 31X0, 3-1-X-0)"`
+		PerformedAt *string  `json:"performed_at" form:"performed_at" query:"performed_at" description:"実施日時(RFC3339)"`
+		Notes       *string  `json:"notes" form:"notes" query:"notes" description:"メモ"`
+	}
+
+	// ReplaceTargetSets エクササイズの目標セットをまとめて置き換えるフォームを表す。空の場合は目標セットを削除する
+	ReplaceTargetSets struct {
+		Sets []TemplateSet `json:"sets" form:"sets" description:"目標セット一覧"`
 	}
 )
 
@@ -29,6 +117,10 @@ func NewListWorkout() *ListWorkout {
 	return &ListWorkout{}
 }
 
+func NewGetWorkout() *GetWorkout {
+	return &GetWorkout{}
+}
+
 func NewCreateWorkoutSession() *CreateWorkoutSession {
 	return &CreateWorkoutSession{}
 }
@@ -40,3 +132,31 @@ func NewCreateExercise() *CreateExercise {
 func NewCreateSet() *CreateSet {
 	return &CreateSet{}
 }
+
+func NewCreateWorkoutLog() *CreateWorkoutLog {
+	return &CreateWorkoutLog{}
+}
+
+func NewUpdateWorkoutSession() *UpdateWorkoutSession {
+	return &UpdateWorkoutSession{}
+}
+
+func NewStartWorkoutSession() *StartWorkoutSession {
+	return &StartWorkoutSession{}
+}
+
+func NewFinishWorkoutSession() *FinishWorkoutSession {
+	return &FinishWorkoutSession{}
+}
+
+func NewUpdateExercise() *UpdateExercise {
+	return &UpdateExercise{}
+}
+
+func NewUpdateSet() *UpdateSet {
+	return &UpdateSet{}
+}
+
+func NewReplaceTargetSets() *ReplaceTargetSets {
+	return &ReplaceTargetSets{}
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/auth"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/service"
+	"github.com/labstack/echo"
+)
+
+type (
+	// Analytics トレーニングの分析のハンドラを表す
+	Analytics interface {
+		WeeklyMuscleVolume(c echo.Context) error
+		WeeklyTargetCompletion(c echo.Context) error
+	}
+
+	// AnalyticsImpl トレーニングの分析のハンドラを表す
+	AnalyticsImpl struct {
+		AnalyticsService service.Analytics
+		UserService      service.User
+	}
+)
+
+func NewAnalytics() Analytics {
+	return &AnalyticsImpl{
+		AnalyticsService: service.NewAnalytics(),
+		UserService:      service.NewUser(),
+	}
+}
+
+func (h *AnalyticsImpl) WeeklyMuscleVolume(c echo.Context) error {
+	f := form.NewWeeklyMuscleVolume()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+
+	from, err := parseDate(f.From)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid from format: "+err.Error())
+	}
+	to, err := parseDate(f.To)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid to format: "+err.Error())
+	}
+	// 指定がない場合はUTCとして扱う
+	loc, err := time.LoadLocation(f.TimeZone)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid tz: "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, f.Unit)
+	if err != nil {
+		return err
+	}
+
+	volume, err := h.AnalyticsService.WeeklyMuscleVolume(auth.UserID(c), from, to, loc)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"muscle_volume": volume.ApplyUnit(unit)})
+}
+
+func (h *AnalyticsImpl) WeeklyTargetCompletion(c echo.Context) error {
+	f := form.NewWeeklyTargetCompletion()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+
+	from, err := parseDate(f.From)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid from format: "+err.Error())
+	}
+	to, err := parseDate(f.To)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid to format: "+err.Error())
+	}
+	// 指定がない場合はUTCとして扱う
+	loc, err := time.LoadLocation(f.TimeZone)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid tz: "+err.Error())
+	}
+
+	completion, err := h.AnalyticsService.WeeklyTargetCompletion(auth.UserID(c), from, to, loc)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"target_completion": completion})
+}
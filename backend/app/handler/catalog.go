@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/auth"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/service"
+	"github.com/labstack/echo"
+)
+
+type (
+	// Catalog 種目カタログのハンドラを表す
+	Catalog interface {
+		List(c echo.Context) error
+		Get(c echo.Context) error
+		Create(c echo.Context) error
+		Update(c echo.Context) error
+		Delete(c echo.Context) error
+	}
+
+	// CatalogImpl 種目カタログのハンドラを表す
+	CatalogImpl struct {
+		CatalogService service.Catalog
+	}
+)
+
+func NewCatalog() Catalog {
+	return &CatalogImpl{
+		CatalogService: service.NewCatalog(),
+	}
+}
+
+func (h *CatalogImpl) List(c echo.Context) error {
+	f := form.NewListCatalog()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+
+	entries, err := h.CatalogService.Search(model.ExerciseCatalogFilter{
+		Query:     f.Query,
+		Muscle:    f.Muscle,
+		Equipment: f.Equipment,
+	})
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"exercises": entries})
+}
+
+func (h *CatalogImpl) Get(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	entry, err := h.CatalogService.Get(id)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"exercise": entry})
+}
+
+func (h *CatalogImpl) Create(c echo.Context) error {
+	f := form.NewCreateCatalogEntry()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	entry, err := h.CatalogService.Create(auth.UserID(c), *f)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(201, map[string]interface{}{"exercise": entry})
+}
+
+func (h *CatalogImpl) Update(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	f := form.NewUpdateCatalogEntry()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if isPut(c) && (f.NameJa == nil || f.NameEn == nil || f.PrimaryMuscle == nil) {
+		return echo.NewHTTPError(400, "validation error name_ja, name_en and primary_muscle are required")
+	}
+	if f.Aliases != nil {
+		for _, a := range *f.Aliases {
+			if _, err := govalidator.ValidateStruct(a); err != nil {
+				return echo.NewHTTPError(400, "validation error "+err.Error())
+			}
+		}
+	}
+
+	attrs := map[string]interface{}{}
+	if f.NameJa != nil {
+		attrs["name_ja"] = *f.NameJa
+	}
+	if f.NameEn != nil {
+		attrs["name_en"] = *f.NameEn
+	}
+	if f.PrimaryMuscle != nil {
+		attrs["primary_muscle"] = *f.PrimaryMuscle
+	}
+	if f.SecondaryMuscles != nil {
+		attrs["secondary_muscles"] = *f.SecondaryMuscles
+	}
+	if f.Equipment != nil {
+		attrs["equipment"] = *f.Equipment
+	}
+	if f.Modality != nil {
+		attrs["modality"] = *f.Modality
+	}
+	if len(attrs) == 0 && f.Aliases == nil {
+		return echo.NewHTTPError(400, "nothing to update")
+	}
+
+	entry, err := h.CatalogService.Update(auth.UserID(c), id, attrs, f.Aliases)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"exercise": entry})
+}
+
+func (h *CatalogImpl) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	if err := h.CatalogService.Delete(auth.UserID(c), id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(204)
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/auth"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/service"
+	"github.com/labstack/echo"
+)
+
+type (
+	// Export トレーニング履歴のエクスポートのハンドラを表す
+	Export interface {
+		WorkoutsCSV(c echo.Context) error
+		WorkoutsJSONLines(c echo.Context) error
+	}
+
+	// ExportImpl トレーニング履歴のエクスポートのハンドラを表す
+	ExportImpl struct {
+		ExportService service.Export
+		UserService   service.User
+	}
+)
+
+func NewExport() Export {
+	return &ExportImpl{
+		ExportService: service.NewExport(),
+		UserService:   service.NewUser(),
+	}
+}
+
+func (h *ExportImpl) WorkoutsCSV(c echo.Context) error {
+	return h.workouts(c, service.ExportCSV, "text/csv; charset=utf-8", "workouts.csv")
+}
+
+func (h *ExportImpl) WorkoutsJSONLines(c echo.Context) error {
+	return h.workouts(c, service.ExportJSONLines, "application/x-ndjson", "workouts.jsonl")
+}
+
+// workouts セットを1行ずつレスポンスに書き出す
+// 書き出しを始める前のエラーはステータスコードで返し、途中で失敗した場合はレスポンスを打ち切る
+func (h *ExportImpl) workouts(c echo.Context, format string, contentType string, filename string) error {
+	f := form.NewExportWorkouts()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	from, err := parseDate(f.From)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid from format: "+err.Error())
+	}
+	to, err := parseDate(f.To)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid to format: "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, f.Unit)
+	if err != nil {
+		return err
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, contentType)
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+
+	// クライアントが切断した場合はDBからの読み出しも中断する
+	err = h.ExportService.Workouts(c.Request().Context(), res, format, auth.UserID(c), f.Exercise, from, to, unit)
+	if err != nil {
+		if !res.Committed {
+			res.Header().Del(echo.HeaderContentType)
+			res.Header().Del(echo.HeaderContentDisposition)
+			return serviceError(err)
+		}
+		c.Logger().Errorf("export aborted: %v", err)
+		return nil
+	}
+	if !res.Committed {
+		res.WriteHeader(200)
+	}
+	return nil
+}
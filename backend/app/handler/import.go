@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/auth"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/service"
+	"github.com/labstack/echo"
+)
+
+// maxImportFileSize 取り込めるCSVの最大サイズ
+const maxImportFileSize = 20 << 20
+
+type (
+	// Import 他のアプリの記録の取り込みのハンドラを表す
+	Import interface {
+		Workouts(c echo.Context) error
+	}
+
+	// ImportImpl 他のアプリの記録の取り込みのハンドラを表す
+	ImportImpl struct {
+		ImportService service.Import
+		UserService   service.User
+	}
+)
+
+func NewImport() Import {
+	return &ImportImpl{
+		ImportService: service.NewImport(),
+		UserService:   service.NewUser(),
+	}
+}
+
+func (h *ImportImpl) Workouts(c echo.Context) error {
+	f := form.NewImportWorkouts()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	var mapping map[string]string
+	if f.Mapping != "" {
+		if err := json.Unmarshal([]byte(f.Mapping), &mapping); err != nil {
+			return echo.NewHTTPError(400, "invalid mapping: "+err.Error())
+		}
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return echo.NewHTTPError(400, "file is required: "+err.Error())
+	}
+	if file.Size > maxImportFileSize {
+		return echo.NewHTTPError(413, "file must be at most "+strconv.Itoa(maxImportFileSize>>20)+"MB")
+	}
+
+	unit, err := weightUnit(c, h.UserService, f.Unit)
+	if err != nil {
+		return err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	result, err := h.ImportService.Workouts(auth.UserID(c), src, f.Format, unit, mapping, f.DryRun)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	if f.DryRun {
+		return c.JSON(200, map[string]interface{}{"import": result})
+	}
+	return c.JSON(201, map[string]interface{}{"import": result})
+}
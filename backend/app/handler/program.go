@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/auth"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/service"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/labstack/echo"
+)
+
+type (
+	// Program トレーニングプログラムのハンドラを表す
+	Program interface {
+		List(c echo.Context) error
+		Get(c echo.Context) error
+		Create(c echo.Context) error
+		Delete(c echo.Context) error
+		Enroll(c echo.Context) error
+		ListEnrollments(c echo.Context) error
+		DeleteEnrollment(c echo.Context) error
+		PlannedSession(c echo.Context) error
+		CreatePlannedSession(c echo.Context) error
+		LinkSession(c echo.Context) error
+		Adherence(c echo.Context) error
+	}
+
+	// ProgramImpl トレーニングプログラムのハンドラを表す
+	ProgramImpl struct {
+		ProgramService service.Program
+		UserService    service.User
+	}
+)
+
+func NewProgram() Program {
+	return &ProgramImpl{
+		ProgramService: service.NewProgram(),
+		UserService:    service.NewUser(),
+	}
+}
+
+func (h *ProgramImpl) List(c echo.Context) error {
+	programs, err := h.ProgramService.List(auth.UserID(c))
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"programs": programs})
+}
+
+func (h *ProgramImpl) Get(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	program, err := h.ProgramService.Get(auth.UserID(c), id)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"program": program})
+}
+
+func (h *ProgramImpl) Create(c echo.Context) error {
+	f := form.NewSaveProgram()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	program, err := h.ProgramService.Create(auth.UserID(c), *f)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"program": program})
+}
+
+func (h *ProgramImpl) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	if err := h.ProgramService.Delete(auth.UserID(c), id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *ProgramImpl) Enroll(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	f := form.NewEnrollProgram()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	startDate, err := parseDate(f.StartDate)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid date format: "+err.Error())
+	}
+
+	// トレーニングマックスの単位の指定がない場合はユーザーの設定とする
+	unit, err := weightUnit(c, h.UserService, "")
+	if err != nil {
+		return err
+	}
+	for i := range f.TrainingMaxes {
+		tm := &f.TrainingMaxes[i]
+		if tm.Unit == "" {
+			tm.Unit = string(unit)
+		} else if _, err := units.ParseUnit(tm.Unit); err != nil {
+			return echo.NewHTTPError(400, "validation error "+err.Error())
+		}
+	}
+
+	enrollment, err := h.ProgramService.Enroll(auth.UserID(c), id, startDate, f.TrainingMaxes)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"enrollment": enrollment.ApplyUnit(unit)})
+}
+
+func (h *ProgramImpl) ListEnrollments(c echo.Context) error {
+	unit, err := weightUnit(c, h.UserService, c.QueryParam("unit"))
+	if err != nil {
+		return err
+	}
+
+	enrollments, err := h.ProgramService.ListEnrollments(auth.UserID(c))
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"enrollments": enrollments.ApplyUnit(unit)})
+}
+
+func (h *ProgramImpl) DeleteEnrollment(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	if err := h.ProgramService.DeleteEnrollment(auth.UserID(c), id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *ProgramImpl) PlannedSession(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	f := form.NewGetPlannedSession()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	date, err := parseDate(f.Date)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid date format: "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, f.Unit)
+	if err != nil {
+		return err
+	}
+
+	planned, err := h.ProgramService.PlannedSession(auth.UserID(c), id, date)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"plan": planned.ApplyUnit(unit)})
+}
+
+func (h *ProgramImpl) CreatePlannedSession(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	f := form.NewCreatePlannedSession()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	date, err := parseDate(f.Date)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid date format: "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, "")
+	if err != nil {
+		return err
+	}
+
+	workoutSession, err := h.ProgramService.CreatePlannedSession(auth.UserID(c), id, date)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"workout": workoutSession.ApplyUnit(unit)})
+}
+
+func (h *ProgramImpl) LinkSession(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+	sessionId, err := strconv.ParseInt(c.Param("session_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid session_id")
+	}
+
+	f := form.NewLinkProgramDay()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	date, err := parseDate(f.Date)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid date format: "+err.Error())
+	}
+
+	workoutSession, err := h.ProgramService.LinkSession(auth.UserID(c), id, sessionId, date)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"workout": workoutSession})
+}
+
+func (h *ProgramImpl) Adherence(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	f := form.NewGetAdherence()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	asOf, err := parseDate(f.AsOf)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid date format: "+err.Error())
+	}
+
+	adherence, err := h.ProgramService.Adherence(auth.UserID(c), id, asOf)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"adherence": adherence})
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/auth"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/service"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/labstack/echo"
+)
+
+type (
+	// Progression 重量・回数の進め方と次のセッションの提案のハンドラを表す
+	Progression interface {
+		ListRules(c echo.Context) error
+		SaveRule(c echo.Context) error
+		DeleteRule(c echo.Context) error
+		Suggest(c echo.Context) error
+	}
+
+	// ProgressionImpl 重量・回数の進め方と次のセッションの提案のハンドラを表す
+	ProgressionImpl struct {
+		ProgressionService service.Progression
+		UserService        service.User
+	}
+)
+
+func NewProgression() Progression {
+	return &ProgressionImpl{
+		ProgressionService: service.NewProgression(),
+		UserService:        service.NewUser(),
+	}
+}
+
+func (h *ProgressionImpl) ListRules(c echo.Context) error {
+	unit, err := weightUnit(c, h.UserService, c.QueryParam("unit"))
+	if err != nil {
+		return err
+	}
+
+	rules, err := h.ProgressionService.ListRules(auth.UserID(c))
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"rules": rules.ApplyUnit(unit)})
+}
+
+func (h *ProgressionImpl) SaveRule(c echo.Context) error {
+	f := form.NewSaveProgressionRule()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, "")
+	if err != nil {
+		return err
+	}
+	if f.Unit == "" {
+		f.Unit = string(unit)
+	} else if _, err := units.ParseUnit(f.Unit); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	rule, err := h.ProgressionService.SaveRule(auth.UserID(c), *f)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"rule": rule.ApplyUnit(unit)})
+}
+
+func (h *ProgressionImpl) DeleteRule(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	if err := h.ProgressionService.DeleteRule(auth.UserID(c), id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *ProgressionImpl) Suggest(c echo.Context) error {
+	f := form.NewGetProgressionSuggestion()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, f.Unit)
+	if err != nil {
+		return err
+	}
+
+	suggestion, err := h.ProgressionService.Suggest(auth.UserID(c), c.Param("exercise"))
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"suggestion": suggestion.ApplyUnit(unit)})
+}
@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
+	"github.com/asaskevich/govalidator"
 	"github.com/labstack/echo"
 
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/auth"
 	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
 	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/service"
 )
 
@@ -14,41 +18,161 @@ type (
 	// Recommendation トレーニングメニュー提案のハンドラを表す
 	Recommendation interface {
 		ProposeTrainingMenu(c echo.Context) error
+		ProposeTrainingMenuStream(c echo.Context) error
+		CreateWorkout(c echo.Context) error
 	}
 
 	// RecommendationImpl トレーニングメニュー提案のハンドラ実装
 	RecommendationImpl struct {
 		RecommendationService service.Recommendation
+		WorkoutService        service.Workout
+		UserService           service.User
 	}
 )
 
-// コンストラクタ
-func NewRecommendation() Recommendation {
-	return &RecommendationImpl{
-		RecommendationService: service.NewRecommendation(),
+// コンストラクタ: メニューを生成するプロバイダの設定が不正な場合はエラーを返却
+func NewRecommendation() (Recommendation, error) {
+	recommendationService, err := service.NewRecommendation()
+	if err != nil {
+		return nil, err
 	}
+	return &RecommendationImpl{
+		RecommendationService: recommendationService,
+		WorkoutService:        service.NewWorkout(),
+		UserService:           service.NewUser(),
+	}, nil
 }
 
 // ユーザが選択した条件に応じてトレーニングメニューを提案
 func (h *RecommendationImpl) ProposeTrainingMenu(c echo.Context) error {
-	f := form.NewProposeTrainingMenu()
-	fmt.Println(f)
-	if err := c.Bind(f); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "invalid form: "+err.Error())
+	f, err := bindProposeTrainingMenu(c)
+	if err != nil {
+		return err
 	}
 
 	// OpenAI API等を利用して提案を行う
-	result, err := h.RecommendationService.ProposeTrainingMenu(
+	menu, err := h.RecommendationService.ProposeTrainingMenu(
+		c.Request().Context(),
+		auth.UserID(c),
 		f.TrainingGoal,
 		f.TargetParts,
 		f.ExperienceLevel,
 		f.AvailableTime,
 	)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return serviceError(err)
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"recommendation": result,
+		"menu": menu,
 	})
 }
+
+// ProposeTrainingMenuStream 条件に応じたメニューを生成中の出力とあわせてServer-Sent Eventsで送る
+// 生成中はdeltaイベントで出力の差分を、検証・保存したメニューはmenuイベントで送る
+// 送り始める前のエラーはステータスコードで返し、送り始めた後のエラーはerrorイベントで送る
+// クライアントが切断した場合はリクエストのコンテキストのキャンセルにより生成を中断する
+func (h *RecommendationImpl) ProposeTrainingMenuStream(c echo.Context) error {
+	f, err := bindProposeTrainingMenu(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	res := c.Response()
+	menu, err := h.RecommendationService.ProposeTrainingMenuStream(
+		ctx,
+		auth.UserID(c),
+		f.TrainingGoal,
+		f.TargetParts,
+		f.ExperienceLevel,
+		f.AvailableTime,
+		func(delta response.MenuDelta) error {
+			return writeEvent(res, "delta", delta)
+		},
+	)
+	if err != nil {
+		if ctx.Err() != nil {
+			c.Logger().Infof("recommendation stream canceled: %v", err)
+			return nil
+		}
+		if !res.Committed {
+			return serviceError(err)
+		}
+		c.Logger().Errorf("recommendation stream aborted: %v", err)
+		code, message := http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)
+		if he, ok := serviceError(err).(*echo.HTTPError); ok {
+			code, message = he.Code, fmt.Sprint(he.Message)
+		}
+		if err := writeEvent(res, "error", map[string]interface{}{"status": code, "message": message}); err != nil {
+			c.Logger().Errorf("couldn't send error event: %v", err)
+		}
+		return nil
+	}
+
+	if err := writeEvent(res, "menu", map[string]interface{}{"menu": menu}); err != nil {
+		c.Logger().Errorf("couldn't send menu event: %v", err)
+	}
+	return nil
+}
+
+// bindProposeTrainingMenu 提案の条件を読み込んで検証
+func bindProposeTrainingMenu(c echo.Context) (*form.ProposeTrainingMenu, error) {
+	f := form.NewProposeTrainingMenu()
+	if err := c.Bind(f); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid form: "+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "validation error "+err.Error())
+	}
+	return f, nil
+}
+
+// writeEvent dataをJSONにしたServer-Sent Eventsのイベントを書き込み、すぐにクライアントへ送り出す
+// 最初のイベントを書き込む際にイベントストリームのヘッダを送る
+func writeEvent(res *echo.Response, event string, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if !res.Committed {
+		res.Header().Set(echo.HeaderContentType, "text/event-stream")
+		res.Header().Set("Cache-Control", "no-cache")
+		// プロキシでバッファリングさせない
+		res.Header().Set("X-Accel-Buffering", "no")
+		res.WriteHeader(http.StatusOK)
+	}
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event, b); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
+
+// 提案したメニューから予定のセッションを作成
+func (h *RecommendationImpl) CreateWorkout(c echo.Context) error {
+	f := form.NewCreateWorkoutFromRecommendation()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid form: "+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "validation error "+err.Error())
+	}
+
+	parsedDate, err := parseDate(f.Date)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid date format: "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, "")
+	if err != nil {
+		return err
+	}
+
+	workoutSession, err := h.WorkoutService.CreateFromRecommendation(auth.UserID(c), parsedDate, f.RecommendationID, f.Menu)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"workout": workoutSession.ApplyUnit(unit)})
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/auth"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/service"
+	"github.com/labstack/echo"
+)
+
+type (
+	// Record 自己ベストのハンドラを表す
+	Record interface {
+		List(c echo.Context) error
+	}
+
+	// RecordImpl 自己ベストのハンドラを表す
+	RecordImpl struct {
+		RecordService service.Record
+		UserService   service.User
+	}
+)
+
+func NewRecord() Record {
+	return &RecordImpl{
+		RecordService: service.NewRecord(),
+		UserService:   service.NewUser(),
+	}
+}
+
+func (h *RecordImpl) List(c echo.Context) error {
+	unit, err := weightUnit(c, h.UserService, c.QueryParam("unit"))
+	if err != nil {
+		return err
+	}
+
+	records, err := h.RecordService.List(auth.UserID(c))
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"records": records.ApplyUnit(unit)})
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/auth"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/metrics"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/service"
+	"github.com/labstack/echo"
+)
+
+type (
+	// Stats 統計のハンドラを表す
+	Stats interface {
+		ExerciseProgress(c echo.Context) error
+	}
+
+	// StatsImpl 統計のハンドラを表す
+	StatsImpl struct {
+		StatsService service.Stats
+		UserService  service.User
+	}
+)
+
+func NewStats() Stats {
+	return &StatsImpl{
+		StatsService: service.NewStats(),
+		UserService:  service.NewUser(),
+	}
+}
+
+func (h *StatsImpl) ExerciseProgress(c echo.Context) error {
+	f := form.NewExerciseProgress()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	from, err := parseDate(f.From)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid from format: "+err.Error())
+	}
+	to, err := parseDate(f.To)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid to format: "+err.Error())
+	}
+	formula, err := metrics.ParseFormula(f.Formula)
+	if err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, f.Unit)
+	if err != nil {
+		return err
+	}
+
+	progress, err := h.StatsService.ExerciseProgress(auth.UserID(c), c.Param("exercise"), from, to, f.Bucket, formula)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"progress": progress.ApplyUnit(unit)})
+}
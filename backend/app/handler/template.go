@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/auth"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/service"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/labstack/echo"
+)
+
+type (
+	// Template ワークアウトのテンプレートのハンドラを表す
+	Template interface {
+		List(c echo.Context) error
+		Get(c echo.Context) error
+		Create(c echo.Context) error
+		CreateFromWorkout(c echo.Context) error
+		Update(c echo.Context) error
+		Delete(c echo.Context) error
+		Instantiate(c echo.Context) error
+	}
+
+	// TemplateImpl ワークアウトのテンプレートのハンドラを表す
+	TemplateImpl struct {
+		TemplateService service.Template
+		UserService     service.User
+	}
+)
+
+func NewTemplate() Template {
+	return &TemplateImpl{
+		TemplateService: service.NewTemplate(),
+		UserService:     service.NewUser(),
+	}
+}
+
+func (h *TemplateImpl) List(c echo.Context) error {
+	f := form.NewGetTemplate()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, f.Unit)
+	if err != nil {
+		return err
+	}
+
+	templates, err := h.TemplateService.List(auth.UserID(c))
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"templates": templates.ApplyUnit(unit)})
+}
+
+func (h *TemplateImpl) Get(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	f := form.NewGetTemplate()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, f.Unit)
+	if err != nil {
+		return err
+	}
+
+	template, err := h.TemplateService.Get(auth.UserID(c), id)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"template": template.ApplyUnit(unit)})
+}
+
+func (h *TemplateImpl) Create(c echo.Context) error {
+	f, unit, err := h.bindSaveTemplate(c)
+	if err != nil {
+		return err
+	}
+
+	template, err := h.TemplateService.Create(auth.UserID(c), *f)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"template": template.ApplyUnit(unit)})
+}
+
+func (h *TemplateImpl) CreateFromWorkout(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	f := form.NewCreateTemplateFromWorkout()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, "")
+	if err != nil {
+		return err
+	}
+
+	template, err := h.TemplateService.CreateFromWorkout(auth.UserID(c), id, f.Name)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"template": template.ApplyUnit(unit)})
+}
+
+func (h *TemplateImpl) Update(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	f, unit, err := h.bindSaveTemplate(c)
+	if err != nil {
+		return err
+	}
+
+	template, err := h.TemplateService.Update(auth.UserID(c), id, *f)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"template": template.ApplyUnit(unit)})
+}
+
+func (h *TemplateImpl) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	if err := h.TemplateService.Delete(auth.UserID(c), id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *TemplateImpl) Instantiate(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	f := form.NewInstantiateTemplate()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	parsedDate, err := parseDate(f.Date)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid date format: "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, "")
+	if err != nil {
+		return err
+	}
+
+	workoutSession, err := h.TemplateService.Instantiate(auth.UserID(c), id, parsedDate, f.Source)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"workout": workoutSession.ApplyUnit(unit)})
+}
+
+// bindSaveTemplate テンプレートの作成・更新フォームを検証し、目標セットの単位の指定がない場合はユーザーの設定を補う
+func (h *TemplateImpl) bindSaveTemplate(c echo.Context) (*form.SaveTemplate, units.Unit, error) {
+	f := form.NewSaveTemplate()
+	if err := c.Bind(f); err != nil {
+		return nil, "", echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return nil, "", echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, "")
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range f.Exercises {
+		for j := range f.Exercises[i].Sets {
+			set := &f.Exercises[i].Sets[j]
+			if set.Unit == "" {
+				set.Unit = string(unit)
+			} else if _, err := units.ParseUnit(set.Unit); err != nil {
+				return nil, "", echo.NewHTTPError(400, "validation error "+err.Error())
+			}
+		}
+	}
+	return f, unit, nil
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/auth"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/service"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/labstack/echo"
+)
+
+type (
+	// User ユーザーのハンドラを表す
+	User interface {
+		Signup(c echo.Context) error
+		Login(c echo.Context) error
+		Me(c echo.Context) error
+		UpdatePreferences(c echo.Context) error
+	}
+
+	// UserImpl ユーザーのハンドラを表す
+	UserImpl struct {
+		UserService service.User
+	}
+)
+
+func NewUser() User {
+	return &UserImpl{
+		UserService: service.NewUser(),
+	}
+}
+
+func (h *UserImpl) Signup(c echo.Context) error {
+	f := form.NewSignup()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	result, err := h.UserService.Signup(f.Email, f.Password, f.Name)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(201, result)
+}
+
+func (h *UserImpl) Login(c echo.Context) error {
+	f := form.NewLogin()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	result, err := h.UserService.Login(f.Email, f.Password)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, result)
+}
+
+func (h *UserImpl) Me(c echo.Context) error {
+	user, err := h.UserService.Get(auth.UserID(c))
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"user": user})
+}
+
+func (h *UserImpl) UpdatePreferences(c echo.Context) error {
+	f := form.NewUpdatePreferences()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	user, err := h.UserService.UpdatePreferences(auth.UserID(c), units.Unit(f.WeightUnit))
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"user": user})
+}
@@ -1,13 +1,17 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 	"time"
 
 	"github.com/asaskevich/govalidator"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/auth"
 	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
-	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/metrics"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
 	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/service"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
 	"github.com/labstack/echo"
 )
 
@@ -19,17 +23,30 @@ type (
 		CreateWorkoutSession(c echo.Context) error
 		CreateExercise(c echo.Context) error
 		CreateSet(c echo.Context) error
+		CreateWorkoutLog(c echo.Context) error
+		UpdateWorkoutSession(c echo.Context) error
+		StartWorkoutSession(c echo.Context) error
+		FinishWorkoutSession(c echo.Context) error
+		ReopenWorkoutSession(c echo.Context) error
+		UpdateExercise(c echo.Context) error
+		UpdateSet(c echo.Context) error
+		ReplaceTargetSets(c echo.Context) error
+		DeleteWorkoutSession(c echo.Context) error
+		DeleteExercise(c echo.Context) error
+		DeleteSet(c echo.Context) error
 	}
 
 	// WorkoutImpl ワークアウトのハンドラを表す
 	WorkoutImpl struct {
 		WorkoutService service.Workout
+		UserService    service.User
 	}
 )
 
 func NewWorkout() Workout {
 	return &WorkoutImpl{
 		WorkoutService: service.NewWorkout(),
+		UserService:    service.NewUser(),
 	}
 }
 
@@ -39,28 +56,49 @@ func (h *WorkoutImpl) List(c echo.Context) error {
 		return echo.NewHTTPError(400, "invalid form"+err.Error())
 	}
 
-	var workoutSessions response.WorkoutSessions
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	filter := model.WorkoutSessionFilter{
+		ID:           f.ID,
+		ExerciseName: f.ExerciseName,
+		Desc:         f.Sort != "asc",
+		Limit:        f.Limit,
+	}
+
 	var err error
+	if filter.Date, err = parseDate(f.Date); err != nil {
+		return echo.NewHTTPError(400, "invalid date format: "+err.Error())
+	}
+	if filter.From, err = parseDate(f.From); err != nil {
+		return echo.NewHTTPError(400, "invalid from format: "+err.Error())
+	}
+	if filter.To, err = parseDate(f.To); err != nil {
+		return echo.NewHTTPError(400, "invalid to format: "+err.Error())
+	}
 
-	var parsedDate time.Time
-	if f.Date != "" {
-		var err error
-		parsedDate, err = time.Parse(time.RFC3339, f.Date)
-		if err != nil {
-			return echo.NewHTTPError(400, "invalid date format: "+err.Error())
-		}
+	formula, err := metrics.ParseFormula(f.Formula)
+	if err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
 	}
 
-	workoutSessions, err = h.WorkoutService.List(f.ID, parsedDate)
+	unit, err := weightUnit(c, h.UserService, f.Unit)
 	if err != nil {
 		return err
 	}
 
+	workoutSessions, nextCursor, err := h.WorkoutService.List(auth.UserID(c), filter, f.Cursor, formula)
+	if err != nil {
+		return serviceError(err)
+	}
+	workoutSessions.ApplyUnit(unit)
+
 	if len(workoutSessions) == 0 {
-		return c.JSON(200, map[string]interface{}{"workouts": []interface{}{}})
+		return c.JSON(200, map[string]interface{}{"workouts": []interface{}{}, "next_cursor": nextCursor})
 	}
 
-	return c.JSON(200, map[string]interface{}{"workouts": workoutSessions})
+	return c.JSON(200, map[string]interface{}{"workouts": workoutSessions, "next_cursor": nextCursor})
 }
 
 func (h *WorkoutImpl) Get(c echo.Context) error {
@@ -69,12 +107,30 @@ func (h *WorkoutImpl) Get(c echo.Context) error {
 		return echo.NewHTTPError(400, "invalid id")
 	}
 
-	workoutSession, err := h.WorkoutService.Get(id)
+	f := form.NewGetWorkout()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	formula, err := metrics.ParseFormula(f.Formula)
+	if err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, f.Unit)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(200, map[string]interface{}{"workout": workoutSession})
+	workoutSession, err := h.WorkoutService.Get(auth.UserID(c), id, formula)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"workout": workoutSession.ApplyUnit(unit)})
 }
 
 func (h *WorkoutImpl) CreateWorkoutSession(c echo.Context) error {
@@ -92,7 +148,7 @@ func (h *WorkoutImpl) CreateWorkoutSession(c echo.Context) error {
 		return echo.NewHTTPError(400, "invalid date format: "+err.Error())
 	}
 
-	workoutSession, err := h.WorkoutService.CreateWorkoutSession(parsedDate, f.UserID)
+	workoutSession, err := h.WorkoutService.CreateWorkoutSession(parsedDate, auth.UserID(c))
 	if err != nil {
 		return err
 	}
@@ -114,19 +170,19 @@ func (h *WorkoutImpl) CreateExercise(c echo.Context) error {
 		return echo.NewHTTPError(400, "validation error "+err.Error())
 	}
 
-	exercise, err := h.WorkoutService.CreateExercise(id, f.ExerciseName)
+	exercise, err := h.WorkoutService.CreateExercise(auth.UserID(c), id, f.CatalogID, f.ExerciseName, f.Modality)
 	if err != nil {
-		return err
+		return serviceError(err)
 	}
 
 	return c.JSON(200, map[string]interface{}{"exercise": exercise})
 }
 
 func (h *WorkoutImpl) CreateSet(c echo.Context) error {
-	// id, err := strconv.ParseInt(c.Param("id"), 10, 64)
-	// if err != nil {
-	// 	return echo.NewHTTPError(400, "invalid id")
-	// }
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
 
 	exercise_id, err := strconv.ParseInt(c.Param("exercise_id"), 10, 64)
 	if err != nil {
@@ -141,10 +197,475 @@ func (h *WorkoutImpl) CreateSet(c echo.Context) error {
 		return echo.NewHTTPError(400, "validation error "+err.Error())
 	}
 
-	sets, err := h.WorkoutService.CreateSet(exercise_id, f.SetNumber, f.Weight, f.Reps)
+	// 入力の単位の指定がない場合は、表示と同じくユーザーの設定した単位とする
+	unit, err := weightUnit(c, h.UserService, "")
+	if err != nil {
+		return err
+	}
+	if f.Unit == "" {
+		f.Unit = string(unit)
+	}
+
+	sets, personalRecords, err := h.WorkoutService.CreateSet(auth.UserID(c), id, exercise_id, *f)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"sets": sets.ApplyUnit(unit), "personal_records": personalRecords.ApplyUnit(unit)})
+}
+
+func (h *WorkoutImpl) CreateWorkoutLog(c echo.Context) error {
+	f := form.NewCreateWorkoutLog()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	parsedDate, err := time.Parse(time.RFC3339, f.Date)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid date format: "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, "")
+	if err != nil {
+		return err
+	}
+	for i := range f.Exercises {
+		for j := range f.Exercises[i].Sets {
+			set := &f.Exercises[i].Sets[j]
+			if set.Unit == "" {
+				set.Unit = string(unit)
+			} else if _, err := units.ParseUnit(set.Unit); err != nil {
+				return echo.NewHTTPError(400, "validation error "+err.Error())
+			}
+		}
+	}
+
+	workoutSession, err := h.WorkoutService.CreateWorkoutLog(parsedDate, auth.UserID(c), f.Exercises)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"workout": workoutSession.ApplyUnit(unit)})
+}
+
+func (h *WorkoutImpl) UpdateWorkoutSession(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	f := form.NewUpdateWorkoutSession()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if isPut(c) && f.Date == nil {
+		return echo.NewHTTPError(400, "validation error date: non zero value required")
+	}
+
+	attrs := map[string]interface{}{}
+	if f.Date != nil {
+		parsedDate, err := time.Parse(time.RFC3339, *f.Date)
+		if err != nil {
+			return echo.NewHTTPError(400, "invalid date format: "+err.Error())
+		}
+		attrs["training_date"] = parsedDate
+	}
+	sessionNotesAndRating(attrs, f.Notes, f.Rating)
+	if len(attrs) == 0 {
+		return echo.NewHTTPError(400, "nothing to update")
+	}
+
+	workoutSession, err := h.WorkoutService.UpdateWorkoutSession(auth.UserID(c), id, attrs)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"workout": workoutSession})
+}
+
+func (h *WorkoutImpl) StartWorkoutSession(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	f := form.NewStartWorkoutSession()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	startedAt, err := parseOptionalTime(f.StartedAt)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid started_at format: "+err.Error())
+	}
+
+	workoutSession, err := h.WorkoutService.StartWorkoutSession(auth.UserID(c), id, startedAt)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"workout": workoutSession})
+}
+
+func (h *WorkoutImpl) FinishWorkoutSession(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	f := form.NewFinishWorkoutSession()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	finishedAt, err := parseOptionalTime(f.FinishedAt)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid finished_at format: "+err.Error())
+	}
+
+	attrs := map[string]interface{}{}
+	sessionNotesAndRating(attrs, f.Notes, f.Rating)
+
+	workoutSession, err := h.WorkoutService.FinishWorkoutSession(auth.UserID(c), id, finishedAt, attrs)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"workout": workoutSession})
+}
+
+func (h *WorkoutImpl) ReopenWorkoutSession(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	workoutSession, err := h.WorkoutService.ReopenWorkoutSession(auth.UserID(c), id)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"workout": workoutSession})
+}
+
+// sessionNotesAndRating セッションのメモと評価を更新内容に加える。評価は0の場合は消去する
+func sessionNotesAndRating(attrs map[string]interface{}, notes *string, rating *int64) {
+	if notes != nil {
+		attrs["notes"] = *notes
+	}
+	if rating != nil {
+		attrs["rating"] = nil
+		if *rating != 0 {
+			attrs["rating"] = *rating
+		}
+	}
+}
+
+// parseOptionalTime RFC3339の日時を解析。空文字の場合はゼロ値を返却
+func parseOptionalTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func (h *WorkoutImpl) UpdateExercise(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	exercise_id, err := strconv.ParseInt(c.Param("exercise_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid exercise_id")
+	}
+
+	f := form.NewUpdateExercise()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if isPut(c) && f.ExerciseName == nil && f.CatalogID == nil {
+		return echo.NewHTTPError(400, "validation error exercise_name or catalog_id: non zero value required")
+	}
+
+	attrs := map[string]interface{}{}
+	if f.ExerciseName != nil {
+		if *f.ExerciseName == "" {
+			return echo.NewHTTPError(400, "validation error exercise_name: non zero value required")
+		}
+		attrs["exercise_name"] = *f.ExerciseName
+	}
+	if f.CatalogID != nil {
+		attrs["catalog_id"] = *f.CatalogID
+	}
+	if f.Modality != nil {
+		attrs["modality"] = *f.Modality
+	}
+	if len(attrs) == 0 {
+		return echo.NewHTTPError(400, "nothing to update")
+	}
+
+	unit, err := weightUnit(c, h.UserService, "")
+	if err != nil {
+		return err
+	}
+
+	exercise, err := h.WorkoutService.UpdateExercise(auth.UserID(c), id, exercise_id, attrs)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"exercise": exercise.ApplyUnit(unit)})
+}
+
+func (h *WorkoutImpl) ReplaceTargetSets(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	exercise_id, err := strconv.ParseInt(c.Param("exercise_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid exercise_id")
+	}
+
+	f := form.NewReplaceTargetSets()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if _, err := govalidator.ValidateStruct(f); err != nil {
+		return echo.NewHTTPError(400, "validation error "+err.Error())
+	}
+
+	unit, err := weightUnit(c, h.UserService, "")
 	if err != nil {
 		return err
 	}
+	for i := range f.Sets {
+		if f.Sets[i].Unit == "" {
+			f.Sets[i].Unit = string(unit)
+		} else if _, err := units.ParseUnit(f.Sets[i].Unit); err != nil {
+			return echo.NewHTTPError(400, "validation error "+err.Error())
+		}
+	}
+
+	exercise, err := h.WorkoutService.ReplaceTargetSets(auth.UserID(c), id, exercise_id, f.Sets)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"exercise": exercise.ApplyUnit(unit)})
+}
+
+func (h *WorkoutImpl) UpdateSet(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	exercise_id, err := strconv.ParseInt(c.Param("exercise_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid exercise_id")
+	}
 
-	return c.JSON(200, map[string]interface{}{"sets": sets})
+	set_id, err := strconv.ParseInt(c.Param("set_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid set_id")
+	}
+
+	f := form.NewUpdateSet()
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(400, "invalid form"+err.Error())
+	}
+	if isPut(c) && (f.SetNumber == nil || f.Weight == nil || f.Reps == nil) {
+		return echo.NewHTTPError(400, "validation error set_number, weight and reps are required")
+	}
+
+	attrs := map[string]interface{}{}
+	if f.SetNumber != nil {
+		attrs["set_number"] = *f.SetNumber
+	}
+	if f.Reps != nil {
+		attrs["reps"] = *f.Reps
+	}
+	// 時間・距離は0の場合は消去する
+	if f.DurationSeconds != nil {
+		attrs["duration_seconds"] = nil
+		if *f.DurationSeconds != 0 {
+			attrs["duration_seconds"] = *f.DurationSeconds
+		}
+	}
+	if f.DistanceMeters != nil {
+		attrs["distance_meters"] = nil
+		if *f.DistanceMeters != 0 {
+			attrs["distance_meters"] = *f.DistanceMeters
+		}
+	}
+	if f.SetType != nil {
+		attrs["set_type"] = *f.SetType
+	}
+	if f.RPE != nil {
+		attrs["rpe"] = *f.RPE
+	}
+	if f.RIR != nil {
+		attrs["rir"] = *f.RIR
+	}
+	if f.RestSeconds != nil {
+		attrs["rest_seconds"] = *f.RestSeconds
+	}
+	if f.Tempo != nil {
+		attrs["tempo"] = *f.Tempo
+	}
+	if f.PerformedAt != nil {
+		// 空文字の場合は実施日時を消去する
+		if *f.PerformedAt == "" {
+			attrs["performed_at"] = nil
+		} else {
+			performedAt, err := time.Parse(time.RFC3339, *f.PerformedAt)
+			if err != nil {
+				return echo.NewHTTPError(400, "invalid performed_at format: "+err.Error())
+			}
+			attrs["performed_at"] = performedAt
+		}
+	}
+	if f.Notes != nil {
+		attrs["notes"] = *f.Notes
+	}
+
+	unit, err := weightUnit(c, h.UserService, "")
+	if err != nil {
+		return err
+	}
+	if f.Weight != nil {
+		attrs["weight"] = *f.Weight
+		attrs["unit"] = string(unit)
+		if f.Unit != "" {
+			enteredUnit, err := units.ParseUnit(f.Unit)
+			if err != nil {
+				return echo.NewHTTPError(400, "validation error "+err.Error())
+			}
+			attrs["unit"] = string(enteredUnit)
+		}
+	}
+	if len(attrs) == 0 {
+		return echo.NewHTTPError(400, "nothing to update")
+	}
+
+	set, err := h.WorkoutService.UpdateSet(auth.UserID(c), id, exercise_id, set_id, attrs)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(200, map[string]interface{}{"set": set.ApplyUnit(unit)})
+}
+
+func (h *WorkoutImpl) DeleteWorkoutSession(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	if err := h.WorkoutService.DeleteWorkoutSession(auth.UserID(c), id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *WorkoutImpl) DeleteExercise(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	exercise_id, err := strconv.ParseInt(c.Param("exercise_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid exercise_id")
+	}
+
+	if err := h.WorkoutService.DeleteExercise(auth.UserID(c), id, exercise_id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *WorkoutImpl) DeleteSet(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid id")
+	}
+
+	exercise_id, err := strconv.ParseInt(c.Param("exercise_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid exercise_id")
+	}
+
+	set_id, err := strconv.ParseInt(c.Param("set_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(400, "invalid set_id")
+	}
+
+	if err := h.WorkoutService.DeleteSet(auth.UserID(c), id, exercise_id, set_id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(204)
+}
+
+// parseDate RFC3339または日付のみの形式を受け付ける。空文字の場合はゼロ値を返却
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// weightUnit 表示・入力に用いる重量の単位を返却。指定がない場合はユーザーの設定した単位とする
+func weightUnit(c echo.Context, userService service.User, requested string) (units.Unit, error) {
+	if requested != "" {
+		unit, err := units.ParseUnit(requested)
+		if err != nil {
+			return "", echo.NewHTTPError(400, "validation error "+err.Error())
+		}
+		return unit, nil
+	}
+
+	unit, err := userService.WeightUnit(auth.UserID(c))
+	if err != nil {
+		return "", serviceError(err)
+	}
+	return unit, nil
+}
+
+// isPut PUTの場合は全項目の指定を必須とする
+func isPut(c echo.Context) bool {
+	return c.Request().Method == echo.PUT
+}
+
+// serviceError サービスのエラーをHTTPエラーに変換
+func serviceError(err error) error {
+	if errors.Is(err, service.ErrNotFound) {
+		return echo.NewHTTPError(404, err.Error())
+	}
+	if errors.Is(err, service.ErrInvalidArgument) {
+		return echo.NewHTTPError(400, err.Error())
+	}
+	if errors.Is(err, service.ErrConflict) {
+		return echo.NewHTTPError(409, err.Error())
+	}
+	if errors.Is(err, service.ErrUnauthorized) {
+		return echo.NewHTTPError(401, err.Error())
+	}
+	if errors.Is(err, service.ErrForbidden) {
+		return echo.NewHTTPError(403, err.Error())
+	}
+	if errors.Is(err, service.ErrUpstream) {
+		return echo.NewHTTPError(502, err.Error())
+	}
+	return err
 }
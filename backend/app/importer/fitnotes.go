@@ -0,0 +1,83 @@
+package importer
+
+import (
+	"strings"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+)
+
+// fitNotesWeightColumns FitNotesの重量の列名と単位。単位の列で指定する書式では空文字とする
+var fitNotesWeightColumns = []struct {
+	name string
+	unit string
+}{
+	{"weight (kgs)", "kg"},
+	{"weight (kg)", "kg"},
+	{"weight (lbs)", "lb"},
+	{"weight (lb)", "lb"},
+	{"weight", ""},
+}
+
+// parseFitNotes FitNotesの1行を読み取る
+// FitNotesは日付のみを記録するため、同じ日の行を1つのセッションとする
+func parseFitNotes(cols columns, record []string, unit units.Unit) (row, bool, error) {
+	date := cols.get(record, "date")
+	d, err := parseTime(date, "2006-01-02")
+	if err != nil {
+		return row{}, false, err
+	}
+
+	set := Set{SetType: model.SetTypeWorking, Notes: cols.get(record, "comment")}
+	for _, c := range fitNotesWeightColumns {
+		if !cols.has(c.name) {
+			continue
+		}
+		entered := c.unit
+		if entered == "" {
+			entered = cols.get(record, "weight unit")
+		}
+		if set.Unit, err = weightUnit(entered, unit); err != nil {
+			return row{}, false, err
+		}
+		if set.Weight, err = parseFloat(cols.get(record, c.name)); err != nil {
+			return row{}, false, err
+		}
+		break
+	}
+	if set.Unit == "" {
+		set.Unit = unit
+	}
+	if set.Reps, err = parseInt(cols.get(record, "reps")); err != nil {
+		return row{}, false, err
+	}
+	if set.DurationSeconds, err = clockSeconds(cols.get(record, "time")); err != nil {
+		return row{}, false, err
+	}
+	distance, err := parseFloat(cols.get(record, "distance"))
+	if err != nil {
+		return row{}, false, err
+	}
+	if distance != 0 {
+		distanceUnit := cols.get(record, "distance unit")
+		if distanceUnit == "" {
+			distanceUnit = defaultDistanceUnit(set.Unit)
+		}
+		if set.DistanceMeters, err = distanceMeters(distance, distanceUnit); err != nil {
+			return row{}, false, err
+		}
+	}
+	if isEmptySet(set) {
+		return row{}, false, nil
+	}
+	if strings.EqualFold(cols.get(record, "is warmup"), "true") {
+		set.SetType = model.SetTypeWarmup
+	}
+
+	return row{
+		sessionKey: date,
+		date:       d,
+		exercise:   cols.get(record, "exercise"),
+		set:        set,
+	}, true, nil
+}
@@ -0,0 +1,103 @@
+package importer
+
+import (
+	"strings"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+)
+
+// hevyTimeLayouts Hevyのバージョンによって異なる日時の書式
+var hevyTimeLayouts = []string{"2 Jan 2006, 15:04", "2 Jan 2006 15:04", time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+// hevySetTypes Hevyの"set_type"をセットの種類に変換
+var hevySetTypes = map[string]string{
+	"normal":  model.SetTypeWorking,
+	"warmup":  model.SetTypeWarmup,
+	"dropset": model.SetTypeDrop,
+	"failure": model.SetTypeFailure,
+}
+
+// hevyDistanceColumns Hevyの距離の列名と単位
+var hevyDistanceColumns = []struct {
+	name string
+	unit string
+}{
+	{"distance_km", "km"},
+	{"distance_miles", "mi"},
+	{"distance_meters", "m"},
+}
+
+// parseHevy Hevyの1行を読み取る
+// 開始日時とタイトルが同じ行を1つのセッションとし、種目のメモは最初のセットのメモとして取り込む
+func parseHevy(cols columns, record []string, unit units.Unit) (row, bool, error) {
+	start := cols.get(record, "start_time")
+	startedAt, err := parseTime(start, hevyTimeLayouts...)
+	if err != nil {
+		return row{}, false, err
+	}
+	var finishedAt time.Time
+	if end := cols.get(record, "end_time"); end != "" {
+		if finishedAt, err = parseTime(end, hevyTimeLayouts...); err != nil {
+			return row{}, false, err
+		}
+	}
+
+	set := Set{SetType: model.SetTypeWorking, Unit: unit}
+	if setType, ok := hevySetTypes[strings.ToLower(cols.get(record, "set_type"))]; ok {
+		set.SetType = setType
+	}
+	// 重量の列名に単位が付く
+	switch {
+	case cols.has("weight_kg"):
+		set.Unit = units.Kilogram
+		set.Weight, err = parseFloat(cols.get(record, "weight_kg"))
+	case cols.has("weight_lbs"):
+		set.Unit = units.Pound
+		set.Weight, err = parseFloat(cols.get(record, "weight_lbs"))
+	}
+	if err != nil {
+		return row{}, false, err
+	}
+	if set.Reps, err = parseInt(cols.get(record, "reps")); err != nil {
+		return row{}, false, err
+	}
+	if set.DurationSeconds, err = parseInt(cols.get(record, "duration_seconds")); err != nil {
+		return row{}, false, err
+	}
+	if set.RPE, err = parseFloat(cols.get(record, "rpe")); err != nil {
+		return row{}, false, err
+	}
+	for _, c := range hevyDistanceColumns {
+		if !cols.has(c.name) {
+			continue
+		}
+		distance, err := parseFloat(cols.get(record, c.name))
+		if err != nil {
+			return row{}, false, err
+		}
+		if distance != 0 {
+			if set.DistanceMeters, err = distanceMeters(distance, c.unit); err != nil {
+				return row{}, false, err
+			}
+		}
+	}
+	if isEmptySet(set) {
+		return row{}, false, nil
+	}
+	if index := cols.get(record, "set_index"); index == "" || index == "0" {
+		set.Notes = cols.get(record, "exercise_notes")
+	}
+
+	return row{
+		sessionKey:   start + "\x00" + cols.get(record, "title"),
+		date:         trainingDate(startedAt),
+		startedAt:    startedAt,
+		finishedAt:   finishedAt,
+		sessionName:  cols.get(record, "title"),
+		sessionNotes: cols.get(record, "description"),
+		exercise:     cols.get(record, "exercise_title"),
+		set:          set,
+	}, true, nil
+}
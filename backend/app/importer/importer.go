@@ -0,0 +1,386 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+)
+
+// 取り込める他のアプリのCSVの形式
+const (
+	FormatStrong   = "strong"
+	FormatHevy     = "hevy"
+	FormatFitNotes = "fitnotes"
+)
+
+var Formats = []string{FormatStrong, FormatHevy, FormatFitNotes}
+
+// ErrUnknownFormat ヘッダから形式を判別できない、または未対応の形式
+var ErrUnknownFormat = errors.New("unknown import format")
+
+// utf8BOM 表計算ソフトで保存したCSVの先頭に付くBOM
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+type (
+	// Session 取り込むセッションを表す。Keyは再取り込み時の重複の判定に使う
+	Session struct {
+		Key  string
+		Line int
+		Date time.Time
+		// 記録がない場合はゼロ値
+		StartedAt  time.Time
+		FinishedAt time.Time
+		Name       string
+		Notes      string
+		Exercises  []Exercise
+	}
+
+	// Exercise 取り込むエクササイズを表す。Nameは取り込み元のアプリの種目名
+	Exercise struct {
+		Name string
+		Sets []Set
+	}
+
+	// Set 取り込むセットを表す。重量はUnitの単位で、記録のない時間・距離・RPEは0とする
+	Set struct {
+		Line            int
+		SetNumber       int64
+		SetType         string
+		Weight          float64
+		Unit            units.Unit
+		Reps            int64
+		DurationSeconds int64
+		DistanceMeters  float64
+		RPE             float64
+		Notes           string
+	}
+
+	// row CSVの1行を表す。sessionKeyが同じ行を1つのセッションにまとめる
+	row struct {
+		sessionKey   string
+		date         time.Time
+		startedAt    time.Time
+		finishedAt   time.Time
+		sessionName  string
+		sessionNotes string
+		exercise     string
+		set          Set
+	}
+
+	// columns ヘッダの列名(小文字)から列の位置を引く
+	columns map[string]int
+
+	// parser 形式ごとにCSVの1行を読み取る。記録のない行はfalseを返却して読み飛ばす
+	parser func(cols columns, record []string, unit units.Unit) (row, bool, error)
+)
+
+// Parse CSVを読み込み、セッション・エクササイズ・セットにまとめる
+// formatが空の場合はヘッダから形式を判別する。重量の単位を記録しない形式ではunitの単位とみなす
+// 判別した形式もあわせて返却する
+func Parse(r io.Reader, format string, unit units.Unit) (string, []Session, error) {
+	br := bufio.NewReader(r)
+	if b, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(b, utf8BOM) {
+		br.Discard(len(utf8BOM))
+	}
+	// ロケールによってはセミコロン区切りで書き出されるため、1行目から区切り文字を判別する
+	first, _ := br.Peek(4096)
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if delimiter(first) == ';' {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return "", nil, fmt.Errorf("empty file: %w", ErrUnknownFormat)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	cols := newColumns(header)
+
+	if format == "" {
+		format = Detect(header)
+	}
+	var parse parser
+	switch format {
+	case FormatStrong:
+		parse = parseStrong
+	case FormatHevy:
+		parse = parseHevy
+	case FormatFitNotes:
+		parse = parseFitNotes
+	default:
+		return "", nil, fmt.Errorf("%q: %w", format, ErrUnknownFormat)
+	}
+	if err := cols.require(format); err != nil {
+		return "", nil, err
+	}
+
+	var rows []row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlank(record) {
+			continue
+		}
+
+		r, ok, err := parse(cols, record, unit)
+		if err != nil {
+			return "", nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if !ok {
+			continue
+		}
+		r.set.Line = line
+		rows = append(rows, r)
+	}
+
+	return format, group(format, rows), nil
+}
+
+// Detect ヘッダの列名から形式を判別。判別できない場合は空文字を返却
+func Detect(header []string) string {
+	cols := newColumns(header)
+	switch {
+	case cols.has("workout name") && cols.has("exercise name") && cols.has("set order"):
+		return FormatStrong
+	case cols.has("exercise_title") && cols.has("start_time"):
+		return FormatHevy
+	case cols.has("exercise") && cols.has("category") && cols.has("date"):
+		return FormatFitNotes
+	}
+	return ""
+}
+
+// group 行をセッション・エクササイズの順にまとめ、エクササイズごとにセット番号を振り直す
+// セッション・エクササイズはCSVに最初に現れた順とする
+func group(format string, rows []row) []Session {
+	var sessions []Session
+	sessionIndex := map[string]int{}
+	exerciseIndex := map[string]map[string]int{}
+
+	for _, r := range rows {
+		i, ok := sessionIndex[r.sessionKey]
+		if !ok {
+			i = len(sessions)
+			sessionIndex[r.sessionKey] = i
+			exerciseIndex[r.sessionKey] = map[string]int{}
+			sessions = append(sessions, Session{
+				Key:        importKey(format, r.sessionKey),
+				Line:       r.set.Line,
+				Date:       r.date,
+				StartedAt:  r.startedAt,
+				FinishedAt: r.finishedAt,
+				Name:       r.sessionName,
+				Notes:      r.sessionNotes,
+			})
+		}
+		session := &sessions[i]
+
+		j, ok := exerciseIndex[r.sessionKey][r.exercise]
+		if !ok {
+			j = len(session.Exercises)
+			exerciseIndex[r.sessionKey][r.exercise] = j
+			session.Exercises = append(session.Exercises, Exercise{Name: r.exercise})
+		}
+		exercise := &session.Exercises[j]
+
+		r.set.SetNumber = int64(len(exercise.Sets) + 1)
+		exercise.Sets = append(exercise.Sets, r.set)
+	}
+	return sessions
+}
+
+// importKey 取り込み元の形式とセッションを識別する値から、再取り込みでも変わらないキーを作る
+func importKey(format string, sessionKey string) string {
+	sum := sha256.Sum256([]byte(format + "\x00" + sessionKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// delimiter 1行目にカンマがなくセミコロンがあればセミコロン区切りとみなす
+func delimiter(first []byte) rune {
+	line, _, _ := bytes.Cut(first, []byte("\n"))
+	if !bytes.ContainsRune(line, ',') && bytes.ContainsRune(line, ';') {
+		return ';'
+	}
+	return ','
+}
+
+func isBlank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func newColumns(header []string) columns {
+	cols := columns{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := cols[name]; !ok {
+			cols[name] = i
+		}
+	}
+	return cols
+}
+
+func (c columns) has(name string) bool {
+	_, ok := c[name]
+	return ok
+}
+
+// get 列の値を返却。列がない場合は空文字とする
+func (c columns) get(record []string, name string) string {
+	i, ok := c[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// first namesのうち最初に存在する列の名前を返却
+func (c columns) first(names ...string) string {
+	for _, name := range names {
+		if c.has(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// require 形式ごとに必須の列がそろっているか確認
+func (c columns) require(format string) error {
+	required := map[string][]string{
+		FormatStrong:   {"date", "exercise name", "weight", "reps"},
+		FormatHevy:     {"start_time", "exercise_title", "reps"},
+		FormatFitNotes: {"date", "exercise", "reps"},
+	}[format]
+	for _, name := range required {
+		if !c.has(name) {
+			return fmt.Errorf("column %q is required for %s: %w", name, format, ErrUnknownFormat)
+		}
+	}
+	return nil
+}
+
+// parseFloat 空欄は0とする。小数点にカンマを使うロケールの値も読み取る
+func parseFloat(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if !strings.Contains(s, ".") {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return v, nil
+}
+
+// parseInt 空欄は0とする。小数で書き出された回数・秒数も整数として読み取る
+func parseInt(s string) (int64, error) {
+	v, err := parseFloat(s)
+	if err != nil {
+		return 0, err
+	}
+	return int64(v), nil
+}
+
+// parseTime いずれかのレイアウトで日時を読み取る。タイムゾーンは記録された時刻のまま扱う
+func parseTime(s string, layouts ...string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// trainingDate 日時から日付のみを取り出す
+func trainingDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// weightUnit 取り込み元の単位の表記を単位に変換。空の場合はfallbackとする
+func weightUnit(s string, fallback units.Unit) (units.Unit, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return fallback, nil
+	case "kg", "kgs":
+		return units.Kilogram, nil
+	case "lb", "lbs":
+		return units.Pound, nil
+	}
+	return "", fmt.Errorf("unknown weight unit %q", s)
+}
+
+// distanceMeters 距離を取り込み元の単位からmに変換
+func distanceMeters(distance float64, unit string) (float64, error) {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "km", "kms":
+		return distance * 1000, nil
+	case "m", "meters":
+		return distance, nil
+	case "mi", "mile", "miles":
+		return distance * 1609.344, nil
+	case "ft", "feet":
+		return distance * 0.3048, nil
+	case "yd", "yds", "yards":
+		return distance * 0.9144, nil
+	}
+	return 0, fmt.Errorf("unknown distance unit %q", unit)
+}
+
+// defaultDistanceUnit 距離の単位を記録しない形式では、重量がポンドならマイル、それ以外はkmとみなす
+func defaultDistanceUnit(unit units.Unit) string {
+	if unit == units.Pound {
+		return "mi"
+	}
+	return "km"
+}
+
+// clockSeconds h:mm:ss または mm:ss の時間を秒に変換
+func clockSeconds(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if !strings.Contains(s, ":") {
+		return parseInt(s)
+	}
+	var seconds int64
+	for _, part := range strings.Split(s, ":") {
+		v, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+		seconds = seconds*60 + v
+	}
+	return seconds, nil
+}
+
+// isEmptySet 重量・回数・時間・距離のいずれも記録のないセット
+func isEmptySet(set Set) bool {
+	return set.Weight == 0 && set.Reps == 0 && set.DurationSeconds == 0 && set.DistanceMeters == 0
+}
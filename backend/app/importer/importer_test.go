@@ -0,0 +1,141 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		testCase  string
+		csv       string
+		format    string
+		unit      units.Unit
+		assertion func(format string, sessions []Session, err error)
+	}{
+		{
+			testCase: "正常系(Strong、セミコロン区切り・BOM付き)",
+			csv: "\ufeffDate;Workout Name;Duration;Exercise Name;Set Order;Weight;Reps;Distance;Seconds;Notes;Workout Notes;RPE\n" +
+				"2023-01-15 08:30:00;Push Day;1h 5m;Bench Press (Barbell);W;40;10;0;0;;脚が痛い;\n" +
+				"2023-01-15 08:30:00;Push Day;1h 5m;Bench Press (Barbell);1;60,5;8;0;0;きつい;脚が痛い;8.5\n" +
+				"2023-01-15 08:30:00;Push Day;1h 5m;Bench Press (Barbell);Rest Timer;0;0;0;90;;脚が痛い;\n" +
+				"2023-01-15 08:30:00;Push Day;1h 5m;Running;1;0;0;5;1500;;脚が痛い;\n",
+			unit: units.Kilogram,
+			assertion: func(format string, sessions []Session, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, FormatStrong, format)
+				if assert.Len(t, sessions, 1) {
+					s := sessions[0]
+					assert.Equal(t, date, s.Date)
+					assert.Equal(t, time.Date(2023, 1, 15, 8, 30, 0, 0, time.UTC), s.StartedAt)
+					assert.Equal(t, time.Date(2023, 1, 15, 9, 35, 0, 0, time.UTC), s.FinishedAt)
+					assert.Equal(t, "Push Day", s.Name)
+					assert.Equal(t, "脚が痛い", s.Notes)
+					assert.Equal(t, 2, s.Line)
+					assert.Len(t, s.Key, 64)
+					assert.Equal(t, []Exercise{
+						{Name: "Bench Press (Barbell)", Sets: []Set{
+							{Line: 2, SetNumber: 1, SetType: model.SetTypeWarmup, Weight: 40, Unit: units.Kilogram, Reps: 10},
+							{Line: 3, SetNumber: 2, SetType: model.SetTypeWorking, Weight: 60.5, Unit: units.Kilogram, Reps: 8, RPE: 8.5, Notes: "きつい"},
+						}},
+						{Name: "Running", Sets: []Set{
+							{Line: 5, SetNumber: 1, SetType: model.SetTypeWorking, Unit: units.Kilogram, DurationSeconds: 1500, DistanceMeters: 5000},
+						}},
+					}, s.Exercises)
+				}
+			},
+		},
+		{
+			testCase: "正常系(Hevy、ポンドの列)",
+			csv: `"title","start_time","end_time","description","exercise_title","superset_id","exercise_notes","set_index","set_type","weight_lbs","reps","distance_miles","duration_seconds","rpe"` + "\n" +
+				`"Legs","15 Jan 2023, 08:30","15 Jan 2023, 09:30","","Squat (Barbell)","","深く",0,"warmup",135,5,,,` + "\n" +
+				`"Legs","15 Jan 2023, 08:30","15 Jan 2023, 09:30","","Squat (Barbell)","","深く",1,"normal",225,5,,,9` + "\n" +
+				`"Legs","16 Jan 2023, 08:30","16 Jan 2023, 09:30","","Squat (Barbell)","","",0,"normal",230,5,,,` + "\n",
+			unit: units.Kilogram,
+			assertion: func(format string, sessions []Session, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, FormatHevy, format)
+				if assert.Len(t, sessions, 2) {
+					assert.NotEqual(t, sessions[0].Key, sessions[1].Key)
+					assert.Equal(t, time.Date(2023, 1, 15, 9, 30, 0, 0, time.UTC), sessions[0].FinishedAt)
+					assert.Equal(t, []Set{
+						{Line: 2, SetNumber: 1, SetType: model.SetTypeWarmup, Weight: 135, Unit: units.Pound, Reps: 5, Notes: "深く"},
+						{Line: 3, SetNumber: 2, SetType: model.SetTypeWorking, Weight: 225, Unit: units.Pound, Reps: 5, RPE: 9},
+					}, sessions[0].Exercises[0].Sets)
+				}
+			},
+		},
+		{
+			testCase: "正常系(FitNotes、同じ日を1つのセッションにまとめる)",
+			csv: "Date,Exercise,Category,Weight (lbs),Reps,Distance,Distance Unit,Time,Comment\n" +
+				"2023-01-15,Deadlift,Back,315,5,,,,\n" +
+				"2023-01-15,Plank,Abs,,,,,0:01:30,\n" +
+				"2023-01-15,Deadlift,Back,315,5,,,,\"重い, けど引けた\"\n",
+			unit: units.Kilogram,
+			assertion: func(format string, sessions []Session, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, FormatFitNotes, format)
+				if assert.Len(t, sessions, 1) {
+					assert.Equal(t, date, sessions[0].Date)
+					assert.True(t, sessions[0].StartedAt.IsZero())
+					assert.Equal(t, []Exercise{
+						{Name: "Deadlift", Sets: []Set{
+							{Line: 2, SetNumber: 1, SetType: model.SetTypeWorking, Weight: 315, Unit: units.Pound, Reps: 5},
+							{Line: 4, SetNumber: 2, SetType: model.SetTypeWorking, Weight: 315, Unit: units.Pound, Reps: 5, Notes: "重い, けど引けた"},
+						}},
+						{Name: "Plank", Sets: []Set{
+							{Line: 3, SetNumber: 1, SetType: model.SetTypeWorking, Unit: units.Pound, DurationSeconds: 90},
+						}},
+					}, sessions[0].Exercises)
+				}
+			},
+		},
+		{
+			testCase: "エラー(形式を判別できない)",
+			csv:      "foo,bar\n1,2\n",
+			assertion: func(format string, sessions []Session, err error) {
+				assert.True(t, errors.Is(err, ErrUnknownFormat))
+			},
+		},
+		{
+			testCase: "エラー(指定の形式の列がない)",
+			csv:      "Date,Exercise,Category\n2023-01-15,Deadlift,Back\n",
+			format:   FormatStrong,
+			assertion: func(format string, sessions []Session, err error) {
+				assert.True(t, errors.Is(err, ErrUnknownFormat))
+			},
+		},
+		{
+			testCase: "エラー(数値として読み取れない行)",
+			csv:      "Date,Exercise,Category,Weight (kgs),Reps\n2023-01-15,Deadlift,Back,100,5\n2023-01-16,Deadlift,Back,abc,5\n",
+			assertion: func(format string, sessions []Session, err error) {
+				assert.EqualError(t, err, `line 3: invalid number "abc"`)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			tt.assertion(Parse(strings.NewReader(tt.csv), tt.format, tt.unit))
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, FormatStrong, Detect([]string{"Date", "Workout Name", "Exercise Name", "Set Order", "Weight", "Reps"}))
+	assert.Equal(t, FormatHevy, Detect([]string{"title", "start_time", "exercise_title", "reps"}))
+	assert.Equal(t, FormatFitNotes, Detect([]string{"Date", "Exercise", "Category", "Reps"}))
+	assert.Equal(t, "", Detect([]string{"Date", "Exercise"}))
+}
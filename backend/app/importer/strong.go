@@ -0,0 +1,101 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+)
+
+// strongSetTypes Strongの"Set Order"のうち、番号以外で書き出されるセットの種類
+var strongSetTypes = map[string]string{
+	"w": model.SetTypeWarmup,
+	"d": model.SetTypeDrop,
+	"f": model.SetTypeFailure,
+}
+
+// parseStrong Strongの1行を読み取る
+// 日時とワークアウト名が同じ行を1つのセッションとし、休憩タイマーの行は読み飛ばす
+func parseStrong(cols columns, record []string, unit units.Unit) (row, bool, error) {
+	order := strings.ToLower(cols.get(record, "set order"))
+	if order == "rest timer" {
+		return row{}, false, nil
+	}
+
+	date := cols.get(record, "date")
+	startedAt, err := parseTime(date, "2006-01-02 15:04:05", "2006-01-02 15:04", time.RFC3339, "2006-01-02")
+	if err != nil {
+		return row{}, false, err
+	}
+
+	set := Set{SetType: model.SetTypeWorking, Notes: cols.get(record, "notes")}
+	if setType, ok := strongSetTypes[order]; ok {
+		set.SetType = setType
+	}
+	if set.Unit, err = weightUnit(cols.get(record, "weight unit"), unit); err != nil {
+		return row{}, false, err
+	}
+	if set.Weight, err = parseFloat(cols.get(record, "weight")); err != nil {
+		return row{}, false, err
+	}
+	if set.Reps, err = parseInt(cols.get(record, "reps")); err != nil {
+		return row{}, false, err
+	}
+	if set.DurationSeconds, err = parseInt(cols.get(record, cols.first("seconds", "duration_seconds"))); err != nil {
+		return row{}, false, err
+	}
+	if set.RPE, err = parseFloat(cols.get(record, "rpe")); err != nil {
+		return row{}, false, err
+	}
+	distance, err := parseFloat(cols.get(record, "distance"))
+	if err != nil {
+		return row{}, false, err
+	}
+	if distance != 0 {
+		distanceUnit := cols.get(record, "distance unit")
+		if distanceUnit == "" {
+			distanceUnit = defaultDistanceUnit(set.Unit)
+		}
+		if set.DistanceMeters, err = distanceMeters(distance, distanceUnit); err != nil {
+			return row{}, false, err
+		}
+	}
+	if isEmptySet(set) {
+		return row{}, false, nil
+	}
+
+	r := row{
+		sessionKey:   date + "\x00" + cols.get(record, "workout name"),
+		date:         trainingDate(startedAt),
+		startedAt:    startedAt,
+		sessionName:  cols.get(record, "workout name"),
+		sessionNotes: cols.get(record, "workout notes"),
+		exercise:     cols.get(record, "exercise name"),
+		set:          set,
+	}
+	duration, err := strongDuration(cols.get(record, cols.first("duration", "duration (sec)")))
+	if err != nil {
+		return row{}, false, err
+	}
+	if duration > 0 && len(date) > len("2006-01-02") {
+		r.finishedAt = startedAt.Add(duration)
+	}
+	return r, true, nil
+}
+
+// strongDuration "1h 5m"のようなワークアウトの所要時間を読み取る。単位のない数値は秒とする
+func strongDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if seconds, err := parseInt(s); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	d, err := time.ParseDuration(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,86 @@
+package llm
+
+import (
+	"context"
+	"sync"
+)
+
+// FakeMenu Fakeが既定で返すトレーニングメニュー
+const FakeMenu = `{"title":"全身の基本メニュー","estimated_minutes":15,"exercises":[` +
+	`{"name":"スクワット","sets":3,"reps":10,"rest_seconds":90,"estimated_minutes":8,"notes":"10回で余裕が2回残る重量"},` +
+	`{"name":"腕立て伏せ","sets":3,"reps":10,"rest_seconds":60,"estimated_minutes":7,"notes":"胸を床に近づける"}],` +
+	`"notes":"最後に軽くストレッチ"}`
+
+// fakeChunkRunes Streamで1回に渡す文字数
+const fakeChunkRunes = 16
+
+// Fake 決まった応答を返すプロバイダ。ネットワークを使わずに動作を確認するため
+// Responsesを呼び出された順に返し、尽きた後は最後の応答を繰り返す。Responsesがない場合はFakeMenuを返す
+// Errを指定した場合は常にErrを返す。受け取った依頼はRequestsに記録する
+type Fake struct {
+	Responses []string
+	Err       error
+
+	mu       sync.Mutex
+	requests []Request
+}
+
+func NewFake(responses ...string) *Fake {
+	return &Fake{Responses: responses}
+}
+
+// Complete 次の決まった応答を返却
+func (p *Fake) Complete(ctx context.Context, request Request) (string, error) {
+	return p.next(ctx, request)
+}
+
+// Stream 次の決まった応答を一定の文字数ずつonDeltaに渡し、全体を返却
+func (p *Fake) Stream(ctx context.Context, request Request, onDelta func(delta string) error) (string, error) {
+	content, err := p.next(ctx, request)
+	if err != nil {
+		return "", err
+	}
+	runes := []rune(content)
+	for i := 0; i < len(runes); i += fakeChunkRunes {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		end := i + fakeChunkRunes
+		if end > len(runes) {
+			end = len(runes)
+		}
+		if err := onDelta(string(runes[i:end])); err != nil {
+			return "", err
+		}
+	}
+	return content, nil
+}
+
+// next 依頼を記録し、次の決まった応答を返却
+func (p *Fake) next(ctx context.Context, request Request) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, request)
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if p.Err != nil {
+		return "", p.Err
+	}
+	if len(p.Responses) == 0 {
+		return FakeMenu, nil
+	}
+	i := len(p.requests) - 1
+	if i >= len(p.Responses) {
+		i = len(p.Responses) - 1
+	}
+	return p.Responses[i], nil
+}
+
+// Requests 受け取った依頼を順に返却
+func (p *Fake) Requests() []Request {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Request(nil), p.requests...)
+}
@@ -0,0 +1,129 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// 利用できるプロバイダ
+const (
+	// ProviderOpenAI OpenAIのAPI、またはBaseURLで指定したOpenAI互換のサーバー
+	ProviderOpenAI = "openai"
+	// ProviderFake 決まった応答を返す。ネットワークを使わずに動作を確認するため
+	ProviderFake = "fake"
+)
+
+var Providers = []string{ProviderOpenAI, ProviderFake}
+
+// メッセージの送り手
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// 設定がない場合の値
+const (
+	DefaultModel       = "gpt-3.5-turbo"
+	DefaultTimeout     = 60 * time.Second
+	DefaultMaxTokens   = 1200
+	DefaultTemperature = 0.7
+)
+
+type (
+	// Provider 会話の続きを生成する
+	// Streamは生成された差分を順にonDeltaへ渡し、生成した全体を返却する。onDeltaがエラーを返した場合は生成を中断する
+	Provider interface {
+		Complete(ctx context.Context, request Request) (string, error)
+		Stream(ctx context.Context, request Request, onDelta func(delta string) error) (string, error)
+	}
+
+	// Message 会話の1件のメッセージを表す
+	Message struct {
+		Role    string
+		Content string
+	}
+
+	// Request 生成の依頼を表す。JSONがtrueの場合はJSONオブジェクトのみで応答させる
+	Request struct {
+		Messages []Message
+		JSON     bool
+	}
+
+	// Config プロバイダの設定を表す
+	// BaseURLを指定するとOpenAI互換のローカルのサーバー等に接続する
+	Config struct {
+		Provider    string
+		APIKey      string
+		Model       string
+		BaseURL     string
+		Timeout     time.Duration
+		MaxTokens   int
+		Temperature float32
+	}
+)
+
+// New 設定に合うプロバイダを返却
+func New(config Config) (Provider, error) {
+	switch config.Provider {
+	case ProviderOpenAI, "":
+		return NewOpenAI(config), nil
+	case ProviderFake:
+		return NewFake(), nil
+	}
+	return nil, fmt.Errorf("unknown llm provider %q", config.Provider)
+}
+
+// ConfigFromEnv 環境変数から設定を読み込み
+func ConfigFromEnv() (Config, error) {
+	return LoadConfig(os.Getenv)
+}
+
+// LoadConfig getenvで読み込んだ値から設定を作成。指定がない項目は既定の値とする
+// APIキーはLLM_API_KEYがなければOPENAI_API_KEYを使う
+func LoadConfig(getenv func(key string) string) (Config, error) {
+	config := Config{
+		Provider:    getenv("LLM_PROVIDER"),
+		APIKey:      getenv("LLM_API_KEY"),
+		Model:       getenv("LLM_MODEL"),
+		BaseURL:     getenv("LLM_BASE_URL"),
+		Timeout:     DefaultTimeout,
+		MaxTokens:   DefaultMaxTokens,
+		Temperature: DefaultTemperature,
+	}
+	if config.Provider == "" {
+		config.Provider = ProviderOpenAI
+	}
+	if config.APIKey == "" {
+		config.APIKey = getenv("OPENAI_API_KEY")
+	}
+	if config.Model == "" {
+		config.Model = DefaultModel
+	}
+
+	if s := getenv("LLM_TIMEOUT"); s != "" {
+		timeout, err := time.ParseDuration(s)
+		if err != nil || timeout <= 0 {
+			return Config{}, fmt.Errorf("invalid LLM_TIMEOUT %q", s)
+		}
+		config.Timeout = timeout
+	}
+	if s := getenv("LLM_MAX_TOKENS"); s != "" {
+		maxTokens, err := strconv.Atoi(s)
+		if err != nil || maxTokens <= 0 {
+			return Config{}, fmt.Errorf("invalid LLM_MAX_TOKENS %q", s)
+		}
+		config.MaxTokens = maxTokens
+	}
+	if s := getenv("LLM_TEMPERATURE"); s != "" {
+		temperature, err := strconv.ParseFloat(s, 32)
+		if err != nil || temperature < 0 || temperature > 2 {
+			return Config{}, fmt.Errorf("invalid LLM_TEMPERATURE %q", s)
+		}
+		config.Temperature = float32(temperature)
+	}
+	return config, nil
+}
@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		testCase  string
+		env       map[string]string
+		assertion func(config Config, err error)
+	}{
+		{
+			testCase: "正常系(指定がない項目は既定の値)",
+			env:      map[string]string{"OPENAI_API_KEY": "sk-test"},
+			assertion: func(config Config, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, Config{
+					Provider:    ProviderOpenAI,
+					APIKey:      "sk-test",
+					Model:       DefaultModel,
+					Timeout:     DefaultTimeout,
+					MaxTokens:   DefaultMaxTokens,
+					Temperature: DefaultTemperature,
+				}, config)
+			},
+		},
+		{
+			testCase: "正常系(すべて指定)",
+			env: map[string]string{
+				"LLM_PROVIDER":    ProviderOpenAI,
+				"LLM_API_KEY":     "local",
+				"OPENAI_API_KEY":  "sk-test",
+				"LLM_MODEL":       "llama3",
+				"LLM_BASE_URL":    "http://localhost:11434/v1",
+				"LLM_TIMEOUT":     "2m",
+				"LLM_MAX_TOKENS":  "2000",
+				"LLM_TEMPERATURE": "0",
+			},
+			assertion: func(config Config, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, Config{
+					Provider:    ProviderOpenAI,
+					APIKey:      "local",
+					Model:       "llama3",
+					BaseURL:     "http://localhost:11434/v1",
+					Timeout:     2 * time.Minute,
+					MaxTokens:   2000,
+					Temperature: 0,
+				}, config)
+			},
+		},
+		{
+			testCase: "エラー(タイムアウトが不正)",
+			env:      map[string]string{"LLM_TIMEOUT": "30"},
+			assertion: func(config Config, err error) {
+				assert.EqualError(t, err, `invalid LLM_TIMEOUT "30"`)
+			},
+		},
+		{
+			testCase: "エラー(トークン数が不正)",
+			env:      map[string]string{"LLM_MAX_TOKENS": "0"},
+			assertion: func(config Config, err error) {
+				assert.EqualError(t, err, `invalid LLM_MAX_TOKENS "0"`)
+			},
+		},
+		{
+			testCase: "エラー(温度が範囲外)",
+			env:      map[string]string{"LLM_TEMPERATURE": "3"},
+			assertion: func(config Config, err error) {
+				assert.EqualError(t, err, `invalid LLM_TEMPERATURE "3"`)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			tt.assertion(LoadConfig(func(key string) string { return tt.env[key] }))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	p, err := New(Config{Provider: ProviderFake})
+	assert.NoError(t, err)
+	assert.IsType(t, &Fake{}, p)
+
+	p, err = New(Config{Provider: ProviderOpenAI, Model: DefaultModel})
+	assert.NoError(t, err)
+	assert.IsType(t, &OpenAI{}, p)
+
+	_, err = New(Config{Provider: "unknown"})
+	assert.EqualError(t, err, `unknown llm provider "unknown"`)
+}
@@ -0,0 +1,107 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// OpenAI OpenAIのAPI、またはOpenAI互換のサーバーで生成するプロバイダ
+type OpenAI struct {
+	client      *openai.Client
+	model       string
+	maxTokens   int
+	temperature float32
+}
+
+func NewOpenAI(config Config) *OpenAI {
+	clientConfig := openai.DefaultConfig(config.APIKey)
+	if config.BaseURL != "" {
+		clientConfig.BaseURL = config.BaseURL
+	}
+	clientConfig.HTTPClient = &http.Client{Timeout: config.Timeout}
+
+	temperature := config.Temperature
+	if temperature == 0 {
+		// 0は未指定として送られずサーバーの既定値になるため、0に最も近い値を送る
+		temperature = math.SmallestNonzeroFloat32
+	}
+	return &OpenAI{
+		client:      openai.NewClientWithConfig(clientConfig),
+		model:       config.Model,
+		maxTokens:   config.MaxTokens,
+		temperature: temperature,
+	}
+}
+
+// Complete 会話の続きを生成し、その本文を返却
+func (p *OpenAI) Complete(ctx context.Context, request Request) (string, error) {
+	resp, err := p.client.CreateChatCompletion(ctx, p.chatRequest(request))
+	if err != nil {
+		return "", fmt.Errorf("couldn't create chat completion: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no choices in chat completion")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// Stream 会話の続きを生成しながら差分をonDeltaに渡し、生成した全体を返却
+// ctxがキャンセルされた場合、またはonDeltaがエラーを返した場合はサーバーへのリクエストを中断する
+// タイムアウトは生成の完了までにかかる時間に対してかかる
+func (p *OpenAI) Stream(ctx context.Context, request Request, onDelta func(delta string) error) (string, error) {
+	stream, err := p.client.CreateChatCompletionStream(ctx, p.chatRequest(request))
+	if err != nil {
+		return "", fmt.Errorf("couldn't create chat completion stream: %w", err)
+	}
+	defer stream.Close()
+
+	var content strings.Builder
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("couldn't receive chat completion stream: %w", err)
+		}
+		if len(resp.Choices) == 0 || resp.Choices[0].Delta.Content == "" {
+			continue
+		}
+		delta := resp.Choices[0].Delta.Content
+		content.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return "", err
+		}
+	}
+	if content.Len() == 0 {
+		return "", fmt.Errorf("no content in chat completion stream")
+	}
+	return content.String(), nil
+}
+
+// chatRequest 依頼を設定のモデル・トークン数・温度のリクエストに変換
+func (p *OpenAI) chatRequest(request Request) openai.ChatCompletionRequest {
+	messages := make([]openai.ChatCompletionMessage, 0, len(request.Messages))
+	for _, m := range request.Messages {
+		messages = append(messages, openai.ChatCompletionMessage{Role: m.Role, Content: m.Content})
+	}
+	chatRequest := openai.ChatCompletionRequest{
+		Model:       p.model,
+		Messages:    messages,
+		MaxTokens:   p.maxTokens,
+		Temperature: p.temperature,
+	}
+	if request.JSON {
+		chatRequest.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		}
+	}
+	return chatRequest
+}
@@ -0,0 +1,204 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAIComplete(t *testing.T) {
+	t.Parallel()
+	messages := []Message{{Role: RoleSystem, Content: "JSONで返す"}, {Role: RoleUser, Content: "メニューを提案"}}
+	tests := []struct {
+		testCase string
+		request  Request
+		// handler スタブのサーバーの応答。受け取ったリクエストの本文を渡す
+		handler   func(w http.ResponseWriter, body map[string]interface{})
+		timeout   time.Duration
+		assertion func(content string, err error)
+	}{
+		{
+			testCase: "正常系",
+			request:  Request{Messages: messages, JSON: true},
+			handler: func(w http.ResponseWriter, body map[string]interface{}) {
+				assert.Equal(t, "local-model", body["model"])
+				assert.Equal(t, float64(300), body["max_tokens"])
+				assert.Equal(t, map[string]interface{}{"type": "json_object"}, body["response_format"])
+				assert.Len(t, body["messages"], 2)
+				writeChoices(w, `{"title":"胸の日"}`)
+			},
+			assertion: func(content string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, `{"title":"胸の日"}`, content)
+			},
+		},
+		{
+			testCase: "正常系(JSONを指定しない場合は応答の形式を送らない)",
+			request:  Request{Messages: messages},
+			handler: func(w http.ResponseWriter, body map[string]interface{}) {
+				assert.NotContains(t, body, "response_format")
+				writeChoices(w, "メニュー")
+			},
+			assertion: func(content string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "メニュー", content)
+			},
+		},
+		{
+			testCase: "エラー(サーバーがエラーを返す)",
+			request:  Request{Messages: messages},
+			handler: func(w http.ResponseWriter, body map[string]interface{}) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"error":{"message":"rate limited","type":"requests"}}`))
+			},
+			assertion: func(content string, err error) {
+				assert.ErrorContains(t, err, "rate limited")
+			},
+		},
+		{
+			testCase: "エラー(応答がない)",
+			request:  Request{Messages: messages},
+			handler: func(w http.ResponseWriter, body map[string]interface{}) {
+				writeChoices(w)
+			},
+			assertion: func(content string, err error) {
+				assert.EqualError(t, err, "no choices in chat completion")
+			},
+		},
+		{
+			testCase: "エラー(タイムアウト)",
+			request:  Request{Messages: messages},
+			timeout:  50 * time.Millisecond,
+			handler: func(w http.ResponseWriter, body map[string]interface{}) {
+				time.Sleep(200 * time.Millisecond)
+				writeChoices(w, "遅い応答")
+			},
+			assertion: func(content string, err error) {
+				assert.Error(t, err)
+				assert.Empty(t, content)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/chat/completions", r.URL.Path)
+				assert.Equal(t, "Bearer local", r.Header.Get("Authorization"))
+				body := map[string]interface{}{}
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				tt.handler(w, body)
+			}))
+			defer server.Close()
+
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = time.Second
+			}
+			p := NewOpenAI(Config{APIKey: "local", Model: "local-model", BaseURL: server.URL, Timeout: timeout, MaxTokens: 300, Temperature: 0.7})
+			tt.assertion(p.Complete(context.Background(), tt.request))
+		})
+	}
+}
+
+// writeChoices contentsを応答の候補とするOpenAI互換の応答を書き込む
+func writeChoices(w http.ResponseWriter, contents ...string) {
+	choices := []map[string]interface{}{}
+	for i, content := range contents {
+		choices = append(choices, map[string]interface{}{
+			"index":   i,
+			"message": map[string]string{"role": RoleAssistant, "content": content},
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"choices": choices})
+}
+
+func TestOpenAIStream(t *testing.T) {
+	t.Parallel()
+	messages := []Message{{Role: RoleUser, Content: "メニューを提案"}}
+
+	t.Run("正常系", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := map[string]interface{}{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, true, body["stream"])
+			assert.Equal(t, map[string]interface{}{"type": "json_object"}, body["response_format"])
+			writeStreamChunks(w, `{"title":`, "", `"胸の日"}`)
+		}))
+		defer server.Close()
+
+		var deltas []string
+		p := NewOpenAI(Config{Model: "local-model", BaseURL: server.URL, Timeout: time.Second})
+		content, err := p.Stream(context.Background(), Request{Messages: messages, JSON: true}, func(delta string) error {
+			deltas = append(deltas, delta)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"title":"胸の日"}`, content)
+		// 空の差分は渡さない
+		assert.Equal(t, []string{`{"title":`, `"胸の日"}`}, deltas)
+	})
+
+	t.Run("エラー(サーバーがエラーを返す)", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":{"message":"overloaded","type":"server_error"}}`))
+		}))
+		defer server.Close()
+
+		p := NewOpenAI(Config{Model: "local-model", BaseURL: server.URL, Timeout: time.Second})
+		_, err := p.Stream(context.Background(), Request{Messages: messages}, func(delta string) error { return nil })
+		assert.ErrorContains(t, err, "overloaded")
+	})
+
+	t.Run("エラー(差分の受け取りを中断するとサーバーへのリクエストを切る)", func(t *testing.T) {
+		t.Parallel()
+		canceled := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeStreamChunks(w, "途中まで")
+			// 残りを送る前にクライアントが切断するまで待つ
+			select {
+			case <-r.Context().Done():
+				close(canceled)
+			case <-time.After(time.Second):
+			}
+		}))
+		defer server.Close()
+
+		stop := errors.New("client disconnected")
+		p := NewOpenAI(Config{Model: "local-model", BaseURL: server.URL, Timeout: 5 * time.Second})
+		_, err := p.Stream(context.Background(), Request{Messages: messages}, func(delta string) error { return stop })
+		assert.ErrorIs(t, err, stop)
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Error("upstream request was not canceled")
+		}
+	})
+}
+
+// writeStreamChunks deltasを1件ずつ差分とするOpenAI互換のストリームの応答を書き込む。終了の印は送らず、ハンドラを抜けた時点で応答を閉じる
+func writeStreamChunks(w http.ResponseWriter, deltas ...string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	for _, delta := range deltas {
+		b, _ := json.Marshal(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"index": 0, "delta": map[string]string{"content": delta}},
+			},
+		})
+		_, _ = w.Write([]byte("data: " + string(b) + "\n\n"))
+	}
+	w.(http.Flusher).Flush()
+}
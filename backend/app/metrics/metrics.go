@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+)
+
+// Formula 推定1RMの計算式
+type Formula string
+
+const (
+	// Epley weight * (1 + reps / 30)
+	Epley Formula = "epley"
+	// Brzycki weight * 36 / (37 - reps)。37回以上は計算できない
+	Brzycki Formula = "brzycki"
+	// Lombardi weight * reps ^ 0.10
+	Lombardi Formula = "lombardi"
+
+	// DefaultFormula 指定がない場合の計算式
+	DefaultFormula = Epley
+)
+
+// ParseFormula 文字列から計算式を取得。空文字の場合は既定の計算式を返却
+func ParseFormula(s string) (Formula, error) {
+	switch f := Formula(s); f {
+	case "":
+		return DefaultFormula, nil
+	case Epley, Brzycki, Lombardi:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown formula %q", s)
+	}
+}
+
+// EstimateOneRepMax 重量と回数から推定1RMを求める。計算できない場合は0を返却
+func EstimateOneRepMax(formula Formula, weight float64, reps int64) float64 {
+	if weight <= 0 || reps <= 0 {
+		return 0
+	}
+	if reps == 1 {
+		return weight
+	}
+
+	var e1rm float64
+	switch formula {
+	case Brzycki:
+		if reps >= 37 {
+			return 0
+		}
+		e1rm = weight * 36 / float64(37-reps)
+	case Lombardi:
+		e1rm = weight * math.Pow(float64(reps), 0.10)
+	default:
+		e1rm = weight * (1 + float64(reps)/30)
+	}
+	return round(e1rm)
+}
+
+// Volume 重量と回数からボリューム(トン数)を求める
+func Volume(weight float64, reps int64) float64 {
+	if weight <= 0 || reps <= 0 {
+		return 0
+	}
+	return round(weight * float64(reps))
+}
+
+// Pace 時間と距離から1kmあたりの秒数を求める。計算できない場合は0を返却
+func Pace(durationSeconds int64, distanceMeters float64) float64 {
+	if durationSeconds <= 0 || distanceMeters <= 0 {
+		return 0
+	}
+	return round(float64(durationSeconds) / (distanceMeters / 1000))
+}
+
+// round 小数点以下2桁に丸める
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateOneRepMax(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		testCase string
+		formula  Formula
+		weight   float64
+		reps     int64
+		want     float64
+	}{
+		{testCase: "Epley", formula: Epley, weight: 100, reps: 5, want: 116.67},
+		{testCase: "Brzycki", formula: Brzycki, weight: 100, reps: 5, want: 112.5},
+		{testCase: "Lombardi", formula: Lombardi, weight: 100, reps: 5, want: 117.46},
+		{testCase: "1回はそのままの重量", formula: Brzycki, weight: 100, reps: 1, want: 100},
+		{testCase: "Brzyckiは37回以上を計算しない", formula: Brzycki, weight: 20, reps: 40, want: 0},
+		{testCase: "回数が0", formula: Epley, weight: 100, reps: 0, want: 0},
+		{testCase: "自重", formula: Epley, weight: 0, reps: 10, want: 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, EstimateOneRepMax(tt.formula, tt.weight, tt.reps))
+		})
+	}
+}
+
+func TestParseFormula(t *testing.T) {
+	t.Parallel()
+	f, err := ParseFormula("")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultFormula, f)
+
+	f, err = ParseFormula("lombardi")
+	assert.NoError(t, err)
+	assert.Equal(t, Lombardi, f)
+
+	_, err = ParseFormula("mayhew")
+	assert.Error(t, err)
+}
+
+func TestVolume(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, float64(600), Volume(60, 10))
+	assert.Equal(t, float64(0), Volume(0, 10))
+}
+
+func TestPace(t *testing.T) {
+	t.Parallel()
+	// 5kmを25分で走った場合は1kmあたり300秒
+	assert.Equal(t, float64(300), Pace(1500, 5000))
+	assert.Equal(t, float64(0), Pace(1500, 0))
+	assert.Equal(t, float64(0), Pace(0, 5000))
+}
@@ -6,23 +6,65 @@ import (
 	"github.com/pkg/errors"
 )
 
+// 種目の記録方法
+const (
+	// ModalityWeighted 重量x回数で記録するウェイト種目
+	ModalityWeighted = "weighted"
+	// ModalityBodyweight 回数で記録する自重種目。weightには加重を記録する
+	ModalityBodyweight = "bodyweight"
+	// ModalityAssisted 回数で記録するアシスト付きの自重種目。weightにはアシスト重量を記録する
+	ModalityAssisted = "assisted"
+	// ModalityTimed 時間で記録する種目。weightには加重を記録する
+	ModalityTimed = "timed"
+	// ModalityDistance 距離・時間で記録する有酸素種目
+	ModalityDistance = "distance"
+)
+
+// Modalities 種目の記録方法の一覧
+var Modalities = []string{ModalityWeighted, ModalityBodyweight, ModalityAssisted, ModalityTimed, ModalityDistance}
+
 type (
 	// Exercise ワークアウトのインターフェースを表す
 	Exercise interface {
 		LoadBySessionID(sessionId int64) (*Exercises, error)
+		LoadBySessionIDs(sessionIds []int64) (*Exercises, error)
+		LoadOwnersByCatalogIDTx(tx dbr.SessionRunner, catalogId int64) (*ExerciseOwners, error)
 		Load(id int64) (*ExerciseImpl, error)
-		Update(attrs map[string]interface{}) (bool, error)
-		Create(sessionId int64, exerciseName string) (*ExerciseImpl, error)
+		Update(id int64, attrs map[string]interface{}) (bool, error)
+		UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error)
+		Create(sessionId int64, exerciseName string, catalogId int64, modality string) (*ExerciseImpl, error)
+		CreateTx(tx dbr.SessionRunner, sessionId int64, exerciseName string, catalogId int64, modality string) (*ExerciseImpl, error)
+		Delete(id int64) (bool, error)
+		DeleteTx(tx dbr.SessionRunner, id int64) (bool, error)
+		DeleteBySessionIDTx(tx dbr.SessionRunner, sessionId int64) (int64, error)
+		DetachCatalogTx(tx dbr.SessionRunner, catalogId int64) (int64, error)
 	}
 
 	// ExerciseImpl ワークアウトを表す
 	ExerciseImpl struct {
-		ID           int64  `db:"exercise_id" dbopt:"auto_increment"`
-		SessionID    int64  `db:"session_id"`
-		ExerciseName string `db:"exercise_name"`
+		ID           int64         `db:"exercise_id" dbopt:"auto_increment"`
+		SessionID    int64         `db:"session_id"`
+		CatalogID    dbr.NullInt64 `db:"catalog_id"`
+		ExerciseName string        `db:"exercise_name"`
+		Modality     string        `db:"modality"`
 	}
 
 	Exercises []ExerciseImpl
+
+	// ExerciseOwner カタログの種目を記録したユーザーと、そのエクササイズ名を表す
+	ExerciseOwner struct {
+		UserID       int64  `db:"user_id"`
+		ExerciseName string `db:"exercise_name"`
+	}
+
+	ExerciseOwners []ExerciseOwner
+
+	// ExerciseKey 記録を集計する種目の単位
+	// カタログに紐づく場合はカタログID、紐づかない場合はエクササイズ名で区別する
+	ExerciseKey struct {
+		CatalogID    int64
+		ExerciseName string
+	}
 )
 
 func NewExercises() *Exercises {
@@ -53,6 +95,95 @@ func (r *ExerciseImpl) LoadBySessionIDTx(tx *dbr.Session, sessionId int64) (*Exe
 	return m, nil
 }
 
+// LoadBySessionIDs 複数セッションのエクササイズを1クエリで読み込み
+func (r *ExerciseImpl) LoadBySessionIDs(sessionIds []int64) (*Exercises, error) {
+	return r.LoadBySessionIDsTx(db.GetSession("training_db"), sessionIds)
+}
+
+// LoadBySessionIDsTx トランザクション内で複数セッションのエクササイズを読み込み
+func (r *ExerciseImpl) LoadBySessionIDsTx(tx dbr.SessionRunner, sessionIds []int64) (*Exercises, error) {
+	m := NewExercises()
+	if len(sessionIds) == 0 {
+		return m, nil
+	}
+
+	if _, err := tx.Select("*").From("exercises").
+		Where("session_id IN ?", sessionIds).
+		OrderBy("session_id").
+		OrderBy("exercise_id").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load exercises")
+	}
+	return m, nil
+}
+
+// LoadOwnersByCatalogIDTx トランザクション内でカタログの種目を記録したユーザーとエクササイズ名を重複なく読み込み
+func (r *ExerciseImpl) LoadOwnersByCatalogIDTx(tx dbr.SessionRunner, catalogId int64) (*ExerciseOwners, error) {
+	m := &ExerciseOwners{}
+	if _, err := tx.Select("ws.user_id", "e.exercise_name").Distinct().
+		From(dbr.I("exercises").As("e")).
+		Join(dbr.I("workout_sessions").As("ws"), "ws.session_id = e.session_id").
+		Where("e.catalog_id = ?", catalogId).
+		OrderBy("ws.user_id").
+		OrderBy("e.exercise_name").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load exercise owners")
+	}
+	return m, nil
+}
+
+// Key 記録を集計する種目の単位を返却
+func (m *ExerciseImpl) Key() ExerciseKey {
+	if m.CatalogID.Valid {
+		return ExerciseKey{CatalogID: m.CatalogID.Int64}
+	}
+	return ExerciseKey{ExerciseName: m.ExerciseName}
+}
+
+// NormalizeModality 記録方法が未指定の場合はウェイト種目とする
+func NormalizeModality(modality string) string {
+	if modality == "" {
+		return ModalityWeighted
+	}
+	return modality
+}
+
+// CountsLoadVolume 重量x回数をボリュームとして集計する記録方法か
+// 自重種目は加重分のみを計上し、アシスト重量は負荷を軽くするため計上しない
+func CountsLoadVolume(modality string) bool {
+	switch NormalizeModality(modality) {
+	case ModalityWeighted, ModalityBodyweight:
+		return true
+	}
+	return false
+}
+
+// EstimatesOneRepMax 推定1RMや重量の自己ベストを求める記録方法か
+func EstimatesOneRepMax(modality string) bool {
+	return NormalizeModality(modality) == ModalityWeighted
+}
+
+// IDs エクササイズIDの一覧を返却
+func (e *Exercises) IDs() []int64 {
+	ids := make([]int64, 0, len(*e))
+	for _, exercise := range *e {
+		ids = append(ids, exercise.ID)
+	}
+	return ids
+}
+
+// GroupBySessionID セッションIDごとにエクササイズをまとめる
+func (e *Exercises) GroupBySessionID() map[int64]*Exercises {
+	grouped := make(map[int64]*Exercises)
+	for _, exercise := range *e {
+		if _, ok := grouped[exercise.SessionID]; !ok {
+			grouped[exercise.SessionID] = NewExercises()
+		}
+		*grouped[exercise.SessionID] = append(*grouped[exercise.SessionID], exercise)
+	}
+	return grouped
+}
+
 // Load 指定のIDを読み込み
 func (m *ExerciseImpl) Load(id int64) (*ExerciseImpl, error) {
 	return m.LoadTx(db.GetSession("training_db"), id)
@@ -61,21 +192,22 @@ func (m *ExerciseImpl) Load(id int64) (*ExerciseImpl, error) {
 
 // LoadTx トランザクション内で指定のIDを読み込み
 func (m *ExerciseImpl) LoadTx(tx dbr.SessionRunner, id int64) (*ExerciseImpl, error) {
-	if _, err := tx.Select("*").From("exercises").Where("exercise_id=?", id).Load(m); err != nil {
+	r := &ExerciseImpl{}
+	if _, err := tx.Select("*").From("exercises").Where("exercise_id=?", id).Load(r); err != nil {
 		return nil, errors.Wrapf(err, "couldn't load exercises")
 	}
-	return m, nil
+	return r, nil
 }
 
 // Update 更新
-func (m *ExerciseImpl) Update(attrs map[string]interface{}) (bool, error) {
-	return m.UpdateTx(db.GetSession("training_db"), attrs)
+func (r *ExerciseImpl) Update(id int64, attrs map[string]interface{}) (bool, error) {
+	return r.UpdateTx(db.GetSession("training_db"), id, attrs)
 	// return false, nil
 }
 
 // UpdateTx トランザクション内で更新
-func (m *ExerciseImpl) UpdateTx(tx dbr.SessionRunner, attrs map[string]interface{}) (bool, error) {
-	res, err := tx.Update("exercises").SetMap(attrs).Where("exercise_id=?", m.ID).Exec()
+func (r *ExerciseImpl) UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error) {
+	res, err := tx.Update("exercises").SetMap(attrs).Where("exercise_id=?", id).Exec()
 	if err != nil {
 		return false, errors.Wrapf(err, "couldn't update exercises")
 	}
@@ -89,20 +221,25 @@ func (m *ExerciseImpl) UpdateTx(tx dbr.SessionRunner, attrs map[string]interface
 }
 
 // Create 作成
-func (r *ExerciseImpl) Create(sessionId int64, exerciseName string) (*ExerciseImpl, error) {
-	return r.CreateTx(db.GetSession("training_db"), sessionId, exerciseName)
+// catalogIdが0の場合はカタログに紐づけずに作成し、modalityが空の場合はウェイト種目とする
+func (r *ExerciseImpl) Create(sessionId int64, exerciseName string, catalogId int64, modality string) (*ExerciseImpl, error) {
+	return r.CreateTx(db.GetSession("training_db"), sessionId, exerciseName, catalogId, modality)
 	// return nil, nil
 }
 
 // CreateTx トランザクション内で作成
-func (r *ExerciseImpl) CreateTx(tx dbr.SessionRunner, sessionId int64, exerciseName string) (*ExerciseImpl, error) {
+func (r *ExerciseImpl) CreateTx(tx dbr.SessionRunner, sessionId int64, exerciseName string, catalogId int64, modality string) (*ExerciseImpl, error) {
 	m := &ExerciseImpl{
 		SessionID:    sessionId,
 		ExerciseName: exerciseName,
+		Modality:     NormalizeModality(modality),
+	}
+	if catalogId != 0 {
+		m.CatalogID = dbr.NewNullInt64(catalogId)
 	}
 
 	res, err := tx.InsertInto("exercises").
-		Columns("session_id", "exercise_name").
+		Columns("session_id", "catalog_id", "exercise_name", "modality").
 		Record(m).
 		Exec()
 
@@ -117,3 +254,53 @@ func (r *ExerciseImpl) CreateTx(tx dbr.SessionRunner, sessionId int64, exerciseN
 	m.ID = lastID
 	return m, nil
 }
+
+// Delete 削除
+func (r *ExerciseImpl) Delete(id int64) (bool, error) {
+	return r.DeleteTx(db.GetSession("training_db"), id)
+}
+
+// DeleteTx トランザクション内で削除
+func (r *ExerciseImpl) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	res, err := tx.DeleteFrom("exercises").Where("exercise_id=?", id).Exec()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't delete exercises")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows == 1, nil
+}
+
+// DeleteBySessionIDTx トランザクション内でセッションに紐づくエクササイズを削除
+func (r *ExerciseImpl) DeleteBySessionIDTx(tx dbr.SessionRunner, sessionId int64) (int64, error) {
+	res, err := tx.DeleteFrom("exercises").Where("session_id=?", sessionId).Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't delete exercises")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
+
+// DetachCatalogTx トランザクション内で記録済みのエクササイズの名前を残したままカタログとの紐づけを外す
+func (r *ExerciseImpl) DetachCatalogTx(tx dbr.SessionRunner, catalogId int64) (int64, error) {
+	res, err := tx.Update("exercises").Set("catalog_id", nil).Where("catalog_id=?", catalogId).Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't detach exercises")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+type (
+	// ExerciseAlias 種目の別名のインターフェースを表す
+	ExerciseAlias interface {
+		LoadByCatalogIDs(catalogIds []int64) (*ExerciseAliases, error)
+		CreateTx(tx dbr.SessionRunner, catalogId int64, alias string, locale string) (*ExerciseAliasImpl, error)
+		DeleteByCatalogIDTx(tx dbr.SessionRunner, catalogId int64) (int64, error)
+	}
+
+	// ExerciseAliasImpl 種目の別名を表す
+	ExerciseAliasImpl struct {
+		ID        int64  `db:"alias_id" dbopt:"auto_increment"`
+		CatalogID int64  `db:"catalog_id"`
+		Alias     string `db:"alias"`
+		Locale    string `db:"locale"`
+	}
+
+	ExerciseAliases []ExerciseAliasImpl
+)
+
+func NewExerciseAliases() *ExerciseAliases {
+	return &ExerciseAliases{}
+}
+
+func NewExerciseAlias() ExerciseAlias {
+	return &ExerciseAliasImpl{}
+}
+
+// LoadByCatalogIDs 複数の種目の別名を1クエリで読み込み
+func (m *ExerciseAliasImpl) LoadByCatalogIDs(catalogIds []int64) (*ExerciseAliases, error) {
+	return m.LoadByCatalogIDsTx(db.GetSession("training_db"), catalogIds)
+}
+
+// LoadByCatalogIDsTx トランザクション内で複数の種目の別名を読み込み
+func (m *ExerciseAliasImpl) LoadByCatalogIDsTx(tx dbr.SessionRunner, catalogIds []int64) (*ExerciseAliases, error) {
+	r := NewExerciseAliases()
+	if len(catalogIds) == 0 {
+		return r, nil
+	}
+
+	if _, err := tx.Select("*").From("exercise_aliases").
+		Where("catalog_id IN ?", catalogIds).
+		OrderBy("catalog_id").
+		OrderBy("alias_id").
+		Load(r); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load exercise_aliases")
+	}
+	return r, nil
+}
+
+// CreateTx トランザクション内で作成
+func (m *ExerciseAliasImpl) CreateTx(tx dbr.SessionRunner, catalogId int64, alias string, locale string) (*ExerciseAliasImpl, error) {
+	r := &ExerciseAliasImpl{
+		CatalogID: catalogId,
+		Alias:     alias,
+		Locale:    locale,
+	}
+
+	res, err := tx.InsertInto("exercise_aliases").
+		Columns("catalog_id", "alias", "locale").
+		Record(r).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create exercise_aliases")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for exercise_aliases")
+	}
+	r.ID = lastID
+	return r, nil
+}
+
+// DeleteByCatalogIDTx トランザクション内で種目に紐づく別名を削除
+func (m *ExerciseAliasImpl) DeleteByCatalogIDTx(tx dbr.SessionRunner, catalogId int64) (int64, error) {
+	res, err := tx.DeleteFrom("exercise_aliases").Where("catalog_id=?", catalogId).Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't delete exercise_aliases")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
+
+// GroupByCatalogID 種目IDごとに別名をまとめる
+func (a *ExerciseAliases) GroupByCatalogID() map[int64]*ExerciseAliases {
+	grouped := make(map[int64]*ExerciseAliases)
+	for _, alias := range *a {
+		if _, ok := grouped[alias.CatalogID]; !ok {
+			grouped[alias.CatalogID] = NewExerciseAliases()
+		}
+		*grouped[alias.CatalogID] = append(*grouped[alias.CatalogID], alias)
+	}
+	return grouped
+}
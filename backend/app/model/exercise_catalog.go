@@ -0,0 +1,234 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+// MuscleGroups カタログで扱う部位の一覧
+var MuscleGroups = []string{
+	"chest", "back", "shoulders", "biceps", "triceps", "forearms", "traps",
+	"quads", "hamstrings", "glutes", "calves", "core", "cardio",
+}
+
+type (
+	// ExerciseCatalog 種目カタログのインターフェースを表す
+	ExerciseCatalog interface {
+		Search(filter ExerciseCatalogFilter) (*ExerciseCatalogs, error)
+		Load(id int64) (*ExerciseCatalogImpl, error)
+		LoadByIDs(ids []int64) (*ExerciseCatalogs, error)
+		Resolve(name string) (*ExerciseCatalogImpl, error)
+		Update(id int64, attrs map[string]interface{}) (bool, error)
+		UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error)
+		CreateTx(tx dbr.SessionRunner, nameJa string, nameEn string, primaryMuscle string, secondaryMuscles []string, equipment string, modality string) (*ExerciseCatalogImpl, error)
+		DeleteTx(tx dbr.SessionRunner, id int64) (bool, error)
+	}
+
+	// ExerciseCatalogImpl 種目カタログを表す
+	ExerciseCatalogImpl struct {
+		ID               int64  `db:"catalog_id" dbopt:"auto_increment"`
+		NameJa           string `db:"name_ja"`
+		NameEn           string `db:"name_en"`
+		PrimaryMuscle    string `db:"primary_muscle"`
+		SecondaryMuscles string `db:"secondary_muscles"`
+		Equipment        string `db:"equipment"`
+		Modality         string `db:"modality"`
+	}
+
+	ExerciseCatalogs []ExerciseCatalogImpl
+
+	// ExerciseCatalogFilter 種目カタログの検索条件
+	ExerciseCatalogFilter struct {
+		Query     string
+		Muscle    string
+		Equipment string
+	}
+)
+
+func NewExerciseCatalogs() *ExerciseCatalogs {
+	return &ExerciseCatalogs{}
+}
+
+func NewExerciseCatalog() ExerciseCatalog {
+	return &ExerciseCatalogImpl{}
+}
+
+// JoinMuscles 部位の一覧をカラムの形式に変換
+func JoinMuscles(muscles []string) string {
+	return strings.Join(muscles, ",")
+}
+
+// SecondaryMuscleList 補助的に使う部位の一覧を返却
+func (m *ExerciseCatalogImpl) SecondaryMuscleList() []string {
+	if m.SecondaryMuscles == "" {
+		return []string{}
+	}
+	return strings.Split(m.SecondaryMuscles, ",")
+}
+
+// Search 名前・別名の部分一致と部位・器具で検索
+func (m *ExerciseCatalogImpl) Search(filter ExerciseCatalogFilter) (*ExerciseCatalogs, error) {
+	return m.SearchTx(db.GetSession("training_db"), filter)
+}
+
+// SearchTx トランザクション内で検索
+func (m *ExerciseCatalogImpl) SearchTx(tx dbr.SessionRunner, filter ExerciseCatalogFilter) (*ExerciseCatalogs, error) {
+	r := NewExerciseCatalogs()
+
+	builder := tx.Select("c.*").Distinct().
+		From(dbr.I("exercise_catalog").As("c")).
+		LeftJoin(dbr.I("exercise_aliases").As("a"), "a.catalog_id = c.catalog_id")
+
+	if filter.Query != "" {
+		pattern := "%" + escapeLike(filter.Query) + "%"
+		builder = builder.Where("c.name_ja LIKE ? OR c.name_en LIKE ? OR a.alias LIKE ?", pattern, pattern, pattern)
+	}
+	if filter.Muscle != "" {
+		builder = builder.Where("c.primary_muscle = ? OR FIND_IN_SET(?, c.secondary_muscles) > 0", filter.Muscle, filter.Muscle)
+	}
+	if filter.Equipment != "" {
+		builder = builder.Where("c.equipment = ?", filter.Equipment)
+	}
+
+	if _, err := builder.OrderBy("c.catalog_id").Load(r); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load exercise_catalog")
+	}
+	return r, nil
+}
+
+// escapeLike LIKE句のワイルドカードをエスケープ
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Load 指定のIDを読み込み
+func (m *ExerciseCatalogImpl) Load(id int64) (*ExerciseCatalogImpl, error) {
+	return m.LoadTx(db.GetSession("training_db"), id)
+}
+
+// LoadTx トランザクション内で指定のIDを読み込み
+func (m *ExerciseCatalogImpl) LoadTx(tx dbr.SessionRunner, id int64) (*ExerciseCatalogImpl, error) {
+	r := &ExerciseCatalogImpl{}
+	if _, err := tx.Select("*").From("exercise_catalog").Where("catalog_id=?", id).Load(r); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load exercise_catalog")
+	}
+	return r, nil
+}
+
+// LoadByIDs 複数のIDをまとめて読み込み
+func (m *ExerciseCatalogImpl) LoadByIDs(ids []int64) (*ExerciseCatalogs, error) {
+	return m.LoadByIDsTx(db.GetSession("training_db"), ids)
+}
+
+// LoadByIDsTx トランザクション内で複数のIDをまとめて読み込み
+func (m *ExerciseCatalogImpl) LoadByIDsTx(tx dbr.SessionRunner, ids []int64) (*ExerciseCatalogs, error) {
+	r := NewExerciseCatalogs()
+	if len(ids) == 0 {
+		return r, nil
+	}
+
+	if _, err := tx.Select("*").From("exercise_catalog").Where("catalog_id IN ?", ids).OrderBy("catalog_id").Load(r); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load exercise_catalog")
+	}
+	return r, nil
+}
+
+// Resolve 日本語名・英語名・別名のいずれかに一致する種目を読み込み
+// 一致しない場合はIDが0のレコードを返却
+func (m *ExerciseCatalogImpl) Resolve(name string) (*ExerciseCatalogImpl, error) {
+	return m.ResolveTx(db.GetSession("training_db"), name)
+}
+
+// ResolveTx トランザクション内で名前から種目を読み込み
+func (m *ExerciseCatalogImpl) ResolveTx(tx dbr.SessionRunner, name string) (*ExerciseCatalogImpl, error) {
+	r := &ExerciseCatalogImpl{}
+	if _, err := tx.Select("c.*").
+		From(dbr.I("exercise_catalog").As("c")).
+		LeftJoin(dbr.I("exercise_aliases").As("a"), "a.catalog_id = c.catalog_id").
+		Where("c.name_ja = ? OR c.name_en = ? OR a.alias = ?", name, name, name).
+		OrderBy("c.catalog_id").
+		Limit(1).
+		Load(r); err != nil {
+		return nil, errors.Wrapf(err, "couldn't resolve exercise_catalog")
+	}
+	return r, nil
+}
+
+// Update 更新
+func (m *ExerciseCatalogImpl) Update(id int64, attrs map[string]interface{}) (bool, error) {
+	return m.UpdateTx(db.GetSession("training_db"), id, attrs)
+}
+
+// UpdateTx トランザクション内で更新
+func (m *ExerciseCatalogImpl) UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error) {
+	res, err := tx.Update("exercise_catalog").SetMap(attrs).Where("catalog_id=?", id).Exec()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't update exercise_catalog")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows == 1, nil
+}
+
+// CreateTx トランザクション内で作成。modalityが空の場合はウェイト種目とする
+func (m *ExerciseCatalogImpl) CreateTx(tx dbr.SessionRunner, nameJa string, nameEn string, primaryMuscle string, secondaryMuscles []string, equipment string, modality string) (*ExerciseCatalogImpl, error) {
+	r := &ExerciseCatalogImpl{
+		NameJa:           nameJa,
+		NameEn:           nameEn,
+		PrimaryMuscle:    primaryMuscle,
+		SecondaryMuscles: JoinMuscles(secondaryMuscles),
+		Equipment:        equipment,
+		Modality:         NormalizeModality(modality),
+	}
+
+	res, err := tx.InsertInto("exercise_catalog").
+		Columns("name_ja", "name_en", "primary_muscle", "secondary_muscles", "equipment", "modality").
+		Record(r).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create exercise_catalog")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for exercise_catalog")
+	}
+	r.ID = lastID
+	return r, nil
+}
+
+// DeleteTx トランザクション内で別名とあわせて削除
+// 種目を参照するエクササイズ・テンプレート等は、呼び出し側で先にカタログとの紐づけを外しておく
+func (m *ExerciseCatalogImpl) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	if _, err := tx.DeleteFrom("exercise_aliases").Where("catalog_id=?", id).Exec(); err != nil {
+		return false, errors.Wrapf(err, "couldn't delete exercise_aliases")
+	}
+
+	res, err := tx.DeleteFrom("exercise_catalog").Where("catalog_id=?", id).Exec()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't delete exercise_catalog")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows == 1, nil
+}
+
+// IDs カタログIDの一覧を返却
+func (c *ExerciseCatalogs) IDs() []int64 {
+	ids := make([]int64, 0, len(*c))
+	for _, catalog := range *c {
+		ids = append(ids, catalog.ID)
+	}
+	return ids
+}
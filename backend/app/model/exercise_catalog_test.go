@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExerciseCatalogResolve(t *testing.T) {
+	tests := []struct {
+		testCase string
+		name     string
+		want     int64
+	}{
+		{testCase: "日本語名で一致", name: "ベンチプレス", want: 1},
+		{testCase: "英語名で一致", name: "bench press", want: 1},
+		{testCase: "別名で一致", name: "OHP", want: 23},
+		{testCase: "一致しない", name: "存在しない種目", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testCase, func(t *testing.T) {
+			got, err := NewExerciseCatalog().Resolve(tt.name)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got.ID)
+			}
+		})
+	}
+}
+
+func TestExerciseCatalogSearch(t *testing.T) {
+	got, err := NewExerciseCatalog().Search(ExerciseCatalogFilter{Query: "スクワット", Muscle: "quads"})
+
+	if assert.NoError(t, err) {
+		assert.NotEmpty(t, *got)
+		for _, c := range *got {
+			assert.Equal(t, "quads", c.PrimaryMuscle)
+		}
+	}
+}
+
+func TestExerciseCatalogSecondaryMuscleList(t *testing.T) {
+	m := &ExerciseCatalogImpl{SecondaryMuscles: "triceps,shoulders"}
+	assert.Equal(t, []string{"triceps", "shoulders"}, m.SecondaryMuscleList())
+	assert.Equal(t, []string{}, (&ExerciseCatalogImpl{}).SecondaryMuscleList())
+}
@@ -54,7 +54,7 @@ import (
 // }
 
 func TestExerciseLoad(t *testing.T) {
-	e, err := NewExercise().Create(int64(23), "チェストプレス")
+	e, err := NewExercise().Create(int64(23), "チェストプレス", int64(0), "")
 	assert.NoError(t, err)
 
 	m, err := new(ExerciseImpl).Load(e.ID)
@@ -67,10 +67,10 @@ func TestExerciseLoad(t *testing.T) {
 }
 
 func TestExerciseUpdate(t *testing.T) {
-	e, err := NewExercise().Create(int64(23), "チェストプレス")
+	e, err := NewExercise().Create(int64(23), "チェストプレス", int64(0), "")
 	assert.NoError(t, err)
 
-	updated, err := e.Update(map[string]interface{}{"exercise_name": "ベンチプレス"})
+	updated, err := NewExercise().Update(e.ID, map[string]interface{}{"exercise_name": "ベンチプレス"})
 
 	if assert.NoError(t, err) {
 		assert.True(t, updated)
@@ -78,10 +78,23 @@ func TestExerciseUpdate(t *testing.T) {
 }
 
 func TestExerciseCreate(t *testing.T) {
-	e, err := NewExercise().Create(int64(22), "チェストプレス")
+	e, err := NewExercise().Create(int64(22), "チェストプレス", int64(0), "")
 
 	if assert.NoError(t, err) {
 		assert.Equal(t, int64(22), e.SessionID)
 		assert.Equal(t, "チェストプレス", e.ExerciseName)
+		// 記録方法の指定がない場合はウェイト種目とする
+		assert.Equal(t, ModalityWeighted, e.Modality)
+	}
+}
+
+func TestExerciseDelete(t *testing.T) {
+	e, err := NewExercise().Create(int64(23), "チェストプレス", int64(0), "")
+	assert.NoError(t, err)
+
+	deleted, err := NewExercise().Delete(e.ID)
+
+	if assert.NoError(t, err) {
+		assert.True(t, deleted)
 	}
 }
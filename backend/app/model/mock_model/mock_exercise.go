@@ -9,6 +9,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
 )
 
 // MockExercise is a mock of Exercise interface.
@@ -35,18 +36,93 @@ func (m *MockExercise) EXPECT() *MockExerciseMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockExercise) Create(sessionId int64, exerciseName string) (*model.ExerciseImpl, error) {
+func (m *MockExercise) Create(sessionId int64, exerciseName string, catalogId int64, modality string) (*model.ExerciseImpl, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", sessionId, exerciseName)
+	ret := m.ctrl.Call(m, "Create", sessionId, exerciseName, catalogId, modality)
 	ret0, _ := ret[0].(*model.ExerciseImpl)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockExerciseMockRecorder) Create(sessionId, exerciseName interface{}) *gomock.Call {
+func (mr *MockExerciseMockRecorder) Create(sessionId, exerciseName, catalogId, modality interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockExercise)(nil).Create), sessionId, exerciseName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockExercise)(nil).Create), sessionId, exerciseName, catalogId, modality)
+}
+
+// CreateTx mocks base method.
+func (m *MockExercise) CreateTx(tx dbr.SessionRunner, sessionId int64, exerciseName string, catalogId int64, modality string) (*model.ExerciseImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, sessionId, exerciseName, catalogId, modality)
+	ret0, _ := ret[0].(*model.ExerciseImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockExerciseMockRecorder) CreateTx(tx, sessionId, exerciseName, catalogId, modality interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockExercise)(nil).CreateTx), tx, sessionId, exerciseName, catalogId, modality)
+}
+
+// Delete mocks base method.
+func (m *MockExercise) Delete(id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockExerciseMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockExercise)(nil).Delete), id)
+}
+
+// DeleteBySessionIDTx mocks base method.
+func (m *MockExercise) DeleteBySessionIDTx(tx dbr.SessionRunner, sessionId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBySessionIDTx", tx, sessionId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBySessionIDTx indicates an expected call of DeleteBySessionIDTx.
+func (mr *MockExerciseMockRecorder) DeleteBySessionIDTx(tx, sessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBySessionIDTx", reflect.TypeOf((*MockExercise)(nil).DeleteBySessionIDTx), tx, sessionId)
+}
+
+// DeleteTx mocks base method.
+func (m *MockExercise) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTx", tx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTx indicates an expected call of DeleteTx.
+func (mr *MockExerciseMockRecorder) DeleteTx(tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTx", reflect.TypeOf((*MockExercise)(nil).DeleteTx), tx, id)
+}
+
+// DetachCatalogTx mocks base method.
+func (m *MockExercise) DetachCatalogTx(tx dbr.SessionRunner, catalogId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachCatalogTx", tx, catalogId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachCatalogTx indicates an expected call of DetachCatalogTx.
+func (mr *MockExerciseMockRecorder) DetachCatalogTx(tx, catalogId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachCatalogTx", reflect.TypeOf((*MockExercise)(nil).DetachCatalogTx), tx, catalogId)
 }
 
 // Load mocks base method.
@@ -79,17 +155,62 @@ func (mr *MockExerciseMockRecorder) LoadBySessionID(sessionId interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBySessionID", reflect.TypeOf((*MockExercise)(nil).LoadBySessionID), sessionId)
 }
 
+// LoadBySessionIDs mocks base method.
+func (m *MockExercise) LoadBySessionIDs(sessionIds []int64) (*model.Exercises, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadBySessionIDs", sessionIds)
+	ret0, _ := ret[0].(*model.Exercises)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadBySessionIDs indicates an expected call of LoadBySessionIDs.
+func (mr *MockExerciseMockRecorder) LoadBySessionIDs(sessionIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBySessionIDs", reflect.TypeOf((*MockExercise)(nil).LoadBySessionIDs), sessionIds)
+}
+
+// LoadOwnersByCatalogIDTx mocks base method.
+func (m *MockExercise) LoadOwnersByCatalogIDTx(tx dbr.SessionRunner, catalogId int64) (*model.ExerciseOwners, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOwnersByCatalogIDTx", tx, catalogId)
+	ret0, _ := ret[0].(*model.ExerciseOwners)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOwnersByCatalogIDTx indicates an expected call of LoadOwnersByCatalogIDTx.
+func (mr *MockExerciseMockRecorder) LoadOwnersByCatalogIDTx(tx, catalogId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOwnersByCatalogIDTx", reflect.TypeOf((*MockExercise)(nil).LoadOwnersByCatalogIDTx), tx, catalogId)
+}
+
 // Update mocks base method.
-func (m *MockExercise) Update(attrs map[string]interface{}) (bool, error) {
+func (m *MockExercise) Update(id int64, attrs map[string]interface{}) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", attrs)
+	ret := m.ctrl.Call(m, "Update", id, attrs)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockExerciseMockRecorder) Update(attrs interface{}) *gomock.Call {
+func (mr *MockExerciseMockRecorder) Update(id, attrs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockExercise)(nil).Update), id, attrs)
+}
+
+// UpdateTx mocks base method.
+func (m *MockExercise) UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTx", tx, id, attrs)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTx indicates an expected call of UpdateTx.
+func (mr *MockExerciseMockRecorder) UpdateTx(tx, id, attrs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockExercise)(nil).Update), attrs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTx", reflect.TypeOf((*MockExercise)(nil).UpdateTx), tx, id, attrs)
 }
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./backend/app/model/exercise_alias.go
+
+// Package mock_model is a generated GoMock package.
+package mock_model
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
+)
+
+// MockExerciseAlias is a mock of ExerciseAlias interface.
+type MockExerciseAlias struct {
+	ctrl     *gomock.Controller
+	recorder *MockExerciseAliasMockRecorder
+}
+
+// MockExerciseAliasMockRecorder is the mock recorder for MockExerciseAlias.
+type MockExerciseAliasMockRecorder struct {
+	mock *MockExerciseAlias
+}
+
+// NewMockExerciseAlias creates a new mock instance.
+func NewMockExerciseAlias(ctrl *gomock.Controller) *MockExerciseAlias {
+	mock := &MockExerciseAlias{ctrl: ctrl}
+	mock.recorder = &MockExerciseAliasMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExerciseAlias) EXPECT() *MockExerciseAliasMockRecorder {
+	return m.recorder
+}
+
+// CreateTx mocks base method.
+func (m *MockExerciseAlias) CreateTx(tx dbr.SessionRunner, catalogId int64, alias, locale string) (*model.ExerciseAliasImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, catalogId, alias, locale)
+	ret0, _ := ret[0].(*model.ExerciseAliasImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockExerciseAliasMockRecorder) CreateTx(tx, catalogId, alias, locale interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockExerciseAlias)(nil).CreateTx), tx, catalogId, alias, locale)
+}
+
+// DeleteByCatalogIDTx mocks base method.
+func (m *MockExerciseAlias) DeleteByCatalogIDTx(tx dbr.SessionRunner, catalogId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByCatalogIDTx", tx, catalogId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByCatalogIDTx indicates an expected call of DeleteByCatalogIDTx.
+func (mr *MockExerciseAliasMockRecorder) DeleteByCatalogIDTx(tx, catalogId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByCatalogIDTx", reflect.TypeOf((*MockExerciseAlias)(nil).DeleteByCatalogIDTx), tx, catalogId)
+}
+
+// LoadByCatalogIDs mocks base method.
+func (m *MockExerciseAlias) LoadByCatalogIDs(catalogIds []int64) (*model.ExerciseAliases, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByCatalogIDs", catalogIds)
+	ret0, _ := ret[0].(*model.ExerciseAliases)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByCatalogIDs indicates an expected call of LoadByCatalogIDs.
+func (mr *MockExerciseAliasMockRecorder) LoadByCatalogIDs(catalogIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByCatalogIDs", reflect.TypeOf((*MockExerciseAlias)(nil).LoadByCatalogIDs), catalogIds)
+}
@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./backend/app/model/exercise_catalog.go
+
+// Package mock_model is a generated GoMock package.
+package mock_model
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
+)
+
+// MockExerciseCatalog is a mock of ExerciseCatalog interface.
+type MockExerciseCatalog struct {
+	ctrl     *gomock.Controller
+	recorder *MockExerciseCatalogMockRecorder
+}
+
+// MockExerciseCatalogMockRecorder is the mock recorder for MockExerciseCatalog.
+type MockExerciseCatalogMockRecorder struct {
+	mock *MockExerciseCatalog
+}
+
+// NewMockExerciseCatalog creates a new mock instance.
+func NewMockExerciseCatalog(ctrl *gomock.Controller) *MockExerciseCatalog {
+	mock := &MockExerciseCatalog{ctrl: ctrl}
+	mock.recorder = &MockExerciseCatalogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExerciseCatalog) EXPECT() *MockExerciseCatalogMockRecorder {
+	return m.recorder
+}
+
+// CreateTx mocks base method.
+func (m *MockExerciseCatalog) CreateTx(tx dbr.SessionRunner, nameJa, nameEn, primaryMuscle string, secondaryMuscles []string, equipment, modality string) (*model.ExerciseCatalogImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, nameJa, nameEn, primaryMuscle, secondaryMuscles, equipment, modality)
+	ret0, _ := ret[0].(*model.ExerciseCatalogImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockExerciseCatalogMockRecorder) CreateTx(tx, nameJa, nameEn, primaryMuscle, secondaryMuscles, equipment, modality interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockExerciseCatalog)(nil).CreateTx), tx, nameJa, nameEn, primaryMuscle, secondaryMuscles, equipment, modality)
+}
+
+// DeleteTx mocks base method.
+func (m *MockExerciseCatalog) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTx", tx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTx indicates an expected call of DeleteTx.
+func (mr *MockExerciseCatalogMockRecorder) DeleteTx(tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTx", reflect.TypeOf((*MockExerciseCatalog)(nil).DeleteTx), tx, id)
+}
+
+// Load mocks base method.
+func (m *MockExerciseCatalog) Load(id int64) (*model.ExerciseCatalogImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", id)
+	ret0, _ := ret[0].(*model.ExerciseCatalogImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockExerciseCatalogMockRecorder) Load(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockExerciseCatalog)(nil).Load), id)
+}
+
+// LoadByIDs mocks base method.
+func (m *MockExerciseCatalog) LoadByIDs(ids []int64) (*model.ExerciseCatalogs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByIDs", ids)
+	ret0, _ := ret[0].(*model.ExerciseCatalogs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByIDs indicates an expected call of LoadByIDs.
+func (mr *MockExerciseCatalogMockRecorder) LoadByIDs(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByIDs", reflect.TypeOf((*MockExerciseCatalog)(nil).LoadByIDs), ids)
+}
+
+// Resolve mocks base method.
+func (m *MockExerciseCatalog) Resolve(name string) (*model.ExerciseCatalogImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", name)
+	ret0, _ := ret[0].(*model.ExerciseCatalogImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockExerciseCatalogMockRecorder) Resolve(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockExerciseCatalog)(nil).Resolve), name)
+}
+
+// Search mocks base method.
+func (m *MockExerciseCatalog) Search(filter model.ExerciseCatalogFilter) (*model.ExerciseCatalogs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", filter)
+	ret0, _ := ret[0].(*model.ExerciseCatalogs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockExerciseCatalogMockRecorder) Search(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockExerciseCatalog)(nil).Search), filter)
+}
+
+// Update mocks base method.
+func (m *MockExerciseCatalog) Update(id int64, attrs map[string]interface{}) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", id, attrs)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockExerciseCatalogMockRecorder) Update(id, attrs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockExerciseCatalog)(nil).Update), id, attrs)
+}
+
+// UpdateTx mocks base method.
+func (m *MockExerciseCatalog) UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTx", tx, id, attrs)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTx indicates an expected call of UpdateTx.
+func (mr *MockExerciseCatalogMockRecorder) UpdateTx(tx, id, attrs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTx", reflect.TypeOf((*MockExerciseCatalog)(nil).UpdateTx), tx, id, attrs)
+}
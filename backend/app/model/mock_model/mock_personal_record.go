@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./backend/app/model/personal_record.go
+
+// Package mock_model is a generated GoMock package.
+package mock_model
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
+)
+
+// MockPersonalRecord is a mock of PersonalRecord interface.
+type MockPersonalRecord struct {
+	ctrl     *gomock.Controller
+	recorder *MockPersonalRecordMockRecorder
+}
+
+// MockPersonalRecordMockRecorder is the mock recorder for MockPersonalRecord.
+type MockPersonalRecordMockRecorder struct {
+	mock *MockPersonalRecord
+}
+
+// NewMockPersonalRecord creates a new mock instance.
+func NewMockPersonalRecord(ctrl *gomock.Controller) *MockPersonalRecord {
+	mock := &MockPersonalRecord{ctrl: ctrl}
+	mock.recorder = &MockPersonalRecordMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPersonalRecord) EXPECT() *MockPersonalRecordMockRecorder {
+	return m.recorder
+}
+
+// LoadByKey mocks base method.
+func (m *MockPersonalRecord) LoadByKey(userId int64, key model.ExerciseKey) (*model.PersonalRecords, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByKey", userId, key)
+	ret0, _ := ret[0].(*model.PersonalRecords)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByKey indicates an expected call of LoadByKey.
+func (mr *MockPersonalRecordMockRecorder) LoadByKey(userId, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByKey", reflect.TypeOf((*MockPersonalRecord)(nil).LoadByKey), userId, key)
+}
+
+// LoadByKeyTx mocks base method.
+func (m *MockPersonalRecord) LoadByKeyTx(tx dbr.SessionRunner, userId int64, key model.ExerciseKey) (*model.PersonalRecords, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByKeyTx", tx, userId, key)
+	ret0, _ := ret[0].(*model.PersonalRecords)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByKeyTx indicates an expected call of LoadByKeyTx.
+func (mr *MockPersonalRecordMockRecorder) LoadByKeyTx(tx, userId, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByKeyTx", reflect.TypeOf((*MockPersonalRecord)(nil).LoadByKeyTx), tx, userId, key)
+}
+
+// LoadByUserID mocks base method.
+func (m *MockPersonalRecord) LoadByUserID(userId int64) (*model.PersonalRecords, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByUserID", userId)
+	ret0, _ := ret[0].(*model.PersonalRecords)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByUserID indicates an expected call of LoadByUserID.
+func (mr *MockPersonalRecordMockRecorder) LoadByUserID(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByUserID", reflect.TypeOf((*MockPersonalRecord)(nil).LoadByUserID), userId)
+}
+
+// ReplaceTx mocks base method.
+func (m *MockPersonalRecord) ReplaceTx(tx dbr.SessionRunner, userId int64, key model.ExerciseKey, records *model.PersonalRecords) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceTx", tx, userId, key, records)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceTx indicates an expected call of ReplaceTx.
+func (mr *MockPersonalRecordMockRecorder) ReplaceTx(tx, userId, key, records interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceTx", reflect.TypeOf((*MockPersonalRecord)(nil).ReplaceTx), tx, userId, key, records)
+}
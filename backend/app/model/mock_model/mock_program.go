@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./backend/app/model/program.go
+
+// Package mock_model is a generated GoMock package.
+package mock_model
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
+)
+
+// MockProgram is a mock of Program interface.
+type MockProgram struct {
+	ctrl     *gomock.Controller
+	recorder *MockProgramMockRecorder
+}
+
+// MockProgramMockRecorder is the mock recorder for MockProgram.
+type MockProgramMockRecorder struct {
+	mock *MockProgram
+}
+
+// NewMockProgram creates a new mock instance.
+func NewMockProgram(ctrl *gomock.Controller) *MockProgram {
+	mock := &MockProgram{ctrl: ctrl}
+	mock.recorder = &MockProgramMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProgram) EXPECT() *MockProgramMockRecorder {
+	return m.recorder
+}
+
+// CreateTx mocks base method.
+func (m *MockProgram) CreateTx(tx dbr.SessionRunner, userId int64, name string, weeks int64) (*model.ProgramImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, userId, name, weeks)
+	ret0, _ := ret[0].(*model.ProgramImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockProgramMockRecorder) CreateTx(tx, userId, name, weeks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockProgram)(nil).CreateTx), tx, userId, name, weeks)
+}
+
+// DeleteTx mocks base method.
+func (m *MockProgram) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTx", tx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTx indicates an expected call of DeleteTx.
+func (mr *MockProgramMockRecorder) DeleteTx(tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTx", reflect.TypeOf((*MockProgram)(nil).DeleteTx), tx, id)
+}
+
+// Load mocks base method.
+func (m *MockProgram) Load(id int64) (*model.ProgramImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", id)
+	ret0, _ := ret[0].(*model.ProgramImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockProgramMockRecorder) Load(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockProgram)(nil).Load), id)
+}
+
+// LoadByIDs mocks base method.
+func (m *MockProgram) LoadByIDs(ids []int64) (*model.Programs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByIDs", ids)
+	ret0, _ := ret[0].(*model.Programs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByIDs indicates an expected call of LoadByIDs.
+func (mr *MockProgramMockRecorder) LoadByIDs(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByIDs", reflect.TypeOf((*MockProgram)(nil).LoadByIDs), ids)
+}
+
+// LoadByName mocks base method.
+func (m *MockProgram) LoadByName(userId int64, name string) (*model.ProgramImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByName", userId, name)
+	ret0, _ := ret[0].(*model.ProgramImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByName indicates an expected call of LoadByName.
+func (mr *MockProgramMockRecorder) LoadByName(userId, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByName", reflect.TypeOf((*MockProgram)(nil).LoadByName), userId, name)
+}
+
+// LoadByUserID mocks base method.
+func (m *MockProgram) LoadByUserID(userId int64) (*model.Programs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByUserID", userId)
+	ret0, _ := ret[0].(*model.Programs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByUserID indicates an expected call of LoadByUserID.
+func (mr *MockProgramMockRecorder) LoadByUserID(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByUserID", reflect.TypeOf((*MockProgram)(nil).LoadByUserID), userId)
+}
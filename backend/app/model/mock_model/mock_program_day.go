@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./backend/app/model/program_day.go
+
+// Package mock_model is a generated GoMock package.
+package mock_model
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
+)
+
+// MockProgramDay is a mock of ProgramDay interface.
+type MockProgramDay struct {
+	ctrl     *gomock.Controller
+	recorder *MockProgramDayMockRecorder
+}
+
+// MockProgramDayMockRecorder is the mock recorder for MockProgramDay.
+type MockProgramDayMockRecorder struct {
+	mock *MockProgramDay
+}
+
+// NewMockProgramDay creates a new mock instance.
+func NewMockProgramDay(ctrl *gomock.Controller) *MockProgramDay {
+	mock := &MockProgramDay{ctrl: ctrl}
+	mock.recorder = &MockProgramDayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProgramDay) EXPECT() *MockProgramDayMockRecorder {
+	return m.recorder
+}
+
+// CreateTx mocks base method.
+func (m *MockProgramDay) CreateTx(tx dbr.SessionRunner, programId, week, day int64, name string) (*model.ProgramDayImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, programId, week, day, name)
+	ret0, _ := ret[0].(*model.ProgramDayImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockProgramDayMockRecorder) CreateTx(tx, programId, week, day, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockProgramDay)(nil).CreateTx), tx, programId, week, day, name)
+}
+
+// DeleteByProgramIDTx mocks base method.
+func (m *MockProgramDay) DeleteByProgramIDTx(tx dbr.SessionRunner, programId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByProgramIDTx", tx, programId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByProgramIDTx indicates an expected call of DeleteByProgramIDTx.
+func (mr *MockProgramDayMockRecorder) DeleteByProgramIDTx(tx, programId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByProgramIDTx", reflect.TypeOf((*MockProgramDay)(nil).DeleteByProgramIDTx), tx, programId)
+}
+
+// LoadByProgramIDs mocks base method.
+func (m *MockProgramDay) LoadByProgramIDs(programIds []int64) (*model.ProgramDays, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByProgramIDs", programIds)
+	ret0, _ := ret[0].(*model.ProgramDays)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByProgramIDs indicates an expected call of LoadByProgramIDs.
+func (mr *MockProgramDayMockRecorder) LoadByProgramIDs(programIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByProgramIDs", reflect.TypeOf((*MockProgramDay)(nil).LoadByProgramIDs), programIds)
+}
@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./backend/app/model/program_enrollment.go
+
+// Package mock_model is a generated GoMock package.
+package mock_model
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
+)
+
+// MockProgramEnrollment is a mock of ProgramEnrollment interface.
+type MockProgramEnrollment struct {
+	ctrl     *gomock.Controller
+	recorder *MockProgramEnrollmentMockRecorder
+}
+
+// MockProgramEnrollmentMockRecorder is the mock recorder for MockProgramEnrollment.
+type MockProgramEnrollmentMockRecorder struct {
+	mock *MockProgramEnrollment
+}
+
+// NewMockProgramEnrollment creates a new mock instance.
+func NewMockProgramEnrollment(ctrl *gomock.Controller) *MockProgramEnrollment {
+	mock := &MockProgramEnrollment{ctrl: ctrl}
+	mock.recorder = &MockProgramEnrollmentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProgramEnrollment) EXPECT() *MockProgramEnrollmentMockRecorder {
+	return m.recorder
+}
+
+// CreateTx mocks base method.
+func (m *MockProgramEnrollment) CreateTx(tx dbr.SessionRunner, userId, programId int64, startDate time.Time) (*model.ProgramEnrollmentImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, userId, programId, startDate)
+	ret0, _ := ret[0].(*model.ProgramEnrollmentImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockProgramEnrollmentMockRecorder) CreateTx(tx, userId, programId, startDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockProgramEnrollment)(nil).CreateTx), tx, userId, programId, startDate)
+}
+
+// DeleteTx mocks base method.
+func (m *MockProgramEnrollment) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTx", tx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTx indicates an expected call of DeleteTx.
+func (mr *MockProgramEnrollmentMockRecorder) DeleteTx(tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTx", reflect.TypeOf((*MockProgramEnrollment)(nil).DeleteTx), tx, id)
+}
+
+// Load mocks base method.
+func (m *MockProgramEnrollment) Load(id int64) (*model.ProgramEnrollmentImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", id)
+	ret0, _ := ret[0].(*model.ProgramEnrollmentImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockProgramEnrollmentMockRecorder) Load(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockProgramEnrollment)(nil).Load), id)
+}
+
+// LoadByProgramID mocks base method.
+func (m *MockProgramEnrollment) LoadByProgramID(programId int64) (*model.ProgramEnrollments, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByProgramID", programId)
+	ret0, _ := ret[0].(*model.ProgramEnrollments)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByProgramID indicates an expected call of LoadByProgramID.
+func (mr *MockProgramEnrollmentMockRecorder) LoadByProgramID(programId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByProgramID", reflect.TypeOf((*MockProgramEnrollment)(nil).LoadByProgramID), programId)
+}
+
+// LoadByUserID mocks base method.
+func (m *MockProgramEnrollment) LoadByUserID(userId int64) (*model.ProgramEnrollments, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByUserID", userId)
+	ret0, _ := ret[0].(*model.ProgramEnrollments)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByUserID indicates an expected call of LoadByUserID.
+func (mr *MockProgramEnrollmentMockRecorder) LoadByUserID(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByUserID", reflect.TypeOf((*MockProgramEnrollment)(nil).LoadByUserID), userId)
+}
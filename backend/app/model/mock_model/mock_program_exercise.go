@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./backend/app/model/program_exercise.go
+
+// Package mock_model is a generated GoMock package.
+package mock_model
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
+)
+
+// MockProgramExercise is a mock of ProgramExercise interface.
+type MockProgramExercise struct {
+	ctrl     *gomock.Controller
+	recorder *MockProgramExerciseMockRecorder
+}
+
+// MockProgramExerciseMockRecorder is the mock recorder for MockProgramExercise.
+type MockProgramExerciseMockRecorder struct {
+	mock *MockProgramExercise
+}
+
+// NewMockProgramExercise creates a new mock instance.
+func NewMockProgramExercise(ctrl *gomock.Controller) *MockProgramExercise {
+	mock := &MockProgramExercise{ctrl: ctrl}
+	mock.recorder = &MockProgramExerciseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProgramExercise) EXPECT() *MockProgramExerciseMockRecorder {
+	return m.recorder
+}
+
+// CreateTx mocks base method.
+func (m *MockProgramExercise) CreateTx(tx dbr.SessionRunner, programDayId, position int64, exerciseName string, catalogId int64, modality string) (*model.ProgramExerciseImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, programDayId, position, exerciseName, catalogId, modality)
+	ret0, _ := ret[0].(*model.ProgramExerciseImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockProgramExerciseMockRecorder) CreateTx(tx, programDayId, position, exerciseName, catalogId, modality interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockProgramExercise)(nil).CreateTx), tx, programDayId, position, exerciseName, catalogId, modality)
+}
+
+// DeleteByProgramIDTx mocks base method.
+func (m *MockProgramExercise) DeleteByProgramIDTx(tx dbr.SessionRunner, programId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByProgramIDTx", tx, programId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByProgramIDTx indicates an expected call of DeleteByProgramIDTx.
+func (mr *MockProgramExerciseMockRecorder) DeleteByProgramIDTx(tx, programId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByProgramIDTx", reflect.TypeOf((*MockProgramExercise)(nil).DeleteByProgramIDTx), tx, programId)
+}
+
+// DetachCatalogTx mocks base method.
+func (m *MockProgramExercise) DetachCatalogTx(tx dbr.SessionRunner, catalogId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachCatalogTx", tx, catalogId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachCatalogTx indicates an expected call of DetachCatalogTx.
+func (mr *MockProgramExerciseMockRecorder) DetachCatalogTx(tx, catalogId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachCatalogTx", reflect.TypeOf((*MockProgramExercise)(nil).DetachCatalogTx), tx, catalogId)
+}
+
+// LoadByProgramDayIDs mocks base method.
+func (m *MockProgramExercise) LoadByProgramDayIDs(programDayIds []int64) (*model.ProgramExercises, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByProgramDayIDs", programDayIds)
+	ret0, _ := ret[0].(*model.ProgramExercises)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByProgramDayIDs indicates an expected call of LoadByProgramDayIDs.
+func (mr *MockProgramExerciseMockRecorder) LoadByProgramDayIDs(programDayIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByProgramDayIDs", reflect.TypeOf((*MockProgramExercise)(nil).LoadByProgramDayIDs), programDayIds)
+}
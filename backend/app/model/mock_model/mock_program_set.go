@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./backend/app/model/program_set.go
+
+// Package mock_model is a generated GoMock package.
+package mock_model
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
+)
+
+// MockProgramSet is a mock of ProgramSet interface.
+type MockProgramSet struct {
+	ctrl     *gomock.Controller
+	recorder *MockProgramSetMockRecorder
+}
+
+// MockProgramSetMockRecorder is the mock recorder for MockProgramSet.
+type MockProgramSetMockRecorder struct {
+	mock *MockProgramSet
+}
+
+// NewMockProgramSet creates a new mock instance.
+func NewMockProgramSet(ctrl *gomock.Controller) *MockProgramSet {
+	mock := &MockProgramSet{ctrl: ctrl}
+	mock.recorder = &MockProgramSetMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProgramSet) EXPECT() *MockProgramSetMockRecorder {
+	return m.recorder
+}
+
+// CreateTx mocks base method.
+func (m *MockProgramSet) CreateTx(tx dbr.SessionRunner, programExerciseId int64, planned model.PlannedSet) (*model.ProgramSetImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, programExerciseId, planned)
+	ret0, _ := ret[0].(*model.ProgramSetImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockProgramSetMockRecorder) CreateTx(tx, programExerciseId, planned interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockProgramSet)(nil).CreateTx), tx, programExerciseId, planned)
+}
+
+// DeleteByProgramIDTx mocks base method.
+func (m *MockProgramSet) DeleteByProgramIDTx(tx dbr.SessionRunner, programId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByProgramIDTx", tx, programId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByProgramIDTx indicates an expected call of DeleteByProgramIDTx.
+func (mr *MockProgramSetMockRecorder) DeleteByProgramIDTx(tx, programId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByProgramIDTx", reflect.TypeOf((*MockProgramSet)(nil).DeleteByProgramIDTx), tx, programId)
+}
+
+// LoadByProgramExerciseIDs mocks base method.
+func (m *MockProgramSet) LoadByProgramExerciseIDs(programExerciseIds []int64) (*model.ProgramSets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByProgramExerciseIDs", programExerciseIds)
+	ret0, _ := ret[0].(*model.ProgramSets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByProgramExerciseIDs indicates an expected call of LoadByProgramExerciseIDs.
+func (mr *MockProgramSetMockRecorder) LoadByProgramExerciseIDs(programExerciseIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByProgramExerciseIDs", reflect.TypeOf((*MockProgramSet)(nil).LoadByProgramExerciseIDs), programExerciseIds)
+}
@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./backend/app/model/progression_rule.go
+
+// Package mock_model is a generated GoMock package.
+package mock_model
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
+)
+
+// MockProgressionRule is a mock of ProgressionRule interface.
+type MockProgressionRule struct {
+	ctrl     *gomock.Controller
+	recorder *MockProgressionRuleMockRecorder
+}
+
+// MockProgressionRuleMockRecorder is the mock recorder for MockProgressionRule.
+type MockProgressionRuleMockRecorder struct {
+	mock *MockProgressionRule
+}
+
+// NewMockProgressionRule creates a new mock instance.
+func NewMockProgressionRule(ctrl *gomock.Controller) *MockProgressionRule {
+	mock := &MockProgressionRule{ctrl: ctrl}
+	mock.recorder = &MockProgressionRuleMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProgressionRule) EXPECT() *MockProgressionRuleMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockProgressionRule) Delete(id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProgressionRuleMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProgressionRule)(nil).Delete), id)
+}
+
+// DetachCatalogTx mocks base method.
+func (m *MockProgressionRule) DetachCatalogTx(tx dbr.SessionRunner, catalogId int64, exerciseName string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachCatalogTx", tx, catalogId, exerciseName)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachCatalogTx indicates an expected call of DetachCatalogTx.
+func (mr *MockProgressionRuleMockRecorder) DetachCatalogTx(tx, catalogId, exerciseName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachCatalogTx", reflect.TypeOf((*MockProgressionRule)(nil).DetachCatalogTx), tx, catalogId, exerciseName)
+}
+
+// Load mocks base method.
+func (m *MockProgressionRule) Load(id int64) (*model.ProgressionRuleImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", id)
+	ret0, _ := ret[0].(*model.ProgressionRuleImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockProgressionRuleMockRecorder) Load(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockProgressionRule)(nil).Load), id)
+}
+
+// LoadByKey mocks base method.
+func (m *MockProgressionRule) LoadByKey(userId int64, key model.ExerciseKey) (*model.ProgressionRuleImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByKey", userId, key)
+	ret0, _ := ret[0].(*model.ProgressionRuleImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByKey indicates an expected call of LoadByKey.
+func (mr *MockProgressionRuleMockRecorder) LoadByKey(userId, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByKey", reflect.TypeOf((*MockProgressionRule)(nil).LoadByKey), userId, key)
+}
+
+// LoadByUserID mocks base method.
+func (m *MockProgressionRule) LoadByUserID(userId int64) (*model.ProgressionRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByUserID", userId)
+	ret0, _ := ret[0].(*model.ProgressionRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByUserID indicates an expected call of LoadByUserID.
+func (mr *MockProgressionRuleMockRecorder) LoadByUserID(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByUserID", reflect.TypeOf((*MockProgressionRule)(nil).LoadByUserID), userId)
+}
+
+// ReplaceTx mocks base method.
+func (m *MockProgressionRule) ReplaceTx(tx dbr.SessionRunner, rule model.ProgressionRuleImpl) (*model.ProgressionRuleImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceTx", tx, rule)
+	ret0, _ := ret[0].(*model.ProgressionRuleImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceTx indicates an expected call of ReplaceTx.
+func (mr *MockProgressionRuleMockRecorder) ReplaceTx(tx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceTx", reflect.TypeOf((*MockProgressionRule)(nil).ReplaceTx), tx, rule)
+}
@@ -5,10 +5,12 @@
 package mock_model
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
 )
 
 // MockSet is a mock of Set interface.
@@ -35,18 +37,107 @@ func (m *MockSet) EXPECT() *MockSetMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockSet) Create(exerciseID, setNumber int64, weight float64, reps int64) (*model.SetImpl, error) {
+func (m *MockSet) Create(exerciseID, setNumber int64, weight float64, reps int64, unit string, detail model.SetDetail) (*model.SetImpl, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", exerciseID, setNumber, weight, reps)
+	ret := m.ctrl.Call(m, "Create", exerciseID, setNumber, weight, reps, unit, detail)
 	ret0, _ := ret[0].(*model.SetImpl)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockSetMockRecorder) Create(exerciseID, setNumber, weight, reps interface{}) *gomock.Call {
+func (mr *MockSetMockRecorder) Create(exerciseID, setNumber, weight, reps, unit, detail interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSet)(nil).Create), exerciseID, setNumber, weight, reps)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSet)(nil).Create), exerciseID, setNumber, weight, reps, unit, detail)
+}
+
+// CreateTx mocks base method.
+func (m *MockSet) CreateTx(tx dbr.SessionRunner, exerciseID, setNumber int64, weight float64, reps int64, unit string, detail model.SetDetail) (*model.SetImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, exerciseID, setNumber, weight, reps, unit, detail)
+	ret0, _ := ret[0].(*model.SetImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockSetMockRecorder) CreateTx(tx, exerciseID, setNumber, weight, reps, unit, detail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockSet)(nil).CreateTx), tx, exerciseID, setNumber, weight, reps, unit, detail)
+}
+
+// Delete mocks base method.
+func (m *MockSet) Delete(id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSetMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSet)(nil).Delete), id)
+}
+
+// DeleteByExerciseIDTx mocks base method.
+func (m *MockSet) DeleteByExerciseIDTx(tx dbr.SessionRunner, exerciseId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByExerciseIDTx", tx, exerciseId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByExerciseIDTx indicates an expected call of DeleteByExerciseIDTx.
+func (mr *MockSetMockRecorder) DeleteByExerciseIDTx(tx, exerciseId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByExerciseIDTx", reflect.TypeOf((*MockSet)(nil).DeleteByExerciseIDTx), tx, exerciseId)
+}
+
+// DeleteBySessionIDTx mocks base method.
+func (m *MockSet) DeleteBySessionIDTx(tx dbr.SessionRunner, sessionId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBySessionIDTx", tx, sessionId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBySessionIDTx indicates an expected call of DeleteBySessionIDTx.
+func (mr *MockSetMockRecorder) DeleteBySessionIDTx(tx, sessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBySessionIDTx", reflect.TypeOf((*MockSet)(nil).DeleteBySessionIDTx), tx, sessionId)
+}
+
+// DeleteTx mocks base method.
+func (m *MockSet) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTx", tx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTx indicates an expected call of DeleteTx.
+func (mr *MockSetMockRecorder) DeleteTx(tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTx", reflect.TypeOf((*MockSet)(nil).DeleteTx), tx, id)
+}
+
+// EachExport mocks base method.
+func (m *MockSet) EachExport(ctx context.Context, filter model.SetHistoryFilter, fn func(*model.SetExport) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachExport", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachExport indicates an expected call of EachExport.
+func (mr *MockSetMockRecorder) EachExport(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachExport", reflect.TypeOf((*MockSet)(nil).EachExport), ctx, filter, fn)
 }
 
 // Load mocks base method.
@@ -79,17 +170,77 @@ func (mr *MockSetMockRecorder) LoadByExerciseID(exerciseId interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByExerciseID", reflect.TypeOf((*MockSet)(nil).LoadByExerciseID), exerciseId)
 }
 
+// LoadByExerciseIDs mocks base method.
+func (m *MockSet) LoadByExerciseIDs(exerciseIds []int64) (*model.Sets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByExerciseIDs", exerciseIds)
+	ret0, _ := ret[0].(*model.Sets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByExerciseIDs indicates an expected call of LoadByExerciseIDs.
+func (mr *MockSetMockRecorder) LoadByExerciseIDs(exerciseIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByExerciseIDs", reflect.TypeOf((*MockSet)(nil).LoadByExerciseIDs), exerciseIds)
+}
+
+// LoadHistory mocks base method.
+func (m *MockSet) LoadHistory(filter model.SetHistoryFilter) (*model.SetHistories, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadHistory", filter)
+	ret0, _ := ret[0].(*model.SetHistories)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadHistory indicates an expected call of LoadHistory.
+func (mr *MockSetMockRecorder) LoadHistory(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadHistory", reflect.TypeOf((*MockSet)(nil).LoadHistory), filter)
+}
+
+// LoadHistoryTx mocks base method.
+func (m *MockSet) LoadHistoryTx(tx dbr.SessionRunner, filter model.SetHistoryFilter) (*model.SetHistories, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadHistoryTx", tx, filter)
+	ret0, _ := ret[0].(*model.SetHistories)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadHistoryTx indicates an expected call of LoadHistoryTx.
+func (mr *MockSetMockRecorder) LoadHistoryTx(tx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadHistoryTx", reflect.TypeOf((*MockSet)(nil).LoadHistoryTx), tx, filter)
+}
+
 // Update mocks base method.
-func (m *MockSet) Update(attrs map[string]interface{}) (bool, error) {
+func (m *MockSet) Update(id int64, attrs map[string]interface{}) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", attrs)
+	ret := m.ctrl.Call(m, "Update", id, attrs)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockSetMockRecorder) Update(attrs interface{}) *gomock.Call {
+func (mr *MockSetMockRecorder) Update(id, attrs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSet)(nil).Update), id, attrs)
+}
+
+// UpdateTx mocks base method.
+func (m *MockSet) UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTx", tx, id, attrs)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTx indicates an expected call of UpdateTx.
+func (mr *MockSetMockRecorder) UpdateTx(tx, id, attrs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSet)(nil).Update), attrs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTx", reflect.TypeOf((*MockSet)(nil).UpdateTx), tx, id, attrs)
 }
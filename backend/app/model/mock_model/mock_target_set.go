@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./backend/app/model/target_set.go
+
+// Package mock_model is a generated GoMock package.
+package mock_model
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
+)
+
+// MockTargetSet is a mock of TargetSet interface.
+type MockTargetSet struct {
+	ctrl     *gomock.Controller
+	recorder *MockTargetSetMockRecorder
+}
+
+// MockTargetSetMockRecorder is the mock recorder for MockTargetSet.
+type MockTargetSetMockRecorder struct {
+	mock *MockTargetSet
+}
+
+// NewMockTargetSet creates a new mock instance.
+func NewMockTargetSet(ctrl *gomock.Controller) *MockTargetSet {
+	mock := &MockTargetSet{ctrl: ctrl}
+	mock.recorder = &MockTargetSetMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTargetSet) EXPECT() *MockTargetSetMockRecorder {
+	return m.recorder
+}
+
+// CreateTx mocks base method.
+func (m *MockTargetSet) CreateTx(tx dbr.SessionRunner, exerciseId int64, target model.SetTarget) (*model.TargetSetImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, exerciseId, target)
+	ret0, _ := ret[0].(*model.TargetSetImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockTargetSetMockRecorder) CreateTx(tx, exerciseId, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockTargetSet)(nil).CreateTx), tx, exerciseId, target)
+}
+
+// DeleteByExerciseIDTx mocks base method.
+func (m *MockTargetSet) DeleteByExerciseIDTx(tx dbr.SessionRunner, exerciseId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByExerciseIDTx", tx, exerciseId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByExerciseIDTx indicates an expected call of DeleteByExerciseIDTx.
+func (mr *MockTargetSetMockRecorder) DeleteByExerciseIDTx(tx, exerciseId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByExerciseIDTx", reflect.TypeOf((*MockTargetSet)(nil).DeleteByExerciseIDTx), tx, exerciseId)
+}
+
+// DeleteBySessionIDTx mocks base method.
+func (m *MockTargetSet) DeleteBySessionIDTx(tx dbr.SessionRunner, sessionId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBySessionIDTx", tx, sessionId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBySessionIDTx indicates an expected call of DeleteBySessionIDTx.
+func (mr *MockTargetSetMockRecorder) DeleteBySessionIDTx(tx, sessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBySessionIDTx", reflect.TypeOf((*MockTargetSet)(nil).DeleteBySessionIDTx), tx, sessionId)
+}
+
+// LoadByExerciseIDs mocks base method.
+func (m *MockTargetSet) LoadByExerciseIDs(exerciseIds []int64) (*model.TargetSets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByExerciseIDs", exerciseIds)
+	ret0, _ := ret[0].(*model.TargetSets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByExerciseIDs indicates an expected call of LoadByExerciseIDs.
+func (mr *MockTargetSetMockRecorder) LoadByExerciseIDs(exerciseIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByExerciseIDs", reflect.TypeOf((*MockTargetSet)(nil).LoadByExerciseIDs), exerciseIds)
+}
+
+// LoadHistory mocks base method.
+func (m *MockTargetSet) LoadHistory(filter model.SetHistoryFilter) (*model.TargetSetHistories, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadHistory", filter)
+	ret0, _ := ret[0].(*model.TargetSetHistories)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadHistory indicates an expected call of LoadHistory.
+func (mr *MockTargetSetMockRecorder) LoadHistory(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadHistory", reflect.TypeOf((*MockTargetSet)(nil).LoadHistory), filter)
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./backend/app/model/template_exercise.go
+
+// Package mock_model is a generated GoMock package.
+package mock_model
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
+)
+
+// MockTemplateExercise is a mock of TemplateExercise interface.
+type MockTemplateExercise struct {
+	ctrl     *gomock.Controller
+	recorder *MockTemplateExerciseMockRecorder
+}
+
+// MockTemplateExerciseMockRecorder is the mock recorder for MockTemplateExercise.
+type MockTemplateExerciseMockRecorder struct {
+	mock *MockTemplateExercise
+}
+
+// NewMockTemplateExercise creates a new mock instance.
+func NewMockTemplateExercise(ctrl *gomock.Controller) *MockTemplateExercise {
+	mock := &MockTemplateExercise{ctrl: ctrl}
+	mock.recorder = &MockTemplateExerciseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTemplateExercise) EXPECT() *MockTemplateExerciseMockRecorder {
+	return m.recorder
+}
+
+// CreateTx mocks base method.
+func (m *MockTemplateExercise) CreateTx(tx dbr.SessionRunner, templateId, position int64, exerciseName string, catalogId int64, modality string) (*model.TemplateExerciseImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, templateId, position, exerciseName, catalogId, modality)
+	ret0, _ := ret[0].(*model.TemplateExerciseImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockTemplateExerciseMockRecorder) CreateTx(tx, templateId, position, exerciseName, catalogId, modality interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockTemplateExercise)(nil).CreateTx), tx, templateId, position, exerciseName, catalogId, modality)
+}
+
+// DeleteByTemplateIDTx mocks base method.
+func (m *MockTemplateExercise) DeleteByTemplateIDTx(tx dbr.SessionRunner, templateId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByTemplateIDTx", tx, templateId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByTemplateIDTx indicates an expected call of DeleteByTemplateIDTx.
+func (mr *MockTemplateExerciseMockRecorder) DeleteByTemplateIDTx(tx, templateId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByTemplateIDTx", reflect.TypeOf((*MockTemplateExercise)(nil).DeleteByTemplateIDTx), tx, templateId)
+}
+
+// DetachCatalogTx mocks base method.
+func (m *MockTemplateExercise) DetachCatalogTx(tx dbr.SessionRunner, catalogId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachCatalogTx", tx, catalogId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachCatalogTx indicates an expected call of DetachCatalogTx.
+func (mr *MockTemplateExerciseMockRecorder) DetachCatalogTx(tx, catalogId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachCatalogTx", reflect.TypeOf((*MockTemplateExercise)(nil).DetachCatalogTx), tx, catalogId)
+}
+
+// LoadByTemplateIDs mocks base method.
+func (m *MockTemplateExercise) LoadByTemplateIDs(templateIds []int64) (*model.TemplateExercises, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByTemplateIDs", templateIds)
+	ret0, _ := ret[0].(*model.TemplateExercises)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByTemplateIDs indicates an expected call of LoadByTemplateIDs.
+func (mr *MockTemplateExerciseMockRecorder) LoadByTemplateIDs(templateIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByTemplateIDs", reflect.TypeOf((*MockTemplateExercise)(nil).LoadByTemplateIDs), templateIds)
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./backend/app/model/template_set.go
+
+// Package mock_model is a generated GoMock package.
+package mock_model
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
+)
+
+// MockTemplateSet is a mock of TemplateSet interface.
+type MockTemplateSet struct {
+	ctrl     *gomock.Controller
+	recorder *MockTemplateSetMockRecorder
+}
+
+// MockTemplateSetMockRecorder is the mock recorder for MockTemplateSet.
+type MockTemplateSetMockRecorder struct {
+	mock *MockTemplateSet
+}
+
+// NewMockTemplateSet creates a new mock instance.
+func NewMockTemplateSet(ctrl *gomock.Controller) *MockTemplateSet {
+	mock := &MockTemplateSet{ctrl: ctrl}
+	mock.recorder = &MockTemplateSetMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTemplateSet) EXPECT() *MockTemplateSetMockRecorder {
+	return m.recorder
+}
+
+// CreateTx mocks base method.
+func (m *MockTemplateSet) CreateTx(tx dbr.SessionRunner, templateExerciseId int64, target model.SetTarget) (*model.TemplateSetImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, templateExerciseId, target)
+	ret0, _ := ret[0].(*model.TemplateSetImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockTemplateSetMockRecorder) CreateTx(tx, templateExerciseId, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockTemplateSet)(nil).CreateTx), tx, templateExerciseId, target)
+}
+
+// DeleteByTemplateIDTx mocks base method.
+func (m *MockTemplateSet) DeleteByTemplateIDTx(tx dbr.SessionRunner, templateId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByTemplateIDTx", tx, templateId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByTemplateIDTx indicates an expected call of DeleteByTemplateIDTx.
+func (mr *MockTemplateSetMockRecorder) DeleteByTemplateIDTx(tx, templateId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByTemplateIDTx", reflect.TypeOf((*MockTemplateSet)(nil).DeleteByTemplateIDTx), tx, templateId)
+}
+
+// LoadByTemplateExerciseIDs mocks base method.
+func (m *MockTemplateSet) LoadByTemplateExerciseIDs(templateExerciseIds []int64) (*model.TemplateSets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByTemplateExerciseIDs", templateExerciseIds)
+	ret0, _ := ret[0].(*model.TemplateSets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByTemplateExerciseIDs indicates an expected call of LoadByTemplateExerciseIDs.
+func (mr *MockTemplateSetMockRecorder) LoadByTemplateExerciseIDs(templateExerciseIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByTemplateExerciseIDs", reflect.TypeOf((*MockTemplateSet)(nil).LoadByTemplateExerciseIDs), templateExerciseIds)
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./backend/app/model/training_max.go
+
+// Package mock_model is a generated GoMock package.
+package mock_model
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
+)
+
+// MockTrainingMax is a mock of TrainingMax interface.
+type MockTrainingMax struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrainingMaxMockRecorder
+}
+
+// MockTrainingMaxMockRecorder is the mock recorder for MockTrainingMax.
+type MockTrainingMaxMockRecorder struct {
+	mock *MockTrainingMax
+}
+
+// NewMockTrainingMax creates a new mock instance.
+func NewMockTrainingMax(ctrl *gomock.Controller) *MockTrainingMax {
+	mock := &MockTrainingMax{ctrl: ctrl}
+	mock.recorder = &MockTrainingMaxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrainingMax) EXPECT() *MockTrainingMaxMockRecorder {
+	return m.recorder
+}
+
+// CreateTx mocks base method.
+func (m *MockTrainingMax) CreateTx(tx dbr.SessionRunner, enrollmentId int64, key model.ExerciseKey, weight float64, unit string) (*model.TrainingMaxImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, enrollmentId, key, weight, unit)
+	ret0, _ := ret[0].(*model.TrainingMaxImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockTrainingMaxMockRecorder) CreateTx(tx, enrollmentId, key, weight, unit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockTrainingMax)(nil).CreateTx), tx, enrollmentId, key, weight, unit)
+}
+
+// DeleteByEnrollmentIDTx mocks base method.
+func (m *MockTrainingMax) DeleteByEnrollmentIDTx(tx dbr.SessionRunner, enrollmentId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByEnrollmentIDTx", tx, enrollmentId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByEnrollmentIDTx indicates an expected call of DeleteByEnrollmentIDTx.
+func (mr *MockTrainingMaxMockRecorder) DeleteByEnrollmentIDTx(tx, enrollmentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByEnrollmentIDTx", reflect.TypeOf((*MockTrainingMax)(nil).DeleteByEnrollmentIDTx), tx, enrollmentId)
+}
+
+// DetachCatalogTx mocks base method.
+func (m *MockTrainingMax) DetachCatalogTx(tx dbr.SessionRunner, catalogId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachCatalogTx", tx, catalogId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachCatalogTx indicates an expected call of DetachCatalogTx.
+func (mr *MockTrainingMaxMockRecorder) DetachCatalogTx(tx, catalogId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachCatalogTx", reflect.TypeOf((*MockTrainingMax)(nil).DetachCatalogTx), tx, catalogId)
+}
+
+// LoadByEnrollmentID mocks base method.
+func (m *MockTrainingMax) LoadByEnrollmentID(enrollmentId int64) (*model.TrainingMaxes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByEnrollmentID", enrollmentId)
+	ret0, _ := ret[0].(*model.TrainingMaxes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByEnrollmentID indicates an expected call of LoadByEnrollmentID.
+func (mr *MockTrainingMaxMockRecorder) LoadByEnrollmentID(enrollmentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByEnrollmentID", reflect.TypeOf((*MockTrainingMax)(nil).LoadByEnrollmentID), enrollmentId)
+}
+
+// LoadByEnrollmentIDs mocks base method.
+func (m *MockTrainingMax) LoadByEnrollmentIDs(enrollmentIds []int64) (*model.TrainingMaxes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByEnrollmentIDs", enrollmentIds)
+	ret0, _ := ret[0].(*model.TrainingMaxes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByEnrollmentIDs indicates an expected call of LoadByEnrollmentIDs.
+func (mr *MockTrainingMaxMockRecorder) LoadByEnrollmentIDs(enrollmentIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByEnrollmentIDs", reflect.TypeOf((*MockTrainingMax)(nil).LoadByEnrollmentIDs), enrollmentIds)
+}
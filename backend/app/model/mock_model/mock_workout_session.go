@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
 )
 
 // MockWorkoutSession is a mock of WorkoutSession interface.
@@ -36,18 +37,123 @@ func (m *MockWorkoutSession) EXPECT() *MockWorkoutSessionMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockWorkoutSession) Create(date time.Time, userId int64) (*model.WorkoutSessionImpl, error) {
+func (m *MockWorkoutSession) Create(date time.Time, userId int64, status string) (*model.WorkoutSessionImpl, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", date, userId)
+	ret := m.ctrl.Call(m, "Create", date, userId, status)
 	ret0, _ := ret[0].(*model.WorkoutSessionImpl)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockWorkoutSessionMockRecorder) Create(date, userId interface{}) *gomock.Call {
+func (mr *MockWorkoutSessionMockRecorder) Create(date, userId, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWorkoutSession)(nil).Create), date, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWorkoutSession)(nil).Create), date, userId, status)
+}
+
+// CreateFromTemplateTx mocks base method.
+func (m *MockWorkoutSession) CreateFromTemplateTx(tx dbr.SessionRunner, date time.Time, userId, templateId int64) (*model.WorkoutSessionImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFromTemplateTx", tx, date, userId, templateId)
+	ret0, _ := ret[0].(*model.WorkoutSessionImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFromTemplateTx indicates an expected call of CreateFromTemplateTx.
+func (mr *MockWorkoutSessionMockRecorder) CreateFromTemplateTx(tx, date, userId, templateId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFromTemplateTx", reflect.TypeOf((*MockWorkoutSession)(nil).CreateFromTemplateTx), tx, date, userId, templateId)
+}
+
+// CreateImportedTx mocks base method.
+func (m_2 *MockWorkoutSession) CreateImportedTx(tx dbr.SessionRunner, m *model.WorkoutSessionImpl) (*model.WorkoutSessionImpl, error) {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "CreateImportedTx", tx, m)
+	ret0, _ := ret[0].(*model.WorkoutSessionImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImportedTx indicates an expected call of CreateImportedTx.
+func (mr *MockWorkoutSessionMockRecorder) CreateImportedTx(tx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImportedTx", reflect.TypeOf((*MockWorkoutSession)(nil).CreateImportedTx), tx, m)
+}
+
+// CreatePlannedTx mocks base method.
+func (m *MockWorkoutSession) CreatePlannedTx(tx dbr.SessionRunner, date time.Time, userId, enrollmentId, programDayId int64) (*model.WorkoutSessionImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePlannedTx", tx, date, userId, enrollmentId, programDayId)
+	ret0, _ := ret[0].(*model.WorkoutSessionImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePlannedTx indicates an expected call of CreatePlannedTx.
+func (mr *MockWorkoutSessionMockRecorder) CreatePlannedTx(tx, date, userId, enrollmentId, programDayId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePlannedTx", reflect.TypeOf((*MockWorkoutSession)(nil).CreatePlannedTx), tx, date, userId, enrollmentId, programDayId)
+}
+
+// CreateTx mocks base method.
+func (m *MockWorkoutSession) CreateTx(tx dbr.SessionRunner, date time.Time, userId int64, status string) (*model.WorkoutSessionImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, date, userId, status)
+	ret0, _ := ret[0].(*model.WorkoutSessionImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockWorkoutSessionMockRecorder) CreateTx(tx, date, userId, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockWorkoutSession)(nil).CreateTx), tx, date, userId, status)
+}
+
+// Delete mocks base method.
+func (m *MockWorkoutSession) Delete(id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWorkoutSessionMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWorkoutSession)(nil).Delete), id)
+}
+
+// DeleteTx mocks base method.
+func (m *MockWorkoutSession) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTx", tx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTx indicates an expected call of DeleteTx.
+func (mr *MockWorkoutSessionMockRecorder) DeleteTx(tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTx", reflect.TypeOf((*MockWorkoutSession)(nil).DeleteTx), tx, id)
+}
+
+// LinkProgramDay mocks base method.
+func (m *MockWorkoutSession) LinkProgramDay(id, enrollmentId, programDayId int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkProgramDay", id, enrollmentId, programDayId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LinkProgramDay indicates an expected call of LinkProgramDay.
+func (mr *MockWorkoutSessionMockRecorder) LinkProgramDay(id, enrollmentId, programDayId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkProgramDay", reflect.TypeOf((*MockWorkoutSession)(nil).LinkProgramDay), id, enrollmentId, programDayId)
 }
 
 // Load mocks base method.
@@ -65,32 +171,77 @@ func (mr *MockWorkoutSessionMockRecorder) Load(id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockWorkoutSession)(nil).Load), id)
 }
 
-// LoadByIDAndDate mocks base method.
-func (m *MockWorkoutSession) LoadByIDAndDate(id int64, date time.Time) (*model.WorkoutSessions, error) {
+// LoadByFilter mocks base method.
+func (m *MockWorkoutSession) LoadByFilter(filter model.WorkoutSessionFilter) (*model.WorkoutSessions, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoadByIDAndDate", id, date)
+	ret := m.ctrl.Call(m, "LoadByFilter", filter)
 	ret0, _ := ret[0].(*model.WorkoutSessions)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// LoadByIDAndDate indicates an expected call of LoadByIDAndDate.
-func (mr *MockWorkoutSessionMockRecorder) LoadByIDAndDate(id, date interface{}) *gomock.Call {
+// LoadByFilter indicates an expected call of LoadByFilter.
+func (mr *MockWorkoutSessionMockRecorder) LoadByFilter(filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByIDAndDate", reflect.TypeOf((*MockWorkoutSession)(nil).LoadByIDAndDate), id, date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByFilter", reflect.TypeOf((*MockWorkoutSession)(nil).LoadByFilter), filter)
+}
+
+// LoadImportKeys mocks base method.
+func (m *MockWorkoutSession) LoadImportKeys(userId int64, keys []string) (map[string]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadImportKeys", userId, keys)
+	ret0, _ := ret[0].(map[string]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadImportKeys indicates an expected call of LoadImportKeys.
+func (mr *MockWorkoutSessionMockRecorder) LoadImportKeys(userId, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadImportKeys", reflect.TypeOf((*MockWorkoutSession)(nil).LoadImportKeys), userId, keys)
+}
+
+// UnlinkEnrollmentTx mocks base method.
+func (m *MockWorkoutSession) UnlinkEnrollmentTx(tx dbr.SessionRunner, enrollmentId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlinkEnrollmentTx", tx, enrollmentId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlinkEnrollmentTx indicates an expected call of UnlinkEnrollmentTx.
+func (mr *MockWorkoutSessionMockRecorder) UnlinkEnrollmentTx(tx, enrollmentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlinkEnrollmentTx", reflect.TypeOf((*MockWorkoutSession)(nil).UnlinkEnrollmentTx), tx, enrollmentId)
 }
 
 // Update mocks base method.
-func (m *MockWorkoutSession) Update(attrs map[string]interface{}) (bool, error) {
+func (m *MockWorkoutSession) Update(id int64, attrs map[string]interface{}) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", attrs)
+	ret := m.ctrl.Call(m, "Update", id, attrs)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockWorkoutSessionMockRecorder) Update(attrs interface{}) *gomock.Call {
+func (mr *MockWorkoutSessionMockRecorder) Update(id, attrs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWorkoutSession)(nil).Update), id, attrs)
+}
+
+// UpdateTx mocks base method.
+func (m *MockWorkoutSession) UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTx", tx, id, attrs)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTx indicates an expected call of UpdateTx.
+func (mr *MockWorkoutSessionMockRecorder) UpdateTx(tx, id, attrs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWorkoutSession)(nil).Update), attrs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTx", reflect.TypeOf((*MockWorkoutSession)(nil).UpdateTx), tx, id, attrs)
 }
@@ -0,0 +1,141 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./backend/app/model/workout_template.go
+
+// Package mock_model is a generated GoMock package.
+package mock_model
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	dbr "github.com/gocraft/dbr/v2"
+)
+
+// MockWorkoutTemplate is a mock of WorkoutTemplate interface.
+type MockWorkoutTemplate struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkoutTemplateMockRecorder
+}
+
+// MockWorkoutTemplateMockRecorder is the mock recorder for MockWorkoutTemplate.
+type MockWorkoutTemplateMockRecorder struct {
+	mock *MockWorkoutTemplate
+}
+
+// NewMockWorkoutTemplate creates a new mock instance.
+func NewMockWorkoutTemplate(ctrl *gomock.Controller) *MockWorkoutTemplate {
+	mock := &MockWorkoutTemplate{ctrl: ctrl}
+	mock.recorder = &MockWorkoutTemplateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkoutTemplate) EXPECT() *MockWorkoutTemplateMockRecorder {
+	return m.recorder
+}
+
+// CreateTx mocks base method.
+func (m *MockWorkoutTemplate) CreateTx(tx dbr.SessionRunner, userId int64, name string) (*model.WorkoutTemplateImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTx", tx, userId, name)
+	ret0, _ := ret[0].(*model.WorkoutTemplateImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTx indicates an expected call of CreateTx.
+func (mr *MockWorkoutTemplateMockRecorder) CreateTx(tx, userId, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTx", reflect.TypeOf((*MockWorkoutTemplate)(nil).CreateTx), tx, userId, name)
+}
+
+// DeleteTx mocks base method.
+func (m *MockWorkoutTemplate) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTx", tx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTx indicates an expected call of DeleteTx.
+func (mr *MockWorkoutTemplateMockRecorder) DeleteTx(tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTx", reflect.TypeOf((*MockWorkoutTemplate)(nil).DeleteTx), tx, id)
+}
+
+// Load mocks base method.
+func (m *MockWorkoutTemplate) Load(id int64) (*model.WorkoutTemplateImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", id)
+	ret0, _ := ret[0].(*model.WorkoutTemplateImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockWorkoutTemplateMockRecorder) Load(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockWorkoutTemplate)(nil).Load), id)
+}
+
+// LoadByName mocks base method.
+func (m *MockWorkoutTemplate) LoadByName(userId int64, name string) (*model.WorkoutTemplateImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByName", userId, name)
+	ret0, _ := ret[0].(*model.WorkoutTemplateImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByName indicates an expected call of LoadByName.
+func (mr *MockWorkoutTemplateMockRecorder) LoadByName(userId, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByName", reflect.TypeOf((*MockWorkoutTemplate)(nil).LoadByName), userId, name)
+}
+
+// LoadByUserID mocks base method.
+func (m *MockWorkoutTemplate) LoadByUserID(userId int64) (*model.WorkoutTemplates, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadByUserID", userId)
+	ret0, _ := ret[0].(*model.WorkoutTemplates)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadByUserID indicates an expected call of LoadByUserID.
+func (mr *MockWorkoutTemplateMockRecorder) LoadByUserID(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadByUserID", reflect.TypeOf((*MockWorkoutTemplate)(nil).LoadByUserID), userId)
+}
+
+// Update mocks base method.
+func (m *MockWorkoutTemplate) Update(id int64, attrs map[string]interface{}) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", id, attrs)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWorkoutTemplateMockRecorder) Update(id, attrs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWorkoutTemplate)(nil).Update), id, attrs)
+}
+
+// UpdateTx mocks base method.
+func (m *MockWorkoutTemplate) UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTx", tx, id, attrs)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTx indicates an expected call of UpdateTx.
+func (mr *MockWorkoutTemplateMockRecorder) UpdateTx(tx, id, attrs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTx", reflect.TypeOf((*MockWorkoutTemplate)(nil).UpdateTx), tx, id, attrs)
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+// 自己ベストの種類
+const (
+	// RecordTypeMaxWeight 最大重量
+	RecordTypeMaxWeight = "max_weight"
+	// RecordTypeRepsAtWeight 重量ごとの最大回数
+	RecordTypeRepsAtWeight = "reps_at_weight"
+	// RecordTypeEstimatedOneRepMax 推定1RM
+	RecordTypeEstimatedOneRepMax = "e1rm"
+	// RecordTypeSessionVolume 1セッションでの最大ボリューム
+	RecordTypeSessionVolume = "session_volume"
+)
+
+type (
+	// PersonalRecord 自己ベストのインターフェースを表す
+	PersonalRecord interface {
+		LoadByUserID(userId int64) (*PersonalRecords, error)
+		LoadByKey(userId int64, key ExerciseKey) (*PersonalRecords, error)
+		LoadByKeyTx(tx dbr.SessionRunner, userId int64, key ExerciseKey) (*PersonalRecords, error)
+		ReplaceTx(tx dbr.SessionRunner, userId int64, key ExerciseKey, records *PersonalRecords) error
+	}
+
+	// PersonalRecordImpl 自己ベストを表す
+	PersonalRecordImpl struct {
+		ID           int64     `db:"record_id" dbopt:"auto_increment"`
+		UserID       int64     `db:"user_id"`
+		CatalogID    int64     `db:"catalog_id"`
+		ExerciseName string    `db:"exercise_name"`
+		RecordType   string    `db:"record_type"`
+		WeightClass  float64   `db:"weight_class"`
+		Value        float64   `db:"value"`
+		Weight       float64   `db:"weight"`
+		Unit         string    `db:"unit"`
+		Reps         int64     `db:"reps"`
+		SessionID    int64     `db:"session_id"`
+		SetID        int64     `db:"set_id"`
+		AchievedOn   time.Time `db:"achieved_on"`
+		// 一覧表示用の種目名。カタログに紐づく場合はカタログの日本語名
+		DisplayName string `db:"display_name"`
+	}
+
+	PersonalRecords []PersonalRecordImpl
+)
+
+func NewPersonalRecords() *PersonalRecords {
+	return &PersonalRecords{}
+}
+
+func NewPersonalRecord() PersonalRecord {
+	return &PersonalRecordImpl{}
+}
+
+// LoadByUserID ユーザーの自己ベストを種目ごとに読み込み
+func (m *PersonalRecordImpl) LoadByUserID(userId int64) (*PersonalRecords, error) {
+	return m.LoadByUserIDTx(db.GetSession("training_db"), userId)
+}
+
+// LoadByUserIDTx トランザクション内でユーザーの自己ベストを読み込み
+func (m *PersonalRecordImpl) LoadByUserIDTx(tx dbr.SessionRunner, userId int64) (*PersonalRecords, error) {
+	r := NewPersonalRecords()
+	if _, err := m.selectRecords(tx).
+		Where("r.user_id = ?", userId).
+		OrderBy("display_name").
+		OrderBy("r.record_type").
+		OrderBy("r.weight_class").
+		Load(r); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load personal_records")
+	}
+	return r, nil
+}
+
+// LoadByKey ユーザーの指定した種目の自己ベストを読み込み
+func (m *PersonalRecordImpl) LoadByKey(userId int64, key ExerciseKey) (*PersonalRecords, error) {
+	return m.LoadByKeyTx(db.GetSession("training_db"), userId, key)
+}
+
+// LoadByKeyTx トランザクション内でユーザーの指定した種目の自己ベストを読み込み
+func (m *PersonalRecordImpl) LoadByKeyTx(tx dbr.SessionRunner, userId int64, key ExerciseKey) (*PersonalRecords, error) {
+	r := NewPersonalRecords()
+	if _, err := m.selectRecords(tx).
+		Where("r.user_id = ? AND r.catalog_id = ? AND r.exercise_name = ?", userId, key.CatalogID, key.ExerciseName).
+		OrderBy("r.record_type").
+		OrderBy("r.weight_class").
+		Load(r); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load personal_records")
+	}
+	return r, nil
+}
+
+func (m *PersonalRecordImpl) selectRecords(tx dbr.SessionRunner) *dbr.SelectStmt {
+	return tx.Select("r.*", "COALESCE(c.name_ja, r.exercise_name) AS display_name").
+		From(dbr.I("personal_records").As("r")).
+		LeftJoin(dbr.I("exercise_catalog").As("c"), "c.catalog_id = r.catalog_id")
+}
+
+// ReplaceTx トランザクション内で種目の自己ベストを置き換え
+func (m *PersonalRecordImpl) ReplaceTx(tx dbr.SessionRunner, userId int64, key ExerciseKey, records *PersonalRecords) error {
+	if _, err := tx.DeleteFrom("personal_records").
+		Where("user_id = ? AND catalog_id = ? AND exercise_name = ?", userId, key.CatalogID, key.ExerciseName).
+		Exec(); err != nil {
+		return errors.Wrapf(err, "couldn't delete personal_records")
+	}
+
+	for i := range *records {
+		record := &(*records)[i]
+		record.UserID = userId
+		record.CatalogID = key.CatalogID
+		record.ExerciseName = key.ExerciseName
+
+		res, err := tx.InsertInto("personal_records").
+			Columns("user_id", "catalog_id", "exercise_name", "record_type", "weight_class", "value", "weight", "unit", "reps", "session_id", "set_id", "achieved_on").
+			Record(record).
+			Exec()
+		if err != nil {
+			return errors.Wrapf(err, "couldn't create personal_records")
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return errors.Wrapf(err, "couldn't get last insert id for personal_records")
+		}
+		record.ID = lastID
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersonalRecordReplace(t *testing.T) {
+	key := ExerciseKey{ExerciseName: "チェストプレス"}
+	records := &PersonalRecords{
+		{RecordType: RecordTypeMaxWeight, Value: 40, Weight: 40, Reps: 8, SessionID: 23, SetID: 1, AchievedOn: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	err := db.Transaction("training_db", func(tx dbr.SessionRunner) error {
+		return NewPersonalRecord().ReplaceTx(tx, int64(42), key, records)
+	})
+	assert.NoError(t, err)
+
+	m, err := NewPersonalRecord().LoadByKey(int64(42), key)
+
+	if assert.NoError(t, err) && assert.Len(t, *m, 1) {
+		assert.Equal(t, RecordTypeMaxWeight, (*m)[0].RecordType)
+		assert.Equal(t, float64(40), (*m)[0].Value)
+		assert.Equal(t, "チェストプレス", (*m)[0].DisplayName)
+	}
+}
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+type (
+	// Program トレーニングプログラムのインターフェースを表す
+	Program interface {
+		LoadByUserID(userId int64) (*Programs, error)
+		LoadByName(userId int64, name string) (*ProgramImpl, error)
+		Load(id int64) (*ProgramImpl, error)
+		LoadByIDs(ids []int64) (*Programs, error)
+		CreateTx(tx dbr.SessionRunner, userId int64, name string, weeks int64) (*ProgramImpl, error)
+		DeleteTx(tx dbr.SessionRunner, id int64) (bool, error)
+	}
+
+	// ProgramImpl トレーニングプログラムを表す
+	ProgramImpl struct {
+		ID     int64  `db:"program_id" dbopt:"auto_increment"`
+		UserID int64  `db:"user_id"`
+		Name   string `db:"name"`
+		Weeks  int64  `db:"weeks"`
+	}
+
+	Programs []ProgramImpl
+)
+
+func NewPrograms() *Programs {
+	return &Programs{}
+}
+
+func NewProgram() Program {
+	return &ProgramImpl{}
+}
+
+// LoadByUserID ユーザーのプログラムを名前順に読み込み
+func (r *ProgramImpl) LoadByUserID(userId int64) (*Programs, error) {
+	return r.LoadByUserIDTx(db.GetSession("training_db"), userId)
+}
+
+// LoadByUserIDTx トランザクション内でユーザーのプログラムを読み込み
+func (r *ProgramImpl) LoadByUserIDTx(tx dbr.SessionRunner, userId int64) (*Programs, error) {
+	m := NewPrograms()
+	if _, err := tx.Select("*").From("programs").
+		Where("user_id = ?", userId).
+		OrderBy("name").
+		OrderBy("program_id").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load programs")
+	}
+	return m, nil
+}
+
+// LoadByName ユーザーの指定の名前のプログラムを読み込み。存在しない場合はIDが0
+func (r *ProgramImpl) LoadByName(userId int64, name string) (*ProgramImpl, error) {
+	return r.LoadByNameTx(db.GetSession("training_db"), userId, name)
+}
+
+// LoadByNameTx トランザクション内でユーザーの指定の名前のプログラムを読み込み
+func (r *ProgramImpl) LoadByNameTx(tx dbr.SessionRunner, userId int64, name string) (*ProgramImpl, error) {
+	m := &ProgramImpl{}
+	if _, err := tx.Select("*").From("programs").
+		Where("user_id = ? AND name = ?", userId, name).
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load programs")
+	}
+	return m, nil
+}
+
+// IDs プログラムIDの一覧を返却
+func (p *Programs) IDs() []int64 {
+	ids := make([]int64, 0, len(*p))
+	for _, program := range *p {
+		ids = append(ids, program.ID)
+	}
+	return ids
+}
+
+// Load 指定のIDを読み込み
+func (m *ProgramImpl) Load(id int64) (*ProgramImpl, error) {
+	return m.LoadTx(db.GetSession("training_db"), id)
+}
+
+// LoadTx トランザクション内で指定のIDを読み込み
+func (m *ProgramImpl) LoadTx(tx dbr.SessionRunner, id int64) (*ProgramImpl, error) {
+	r := &ProgramImpl{}
+	if _, err := tx.Select("*").From("programs").Where("program_id=?", id).Load(r); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load programs")
+	}
+	return r, nil
+}
+
+// LoadByIDs 複数のIDをまとめて読み込み
+func (m *ProgramImpl) LoadByIDs(ids []int64) (*Programs, error) {
+	return m.LoadByIDsTx(db.GetSession("training_db"), ids)
+}
+
+// LoadByIDsTx トランザクション内で複数のIDをまとめて読み込み
+func (m *ProgramImpl) LoadByIDsTx(tx dbr.SessionRunner, ids []int64) (*Programs, error) {
+	r := NewPrograms()
+	if len(ids) == 0 {
+		return r, nil
+	}
+
+	if _, err := tx.Select("*").From("programs").Where("program_id IN ?", ids).OrderBy("program_id").Load(r); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load programs")
+	}
+	return r, nil
+}
+
+// ByID IDごとのプログラムを返却
+func (p *Programs) ByID() map[int64]*ProgramImpl {
+	byId := make(map[int64]*ProgramImpl, len(*p))
+	for i := range *p {
+		byId[(*p)[i].ID] = &(*p)[i]
+	}
+	return byId
+}
+
+// CreateTx トランザクション内で作成
+func (r *ProgramImpl) CreateTx(tx dbr.SessionRunner, userId int64, name string, weeks int64) (*ProgramImpl, error) {
+	m := &ProgramImpl{
+		UserID: userId,
+		Name:   name,
+		Weeks:  weeks,
+	}
+
+	res, err := tx.InsertInto("programs").
+		Columns("user_id", "name", "weeks").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create programs")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for programs")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// DeleteTx トランザクション内で削除
+func (r *ProgramImpl) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	res, err := tx.DeleteFrom("programs").Where("program_id=?", id).Exec()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't delete programs")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows == 1, nil
+}
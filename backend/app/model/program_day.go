@@ -0,0 +1,138 @@
+package model
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+// DaysPerWeek プログラムの1週の日数
+const DaysPerWeek = 7
+
+type (
+	// ProgramDay プログラムの予定の日のインターフェースを表す
+	ProgramDay interface {
+		LoadByProgramIDs(programIds []int64) (*ProgramDays, error)
+		CreateTx(tx dbr.SessionRunner, programId int64, week int64, day int64, name string) (*ProgramDayImpl, error)
+		DeleteByProgramIDTx(tx dbr.SessionRunner, programId int64) (int64, error)
+	}
+
+	// ProgramDayImpl プログラムの予定の日を表す
+	// Weekは1始まりの週、Dayは週の開始日から数えた1〜7日目
+	ProgramDayImpl struct {
+		ID        int64  `db:"program_day_id" dbopt:"auto_increment"`
+		ProgramID int64  `db:"program_id"`
+		Week      int64  `db:"week"`
+		Day       int64  `db:"day"`
+		Name      string `db:"name"`
+	}
+
+	ProgramDays []ProgramDayImpl
+)
+
+func NewProgramDays() *ProgramDays {
+	return &ProgramDays{}
+}
+
+func NewProgramDay() ProgramDay {
+	return &ProgramDayImpl{}
+}
+
+// LoadByProgramIDs 複数プログラムの予定の日を日付順に1クエリで読み込み
+func (r *ProgramDayImpl) LoadByProgramIDs(programIds []int64) (*ProgramDays, error) {
+	return r.LoadByProgramIDsTx(db.GetSession("training_db"), programIds)
+}
+
+// LoadByProgramIDsTx トランザクション内で複数プログラムの予定の日を読み込み
+func (r *ProgramDayImpl) LoadByProgramIDsTx(tx dbr.SessionRunner, programIds []int64) (*ProgramDays, error) {
+	m := NewProgramDays()
+	if len(programIds) == 0 {
+		return m, nil
+	}
+
+	if _, err := tx.Select("*").From("program_days").
+		Where("program_id IN ?", programIds).
+		OrderBy("program_id").
+		OrderBy("week").
+		OrderBy("day").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load program_days")
+	}
+	return m, nil
+}
+
+// IDs 予定の日のIDの一覧を返却
+func (d *ProgramDays) IDs() []int64 {
+	ids := make([]int64, 0, len(*d))
+	for _, day := range *d {
+		ids = append(ids, day.ID)
+	}
+	return ids
+}
+
+// GroupByProgramID プログラムIDごとに予定の日をまとめる
+func (d *ProgramDays) GroupByProgramID() map[int64]*ProgramDays {
+	grouped := make(map[int64]*ProgramDays)
+	for _, day := range *d {
+		if _, ok := grouped[day.ProgramID]; !ok {
+			grouped[day.ProgramID] = NewProgramDays()
+		}
+		*grouped[day.ProgramID] = append(*grouped[day.ProgramID], day)
+	}
+	return grouped
+}
+
+// Find 指定の週・日の予定を返却。予定がない休養日はnil
+func (d *ProgramDays) Find(week int64, day int64) *ProgramDayImpl {
+	for i := range *d {
+		if (*d)[i].Week == week && (*d)[i].Day == day {
+			return &(*d)[i]
+		}
+	}
+	return nil
+}
+
+// Offset 開始日から数えた日数
+func (m *ProgramDayImpl) Offset() int64 {
+	return (m.Week-1)*DaysPerWeek + m.Day - 1
+}
+
+// CreateTx トランザクション内で作成
+func (r *ProgramDayImpl) CreateTx(tx dbr.SessionRunner, programId int64, week int64, day int64, name string) (*ProgramDayImpl, error) {
+	m := &ProgramDayImpl{
+		ProgramID: programId,
+		Week:      week,
+		Day:       day,
+		Name:      name,
+	}
+
+	res, err := tx.InsertInto("program_days").
+		Columns("program_id", "week", "day", "name").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create program_days")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for program_days")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// DeleteByProgramIDTx トランザクション内でプログラムの予定の日を削除
+func (r *ProgramDayImpl) DeleteByProgramIDTx(tx dbr.SessionRunner, programId int64) (int64, error) {
+	res, err := tx.DeleteFrom("program_days").Where("program_id=?", programId).Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't delete program_days")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+type (
+	// ProgramEnrollment プログラムへの参加のインターフェースを表す
+	ProgramEnrollment interface {
+		LoadByUserID(userId int64) (*ProgramEnrollments, error)
+		LoadByProgramID(programId int64) (*ProgramEnrollments, error)
+		Load(id int64) (*ProgramEnrollmentImpl, error)
+		CreateTx(tx dbr.SessionRunner, userId int64, programId int64, startDate time.Time) (*ProgramEnrollmentImpl, error)
+		DeleteTx(tx dbr.SessionRunner, id int64) (bool, error)
+	}
+
+	// ProgramEnrollmentImpl プログラムへの参加を表す。StartDateを1週目の1日目とする
+	ProgramEnrollmentImpl struct {
+		ID        int64     `db:"enrollment_id" dbopt:"auto_increment"`
+		UserID    int64     `db:"user_id"`
+		ProgramID int64     `db:"program_id"`
+		StartDate time.Time `db:"start_date"`
+	}
+
+	ProgramEnrollments []ProgramEnrollmentImpl
+)
+
+func NewProgramEnrollments() *ProgramEnrollments {
+	return &ProgramEnrollments{}
+}
+
+func NewProgramEnrollment() ProgramEnrollment {
+	return &ProgramEnrollmentImpl{}
+}
+
+// LoadByUserID ユーザーの参加を開始日の新しい順に読み込み
+func (r *ProgramEnrollmentImpl) LoadByUserID(userId int64) (*ProgramEnrollments, error) {
+	return r.LoadByUserIDTx(db.GetSession("training_db"), userId)
+}
+
+// LoadByUserIDTx トランザクション内でユーザーの参加を読み込み
+func (r *ProgramEnrollmentImpl) LoadByUserIDTx(tx dbr.SessionRunner, userId int64) (*ProgramEnrollments, error) {
+	m := NewProgramEnrollments()
+	if _, err := tx.Select("*").From("program_enrollments").
+		Where("user_id = ?", userId).
+		OrderDesc("start_date").
+		OrderDesc("enrollment_id").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load program_enrollments")
+	}
+	return m, nil
+}
+
+// LoadByProgramID プログラムへの参加を読み込み
+func (r *ProgramEnrollmentImpl) LoadByProgramID(programId int64) (*ProgramEnrollments, error) {
+	return r.LoadByProgramIDTx(db.GetSession("training_db"), programId)
+}
+
+// LoadByProgramIDTx トランザクション内でプログラムへの参加を読み込み
+func (r *ProgramEnrollmentImpl) LoadByProgramIDTx(tx dbr.SessionRunner, programId int64) (*ProgramEnrollments, error) {
+	m := NewProgramEnrollments()
+	if _, err := tx.Select("*").From("program_enrollments").
+		Where("program_id = ?", programId).
+		OrderBy("enrollment_id").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load program_enrollments")
+	}
+	return m, nil
+}
+
+// IDs 参加IDの一覧を返却
+func (e *ProgramEnrollments) IDs() []int64 {
+	ids := make([]int64, 0, len(*e))
+	for _, enrollment := range *e {
+		ids = append(ids, enrollment.ID)
+	}
+	return ids
+}
+
+// ProgramIDs 参加したプログラムの重複のないIDの一覧を返却
+func (e *ProgramEnrollments) ProgramIDs() []int64 {
+	ids := make([]int64, 0, len(*e))
+	seen := map[int64]bool{}
+	for _, enrollment := range *e {
+		if !seen[enrollment.ProgramID] {
+			seen[enrollment.ProgramID] = true
+			ids = append(ids, enrollment.ProgramID)
+		}
+	}
+	return ids
+}
+
+// Load 指定のIDを読み込み
+func (m *ProgramEnrollmentImpl) Load(id int64) (*ProgramEnrollmentImpl, error) {
+	return m.LoadTx(db.GetSession("training_db"), id)
+}
+
+// LoadTx トランザクション内で指定のIDを読み込み
+func (m *ProgramEnrollmentImpl) LoadTx(tx dbr.SessionRunner, id int64) (*ProgramEnrollmentImpl, error) {
+	r := &ProgramEnrollmentImpl{}
+	if _, err := tx.Select("*").From("program_enrollments").Where("enrollment_id=?", id).Load(r); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load program_enrollments")
+	}
+	return r, nil
+}
+
+// CreateTx トランザクション内で作成
+func (r *ProgramEnrollmentImpl) CreateTx(tx dbr.SessionRunner, userId int64, programId int64, startDate time.Time) (*ProgramEnrollmentImpl, error) {
+	m := &ProgramEnrollmentImpl{
+		UserID:    userId,
+		ProgramID: programId,
+		StartDate: startDate,
+	}
+
+	res, err := tx.InsertInto("program_enrollments").
+		Columns("user_id", "program_id", "start_date").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create program_enrollments")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for program_enrollments")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// DeleteTx トランザクション内で削除
+func (r *ProgramEnrollmentImpl) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	res, err := tx.DeleteFrom("program_enrollments").Where("enrollment_id=?", id).Exec()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't delete program_enrollments")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows == 1, nil
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+type (
+	// ProgramExercise プログラムの予定の日の種目のインターフェースを表す
+	ProgramExercise interface {
+		LoadByProgramDayIDs(programDayIds []int64) (*ProgramExercises, error)
+		CreateTx(tx dbr.SessionRunner, programDayId int64, position int64, exerciseName string, catalogId int64, modality string) (*ProgramExerciseImpl, error)
+		DeleteByProgramIDTx(tx dbr.SessionRunner, programId int64) (int64, error)
+		DetachCatalogTx(tx dbr.SessionRunner, catalogId int64) (int64, error)
+	}
+
+	// ProgramExerciseImpl プログラムの予定の日の種目を表す
+	ProgramExerciseImpl struct {
+		ID           int64         `db:"program_exercise_id" dbopt:"auto_increment"`
+		ProgramDayID int64         `db:"program_day_id"`
+		Position     int64         `db:"position"`
+		CatalogID    dbr.NullInt64 `db:"catalog_id"`
+		ExerciseName string        `db:"exercise_name"`
+		Modality     string        `db:"modality"`
+	}
+
+	ProgramExercises []ProgramExerciseImpl
+)
+
+func NewProgramExercises() *ProgramExercises {
+	return &ProgramExercises{}
+}
+
+func NewProgramExercise() ProgramExercise {
+	return &ProgramExerciseImpl{}
+}
+
+// LoadByProgramDayIDs 複数の予定の日の種目を並び順に1クエリで読み込み
+func (r *ProgramExerciseImpl) LoadByProgramDayIDs(programDayIds []int64) (*ProgramExercises, error) {
+	return r.LoadByProgramDayIDsTx(db.GetSession("training_db"), programDayIds)
+}
+
+// LoadByProgramDayIDsTx トランザクション内で複数の予定の日の種目を読み込み
+func (r *ProgramExerciseImpl) LoadByProgramDayIDsTx(tx dbr.SessionRunner, programDayIds []int64) (*ProgramExercises, error) {
+	m := NewProgramExercises()
+	if len(programDayIds) == 0 {
+		return m, nil
+	}
+
+	if _, err := tx.Select("*").From("program_exercises").
+		Where("program_day_id IN ?", programDayIds).
+		OrderBy("program_day_id").
+		OrderBy("position").
+		OrderBy("program_exercise_id").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load program_exercises")
+	}
+	return m, nil
+}
+
+// Key 記録を集計する種目の単位を返却
+func (m *ProgramExerciseImpl) Key() ExerciseKey {
+	if m.CatalogID.Valid {
+		return ExerciseKey{CatalogID: m.CatalogID.Int64}
+	}
+	return ExerciseKey{ExerciseName: m.ExerciseName}
+}
+
+// IDs 予定の種目IDの一覧を返却
+func (e *ProgramExercises) IDs() []int64 {
+	ids := make([]int64, 0, len(*e))
+	for _, exercise := range *e {
+		ids = append(ids, exercise.ID)
+	}
+	return ids
+}
+
+// GroupByProgramDayID 予定の日のIDごとに種目をまとめる
+func (e *ProgramExercises) GroupByProgramDayID() map[int64]*ProgramExercises {
+	grouped := make(map[int64]*ProgramExercises)
+	for _, exercise := range *e {
+		if _, ok := grouped[exercise.ProgramDayID]; !ok {
+			grouped[exercise.ProgramDayID] = NewProgramExercises()
+		}
+		*grouped[exercise.ProgramDayID] = append(*grouped[exercise.ProgramDayID], exercise)
+	}
+	return grouped
+}
+
+// CreateTx トランザクション内で作成
+func (r *ProgramExerciseImpl) CreateTx(tx dbr.SessionRunner, programDayId int64, position int64, exerciseName string, catalogId int64, modality string) (*ProgramExerciseImpl, error) {
+	m := &ProgramExerciseImpl{
+		ProgramDayID: programDayId,
+		Position:     position,
+		ExerciseName: exerciseName,
+		Modality:     NormalizeModality(modality),
+	}
+	if catalogId != 0 {
+		m.CatalogID = dbr.NewNullInt64(catalogId)
+	}
+
+	res, err := tx.InsertInto("program_exercises").
+		Columns("program_day_id", "position", "catalog_id", "exercise_name", "modality").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create program_exercises")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for program_exercises")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// DeleteByProgramIDTx トランザクション内でプログラムの全ての予定の日の種目を削除
+func (r *ProgramExerciseImpl) DeleteByProgramIDTx(tx dbr.SessionRunner, programId int64) (int64, error) {
+	res, err := tx.DeleteFrom("program_exercises").
+		Where("program_day_id IN ?", tx.Select("program_day_id").From("program_days").Where("program_id=?", programId)).
+		Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't delete program_exercises")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
+
+// DetachCatalogTx トランザクション内で種目の名前を残したままカタログとの紐づけを外す
+func (r *ProgramExerciseImpl) DetachCatalogTx(tx dbr.SessionRunner, catalogId int64) (int64, error) {
+	res, err := tx.Update("program_exercises").Set("catalog_id", nil).Where("catalog_id=?", catalogId).Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't detach program_exercises")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+type (
+	// ProgramSet プログラムの予定のセットのインターフェースを表す
+	ProgramSet interface {
+		LoadByProgramExerciseIDs(programExerciseIds []int64) (*ProgramSets, error)
+		CreateTx(tx dbr.SessionRunner, programExerciseId int64, planned PlannedSet) (*ProgramSetImpl, error)
+		DeleteByProgramIDTx(tx dbr.SessionRunner, programId int64) (int64, error)
+	}
+
+	// ProgramSetImpl プログラムの予定のセットを表す
+	ProgramSetImpl struct {
+		ID                int64 `db:"program_set_id" dbopt:"auto_increment"`
+		ProgramExerciseID int64 `db:"program_exercise_id"`
+		PlannedSet
+	}
+
+	ProgramSets []ProgramSetImpl
+
+	// PlannedSet 予定のセットの回数と、トレーニングマックスに対する重量の割合(%)を表す
+	// 割合がない場合は重量を指定しないセットとする
+	PlannedSet struct {
+		SetNumber  int64           `db:"set_number"`
+		SetType    string          `db:"set_type"`
+		Percentage dbr.NullFloat64 `db:"percentage"`
+		Reps       int64           `db:"reps"`
+	}
+)
+
+func NewProgramSets() *ProgramSets {
+	return &ProgramSets{}
+}
+
+func NewProgramSet() ProgramSet {
+	return &ProgramSetImpl{}
+}
+
+// LoadByProgramExerciseIDs 複数の予定の種目のセットを1クエリで読み込み
+func (r *ProgramSetImpl) LoadByProgramExerciseIDs(programExerciseIds []int64) (*ProgramSets, error) {
+	return r.LoadByProgramExerciseIDsTx(db.GetSession("training_db"), programExerciseIds)
+}
+
+// LoadByProgramExerciseIDsTx トランザクション内で複数の予定の種目のセットを読み込み
+func (r *ProgramSetImpl) LoadByProgramExerciseIDsTx(tx dbr.SessionRunner, programExerciseIds []int64) (*ProgramSets, error) {
+	m := NewProgramSets()
+	if len(programExerciseIds) == 0 {
+		return m, nil
+	}
+
+	if _, err := tx.Select("*").From("program_sets").
+		Where("program_exercise_id IN ?", programExerciseIds).
+		OrderBy("program_exercise_id").
+		OrderBy("set_number").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load program_sets")
+	}
+	return m, nil
+}
+
+// GroupByProgramExerciseID 予定の種目IDごとにセットをまとめる
+func (s *ProgramSets) GroupByProgramExerciseID() map[int64]*ProgramSets {
+	grouped := make(map[int64]*ProgramSets)
+	for _, set := range *s {
+		if _, ok := grouped[set.ProgramExerciseID]; !ok {
+			grouped[set.ProgramExerciseID] = NewProgramSets()
+		}
+		*grouped[set.ProgramExerciseID] = append(*grouped[set.ProgramExerciseID], set)
+	}
+	return grouped
+}
+
+// Planned 予定のセットの一覧を返却
+func (s *ProgramSets) Planned() []PlannedSet {
+	planned := make([]PlannedSet, 0, len(*s))
+	for _, set := range *s {
+		planned = append(planned, set.PlannedSet)
+	}
+	return planned
+}
+
+// CreateTx トランザクション内で作成。セットの種類が空の場合はworkingとする
+func (r *ProgramSetImpl) CreateTx(tx dbr.SessionRunner, programExerciseId int64, planned PlannedSet) (*ProgramSetImpl, error) {
+	if planned.SetType == "" {
+		planned.SetType = SetTypeWorking
+	}
+	m := &ProgramSetImpl{
+		ProgramExerciseID: programExerciseId,
+		PlannedSet:        planned,
+	}
+
+	res, err := tx.InsertInto("program_sets").
+		Columns("program_exercise_id", "set_number", "set_type", "percentage", "reps").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create program_sets")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for program_sets")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// DeleteByProgramIDTx トランザクション内でプログラムの全ての予定のセットを削除
+func (r *ProgramSetImpl) DeleteByProgramIDTx(tx dbr.SessionRunner, programId int64) (int64, error) {
+	res, err := tx.DeleteFrom("program_sets").
+		Where("program_exercise_id IN ?", tx.Select("e.program_exercise_id").
+			From(dbr.I("program_exercises").As("e")).
+			Join(dbr.I("program_days").As("d"), "d.program_day_id = e.program_day_id").
+			Where("d.program_id=?", programId)).
+		Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't delete program_sets")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgramCreate(t *testing.T) {
+	name := fmt.Sprintf("5/3/1 %d", time.Now().UnixNano())
+	var program *ProgramImpl
+	err := db.Transaction("training_db", func(tx dbr.SessionRunner) error {
+		var err error
+		program, err = NewProgram().CreateTx(tx, int64(42), name, int64(4))
+		if err != nil {
+			return err
+		}
+		day, err := NewProgramDay().CreateTx(tx, program.ID, int64(1), int64(1), "スクワットの日")
+		if err != nil {
+			return err
+		}
+		exercise, err := NewProgramExercise().CreateTx(tx, day.ID, int64(1), "スクワット", int64(0), "")
+		if err != nil {
+			return err
+		}
+		_, err = NewProgramSet().CreateTx(tx, exercise.ID, PlannedSet{SetNumber: 1, Percentage: dbr.NewNullFloat64(65), Reps: 5})
+		return err
+	})
+	assert.NoError(t, err)
+
+	m, err := NewProgram().LoadByName(int64(42), name)
+	if assert.NoError(t, err) {
+		assert.Equal(t, program.ID, m.ID)
+		assert.Equal(t, int64(4), m.Weeks)
+	}
+
+	days, err := NewProgramDay().LoadByProgramIDs([]int64{program.ID})
+	if assert.NoError(t, err) && assert.Len(t, *days, 1) {
+		assert.NotNil(t, days.Find(1, 1))
+		assert.Nil(t, days.Find(1, 2))
+
+		exercises, err := NewProgramExercise().LoadByProgramDayIDs(days.IDs())
+		if assert.NoError(t, err) && assert.Len(t, *exercises, 1) {
+			sets, err := NewProgramSet().LoadByProgramExerciseIDs(exercises.IDs())
+			if assert.NoError(t, err) && assert.Len(t, *sets, 1) {
+				// セットの種類の指定がない場合はworkingとする
+				assert.Equal(t, SetTypeWorking, (*sets)[0].SetType)
+				assert.Equal(t, float64(65), (*sets)[0].Percentage.Float64)
+			}
+		}
+	}
+}
+
+func TestProgramEnrollmentCreate(t *testing.T) {
+	name := fmt.Sprintf("GZCLP %d", time.Now().UnixNano())
+	startDate := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	var enrollment *ProgramEnrollmentImpl
+	err := db.Transaction("training_db", func(tx dbr.SessionRunner) error {
+		program, err := NewProgram().CreateTx(tx, int64(42), name, int64(1))
+		if err != nil {
+			return err
+		}
+		enrollment, err = NewProgramEnrollment().CreateTx(tx, int64(42), program.ID, startDate)
+		if err != nil {
+			return err
+		}
+		_, err = NewTrainingMax().CreateTx(tx, enrollment.ID, ExerciseKey{CatalogID: 1}, 100, "kg")
+		return err
+	})
+	assert.NoError(t, err)
+
+	m, err := NewProgramEnrollment().Load(enrollment.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, startDate, m.StartDate)
+	}
+
+	maxes, err := NewTrainingMax().LoadByEnrollmentID(enrollment.ID)
+	if assert.NoError(t, err) && assert.Len(t, *maxes, 1) {
+		tm, ok := maxes.ByKey()[ExerciseKey{CatalogID: 1}]
+		assert.True(t, ok)
+		assert.Equal(t, float64(100), tm.Weight)
+	}
+
+	grouped, err := NewTrainingMax().LoadByEnrollmentIDs([]int64{enrollment.ID})
+	if assert.NoError(t, err) {
+		assert.Len(t, *grouped.GroupByEnrollmentID()[enrollment.ID], 1)
+	}
+	programs, err := NewProgram().LoadByIDs([]int64{m.ProgramID})
+	if assert.NoError(t, err) && assert.Len(t, *programs, 1) {
+		assert.Equal(t, name, (*programs)[0].Name)
+	}
+}
+
+func TestProgramDayOffset(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, int64(0), (&ProgramDayImpl{Week: 1, Day: 1}).Offset())
+	assert.Equal(t, int64(9), (&ProgramDayImpl{Week: 2, Day: 3}).Offset())
+}
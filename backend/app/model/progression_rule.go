@@ -0,0 +1,187 @@
+package model
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+// 重量・回数の進め方
+const (
+	// ProgressionLinear 目標の回数をこなせたら毎回一定の重量を上げる
+	ProgressionLinear = "linear"
+	// ProgressionDouble 回数の範囲の上限に届くまで回数を増やし、届いたら重量を上げて下限の回数に戻す
+	ProgressionDouble = "double"
+	// ProgressionRPE 前回のRPEから推定した1RMをもとに目標のRPEになる重量を求める
+	ProgressionRPE = "rpe"
+)
+
+// ProgressionStrategies 進め方の一覧
+var ProgressionStrategies = []string{ProgressionLinear, ProgressionDouble, ProgressionRPE}
+
+type (
+	// ProgressionRule 種目ごとの進め方のインターフェースを表す
+	ProgressionRule interface {
+		LoadByUserID(userId int64) (*ProgressionRules, error)
+		LoadByKey(userId int64, key ExerciseKey) (*ProgressionRuleImpl, error)
+		Load(id int64) (*ProgressionRuleImpl, error)
+		ReplaceTx(tx dbr.SessionRunner, rule ProgressionRuleImpl) (*ProgressionRuleImpl, error)
+		Delete(id int64) (bool, error)
+		DetachCatalogTx(tx dbr.SessionRunner, catalogId int64, exerciseName string) (int64, error)
+	}
+
+	// ProgressionRuleImpl 種目ごとの進め方を表す
+	// 重量の増分はkgで保存し、Unitに入力時の単位を記録する。提案する重量もこの単位のプレートの刻みに丸める
+	ProgressionRuleImpl struct {
+		ID               int64           `db:"rule_id" dbopt:"auto_increment"`
+		UserID           int64           `db:"user_id"`
+		CatalogID        int64           `db:"catalog_id"`
+		ExerciseName     string          `db:"exercise_name"`
+		Strategy         string          `db:"strategy"`
+		Increment        float64         `db:"increment"`
+		Unit             string          `db:"unit"`
+		Sets             int64           `db:"sets"`
+		MinReps          int64           `db:"min_reps"`
+		MaxReps          int64           `db:"max_reps"`
+		TargetRPE        dbr.NullFloat64 `db:"target_rpe"`
+		DeloadAfter      int64           `db:"deload_after"`
+		DeloadPercentage float64         `db:"deload_percentage"`
+		// 一覧表示用の種目名。カタログに紐づく場合はカタログの日本語名
+		DisplayName string `db:"display_name"`
+	}
+
+	ProgressionRules []ProgressionRuleImpl
+)
+
+func NewProgressionRules() *ProgressionRules {
+	return &ProgressionRules{}
+}
+
+func NewProgressionRule() ProgressionRule {
+	return &ProgressionRuleImpl{}
+}
+
+// LoadByUserID ユーザーの進め方を種目名順に読み込み
+func (r *ProgressionRuleImpl) LoadByUserID(userId int64) (*ProgressionRules, error) {
+	return r.LoadByUserIDTx(db.GetSession("training_db"), userId)
+}
+
+// LoadByUserIDTx トランザクション内でユーザーの進め方を読み込み
+func (r *ProgressionRuleImpl) LoadByUserIDTx(tx dbr.SessionRunner, userId int64) (*ProgressionRules, error) {
+	m := NewProgressionRules()
+	if _, err := r.selectRules(tx).
+		Where("p.user_id = ?", userId).
+		OrderBy("display_name").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load progression_rules")
+	}
+	return m, nil
+}
+
+// LoadByKey ユーザーの指定した種目の進め方を読み込み。設定がない場合はIDが0
+func (r *ProgressionRuleImpl) LoadByKey(userId int64, key ExerciseKey) (*ProgressionRuleImpl, error) {
+	return r.LoadByKeyTx(db.GetSession("training_db"), userId, key)
+}
+
+// LoadByKeyTx トランザクション内でユーザーの指定した種目の進め方を読み込み
+func (r *ProgressionRuleImpl) LoadByKeyTx(tx dbr.SessionRunner, userId int64, key ExerciseKey) (*ProgressionRuleImpl, error) {
+	m := &ProgressionRuleImpl{}
+	if _, err := r.selectRules(tx).
+		Where("p.user_id = ? AND p.catalog_id = ? AND p.exercise_name = ?", userId, key.CatalogID, key.ExerciseName).
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load progression_rules")
+	}
+	return m, nil
+}
+
+// Load IDで読み込み。存在しない場合はIDが0
+func (r *ProgressionRuleImpl) Load(id int64) (*ProgressionRuleImpl, error) {
+	return r.LoadTx(db.GetSession("training_db"), id)
+}
+
+// LoadTx トランザクション内でIDで読み込み
+func (r *ProgressionRuleImpl) LoadTx(tx dbr.SessionRunner, id int64) (*ProgressionRuleImpl, error) {
+	m := &ProgressionRuleImpl{}
+	if _, err := r.selectRules(tx).Where("p.rule_id = ?", id).Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load progression_rules")
+	}
+	return m, nil
+}
+
+func (r *ProgressionRuleImpl) selectRules(tx dbr.SessionRunner) *dbr.SelectStmt {
+	return tx.Select("p.*", "COALESCE(c.name_ja, p.exercise_name) AS display_name").
+		From(dbr.I("progression_rules").As("p")).
+		LeftJoin(dbr.I("exercise_catalog").As("c"), "c.catalog_id = p.catalog_id")
+}
+
+// Key 記録を集計する種目の単位を返却
+func (m *ProgressionRuleImpl) Key() ExerciseKey {
+	return ExerciseKey{CatalogID: m.CatalogID, ExerciseName: m.ExerciseName}
+}
+
+// ReplaceTx トランザクション内で同じユーザー・種目の進め方を置き換え
+func (r *ProgressionRuleImpl) ReplaceTx(tx dbr.SessionRunner, rule ProgressionRuleImpl) (*ProgressionRuleImpl, error) {
+	if _, err := tx.DeleteFrom("progression_rules").
+		Where("user_id = ? AND catalog_id = ? AND exercise_name = ?", rule.UserID, rule.CatalogID, rule.ExerciseName).
+		Exec(); err != nil {
+		return nil, errors.Wrapf(err, "couldn't delete progression_rules")
+	}
+
+	m := &rule
+	res, err := tx.InsertInto("progression_rules").
+		Columns("user_id", "catalog_id", "exercise_name", "strategy", "increment", "unit", "sets", "min_reps", "max_reps", "target_rpe", "deload_after", "deload_percentage").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create progression_rules")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for progression_rules")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// Delete 削除
+func (r *ProgressionRuleImpl) Delete(id int64) (bool, error) {
+	return r.DeleteTx(db.GetSession("training_db"), id)
+}
+
+// DeleteTx トランザクション内で削除
+func (r *ProgressionRuleImpl) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	res, err := tx.DeleteFrom("progression_rules").Where("rule_id=?", id).Exec()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't delete progression_rules")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows == 1, nil
+}
+
+// DetachCatalogTx トランザクション内でカタログの種目の進め方を、指定の名前の種目の進め方に付け替え
+// 同じ名前の種目の進め方が既にあるユーザーは、そちらを残してカタログの種目の進め方を削除する
+func (r *ProgressionRuleImpl) DetachCatalogTx(tx dbr.SessionRunner, catalogId int64, exerciseName string) (int64, error) {
+	res, err := tx.UpdateBySql(
+		"UPDATE IGNORE progression_rules SET catalog_id = 0, exercise_name = ? WHERE catalog_id = ?",
+		exerciseName, catalogId).
+		Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't detach progression_rules")
+	}
+	if _, err := tx.DeleteFrom("progression_rules").Where("catalog_id=?", catalogId).Exec(); err != nil {
+		return 0, errors.Wrapf(err, "couldn't delete progression_rules")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressionRuleReplace(t *testing.T) {
+	key := ExerciseKey{ExerciseName: "進め方テスト"}
+	for _, increment := range []float64{2.5, 5} {
+		increment := increment
+		err := db.Transaction("training_db", func(tx dbr.SessionRunner) error {
+			_, err := NewProgressionRule().ReplaceTx(tx, ProgressionRuleImpl{
+				UserID: 1, ExerciseName: key.ExerciseName, Strategy: ProgressionLinear, Increment: increment, Unit: "kg", Sets: 3, MinReps: 5, MaxReps: 5, DeloadAfter: 3, DeloadPercentage: 10,
+			})
+			return err
+		})
+		assert.NoError(t, err)
+	}
+
+	// 同じ種目の進め方は置き換える
+	m, err := NewProgressionRule().LoadByKey(1, key)
+	if assert.NoError(t, err) {
+		assert.NotZero(t, m.ID)
+		assert.Equal(t, float64(5), m.Increment)
+		assert.Equal(t, key.ExerciseName, m.DisplayName)
+	}
+
+	ok, err := NewProgressionRule().Delete(m.ID)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+type (
+	// Recommendation 提案したトレーニングメニューのインターフェースを表す
+	Recommendation interface {
+		Load(id int64) (*RecommendationImpl, error)
+		Create(userId int64, menu string) (*RecommendationImpl, error)
+	}
+
+	// RecommendationImpl 提案したトレーニングメニューを表す。Menuは検証済みのメニューのJSON
+	RecommendationImpl struct {
+		ID        int64     `db:"recommendation_id" dbopt:"auto_increment"`
+		UserID    int64     `db:"user_id"`
+		Menu      string    `db:"menu"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+)
+
+func NewRecommendation() Recommendation {
+	return &RecommendationImpl{}
+}
+
+// Load 指定のIDを読み込み。存在しない場合はIDが0
+func (r *RecommendationImpl) Load(id int64) (*RecommendationImpl, error) {
+	return r.LoadTx(db.GetSession("training_db"), id)
+}
+
+// LoadTx トランザクション内で指定のIDを読み込み
+func (r *RecommendationImpl) LoadTx(tx dbr.SessionRunner, id int64) (*RecommendationImpl, error) {
+	m := &RecommendationImpl{}
+	if _, err := tx.Select("*").From("recommendations").Where("recommendation_id = ?", id).Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load recommendations")
+	}
+	return m, nil
+}
+
+// Create 作成
+func (r *RecommendationImpl) Create(userId int64, menu string) (*RecommendationImpl, error) {
+	return r.CreateTx(db.GetSession("training_db"), userId, menu)
+}
+
+// CreateTx トランザクション内で作成
+func (r *RecommendationImpl) CreateTx(tx dbr.SessionRunner, userId int64, menu string) (*RecommendationImpl, error) {
+	m := &RecommendationImpl{
+		UserID:    userId,
+		Menu:      menu,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	res, err := tx.InsertInto("recommendations").
+		Columns("user_id", "menu", "created_at").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create recommendations")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for recommendations")
+	}
+	m.ID = lastID
+	return m, nil
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecommendationCreate(t *testing.T) {
+	menu := `{"title":"胸の日","estimated_minutes":30,"exercises":[{"name":"ベンチプレス","sets":3,"reps":8,"rest_seconds":120,"estimated_minutes":15}]}`
+	r, err := NewRecommendation().Create(int64(42), menu)
+	assert.NoError(t, err)
+
+	m, err := NewRecommendation().Load(r.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(42), m.UserID)
+		assert.Equal(t, menu, m.Menu)
+	}
+
+	m, err = NewRecommendation().Load(int64(-1))
+	if assert.NoError(t, err) {
+		assert.Zero(t, m.ID)
+	}
+}
@@ -1,30 +1,125 @@
 package model
 
 import (
+	"context"
+	"time"
+
 	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
 	"github.com/gocraft/dbr/v2"
 	"github.com/pkg/errors"
 )
 
+// セットの種類
+const (
+	SetTypeWarmup  = "warmup"
+	SetTypeWorking = "working"
+	SetTypeDrop    = "drop"
+	SetTypeFailure = "failure"
+	SetTypeAMRAP   = "amrap"
+)
+
+// SetTypes セットの種類の一覧
+var SetTypes = []string{SetTypeWarmup, SetTypeWorking, SetTypeDrop, SetTypeFailure, SetTypeAMRAP}
+
 type (
 	// Set ワークアウトのインターフェースを表す
 	Set interface {
 		LoadByExerciseID(exerciseId int64) (*Sets, error)
+		LoadByExerciseIDs(exerciseIds []int64) (*Sets, error)
+		LoadHistory(filter SetHistoryFilter) (*SetHistories, error)
+		LoadHistoryTx(tx dbr.SessionRunner, filter SetHistoryFilter) (*SetHistories, error)
+		EachExport(ctx context.Context, filter SetHistoryFilter, fn func(*SetExport) error) error
 		Load(id int64) (*SetImpl, error)
-		Update(attrs map[string]interface{}) (bool, error)
-		Create(exerciseID int64, setNumber int64, weight float64, reps int64) (*SetImpl, error)
+		Update(id int64, attrs map[string]interface{}) (bool, error)
+		UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error)
+		Create(exerciseID int64, setNumber int64, weight float64, reps int64, unit string, detail SetDetail) (*SetImpl, error)
+		CreateTx(tx dbr.SessionRunner, exerciseID int64, setNumber int64, weight float64, reps int64, unit string, detail SetDetail) (*SetImpl, error)
+		Delete(id int64) (bool, error)
+		DeleteTx(tx dbr.SessionRunner, id int64) (bool, error)
+		DeleteByExerciseIDTx(tx dbr.SessionRunner, exerciseId int64) (int64, error)
+		DeleteBySessionIDTx(tx dbr.SessionRunner, sessionId int64) (int64, error)
 	}
 
 	// SetImpl ワークアウトを表す
+	// 重量はkgで保存し、Unitに入力時の単位を記録する
 	SetImpl struct {
 		ID         int64   `db:"set_id" dbopt:"auto_increment"`
 		ExerciseID int64   `db:"exercise_id"`
 		SetNumber  int64   `db:"set_number"`
 		Weight     float64 `db:"weight"`
+		Unit       string  `db:"unit"`
 		Reps       int64   `db:"reps"`
+		SetDetail
+	}
+
+	// SetDetail セットの種類や強度、時間・距離などの記録を表す。RPEとRIRはどちらか一方のみを記録する
+	SetDetail struct {
+		SetType         string          `db:"set_type"`
+		DurationSeconds dbr.NullInt64   `db:"duration_seconds"`
+		DistanceMeters  dbr.NullFloat64 `db:"distance_meters"`
+		RPE             dbr.NullFloat64 `db:"rpe"`
+		RIR             dbr.NullInt64   `db:"rir"`
+		RestSeconds     dbr.NullInt64   `db:"rest_seconds"`
+		Tempo           string          `db:"tempo"`
+		PerformedAt     dbr.NullTime    `db:"performed_at"`
+		Notes           string          `db:"notes"`
 	}
 
 	Sets []SetImpl
+
+	// SetHistory セッションの日付・エクササイズの種目とあわせて読み込んだセットを表す
+	SetHistory struct {
+		SetID        int64     `db:"set_id"`
+		ExerciseID   int64     `db:"exercise_id"`
+		SessionID    int64     `db:"session_id"`
+		TrainingDate time.Time `db:"training_date"`
+		SetNumber    int64     `db:"set_number"`
+		Weight       float64   `db:"weight"`
+		Unit         string    `db:"unit"`
+		Reps         int64     `db:"reps"`
+		SetType      string    `db:"set_type"`
+		// 時間・距離は記録がない場合は0
+		DurationSeconds int64   `db:"duration_seconds"`
+		DistanceMeters  float64 `db:"distance_meters"`
+		// RPEは記録がない場合は0
+		RPE      float64 `db:"rpe"`
+		Modality string  `db:"modality"`
+		// カタログに紐づかない場合はCatalogIDが0、部位が空文字
+		CatalogID        int64  `db:"catalog_id"`
+		ExerciseName     string `db:"exercise_name"`
+		PrimaryMuscle    string `db:"primary_muscle"`
+		SecondaryMuscles string `db:"secondary_muscles"`
+	}
+
+	SetHistories []SetHistory
+
+	// SetExport エクスポートする1セット分の記録をセッション・エクササイズとあわせて表す
+	SetExport struct {
+		SessionID     int64
+		TrainingDate  time.Time
+		SessionStatus string
+		ExerciseID    int64
+		ExerciseName  string
+		// カタログに紐づかない場合は0
+		CatalogID int64
+		Modality  string
+		SetID     int64
+		SetNumber int64
+		Weight    float64
+		Unit      string
+		Reps      int64
+		SetDetail
+	}
+
+	// SetHistoryFilter ユーザーのセット履歴の検索条件を表す。Keyが空の場合は全種目を対象とする
+	// Statusを指定した場合はその状態のセッションのみを対象とする
+	SetHistoryFilter struct {
+		UserID int64
+		Key    ExerciseKey
+		From   time.Time
+		To     time.Time
+		Status string
+	}
 )
 
 func NewSets() *Sets {
@@ -55,6 +150,88 @@ func (r *SetImpl) LoadByExerciseIDTx(tx *dbr.Session, exerciseId int64) (*Sets,
 	return m, nil
 }
 
+// LoadByExerciseIDs 複数エクササイズのセットを1クエリで読み込み
+func (r *SetImpl) LoadByExerciseIDs(exerciseIds []int64) (*Sets, error) {
+	return r.LoadByExerciseIDsTx(db.GetSession("training_db"), exerciseIds)
+}
+
+// LoadByExerciseIDsTx トランザクション内で複数エクササイズのセットを読み込み
+func (r *SetImpl) LoadByExerciseIDsTx(tx dbr.SessionRunner, exerciseIds []int64) (*Sets, error) {
+	m := NewSets()
+	if len(exerciseIds) == 0 {
+		return m, nil
+	}
+
+	if _, err := tx.Select("*").From("sets").
+		Where("exercise_id IN ?", exerciseIds).
+		OrderBy("exercise_id").
+		OrderBy("set_number").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load sets")
+	}
+	return m, nil
+}
+
+// LoadHistory ユーザーのセットを日付順に読み込み
+func (r *SetImpl) LoadHistory(filter SetHistoryFilter) (*SetHistories, error) {
+	return r.LoadHistoryTx(db.GetSession("training_db"), filter)
+}
+
+// LoadHistoryTx トランザクション内でユーザーのセットを日付順に読み込み
+func (r *SetImpl) LoadHistoryTx(tx dbr.SessionRunner, filter SetHistoryFilter) (*SetHistories, error) {
+	m := &SetHistories{}
+
+	builder := tx.Select(
+		"s.set_id", "s.exercise_id", "e.session_id", "ws.training_date", "s.set_number", "s.weight", "s.unit", "s.reps", "s.set_type",
+		"COALESCE(s.duration_seconds, 0) AS duration_seconds", "COALESCE(s.distance_meters, 0) AS distance_meters",
+		"COALESCE(s.rpe, 0) AS rpe", "e.modality",
+		"COALESCE(e.catalog_id, 0) AS catalog_id", "e.exercise_name",
+		"COALESCE(c.primary_muscle, '') AS primary_muscle", "COALESCE(c.secondary_muscles, '') AS secondary_muscles",
+	).
+		From(dbr.I("sets").As("s")).
+		Join(dbr.I("exercises").As("e"), "e.exercise_id = s.exercise_id").
+		Join(dbr.I("workout_sessions").As("ws"), "ws.session_id = e.session_id").
+		LeftJoin(dbr.I("exercise_catalog").As("c"), "c.catalog_id = e.catalog_id").
+		Where("ws.user_id = ?", filter.UserID)
+
+	if filter.Key.CatalogID != 0 {
+		builder = builder.Where("e.catalog_id = ?", filter.Key.CatalogID)
+	} else if filter.Key.ExerciseName != "" {
+		builder = builder.Where("e.catalog_id IS NULL AND e.exercise_name = ?", filter.Key.ExerciseName)
+	}
+	if !filter.From.IsZero() {
+		builder = builder.Where("ws.training_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		builder = builder.Where("ws.training_date <= ?", filter.To)
+	}
+	if filter.Status != "" {
+		builder = builder.Where("ws.status = ?", filter.Status)
+	}
+
+	if _, err := builder.
+		OrderBy("ws.training_date").
+		OrderBy("e.session_id").
+		OrderBy("s.set_number").
+		OrderBy("s.set_id").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load set history")
+	}
+	return m, nil
+}
+
+// GroupByExerciseID エクササイズIDごとにセットをまとめる
+func (s *Sets) GroupByExerciseID() map[int64]*Sets {
+	grouped := make(map[int64]*Sets)
+	for _, set := range *s {
+		if _, ok := grouped[set.ExerciseID]; !ok {
+			grouped[set.ExerciseID] = NewSets()
+		}
+		*grouped[set.ExerciseID] = append(*grouped[set.ExerciseID], set)
+	}
+	return grouped
+}
+
 // Load 指定のIDを読み込み
 func (m *SetImpl) Load(id int64) (*SetImpl, error) {
 	return m.LoadTx(db.GetSession("training_db"), id)
@@ -63,21 +240,22 @@ func (m *SetImpl) Load(id int64) (*SetImpl, error) {
 
 // LoadTx トランザクション内で指定のIDを読み込み
 func (m *SetImpl) LoadTx(tx dbr.SessionRunner, id int64) (*SetImpl, error) {
-	if _, err := tx.Select("*").From("sets").Where("set_id=?", id).Load(m); err != nil {
+	r := &SetImpl{}
+	if _, err := tx.Select("*").From("sets").Where("set_id=?", id).Load(r); err != nil {
 		return nil, errors.Wrapf(err, "couldn't load sets")
 	}
-	return m, nil
+	return r, nil
 }
 
 // Update 更新
-func (m *SetImpl) Update(attrs map[string]interface{}) (bool, error) {
-	return m.UpdateTx(db.GetSession("training_db"), attrs)
+func (r *SetImpl) Update(id int64, attrs map[string]interface{}) (bool, error) {
+	return r.UpdateTx(db.GetSession("training_db"), id, attrs)
 	// return false, nil
 }
 
 // UpdateTx トランザクション内で更新
-func (m *SetImpl) UpdateTx(tx dbr.SessionRunner, attrs map[string]interface{}) (bool, error) {
-	res, err := tx.Update("sets").SetMap(attrs).Where("set_id=?", m.ID).Exec()
+func (r *SetImpl) UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error) {
+	res, err := tx.Update("sets").SetMap(attrs).Where("set_id=?", id).Exec()
 	if err != nil {
 		return false, errors.Wrapf(err, "couldn't update sets")
 	}
@@ -90,23 +268,98 @@ func (m *SetImpl) UpdateTx(tx dbr.SessionRunner, attrs map[string]interface{}) (
 	return rows == 1, nil
 }
 
-// Create 作成
-func (r *SetImpl) Create(exerciseID int64, setNumber int64, weight float64, reps int64) (*SetImpl, error) {
-	return r.CreateTx(db.GetSession("training_db"), exerciseID, setNumber, weight, reps)
+// EachExport ユーザーのセットを日付順に1行ずつ読み込み、fnに渡す
+// 全件をメモリに載せないようDBから順に読み出す。ctxがキャンセルされた場合やfnがエラーを返した場合は中断する
+func (r *SetImpl) EachExport(ctx context.Context, filter SetHistoryFilter, fn func(*SetExport) error) error {
+	return r.EachExportTx(ctx, db.GetSession("training_db"), filter, fn)
+}
+
+// EachExportTx トランザクション内でユーザーのセットを日付順に1行ずつ読み込み、fnに渡す
+func (r *SetImpl) EachExportTx(ctx context.Context, tx dbr.SessionRunner, filter SetHistoryFilter, fn func(*SetExport) error) error {
+	builder := tx.Select(
+		"e.session_id", "ws.training_date", "ws.status", "s.exercise_id", "e.exercise_name", "COALESCE(e.catalog_id, 0)", "e.modality",
+		"s.set_id", "s.set_number", "s.set_type", "s.weight", "s.unit", "s.reps",
+		"s.duration_seconds", "s.distance_meters", "s.rpe", "s.rir", "s.rest_seconds", "s.tempo", "s.performed_at", "s.notes",
+	).
+		From(dbr.I("sets").As("s")).
+		Join(dbr.I("exercises").As("e"), "e.exercise_id = s.exercise_id").
+		Join(dbr.I("workout_sessions").As("ws"), "ws.session_id = e.session_id").
+		Where("ws.user_id = ?", filter.UserID)
+
+	if filter.Key.CatalogID != 0 {
+		builder = builder.Where("e.catalog_id = ?", filter.Key.CatalogID)
+	} else if filter.Key.ExerciseName != "" {
+		builder = builder.Where("e.catalog_id IS NULL AND e.exercise_name = ?", filter.Key.ExerciseName)
+	}
+	if !filter.From.IsZero() {
+		builder = builder.Where("ws.training_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		builder = builder.Where("ws.training_date <= ?", filter.To)
+	}
+	if filter.Status != "" {
+		builder = builder.Where("ws.status = ?", filter.Status)
+	}
+
+	rows, err := builder.
+		OrderBy("ws.training_date").
+		OrderBy("e.session_id").
+		OrderBy("s.exercise_id").
+		OrderBy("s.set_number").
+		RowsContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "couldn't load sets for export")
+	}
+	defer rows.Close()
+
+	m := &SetExport{}
+	for rows.Next() {
+		*m = SetExport{}
+		detail := &m.SetDetail
+		if err := rows.Scan(
+			&m.SessionID, &m.TrainingDate, &m.SessionStatus, &m.ExerciseID, &m.ExerciseName, &m.CatalogID, &m.Modality,
+			&m.SetID, &m.SetNumber, &detail.SetType, &m.Weight, &m.Unit, &m.Reps,
+			&detail.DurationSeconds, &detail.DistanceMeters, &detail.RPE, &detail.RIR, &detail.RestSeconds, &detail.Tempo, &detail.PerformedAt, &detail.Notes,
+		); err != nil {
+			return errors.Wrapf(err, "couldn't scan sets for export")
+		}
+		if err := fn(m); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrapf(err, "couldn't load sets for export")
+	}
+	return nil
+}
+
+// IsWarmup ウォームアップのセットかどうか。ウォームアップはボリュームや自己ベストの集計から除く
+func (s *SetHistory) IsWarmup() bool {
+	return s.SetType == SetTypeWarmup
+}
+
+// Create 作成。weightはkgに変換済みの重量、unitは入力時の単位
+func (r *SetImpl) Create(exerciseID int64, setNumber int64, weight float64, reps int64, unit string, detail SetDetail) (*SetImpl, error) {
+	return r.CreateTx(db.GetSession("training_db"), exerciseID, setNumber, weight, reps, unit, detail)
 	// return nil, nil
 }
 
 // CreateTx トランザクション内で作成
-func (r *SetImpl) CreateTx(tx dbr.SessionRunner, exerciseID int64, setNumber int64, weight float64, reps int64) (*SetImpl, error) {
+func (r *SetImpl) CreateTx(tx dbr.SessionRunner, exerciseID int64, setNumber int64, weight float64, reps int64, unit string, detail SetDetail) (*SetImpl, error) {
+	if detail.SetType == "" {
+		detail.SetType = SetTypeWorking
+	}
 	m := &SetImpl{
 		ExerciseID: exerciseID,
 		SetNumber:  setNumber,
 		Weight:     weight,
+		Unit:       unit,
 		Reps:       reps,
+		SetDetail:  detail,
 	}
 
 	res, err := tx.InsertInto("sets").
-		Columns("exercise_id", "set_number", "weight", "reps").
+		Columns("exercise_id", "set_number", "weight", "unit", "reps", "set_type", "duration_seconds", "distance_meters", "rpe", "rir", "rest_seconds", "tempo", "performed_at", "notes").
 		Record(m).
 		Exec()
 
@@ -121,3 +374,55 @@ func (r *SetImpl) CreateTx(tx dbr.SessionRunner, exerciseID int64, setNumber int
 	m.ID = lastID
 	return m, nil
 }
+
+// Delete 削除
+func (r *SetImpl) Delete(id int64) (bool, error) {
+	return r.DeleteTx(db.GetSession("training_db"), id)
+}
+
+// DeleteTx トランザクション内で削除
+func (r *SetImpl) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	res, err := tx.DeleteFrom("sets").Where("set_id=?", id).Exec()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't delete sets")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows == 1, nil
+}
+
+// DeleteByExerciseIDTx トランザクション内でエクササイズに紐づくセットを削除
+func (r *SetImpl) DeleteByExerciseIDTx(tx dbr.SessionRunner, exerciseId int64) (int64, error) {
+	res, err := tx.DeleteFrom("sets").Where("exercise_id=?", exerciseId).Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't delete sets")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
+
+// DeleteBySessionIDTx トランザクション内でセッションに紐づく全エクササイズのセットを削除
+func (r *SetImpl) DeleteBySessionIDTx(tx dbr.SessionRunner, sessionId int64) (int64, error) {
+	res, err := tx.DeleteFrom("sets").
+		Where("exercise_id IN ?", tx.Select("exercise_id").From("exercises").Where("session_id=?", sessionId)).
+		Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't delete sets")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
@@ -2,7 +2,9 @@ package model
 
 import (
 	"testing"
+	"time"
 
+	"github.com/gocraft/dbr/v2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -54,7 +56,15 @@ import (
 // }
 
 func TestSetLoad(t *testing.T) {
-	s, err := NewSet().Create(int64(5), int64(1), float64(35.0), int64(10))
+	performedAt := time.Date(2024, 10, 1, 19, 30, 0, 0, time.UTC)
+	s, err := NewSet().Create(int64(5), int64(1), float64(35.0), int64(10), "kg", SetDetail{
+		SetType:     SetTypeAMRAP,
+		RPE:         dbr.NewNullFloat64(8.5),
+		RestSeconds: dbr.NewNullInt64(120),
+		Tempo:       "31X0",
+		PerformedAt: dbr.NewNullTime(performedAt),
+		Notes:       "腰に違和感",
+	})
 	assert.NoError(t, err)
 
 	m, err := new(SetImpl).Load(s.ID)
@@ -65,14 +75,21 @@ func TestSetLoad(t *testing.T) {
 		assert.Equal(t, s.SetNumber, m.SetNumber)
 		assert.Equal(t, s.Weight, m.Weight)
 		assert.Equal(t, s.Reps, m.Reps)
+		assert.Equal(t, SetTypeAMRAP, m.SetType)
+		assert.Equal(t, float64(8.5), m.RPE.Float64)
+		assert.False(t, m.RIR.Valid)
+		assert.Equal(t, int64(120), m.RestSeconds.Int64)
+		assert.Equal(t, "31X0", m.Tempo)
+		assert.Equal(t, performedAt, m.PerformedAt.Time)
+		assert.Equal(t, "腰に違和感", m.Notes)
 	}
 }
 
 func TestSetUpdate(t *testing.T) {
-	s, err := NewSet().Create(int64(4), int64(1), float64(35.0), int64(10))
+	s, err := NewSet().Create(int64(4), int64(1), float64(35.0), int64(10), "kg", SetDetail{})
 	assert.NoError(t, err)
 
-	updated, err := s.Update(map[string]interface{}{"set_number": int64(2), "weight": float64(40.0), "reps": int64(12)})
+	updated, err := NewSet().Update(s.ID, map[string]interface{}{"set_number": int64(2), "weight": float64(40.0), "reps": int64(12)})
 
 	if assert.NoError(t, err) {
 		assert.True(t, updated)
@@ -80,12 +97,52 @@ func TestSetUpdate(t *testing.T) {
 }
 
 func TestSetCreate(t *testing.T) {
-	s, err := NewSet().Create(int64(4), int64(1), float64(35.0), int64(10))
+	s, err := NewSet().Create(int64(4), int64(1), float64(35.0), int64(10), "kg", SetDetail{})
 
 	if assert.NoError(t, err) {
 		assert.Equal(t, int64(4), s.ExerciseID)
 		assert.Equal(t, int64(1), s.SetNumber)
 		assert.Equal(t, float64(35.0), s.Weight)
 		assert.Equal(t, int64(10), s.Reps)
+		// 種類の指定がない場合は本番セットとする
+		assert.Equal(t, SetTypeWorking, s.SetType)
+	}
+}
+
+func TestSetDelete(t *testing.T) {
+	s, err := NewSet().Create(int64(4), int64(1), float64(35.0), int64(10), "kg", SetDetail{})
+	assert.NoError(t, err)
+
+	deleted, err := NewSet().Delete(s.ID)
+
+	if assert.NoError(t, err) {
+		assert.True(t, deleted)
+	}
+}
+
+func TestSetLoadHistory(t *testing.T) {
+	date := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	ws, err := NewWorkoutSession().Create(date, int64(77), "")
+	assert.NoError(t, err)
+	e, err := NewExercise().Create(ws.ID, "履歴テスト", int64(0), "")
+	assert.NoError(t, err)
+	s, err := NewSet().Create(e.ID, int64(1), float64(50.0), int64(8), "kg", SetDetail{})
+	assert.NoError(t, err)
+
+	m, err := NewSet().LoadHistory(SetHistoryFilter{UserID: int64(77), Key: e.Key(), From: date, To: date})
+
+	if assert.NoError(t, err) && assert.Len(t, *m, 1) {
+		assert.Equal(t, s.ID, (*m)[0].SetID)
+		assert.Equal(t, ws.ID, (*m)[0].SessionID)
+		assert.Equal(t, date, (*m)[0].TrainingDate)
+		assert.Equal(t, "履歴テスト", (*m)[0].ExerciseName)
+		assert.Equal(t, "", (*m)[0].PrimaryMuscle)
+	}
+
+	// 種目を指定しない場合はユーザーの全種目を対象とする
+	all, err := NewSet().LoadHistory(SetHistoryFilter{UserID: int64(77), From: date, To: date})
+
+	if assert.NoError(t, err) {
+		assert.GreaterOrEqual(t, len(*all), 1)
 	}
 }
@@ -0,0 +1,360 @@
+package model
+
+import (
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+// 目標セットに対する実施結果
+const (
+	// TargetCompleted 目標どおりに実施した
+	TargetCompleted = "completed"
+	// TargetMissed 目標に届かなかった、または実施しなかった
+	TargetMissed = "missed"
+	// TargetExceeded 目標を上回った
+	TargetExceeded = "exceeded"
+)
+
+// targetWeightEpsilon 単位変換による誤差を目標との差とみなさない重量(kg)
+const targetWeightEpsilon = 0.01
+
+type (
+	// TargetSet エクササイズの目標セットのインターフェースを表す
+	TargetSet interface {
+		LoadByExerciseIDs(exerciseIds []int64) (*TargetSets, error)
+		LoadHistory(filter SetHistoryFilter) (*TargetSetHistories, error)
+		CreateTx(tx dbr.SessionRunner, exerciseId int64, target SetTarget) (*TargetSetImpl, error)
+		DeleteByExerciseIDTx(tx dbr.SessionRunner, exerciseId int64) (int64, error)
+		DeleteBySessionIDTx(tx dbr.SessionRunner, sessionId int64) (int64, error)
+	}
+
+	// TargetSetImpl エクササイズの目標セットを表す
+	TargetSetImpl struct {
+		ID         int64 `db:"target_set_id" dbopt:"auto_increment"`
+		ExerciseID int64 `db:"exercise_id"`
+		SetTarget
+	}
+
+	TargetSets []TargetSetImpl
+
+	// SetTarget 目標とするセットの重量・回数・時間・距離・RPEを表す
+	// 重量はセットと同じくkgで保存し、Unitに入力時の単位を記録する
+	SetTarget struct {
+		SetNumber       int64           `db:"set_number"`
+		SetType         string          `db:"set_type"`
+		Weight          float64         `db:"weight"`
+		Unit            string          `db:"unit"`
+		Reps            int64           `db:"reps"`
+		DurationSeconds dbr.NullInt64   `db:"duration_seconds"`
+		DistanceMeters  dbr.NullFloat64 `db:"distance_meters"`
+		RPE             dbr.NullFloat64 `db:"rpe"`
+	}
+
+	// TargetSetHistory セッションの日付・状態とエクササイズの記録方法とあわせて読み込んだ目標セットを表す
+	TargetSetHistory struct {
+		TargetSetID  int64     `db:"target_set_id"`
+		ExerciseID   int64     `db:"exercise_id"`
+		SessionID    int64     `db:"session_id"`
+		TrainingDate time.Time `db:"training_date"`
+		Modality     string    `db:"modality"`
+		SetTarget
+	}
+
+	TargetSetHistories []TargetSetHistory
+
+	// TargetResult 目標セット1件に対する実施結果を表す。実施しなかった場合SetIDは0
+	TargetResult struct {
+		SetNumber int64
+		Status    string
+		SetID     int64
+	}
+
+	// TargetComparison エクササイズの目標セットと実施したセットの比較結果を表す
+	// ExtraSetsは目標にないセット番号で実施したウォームアップ以外のセット数
+	TargetComparison struct {
+		Status    string
+		Results   []TargetResult
+		ExtraSets int64
+	}
+)
+
+func NewTargetSets() *TargetSets {
+	return &TargetSets{}
+}
+
+func NewTargetSet() TargetSet {
+	return &TargetSetImpl{}
+}
+
+// Target 実施したセットを目標セットに変換
+func (m *SetImpl) Target() SetTarget {
+	return SetTarget{
+		SetNumber:       m.SetNumber,
+		SetType:         m.SetType,
+		Weight:          m.Weight,
+		Unit:            m.Unit,
+		Reps:            m.Reps,
+		DurationSeconds: m.DurationSeconds,
+		DistanceMeters:  m.DistanceMeters,
+		RPE:             m.RPE,
+	}
+}
+
+// Targets 実施したセットを目標セットの一覧に変換
+func (s *Sets) Targets() []SetTarget {
+	targets := make([]SetTarget, 0, len(*s))
+	for _, set := range *s {
+		targets = append(targets, set.Target())
+	}
+	return targets
+}
+
+// LoadByExerciseIDs 複数エクササイズの目標セットを1クエリで読み込み
+func (r *TargetSetImpl) LoadByExerciseIDs(exerciseIds []int64) (*TargetSets, error) {
+	return r.LoadByExerciseIDsTx(db.GetSession("training_db"), exerciseIds)
+}
+
+// LoadByExerciseIDsTx トランザクション内で複数エクササイズの目標セットを読み込み
+func (r *TargetSetImpl) LoadByExerciseIDsTx(tx dbr.SessionRunner, exerciseIds []int64) (*TargetSets, error) {
+	m := NewTargetSets()
+	if len(exerciseIds) == 0 {
+		return m, nil
+	}
+
+	if _, err := tx.Select("*").From("target_sets").
+		Where("exercise_id IN ?", exerciseIds).
+		OrderBy("exercise_id").
+		OrderBy("set_number").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load target_sets")
+	}
+	return m, nil
+}
+
+// LoadHistory ユーザーの目標セットを日付順に読み込み
+func (r *TargetSetImpl) LoadHistory(filter SetHistoryFilter) (*TargetSetHistories, error) {
+	return r.LoadHistoryTx(db.GetSession("training_db"), filter)
+}
+
+// LoadHistoryTx トランザクション内でユーザーの目標セットを日付順に読み込み
+func (r *TargetSetImpl) LoadHistoryTx(tx dbr.SessionRunner, filter SetHistoryFilter) (*TargetSetHistories, error) {
+	m := &TargetSetHistories{}
+
+	builder := tx.Select(
+		"t.target_set_id", "t.exercise_id", "e.session_id", "ws.training_date", "e.modality",
+		"t.set_number", "t.set_type", "t.weight", "t.unit", "t.reps", "t.duration_seconds", "t.distance_meters", "t.rpe",
+	).
+		From(dbr.I("target_sets").As("t")).
+		Join(dbr.I("exercises").As("e"), "e.exercise_id = t.exercise_id").
+		Join(dbr.I("workout_sessions").As("ws"), "ws.session_id = e.session_id").
+		Where("ws.user_id = ?", filter.UserID)
+
+	if filter.Key.CatalogID != 0 {
+		builder = builder.Where("e.catalog_id = ?", filter.Key.CatalogID)
+	} else if filter.Key.ExerciseName != "" {
+		builder = builder.Where("e.catalog_id IS NULL AND e.exercise_name = ?", filter.Key.ExerciseName)
+	}
+	if !filter.From.IsZero() {
+		builder = builder.Where("ws.training_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		builder = builder.Where("ws.training_date <= ?", filter.To)
+	}
+	if filter.Status != "" {
+		builder = builder.Where("ws.status = ?", filter.Status)
+	}
+
+	if _, err := builder.
+		OrderBy("ws.training_date").
+		OrderBy("e.session_id").
+		OrderBy("t.exercise_id").
+		OrderBy("t.set_number").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load target set history")
+	}
+	return m, nil
+}
+
+// ExerciseIDs 目標セットのエクササイズIDの一覧を重複なく返却
+func (h *TargetSetHistories) ExerciseIDs() []int64 {
+	ids := []int64{}
+	seen := map[int64]bool{}
+	for _, target := range *h {
+		if !seen[target.ExerciseID] {
+			seen[target.ExerciseID] = true
+			ids = append(ids, target.ExerciseID)
+		}
+	}
+	return ids
+}
+
+// GroupByExerciseID エクササイズIDごとに目標セットをまとめる
+func (s *TargetSets) GroupByExerciseID() map[int64]*TargetSets {
+	grouped := make(map[int64]*TargetSets)
+	for _, target := range *s {
+		if _, ok := grouped[target.ExerciseID]; !ok {
+			grouped[target.ExerciseID] = NewTargetSets()
+		}
+		*grouped[target.ExerciseID] = append(*grouped[target.ExerciseID], target)
+	}
+	return grouped
+}
+
+// Targets 目標セットの一覧を返却
+func (s *TargetSets) Targets() []SetTarget {
+	targets := make([]SetTarget, 0, len(*s))
+	for _, target := range *s {
+		targets = append(targets, target.SetTarget)
+	}
+	return targets
+}
+
+// CreateTx トランザクション内で作成。セットの種類が空の場合はworkingとする
+func (r *TargetSetImpl) CreateTx(tx dbr.SessionRunner, exerciseId int64, target SetTarget) (*TargetSetImpl, error) {
+	if target.SetType == "" {
+		target.SetType = SetTypeWorking
+	}
+	m := &TargetSetImpl{
+		ExerciseID: exerciseId,
+		SetTarget:  target,
+	}
+
+	res, err := tx.InsertInto("target_sets").
+		Columns("exercise_id", "set_number", "set_type", "weight", "unit", "reps", "duration_seconds", "distance_meters", "rpe").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create target_sets")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for target_sets")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// DeleteByExerciseIDTx トランザクション内でエクササイズに紐づく目標セットを削除
+func (r *TargetSetImpl) DeleteByExerciseIDTx(tx dbr.SessionRunner, exerciseId int64) (int64, error) {
+	res, err := tx.DeleteFrom("target_sets").Where("exercise_id=?", exerciseId).Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't delete target_sets")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
+
+// DeleteBySessionIDTx トランザクション内でセッションに紐づく全エクササイズの目標セットを削除
+func (r *TargetSetImpl) DeleteBySessionIDTx(tx dbr.SessionRunner, sessionId int64) (int64, error) {
+	res, err := tx.DeleteFrom("target_sets").
+		Where("exercise_id IN ?", tx.Select("exercise_id").From("exercises").Where("session_id=?", sessionId)).
+		Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't delete target_sets")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
+
+// CompareTargets 目標セットと実施したセットをセット番号で突き合わせて比較
+// 目標に含まれる重量・回数・時間・距離のいずれかが届かなければmissed、すべて満たし1つでも上回ればexceededとする
+// RPEは強度の目安のため比較には用いない
+func CompareTargets(modality string, targets []SetTarget, sets *Sets) TargetComparison {
+	bySetNumber := map[int64]SetImpl{}
+	for _, set := range *sets {
+		if _, ok := bySetNumber[set.SetNumber]; !ok {
+			bySetNumber[set.SetNumber] = set
+		}
+	}
+
+	comparison := TargetComparison{Status: TargetCompleted, Results: make([]TargetResult, 0, len(targets))}
+	targeted := map[int64]bool{}
+	exceeded := false
+	for _, target := range targets {
+		targeted[target.SetNumber] = true
+		result := TargetResult{SetNumber: target.SetNumber, Status: TargetMissed}
+		if set, ok := bySetNumber[target.SetNumber]; ok {
+			result.SetID = set.ID
+			result.Status = compareTarget(modality, target, set)
+		}
+		switch result.Status {
+		case TargetMissed:
+			comparison.Status = TargetMissed
+		case TargetExceeded:
+			exceeded = true
+		}
+		comparison.Results = append(comparison.Results, result)
+	}
+
+	for number, set := range bySetNumber {
+		if !targeted[number] && set.SetType != SetTypeWarmup {
+			comparison.ExtraSets++
+		}
+	}
+	if comparison.Status != TargetMissed && (exceeded || comparison.ExtraSets > 0) {
+		comparison.Status = TargetExceeded
+	}
+	return comparison
+}
+
+// compareTarget 目標セット1件と実施したセットを比較
+func compareTarget(modality string, target SetTarget, set SetImpl) string {
+	// 各項目の差を、上回れば正・届かなければ負として集める
+	diffs := []float64{}
+	if target.Weight > 0 {
+		diff := set.Weight - target.Weight
+		// アシスト重量は軽いほど負荷が高い
+		if NormalizeModality(modality) == ModalityAssisted {
+			diff = -diff
+		}
+		if diff > -targetWeightEpsilon && diff < targetWeightEpsilon {
+			diff = 0
+		}
+		diffs = append(diffs, diff)
+	}
+	if target.Reps > 0 {
+		diffs = append(diffs, float64(set.Reps-target.Reps))
+	}
+	if target.DurationSeconds.Valid {
+		diff := float64(set.DurationSeconds.Int64 - target.DurationSeconds.Int64)
+		if !set.DurationSeconds.Valid {
+			diff = -1
+		} else if NormalizeModality(modality) == ModalityDistance {
+			// 有酸素種目は短い時間で終えるほど良い
+			diff = -diff
+		}
+		diffs = append(diffs, diff)
+	}
+	if target.DistanceMeters.Valid {
+		diff := set.DistanceMeters.Float64 - target.DistanceMeters.Float64
+		if !set.DistanceMeters.Valid {
+			diff = -1
+		}
+		diffs = append(diffs, diff)
+	}
+
+	status := TargetCompleted
+	for _, diff := range diffs {
+		if diff < 0 {
+			return TargetMissed
+		}
+		if diff > 0 {
+			status = TargetExceeded
+		}
+	}
+	return status
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetSetCreate(t *testing.T) {
+	e, err := NewExercise().Create(int64(23), "チェストプレス", int64(0), "")
+	assert.NoError(t, err)
+
+	err = db.Transaction("training_db", func(tx dbr.SessionRunner) error {
+		_, err := NewTargetSet().CreateTx(tx, e.ID, SetTarget{SetNumber: 1, Weight: 40, Unit: "kg", Reps: 8})
+		return err
+	})
+	assert.NoError(t, err)
+
+	m, err := NewTargetSet().LoadByExerciseIDs([]int64{e.ID})
+	if assert.NoError(t, err) && assert.Len(t, *m, 1) {
+		assert.Equal(t, SetTypeWorking, (*m)[0].SetType)
+		assert.Equal(t, float64(40), (*m)[0].Weight)
+		assert.Equal(t, int64(8), (*m)[0].Reps)
+	}
+}
+
+func TestSetTarget(t *testing.T) {
+	set := SetImpl{SetNumber: 2, Weight: 61.235, Unit: "lb", Reps: 5, SetDetail: SetDetail{SetType: SetTypeAMRAP, Notes: "メモ"}}
+
+	assert.Equal(t, SetTarget{SetNumber: 2, SetType: SetTypeAMRAP, Weight: 61.235, Unit: "lb", Reps: 5}, set.Target())
+}
+
+func TestCompareTargets(t *testing.T) {
+	t.Parallel()
+
+	targets := []SetTarget{
+		{SetNumber: 1, Weight: 100, Unit: "kg", Reps: 5},
+		{SetNumber: 2, Weight: 100, Unit: "kg", Reps: 5},
+	}
+
+	testCases := []struct {
+		name     string
+		modality string
+		targets  []SetTarget
+		sets     *Sets
+		want     TargetComparison
+	}{
+		{
+			name:    "正常系(目標どおり)",
+			targets: targets,
+			sets: &Sets{
+				{ID: 1, SetNumber: 1, Weight: 100, Reps: 5},
+				{ID: 2, SetNumber: 2, Weight: 100.001, Reps: 5},
+			},
+			want: TargetComparison{Status: TargetCompleted, Results: []TargetResult{
+				{SetNumber: 1, Status: TargetCompleted, SetID: 1},
+				{SetNumber: 2, Status: TargetCompleted, SetID: 2},
+			}},
+		},
+		{
+			name:    "正常系(回数が届かず、セットを実施していない)",
+			targets: targets,
+			sets:    &Sets{{ID: 1, SetNumber: 1, Weight: 105, Reps: 4}},
+			want: TargetComparison{Status: TargetMissed, Results: []TargetResult{
+				{SetNumber: 1, Status: TargetMissed, SetID: 1},
+				{SetNumber: 2, Status: TargetMissed},
+			}},
+		},
+		{
+			name:    "正常系(目標を上回り、ウォームアップ以外のセットを追加)",
+			targets: targets,
+			sets: &Sets{
+				{ID: 1, SetNumber: 0, Weight: 60, Reps: 5, SetDetail: SetDetail{SetType: SetTypeWarmup}},
+				{ID: 2, SetNumber: 1, Weight: 100, Reps: 5},
+				{ID: 3, SetNumber: 2, Weight: 100, Reps: 5},
+				{ID: 4, SetNumber: 3, Weight: 100, Reps: 5},
+			},
+			want: TargetComparison{Status: TargetExceeded, ExtraSets: 1, Results: []TargetResult{
+				{SetNumber: 1, Status: TargetCompleted, SetID: 2},
+				{SetNumber: 2, Status: TargetCompleted, SetID: 3},
+			}},
+		},
+		{
+			name:     "正常系(アシスト重量は軽いほど上回る)",
+			modality: ModalityAssisted,
+			targets:  []SetTarget{{SetNumber: 1, Weight: 20, Reps: 8}},
+			sets:     &Sets{{ID: 1, SetNumber: 1, Weight: 15, Reps: 8}},
+			want: TargetComparison{Status: TargetExceeded, Results: []TargetResult{
+				{SetNumber: 1, Status: TargetExceeded, SetID: 1},
+			}},
+		},
+		{
+			name:     "正常系(有酸素種目は時間が長いと届かない)",
+			modality: ModalityDistance,
+			targets:  []SetTarget{{SetNumber: 1, DurationSeconds: dbr.NewNullInt64(1500), DistanceMeters: dbr.NewNullFloat64(5000)}},
+			sets:     &Sets{{ID: 1, SetNumber: 1, SetDetail: SetDetail{DurationSeconds: dbr.NewNullInt64(1600), DistanceMeters: dbr.NewNullFloat64(5000)}}},
+			want: TargetComparison{Status: TargetMissed, Results: []TargetResult{
+				{SetNumber: 1, Status: TargetMissed, SetID: 1},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, CompareTargets(tc.modality, tc.targets, tc.sets))
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+type (
+	// TemplateExercise テンプレートの種目のインターフェースを表す
+	TemplateExercise interface {
+		LoadByTemplateIDs(templateIds []int64) (*TemplateExercises, error)
+		CreateTx(tx dbr.SessionRunner, templateId int64, position int64, exerciseName string, catalogId int64, modality string) (*TemplateExerciseImpl, error)
+		DeleteByTemplateIDTx(tx dbr.SessionRunner, templateId int64) (int64, error)
+		DetachCatalogTx(tx dbr.SessionRunner, catalogId int64) (int64, error)
+	}
+
+	// TemplateExerciseImpl テンプレートの種目を表す
+	TemplateExerciseImpl struct {
+		ID           int64         `db:"template_exercise_id" dbopt:"auto_increment"`
+		TemplateID   int64         `db:"template_id"`
+		Position     int64         `db:"position"`
+		CatalogID    dbr.NullInt64 `db:"catalog_id"`
+		ExerciseName string        `db:"exercise_name"`
+		Modality     string        `db:"modality"`
+	}
+
+	TemplateExercises []TemplateExerciseImpl
+)
+
+func NewTemplateExercises() *TemplateExercises {
+	return &TemplateExercises{}
+}
+
+func NewTemplateExercise() TemplateExercise {
+	return &TemplateExerciseImpl{}
+}
+
+// LoadByTemplateIDs 複数テンプレートの種目を並び順に1クエリで読み込み
+func (r *TemplateExerciseImpl) LoadByTemplateIDs(templateIds []int64) (*TemplateExercises, error) {
+	return r.LoadByTemplateIDsTx(db.GetSession("training_db"), templateIds)
+}
+
+// LoadByTemplateIDsTx トランザクション内で複数テンプレートの種目を読み込み
+func (r *TemplateExerciseImpl) LoadByTemplateIDsTx(tx dbr.SessionRunner, templateIds []int64) (*TemplateExercises, error) {
+	m := NewTemplateExercises()
+	if len(templateIds) == 0 {
+		return m, nil
+	}
+
+	if _, err := tx.Select("*").From("template_exercises").
+		Where("template_id IN ?", templateIds).
+		OrderBy("template_id").
+		OrderBy("position").
+		OrderBy("template_exercise_id").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load template_exercises")
+	}
+	return m, nil
+}
+
+// Key 記録を集計する種目の単位を返却
+func (m *TemplateExerciseImpl) Key() ExerciseKey {
+	if m.CatalogID.Valid {
+		return ExerciseKey{CatalogID: m.CatalogID.Int64}
+	}
+	return ExerciseKey{ExerciseName: m.ExerciseName}
+}
+
+// IDs テンプレートの種目IDの一覧を返却
+func (e *TemplateExercises) IDs() []int64 {
+	ids := make([]int64, 0, len(*e))
+	for _, exercise := range *e {
+		ids = append(ids, exercise.ID)
+	}
+	return ids
+}
+
+// GroupByTemplateID テンプレートIDごとに種目をまとめる
+func (e *TemplateExercises) GroupByTemplateID() map[int64]*TemplateExercises {
+	grouped := make(map[int64]*TemplateExercises)
+	for _, exercise := range *e {
+		if _, ok := grouped[exercise.TemplateID]; !ok {
+			grouped[exercise.TemplateID] = NewTemplateExercises()
+		}
+		*grouped[exercise.TemplateID] = append(*grouped[exercise.TemplateID], exercise)
+	}
+	return grouped
+}
+
+// CreateTx トランザクション内で作成
+// catalogIdが0の場合はカタログに紐づけずに作成し、modalityが空の場合はウェイト種目とする
+func (r *TemplateExerciseImpl) CreateTx(tx dbr.SessionRunner, templateId int64, position int64, exerciseName string, catalogId int64, modality string) (*TemplateExerciseImpl, error) {
+	m := &TemplateExerciseImpl{
+		TemplateID:   templateId,
+		Position:     position,
+		ExerciseName: exerciseName,
+		Modality:     NormalizeModality(modality),
+	}
+	if catalogId != 0 {
+		m.CatalogID = dbr.NewNullInt64(catalogId)
+	}
+
+	res, err := tx.InsertInto("template_exercises").
+		Columns("template_id", "position", "catalog_id", "exercise_name", "modality").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create template_exercises")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for template_exercises")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// DeleteByTemplateIDTx トランザクション内でテンプレートに紐づく種目を削除
+func (r *TemplateExerciseImpl) DeleteByTemplateIDTx(tx dbr.SessionRunner, templateId int64) (int64, error) {
+	res, err := tx.DeleteFrom("template_exercises").Where("template_id=?", templateId).Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't delete template_exercises")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
+
+// DetachCatalogTx トランザクション内で種目の名前を残したままカタログとの紐づけを外す
+func (r *TemplateExerciseImpl) DetachCatalogTx(tx dbr.SessionRunner, catalogId int64) (int64, error) {
+	res, err := tx.Update("template_exercises").Set("catalog_id", nil).Where("catalog_id=?", catalogId).Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't detach template_exercises")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
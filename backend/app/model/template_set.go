@@ -0,0 +1,119 @@
+package model
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+type (
+	// TemplateSet テンプレートの種目の目標セットのインターフェースを表す
+	TemplateSet interface {
+		LoadByTemplateExerciseIDs(templateExerciseIds []int64) (*TemplateSets, error)
+		CreateTx(tx dbr.SessionRunner, templateExerciseId int64, target SetTarget) (*TemplateSetImpl, error)
+		DeleteByTemplateIDTx(tx dbr.SessionRunner, templateId int64) (int64, error)
+	}
+
+	// TemplateSetImpl テンプレートの種目の目標セットを表す
+	TemplateSetImpl struct {
+		ID                 int64 `db:"template_set_id" dbopt:"auto_increment"`
+		TemplateExerciseID int64 `db:"template_exercise_id"`
+		SetTarget
+	}
+
+	TemplateSets []TemplateSetImpl
+)
+
+func NewTemplateSets() *TemplateSets {
+	return &TemplateSets{}
+}
+
+func NewTemplateSet() TemplateSet {
+	return &TemplateSetImpl{}
+}
+
+// LoadByTemplateExerciseIDs 複数の種目の目標セットを1クエリで読み込み
+func (r *TemplateSetImpl) LoadByTemplateExerciseIDs(templateExerciseIds []int64) (*TemplateSets, error) {
+	return r.LoadByTemplateExerciseIDsTx(db.GetSession("training_db"), templateExerciseIds)
+}
+
+// LoadByTemplateExerciseIDsTx トランザクション内で複数の種目の目標セットを読み込み
+func (r *TemplateSetImpl) LoadByTemplateExerciseIDsTx(tx dbr.SessionRunner, templateExerciseIds []int64) (*TemplateSets, error) {
+	m := NewTemplateSets()
+	if len(templateExerciseIds) == 0 {
+		return m, nil
+	}
+
+	if _, err := tx.Select("*").From("template_sets").
+		Where("template_exercise_id IN ?", templateExerciseIds).
+		OrderBy("template_exercise_id").
+		OrderBy("set_number").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load template_sets")
+	}
+	return m, nil
+}
+
+// GroupByTemplateExerciseID 種目IDごとに目標セットをまとめる
+func (s *TemplateSets) GroupByTemplateExerciseID() map[int64]*TemplateSets {
+	grouped := make(map[int64]*TemplateSets)
+	for _, set := range *s {
+		if _, ok := grouped[set.TemplateExerciseID]; !ok {
+			grouped[set.TemplateExerciseID] = NewTemplateSets()
+		}
+		*grouped[set.TemplateExerciseID] = append(*grouped[set.TemplateExerciseID], set)
+	}
+	return grouped
+}
+
+// Targets 目標セットの一覧を返却
+func (s *TemplateSets) Targets() []SetTarget {
+	targets := make([]SetTarget, 0, len(*s))
+	for _, set := range *s {
+		targets = append(targets, set.SetTarget)
+	}
+	return targets
+}
+
+// CreateTx トランザクション内で作成。セットの種類が空の場合はworkingとする
+func (r *TemplateSetImpl) CreateTx(tx dbr.SessionRunner, templateExerciseId int64, target SetTarget) (*TemplateSetImpl, error) {
+	if target.SetType == "" {
+		target.SetType = SetTypeWorking
+	}
+	m := &TemplateSetImpl{
+		TemplateExerciseID: templateExerciseId,
+		SetTarget:          target,
+	}
+
+	res, err := tx.InsertInto("template_sets").
+		Columns("template_exercise_id", "set_number", "set_type", "weight", "unit", "reps", "duration_seconds", "distance_meters", "rpe").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create template_sets")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for template_sets")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// DeleteByTemplateIDTx トランザクション内でテンプレートの全種目の目標セットを削除
+func (r *TemplateSetImpl) DeleteByTemplateIDTx(tx dbr.SessionRunner, templateId int64) (int64, error) {
+	res, err := tx.DeleteFrom("template_sets").
+		Where("template_exercise_id IN ?", tx.Select("template_exercise_id").From("template_exercises").Where("template_id=?", templateId)).
+		Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't delete template_sets")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,172 @@
+package model
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+type (
+	// TrainingMax トレーニングマックスのインターフェースを表す
+	TrainingMax interface {
+		LoadByEnrollmentID(enrollmentId int64) (*TrainingMaxes, error)
+		LoadByEnrollmentIDs(enrollmentIds []int64) (*TrainingMaxes, error)
+		CreateTx(tx dbr.SessionRunner, enrollmentId int64, key ExerciseKey, weight float64, unit string) (*TrainingMaxImpl, error)
+		DetachCatalogTx(tx dbr.SessionRunner, catalogId int64) (int64, error)
+		DeleteByEnrollmentIDTx(tx dbr.SessionRunner, enrollmentId int64) (int64, error)
+	}
+
+	// TrainingMaxImpl 参加時に設定した種目ごとのトレーニングマックスを表す
+	// 重量はkgで保存し、Unitに入力時の単位を記録する
+	TrainingMaxImpl struct {
+		ID           int64   `db:"training_max_id" dbopt:"auto_increment"`
+		EnrollmentID int64   `db:"enrollment_id"`
+		CatalogID    int64   `db:"catalog_id"`
+		ExerciseName string  `db:"exercise_name"`
+		Weight       float64 `db:"weight"`
+		Unit         string  `db:"unit"`
+	}
+
+	TrainingMaxes []TrainingMaxImpl
+)
+
+func NewTrainingMaxes() *TrainingMaxes {
+	return &TrainingMaxes{}
+}
+
+func NewTrainingMax() TrainingMax {
+	return &TrainingMaxImpl{}
+}
+
+// LoadByEnrollmentID 参加のトレーニングマックスを読み込み
+func (r *TrainingMaxImpl) LoadByEnrollmentID(enrollmentId int64) (*TrainingMaxes, error) {
+	return r.LoadByEnrollmentIDTx(db.GetSession("training_db"), enrollmentId)
+}
+
+// LoadByEnrollmentIDTx トランザクション内で参加のトレーニングマックスを読み込み
+func (r *TrainingMaxImpl) LoadByEnrollmentIDTx(tx dbr.SessionRunner, enrollmentId int64) (*TrainingMaxes, error) {
+	m := NewTrainingMaxes()
+	if _, err := tx.Select("*").From("training_maxes").
+		Where("enrollment_id = ?", enrollmentId).
+		OrderBy("training_max_id").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load training_maxes")
+	}
+	return m, nil
+}
+
+// LoadByEnrollmentIDs 複数の参加のトレーニングマックスを1クエリで読み込み
+func (r *TrainingMaxImpl) LoadByEnrollmentIDs(enrollmentIds []int64) (*TrainingMaxes, error) {
+	return r.LoadByEnrollmentIDsTx(db.GetSession("training_db"), enrollmentIds)
+}
+
+// LoadByEnrollmentIDsTx トランザクション内で複数の参加のトレーニングマックスを読み込み
+func (r *TrainingMaxImpl) LoadByEnrollmentIDsTx(tx dbr.SessionRunner, enrollmentIds []int64) (*TrainingMaxes, error) {
+	m := NewTrainingMaxes()
+	if len(enrollmentIds) == 0 {
+		return m, nil
+	}
+
+	if _, err := tx.Select("*").From("training_maxes").
+		Where("enrollment_id IN ?", enrollmentIds).
+		OrderBy("enrollment_id").
+		OrderBy("training_max_id").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load training_maxes")
+	}
+	return m, nil
+}
+
+// GroupByEnrollmentID 参加IDごとにトレーニングマックスをまとめる
+func (t *TrainingMaxes) GroupByEnrollmentID() map[int64]*TrainingMaxes {
+	grouped := make(map[int64]*TrainingMaxes)
+	for _, tm := range *t {
+		if _, ok := grouped[tm.EnrollmentID]; !ok {
+			grouped[tm.EnrollmentID] = NewTrainingMaxes()
+		}
+		*grouped[tm.EnrollmentID] = append(*grouped[tm.EnrollmentID], tm)
+	}
+	return grouped
+}
+
+// Key 記録を集計する種目の単位を返却
+func (m *TrainingMaxImpl) Key() ExerciseKey {
+	return ExerciseKey{CatalogID: m.CatalogID, ExerciseName: m.ExerciseName}
+}
+
+// ByKey 種目ごとのトレーニングマックスを返却
+func (t *TrainingMaxes) ByKey() map[ExerciseKey]TrainingMaxImpl {
+	byKey := make(map[ExerciseKey]TrainingMaxImpl, len(*t))
+	for _, tm := range *t {
+		byKey[tm.Key()] = tm
+	}
+	return byKey
+}
+
+// CreateTx トランザクション内で作成
+func (r *TrainingMaxImpl) CreateTx(tx dbr.SessionRunner, enrollmentId int64, key ExerciseKey, weight float64, unit string) (*TrainingMaxImpl, error) {
+	m := &TrainingMaxImpl{
+		EnrollmentID: enrollmentId,
+		CatalogID:    key.CatalogID,
+		ExerciseName: key.ExerciseName,
+		Weight:       weight,
+		Unit:         unit,
+	}
+
+	res, err := tx.InsertInto("training_maxes").
+		Columns("enrollment_id", "catalog_id", "exercise_name", "weight", "unit").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create training_maxes")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for training_maxes")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// DetachCatalogTx トランザクション内でカタログの種目のトレーニングマックスを、参加したプログラムの種目の名前に付け替え
+// プログラムの種目の紐づけを外す前に呼び出し、紐づけを外した後もプログラムの種目と同じ名前で引けるようにする
+// 同じ名前のトレーニングマックスが既にある場合と、プログラムに種目がない場合は付け替えずに削除する
+func (r *TrainingMaxImpl) DetachCatalogTx(tx dbr.SessionRunner, catalogId int64) (int64, error) {
+	res, err := tx.UpdateBySql(
+		"UPDATE IGNORE training_maxes tm "+
+			"JOIN program_enrollments en ON en.enrollment_id = tm.enrollment_id "+
+			"JOIN program_days d ON d.program_id = en.program_id "+
+			"JOIN program_exercises pe ON pe.program_day_id = d.program_day_id AND pe.catalog_id = tm.catalog_id "+
+			"SET tm.catalog_id = 0, tm.exercise_name = pe.exercise_name "+
+			"WHERE tm.catalog_id = ?", catalogId).
+		Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't detach training_maxes")
+	}
+	if _, err := tx.DeleteFrom("training_maxes").Where("catalog_id=?", catalogId).Exec(); err != nil {
+		return 0, errors.Wrapf(err, "couldn't delete training_maxes")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
+
+// DeleteByEnrollmentIDTx トランザクション内でプログラムへの参加のトレーニングマックスを削除
+func (r *TrainingMaxImpl) DeleteByEnrollmentIDTx(tx dbr.SessionRunner, enrollmentId int64) (int64, error) {
+	res, err := tx.DeleteFrom("training_maxes").Where("enrollment_id=?", enrollmentId).Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't delete training_maxes")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+// ユーザーの権限
+const (
+	UserRoleMember = "member"
+	// UserRoleAdmin 全ユーザーで共有する種目カタログを編集できる
+	UserRoleAdmin = "admin"
+)
+
+type (
+	// User ユーザーのインターフェースを表す
+	User interface {
+		Load(id int64) (*UserImpl, error)
+		LoadByEmail(email string) (*UserImpl, error)
+		Create(email string, passwordHash string, name string) (*UserImpl, error)
+		Update(id int64, attrs map[string]interface{}) (bool, error)
+		UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error)
+	}
+
+	// UserImpl ユーザーを表す
+	UserImpl struct {
+		ID           int64     `db:"user_id" dbopt:"auto_increment"`
+		Email        string    `db:"email"`
+		PasswordHash string    `db:"password_hash"`
+		Name         string    `db:"name"`
+		WeightUnit   string    `db:"weight_unit"`
+		Role         string    `db:"role"`
+		CreatedAt    time.Time `db:"created_at"`
+	}
+)
+
+func NewUser() User {
+	return &UserImpl{}
+}
+
+// Load 指定のIDを読み込み
+func (m *UserImpl) Load(id int64) (*UserImpl, error) {
+	return m.LoadTx(db.GetSession("training_db"), id)
+}
+
+// LoadTx トランザクション内で指定のIDを読み込み
+func (m *UserImpl) LoadTx(tx dbr.SessionRunner, id int64) (*UserImpl, error) {
+	r := &UserImpl{}
+	if _, err := tx.Select("*").From("users").Where("user_id=?", id).Load(r); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load users")
+	}
+	return r, nil
+}
+
+// LoadByEmail 指定のメールアドレスを読み込み
+func (m *UserImpl) LoadByEmail(email string) (*UserImpl, error) {
+	return m.LoadByEmailTx(db.GetSession("training_db"), email)
+}
+
+// LoadByEmailTx トランザクション内で指定のメールアドレスを読み込み
+func (m *UserImpl) LoadByEmailTx(tx dbr.SessionRunner, email string) (*UserImpl, error) {
+	r := &UserImpl{}
+	if _, err := tx.Select("*").From("users").Where("email=?", email).Load(r); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load users")
+	}
+	return r, nil
+}
+
+// Update 更新
+func (m *UserImpl) Update(id int64, attrs map[string]interface{}) (bool, error) {
+	return m.UpdateTx(db.GetSession("training_db"), id, attrs)
+}
+
+// UpdateTx トランザクション内で更新
+func (m *UserImpl) UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error) {
+	res, err := tx.Update("users").SetMap(attrs).Where("user_id=?", id).Exec()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't update users")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows == 1, nil
+}
+
+// Create 作成
+func (r *UserImpl) Create(email string, passwordHash string, name string) (*UserImpl, error) {
+	return r.CreateTx(db.GetSession("training_db"), email, passwordHash, name)
+}
+
+// CreateTx トランザクション内で作成
+func (r *UserImpl) CreateTx(tx dbr.SessionRunner, email string, passwordHash string, name string) (*UserImpl, error) {
+	m := &UserImpl{
+		Email:        email,
+		PasswordHash: passwordHash,
+		Name:         name,
+		WeightUnit:   string(units.DefaultUnit),
+		Role:         UserRoleMember,
+		CreatedAt:    time.Now(),
+	}
+
+	res, err := tx.InsertInto("users").
+		Columns("email", "password_hash", "name", "weight_unit", "role", "created_at").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create users")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for users")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// IsAdmin 種目カタログを編集できるユーザーかどうか
+func (m *UserImpl) IsAdmin() bool {
+	return m.Role == UserRoleAdmin
+}
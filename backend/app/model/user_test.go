@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserLoadByEmail(t *testing.T) {
+	u, err := NewUser().Create("load@example.com", "hash", "test")
+	assert.NoError(t, err)
+
+	m, err := new(UserImpl).LoadByEmail(u.Email)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, u.ID, m.ID)
+		assert.Equal(t, u.Email, m.Email)
+		assert.Equal(t, u.PasswordHash, m.PasswordHash)
+	}
+}
+
+func TestUserCreate(t *testing.T) {
+	u, err := NewUser().Create("create@example.com", "hash", "test")
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "create@example.com", u.Email)
+		assert.Equal(t, "hash", u.PasswordHash)
+		assert.Equal(t, "test", u.Name)
+	}
+}
@@ -8,23 +8,69 @@ import (
 	"github.com/pkg/errors"
 )
 
+// セッションの状態。draftからin_progressを経てcompletedに進み、completedは再開するまで編集できない
+const (
+	SessionStatusDraft      = "draft"
+	SessionStatusInProgress = "in_progress"
+	SessionStatusCompleted  = "completed"
+)
+
 type (
 	// WorkoutSession ワークアウトのインターフェースを表す
 	WorkoutSession interface {
-		LoadByIDAndDate(id int64, date time.Time) (*WorkoutSessions, error)
+		LoadByFilter(filter WorkoutSessionFilter) (*WorkoutSessions, error)
 		Load(id int64) (*WorkoutSessionImpl, error)
-		Update(attrs map[string]interface{}) (bool, error)
-		Create(date time.Time, userId int64) (*WorkoutSessionImpl, error)
+		Update(id int64, attrs map[string]interface{}) (bool, error)
+		UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error)
+		Create(date time.Time, userId int64, status string) (*WorkoutSessionImpl, error)
+		CreateTx(tx dbr.SessionRunner, date time.Time, userId int64, status string) (*WorkoutSessionImpl, error)
+		CreateFromTemplateTx(tx dbr.SessionRunner, date time.Time, userId int64, templateId int64) (*WorkoutSessionImpl, error)
+		CreatePlannedTx(tx dbr.SessionRunner, date time.Time, userId int64, enrollmentId int64, programDayId int64) (*WorkoutSessionImpl, error)
+		CreateImportedTx(tx dbr.SessionRunner, m *WorkoutSessionImpl) (*WorkoutSessionImpl, error)
+		LoadImportKeys(userId int64, keys []string) (map[string]bool, error)
+		LinkProgramDay(id int64, enrollmentId int64, programDayId int64) (bool, error)
+		UnlinkEnrollmentTx(tx dbr.SessionRunner, enrollmentId int64) (int64, error)
+		Delete(id int64) (bool, error)
+		DeleteTx(tx dbr.SessionRunner, id int64) (bool, error)
 	}
 
 	// WorkoutSessionImpl ワークアウトを表す
 	WorkoutSessionImpl struct {
-		ID     int64     `db:"session_id" dbopt:"auto_increment"`
-		Date   time.Time `db:"training_date"`
-		UserID int64     `db:"user_id"`
+		ID         int64         `db:"session_id" dbopt:"auto_increment"`
+		Date       time.Time     `db:"training_date"`
+		UserID     int64         `db:"user_id"`
+		TemplateID dbr.NullInt64 `db:"template_id"`
+		// プログラムの予定の日として実施した場合の参加IDと予定の日のID
+		EnrollmentID dbr.NullInt64 `db:"enrollment_id"`
+		ProgramDayID dbr.NullInt64 `db:"program_day_id"`
+		Status       string        `db:"status"`
+		StartedAt    dbr.NullTime  `db:"started_at"`
+		FinishedAt   dbr.NullTime  `db:"finished_at"`
+		Notes        string        `db:"notes"`
+		Rating       dbr.NullInt64 `db:"rating"`
+		// 他のアプリから取り込んだ場合の取り込み元を表すキー
+		ImportKey dbr.NullString `db:"import_key"`
 	}
 
 	WorkoutSessions []WorkoutSessionImpl
+
+	// WorkoutSessionFilter ワークアウト一覧の検索条件を表す
+	WorkoutSessionFilter struct {
+		ID           int64
+		Date         time.Time
+		From         time.Time
+		To           time.Time
+		UserID       int64
+		ExerciseName string
+		TemplateID   int64
+		EnrollmentID int64
+		Status       string
+		Desc         bool
+		Limit        uint64
+		// カーソル(training_date, session_id)より後ろのレコードのみを対象とする
+		AfterDate time.Time
+		AfterID   int64
+	}
 )
 
 func NewWorkoutSessions() *WorkoutSessions {
@@ -35,21 +81,62 @@ func NewWorkoutSession() WorkoutSession {
 	return &WorkoutSessionImpl{}
 }
 
-func (r *WorkoutSessionImpl) LoadByIDAndDate(id int64, date time.Time) (*WorkoutSessions, error) {
-	return r.LoadByIDAndDateTx(db.GetSession("training_db"), id, date)
-	// return nil, nil
+// LoadByFilter 検索条件に合うワークアウトを日付順に読み込み
+func (r *WorkoutSessionImpl) LoadByFilter(filter WorkoutSessionFilter) (*WorkoutSessions, error) {
+	return r.LoadByFilterTx(db.GetSession("training_db"), filter)
 }
 
-func (r *WorkoutSessionImpl) LoadByIDAndDateTx(tx *dbr.Session, id int64, date time.Time) (*WorkoutSessions, error) {
+// LoadByFilterTx トランザクション内で検索条件に合うワークアウトを読み込み
+func (r *WorkoutSessionImpl) LoadByFilterTx(tx dbr.SessionRunner, filter WorkoutSessionFilter) (*WorkoutSessions, error) {
 	m := NewWorkoutSessions()
 
 	builder := tx.Select("*").From("workout_sessions")
 
-	if id != 0 {
-		builder = builder.Where("session_id = ?", id)
+	if filter.ID != 0 {
+		builder = builder.Where("session_id = ?", filter.ID)
+	}
+	if !filter.Date.IsZero() {
+		builder = builder.Where("training_date = ?", filter.Date)
+	}
+	if !filter.From.IsZero() {
+		builder = builder.Where("training_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		builder = builder.Where("training_date <= ?", filter.To)
+	}
+	if filter.UserID != 0 {
+		builder = builder.Where("user_id = ?", filter.UserID)
+	}
+	if filter.TemplateID != 0 {
+		builder = builder.Where("template_id = ?", filter.TemplateID)
+	}
+	if filter.EnrollmentID != 0 {
+		builder = builder.Where("enrollment_id = ?", filter.EnrollmentID)
+	}
+	if filter.Status != "" {
+		builder = builder.Where("status = ?", filter.Status)
+	}
+	if filter.ExerciseName != "" {
+		builder = builder.Where("session_id IN ?",
+			tx.Select("session_id").From("exercises").Where("exercise_name = ?", filter.ExerciseName))
+	}
+
+	if filter.Desc {
+		if filter.AfterID != 0 {
+			builder = builder.Where("(training_date < ? OR (training_date = ? AND session_id < ?))",
+				filter.AfterDate, filter.AfterDate, filter.AfterID)
+		}
+		builder = builder.OrderDesc("training_date").OrderDesc("session_id")
+	} else {
+		if filter.AfterID != 0 {
+			builder = builder.Where("(training_date > ? OR (training_date = ? AND session_id > ?))",
+				filter.AfterDate, filter.AfterDate, filter.AfterID)
+		}
+		builder = builder.OrderAsc("training_date").OrderAsc("session_id")
 	}
-	if !date.IsZero() {
-		builder = builder.Where("training_date = ?", date)
+
+	if filter.Limit != 0 {
+		builder = builder.Limit(filter.Limit)
 	}
 
 	if _, err := builder.Load(m); err != nil {
@@ -58,6 +145,28 @@ func (r *WorkoutSessionImpl) LoadByIDAndDateTx(tx *dbr.Session, id int64, date t
 	return m, nil
 }
 
+// IDs セッションIDの一覧を返却
+func (w *WorkoutSessions) IDs() []int64 {
+	ids := make([]int64, 0, len(*w))
+	for _, workoutSession := range *w {
+		ids = append(ids, workoutSession.ID)
+	}
+	return ids
+}
+
+// IsLocked 実施済みで編集できないセッションかどうか
+func (m *WorkoutSessionImpl) IsLocked() bool {
+	return m.Status == SessionStatusCompleted
+}
+
+// Duration 開始から終了までの時間。どちらかが記録されていない場合はfalseを返却
+func (m *WorkoutSessionImpl) Duration() (time.Duration, bool) {
+	if !m.StartedAt.Valid || !m.FinishedAt.Valid {
+		return 0, false
+	}
+	return m.FinishedAt.Time.Sub(m.StartedAt.Time), true
+}
+
 // Load 指定のIDを読み込み
 func (m *WorkoutSessionImpl) Load(id int64) (*WorkoutSessionImpl, error) {
 	return m.LoadTx(db.GetSession("training_db"), id)
@@ -66,21 +175,22 @@ func (m *WorkoutSessionImpl) Load(id int64) (*WorkoutSessionImpl, error) {
 
 // LoadTx トランザクション内で指定のIDを読み込み
 func (m *WorkoutSessionImpl) LoadTx(tx dbr.SessionRunner, id int64) (*WorkoutSessionImpl, error) {
-	if _, err := tx.Select("*").From("workout_sessions").Where("session_id=?", id).Load(m); err != nil {
+	r := &WorkoutSessionImpl{}
+	if _, err := tx.Select("*").From("workout_sessions").Where("session_id=?", id).Load(r); err != nil {
 		return nil, errors.Wrapf(err, "couldn't load workout_sessions")
 	}
-	return m, nil
+	return r, nil
 }
 
 // Update 更新
-func (m *WorkoutSessionImpl) Update(attrs map[string]interface{}) (bool, error) {
-	return m.UpdateTx(db.GetSession("training_db"), attrs)
+func (r *WorkoutSessionImpl) Update(id int64, attrs map[string]interface{}) (bool, error) {
+	return r.UpdateTx(db.GetSession("training_db"), id, attrs)
 	// return false, nil
 }
 
 // UpdateTx トランザクション内で更新
-func (m *WorkoutSessionImpl) UpdateTx(tx dbr.SessionRunner, attrs map[string]interface{}) (bool, error) {
-	res, err := tx.Update("workout_sessions").SetMap(attrs).Where("session_id=?", m.ID).Exec()
+func (r *WorkoutSessionImpl) UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error) {
+	res, err := tx.Update("workout_sessions").SetMap(attrs).Where("session_id=?", id).Exec()
 	if err != nil {
 		return false, errors.Wrapf(err, "couldn't update workout_sessions")
 	}
@@ -93,21 +203,25 @@ func (m *WorkoutSessionImpl) UpdateTx(tx dbr.SessionRunner, attrs map[string]int
 	return rows == 1, nil
 }
 
-// Create 作成
-func (r *WorkoutSessionImpl) Create(date time.Time, userId int64) (*WorkoutSessionImpl, error) {
-	return r.CreateTx(db.GetSession("training_db"), date, userId)
+// Create 作成。statusが空の場合は下書きとする
+func (r *WorkoutSessionImpl) Create(date time.Time, userId int64, status string) (*WorkoutSessionImpl, error) {
+	return r.CreateTx(db.GetSession("training_db"), date, userId, status)
 	// return nil, nil
 }
 
 // CreateTx トランザクション内で作成
-func (r *WorkoutSessionImpl) CreateTx(tx dbr.SessionRunner, date time.Time, userId int64) (*WorkoutSessionImpl, error) {
+func (r *WorkoutSessionImpl) CreateTx(tx dbr.SessionRunner, date time.Time, userId int64, status string) (*WorkoutSessionImpl, error) {
+	if status == "" {
+		status = SessionStatusDraft
+	}
 	m := &WorkoutSessionImpl{
 		Date:   date,
 		UserID: userId,
+		Status: status,
 	}
 
 	res, err := tx.InsertInto("workout_sessions").
-		Columns("training_date", "user_id").
+		Columns("training_date", "user_id", "status").
 		Record(m).
 		Exec()
 	if err != nil {
@@ -121,3 +235,163 @@ func (r *WorkoutSessionImpl) CreateTx(tx dbr.SessionRunner, date time.Time, user
 	m.ID = lastID
 	return m, nil
 }
+
+// CreateFromTemplateTx トランザクション内でテンプレートから下書きのセッションを作成
+func (r *WorkoutSessionImpl) CreateFromTemplateTx(tx dbr.SessionRunner, date time.Time, userId int64, templateId int64) (*WorkoutSessionImpl, error) {
+	m := &WorkoutSessionImpl{
+		Date:       date,
+		UserID:     userId,
+		TemplateID: dbr.NewNullInt64(templateId),
+		Status:     SessionStatusDraft,
+	}
+
+	res, err := tx.InsertInto("workout_sessions").
+		Columns("training_date", "user_id", "template_id", "status").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create workout_sessions")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for workout_sessions")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// CreatePlannedTx トランザクション内でプログラムの予定の日の下書きのセッションを作成
+func (r *WorkoutSessionImpl) CreatePlannedTx(tx dbr.SessionRunner, date time.Time, userId int64, enrollmentId int64, programDayId int64) (*WorkoutSessionImpl, error) {
+	m := &WorkoutSessionImpl{
+		Date:         date,
+		UserID:       userId,
+		EnrollmentID: dbr.NewNullInt64(enrollmentId),
+		ProgramDayID: dbr.NewNullInt64(programDayId),
+		Status:       SessionStatusDraft,
+	}
+
+	res, err := tx.InsertInto("workout_sessions").
+		Columns("training_date", "user_id", "enrollment_id", "program_day_id", "status").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create workout_sessions")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for workout_sessions")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// CreateImportedTx トランザクション内で他のアプリから取り込んだ実施済みのセッションを作成
+// 日付・ユーザー・取り込み元のキー・開始/終了日時・メモを記録する
+func (r *WorkoutSessionImpl) CreateImportedTx(tx dbr.SessionRunner, m *WorkoutSessionImpl) (*WorkoutSessionImpl, error) {
+	m.Status = SessionStatusCompleted
+
+	res, err := tx.InsertInto("workout_sessions").
+		Columns("training_date", "user_id", "status", "started_at", "finished_at", "notes", "import_key").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create workout_sessions")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for workout_sessions")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// LoadImportKeys 指定の取り込み元のキーのうち、ユーザーが取り込み済みのものを返却
+func (r *WorkoutSessionImpl) LoadImportKeys(userId int64, keys []string) (map[string]bool, error) {
+	return r.LoadImportKeysTx(db.GetSession("training_db"), userId, keys)
+}
+
+// LoadImportKeysTx トランザクション内で取り込み済みの取り込み元のキーを読み込み
+func (r *WorkoutSessionImpl) LoadImportKeysTx(tx dbr.SessionRunner, userId int64, keys []string) (map[string]bool, error) {
+	imported := map[string]bool{}
+	if len(keys) == 0 {
+		return imported, nil
+	}
+
+	var found []string
+	if _, err := tx.Select("import_key").
+		From("workout_sessions").
+		Where("user_id = ? AND import_key IN ?", userId, keys).
+		Load(&found); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load workout_sessions")
+	}
+	for _, key := range found {
+		imported[key] = true
+	}
+	return imported, nil
+}
+
+// LinkProgramDay セッションをプログラムの予定の日に紐づける
+func (r *WorkoutSessionImpl) LinkProgramDay(id int64, enrollmentId int64, programDayId int64) (bool, error) {
+	return r.LinkProgramDayTx(db.GetSession("training_db"), id, enrollmentId, programDayId)
+}
+
+// LinkProgramDayTx トランザクション内でセッションをプログラムの予定の日に紐づける
+func (r *WorkoutSessionImpl) LinkProgramDayTx(tx dbr.SessionRunner, id int64, enrollmentId int64, programDayId int64) (bool, error) {
+	res, err := tx.Update("workout_sessions").
+		Set("enrollment_id", enrollmentId).
+		Set("program_day_id", programDayId).
+		Where("session_id=?", id).
+		Exec()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't update workout_sessions")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows == 1, nil
+}
+
+// UnlinkEnrollmentTx トランザクション内でプログラムへの参加に紐づくセッションの紐づけを外す
+func (r *WorkoutSessionImpl) UnlinkEnrollmentTx(tx dbr.SessionRunner, enrollmentId int64) (int64, error) {
+	res, err := tx.Update("workout_sessions").
+		Set("enrollment_id", nil).
+		Set("program_day_id", nil).
+		Where("enrollment_id=?", enrollmentId).
+		Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't update workout_sessions")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows, nil
+}
+
+// Delete 削除
+func (r *WorkoutSessionImpl) Delete(id int64) (bool, error) {
+	return r.DeleteTx(db.GetSession("training_db"), id)
+}
+
+// DeleteTx トランザクション内で削除
+func (r *WorkoutSessionImpl) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	res, err := tx.DeleteFrom("workout_sessions").Where("session_id=?", id).Exec()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't delete workout_sessions")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows == 1, nil
+}
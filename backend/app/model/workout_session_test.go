@@ -1,85 +1,18 @@
 package model
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
 	"github.com/stretchr/testify/assert"
 )
 
-// func TestWorkoutSessionLoadByIDAndDate(t *testing.T) {
-// 	today := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
-// 	tomorrow := today.Add(24 * time.Hour)
-
-// 	// Create でレコードを挿入
-// 	ws1, err := NewWorkoutSession().Create(today, int64(11))
-// 	assert.NoError(t, err)
-// 	ws2, err := NewWorkoutSession().Create(today, int64(22))
-// 	assert.NoError(t, err)
-// 	ws3, err := NewWorkoutSession().Create(tomorrow, int64(33))
-// 	assert.NoError(t, err)
-
-// 	type args struct {
-// 		id   int64
-// 		date time.Time
-// 	}
-// 	tests := []struct {
-// 		testCase string
-// 		args     args
-// 		want     *WorkoutSessions
-// 	}{
-// 		{
-// 			testCase: "全件取得",
-// 			args: args{
-// 				id:   0,
-// 				date: time.Time{}, // zero 値ならフィルタ条件に掛からない
-// 			},
-// 			want: &WorkoutSessions{
-// 				*ws1, *ws2, *ws3,
-// 			},
-// 		},
-// 		{
-// 			testCase: "idで検索",
-// 			args: args{
-// 				id:   ws1.ID,
-// 				date: time.Time{},
-// 			},
-// 			want: &WorkoutSessions{
-// 				*ws1,
-// 			},
-// 		},
-// 		{
-// 			testCase: "dateで検索",
-// 			args: args{
-// 				id:   0,
-// 				date: today,
-// 			},
-// 			want: &WorkoutSessions{
-// 				*ws1, *ws2,
-// 			},
-// 		},
-// 	}
-
-// 	// 比較時に順序は保証されないのでソートオプションを利用
-// 	opts := cmp.Options{
-// 		cmpopts.SortSlices(func(a, b WorkoutSessionImpl) bool {
-// 			return a.ID < b.ID
-// 		}),
-// 	}
-// 	for _, tt := range tests {
-// 		t.Run(tt.testCase, func(t *testing.T) {
-// 			got, err := NewWorkoutSession().LoadByIDAndDate(tt.args.id, tt.args.date)
-// 			assert.NoError(t, err)
-// 			if diff := cmp.Diff(*tt.want, *got, opts); diff != "" {
-// 				t.Errorf("LoadByIDAndDate mismatch (-want +got):\n%s", diff)
-// 			}
-// 		})
-// 	}
-// }
-
 func TestWorkoutSessionLoad(t *testing.T) {
 	date := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
-	ws, err := NewWorkoutSession().Create(date, 1)
+	ws, err := NewWorkoutSession().Create(date, 1, "")
 	assert.NoError(t, err)
 
 	m, err := new(WorkoutSessionImpl).Load(ws.ID)
@@ -93,12 +26,12 @@ func TestWorkoutSessionLoad(t *testing.T) {
 
 func TestWorkoutSessionUpdate(t *testing.T) {
 	date := time.Now().Truncate(24 * time.Hour)
-	ws, err := NewWorkoutSession().Create(date, 1)
+	ws, err := NewWorkoutSession().Create(date, 1, "")
 	assert.NoError(t, err)
 
 	// user_id を更新
 	newUserID := int64(99)
-	updated, err := ws.Update(map[string]interface{}{"user_id": newUserID})
+	updated, err := NewWorkoutSession().Update(ws.ID, map[string]interface{}{"user_id": newUserID})
 
 	if assert.NoError(t, err) {
 		assert.True(t, updated)
@@ -107,10 +40,65 @@ func TestWorkoutSessionUpdate(t *testing.T) {
 
 func TestWorkoutSessionCreate(t *testing.T) {
 	date := time.Now().Truncate(24 * time.Hour)
-	m, err := NewWorkoutSession().Create(date, int64(42))
+	m, err := NewWorkoutSession().Create(date, int64(42), "")
 
 	if assert.NoError(t, err) {
 		assert.Equal(t, date, m.Date)
 		assert.Equal(t, int64(42), m.UserID)
+		// 状態の指定がない場合は下書きとする
+		assert.Equal(t, SessionStatusDraft, m.Status)
+	}
+}
+
+func TestWorkoutSessionDuration(t *testing.T) {
+	startedAt := time.Date(2024, 10, 1, 19, 0, 0, 0, time.UTC)
+	m := &WorkoutSessionImpl{StartedAt: dbr.NewNullTime(startedAt)}
+
+	_, ok := m.Duration()
+	assert.False(t, ok)
+
+	m.FinishedAt = dbr.NewNullTime(startedAt.Add(75 * time.Minute))
+	d, ok := m.Duration()
+	if assert.True(t, ok) {
+		assert.Equal(t, 75*time.Minute, d)
+	}
+}
+
+func TestWorkoutSessionDelete(t *testing.T) {
+	date := time.Now().Truncate(24 * time.Hour)
+	ws, err := NewWorkoutSession().Create(date, int64(42), "")
+	assert.NoError(t, err)
+
+	deleted, err := NewWorkoutSession().Delete(ws.ID)
+
+	if assert.NoError(t, err) {
+		assert.True(t, deleted)
+	}
+}
+
+func TestWorkoutSessionCreateImported(t *testing.T) {
+	key := fmt.Sprintf("import-%d", time.Now().UnixNano())
+	startedAt := time.Date(2023, 1, 15, 8, 30, 0, 0, time.UTC)
+	err := db.Transaction("training_db", func(tx dbr.SessionRunner) error {
+		_, err := NewWorkoutSession().CreateImportedTx(tx, &WorkoutSessionImpl{
+			Date:      time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
+			UserID:    int64(42),
+			StartedAt: dbr.NewNullTime(startedAt),
+			Notes:     "Push Day",
+			ImportKey: dbr.NewNullString(key),
+		})
+		return err
+	})
+	assert.NoError(t, err)
+
+	imported, err := NewWorkoutSession().LoadImportKeys(int64(42), []string{key, "not-imported"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]bool{key: true}, imported)
+	}
+
+	// 他のユーザーの取り込み済みのキーは対象としない
+	imported, err = NewWorkoutSession().LoadImportKeys(int64(43), []string{key})
+	if assert.NoError(t, err) {
+		assert.Empty(t, imported)
 	}
 }
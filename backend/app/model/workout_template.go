@@ -0,0 +1,152 @@
+package model
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/pkg/errors"
+)
+
+type (
+	// WorkoutTemplate ワークアウトのテンプレートのインターフェースを表す
+	WorkoutTemplate interface {
+		LoadByUserID(userId int64) (*WorkoutTemplates, error)
+		LoadByName(userId int64, name string) (*WorkoutTemplateImpl, error)
+		Load(id int64) (*WorkoutTemplateImpl, error)
+		Update(id int64, attrs map[string]interface{}) (bool, error)
+		UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error)
+		CreateTx(tx dbr.SessionRunner, userId int64, name string) (*WorkoutTemplateImpl, error)
+		DeleteTx(tx dbr.SessionRunner, id int64) (bool, error)
+	}
+
+	// WorkoutTemplateImpl ワークアウトのテンプレートを表す
+	WorkoutTemplateImpl struct {
+		ID     int64  `db:"template_id" dbopt:"auto_increment"`
+		UserID int64  `db:"user_id"`
+		Name   string `db:"name"`
+	}
+
+	WorkoutTemplates []WorkoutTemplateImpl
+)
+
+func NewWorkoutTemplates() *WorkoutTemplates {
+	return &WorkoutTemplates{}
+}
+
+func NewWorkoutTemplate() WorkoutTemplate {
+	return &WorkoutTemplateImpl{}
+}
+
+// LoadByUserID ユーザーのテンプレートを名前順に読み込み
+func (r *WorkoutTemplateImpl) LoadByUserID(userId int64) (*WorkoutTemplates, error) {
+	return r.LoadByUserIDTx(db.GetSession("training_db"), userId)
+}
+
+// LoadByUserIDTx トランザクション内でユーザーのテンプレートを読み込み
+func (r *WorkoutTemplateImpl) LoadByUserIDTx(tx dbr.SessionRunner, userId int64) (*WorkoutTemplates, error) {
+	m := NewWorkoutTemplates()
+	if _, err := tx.Select("*").From("workout_templates").
+		Where("user_id = ?", userId).
+		OrderBy("name").
+		OrderBy("template_id").
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load workout_templates")
+	}
+	return m, nil
+}
+
+// IDs テンプレートIDの一覧を返却
+func (w *WorkoutTemplates) IDs() []int64 {
+	ids := make([]int64, 0, len(*w))
+	for _, template := range *w {
+		ids = append(ids, template.ID)
+	}
+	return ids
+}
+
+// LoadByName ユーザーの指定の名前のテンプレートを読み込み。存在しない場合はIDが0
+func (r *WorkoutTemplateImpl) LoadByName(userId int64, name string) (*WorkoutTemplateImpl, error) {
+	return r.LoadByNameTx(db.GetSession("training_db"), userId, name)
+}
+
+// LoadByNameTx トランザクション内でユーザーの指定の名前のテンプレートを読み込み
+func (r *WorkoutTemplateImpl) LoadByNameTx(tx dbr.SessionRunner, userId int64, name string) (*WorkoutTemplateImpl, error) {
+	m := &WorkoutTemplateImpl{}
+	if _, err := tx.Select("*").From("workout_templates").
+		Where("user_id = ? AND name = ?", userId, name).
+		Load(m); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load workout_templates")
+	}
+	return m, nil
+}
+
+// Load 指定のIDを読み込み
+func (m *WorkoutTemplateImpl) Load(id int64) (*WorkoutTemplateImpl, error) {
+	return m.LoadTx(db.GetSession("training_db"), id)
+}
+
+// LoadTx トランザクション内で指定のIDを読み込み
+func (m *WorkoutTemplateImpl) LoadTx(tx dbr.SessionRunner, id int64) (*WorkoutTemplateImpl, error) {
+	r := &WorkoutTemplateImpl{}
+	if _, err := tx.Select("*").From("workout_templates").Where("template_id=?", id).Load(r); err != nil {
+		return nil, errors.Wrapf(err, "couldn't load workout_templates")
+	}
+	return r, nil
+}
+
+// Update 更新
+func (m *WorkoutTemplateImpl) Update(id int64, attrs map[string]interface{}) (bool, error) {
+	return m.UpdateTx(db.GetSession("training_db"), id, attrs)
+}
+
+// UpdateTx トランザクション内で更新
+func (m *WorkoutTemplateImpl) UpdateTx(tx dbr.SessionRunner, id int64, attrs map[string]interface{}) (bool, error) {
+	res, err := tx.Update("workout_templates").SetMap(attrs).Where("template_id=?", id).Exec()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't update workout_templates")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows == 1, nil
+}
+
+// CreateTx トランザクション内で作成
+func (r *WorkoutTemplateImpl) CreateTx(tx dbr.SessionRunner, userId int64, name string) (*WorkoutTemplateImpl, error) {
+	m := &WorkoutTemplateImpl{
+		UserID: userId,
+		Name:   name,
+	}
+
+	res, err := tx.InsertInto("workout_templates").
+		Columns("user_id", "name").
+		Record(m).
+		Exec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't create workout_templates")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't get last insert id for workout_templates")
+	}
+	m.ID = lastID
+	return m, nil
+}
+
+// DeleteTx トランザクション内で削除。テンプレートから作成したセッションは紐づけを外して残す
+func (r *WorkoutTemplateImpl) DeleteTx(tx dbr.SessionRunner, id int64) (bool, error) {
+	res, err := tx.DeleteFrom("workout_templates").Where("template_id=?", id).Exec()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't delete workout_templates")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrapf(err, "couldn't fetch result")
+	}
+
+	return rows == 1, nil
+}
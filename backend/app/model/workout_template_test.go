@@ -0,0 +1,73 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkoutTemplateCreate(t *testing.T) {
+	name := fmt.Sprintf("Push %d", time.Now().UnixNano())
+	var template *WorkoutTemplateImpl
+	err := db.Transaction("training_db", func(tx dbr.SessionRunner) error {
+		var err error
+		template, err = NewWorkoutTemplate().CreateTx(tx, int64(42), name)
+		if err != nil {
+			return err
+		}
+		exercise, err := NewTemplateExercise().CreateTx(tx, template.ID, int64(1), "ベンチプレス", int64(1), "")
+		if err != nil {
+			return err
+		}
+		_, err = NewTemplateSet().CreateTx(tx, exercise.ID, SetTarget{SetNumber: 1, Weight: 60, Unit: "kg", Reps: 10})
+		return err
+	})
+	assert.NoError(t, err)
+
+	m, err := NewWorkoutTemplate().LoadByName(int64(42), name)
+	if assert.NoError(t, err) {
+		assert.Equal(t, template.ID, m.ID)
+	}
+
+	exercises, err := NewTemplateExercise().LoadByTemplateIDs([]int64{template.ID})
+	if assert.NoError(t, err) && assert.Len(t, *exercises, 1) {
+		assert.Equal(t, ModalityWeighted, (*exercises)[0].Modality)
+
+		sets, err := NewTemplateSet().LoadByTemplateExerciseIDs(exercises.IDs())
+		if assert.NoError(t, err) && assert.Len(t, *sets, 1) {
+			// セットの種類の指定がない場合はworkingとする
+			assert.Equal(t, SetTypeWorking, (*sets)[0].SetType)
+			assert.Equal(t, float64(60), (*sets)[0].Weight)
+		}
+	}
+}
+
+func TestWorkoutTemplateDelete(t *testing.T) {
+	name := fmt.Sprintf("Pull %d", time.Now().UnixNano())
+	var deleted bool
+	err := db.Transaction("training_db", func(tx dbr.SessionRunner) error {
+		template, err := NewWorkoutTemplate().CreateTx(tx, int64(42), name)
+		if err != nil {
+			return err
+		}
+		if _, err := NewTemplateExercise().CreateTx(tx, template.ID, int64(1), "懸垂", int64(0), ModalityBodyweight); err != nil {
+			return err
+		}
+		if _, err := NewTemplateSet().DeleteByTemplateIDTx(tx, template.ID); err != nil {
+			return err
+		}
+		if _, err := NewTemplateExercise().DeleteByTemplateIDTx(tx, template.ID); err != nil {
+			return err
+		}
+		deleted, err = NewWorkoutTemplate().DeleteTx(tx, template.ID)
+		return err
+	})
+
+	if assert.NoError(t, err) {
+		assert.True(t, deleted)
+	}
+}
@@ -0,0 +1,46 @@
+package response
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+)
+
+type (
+	CatalogEntry struct {
+		ID               int64          `json:"catalog_id"`
+		NameJa           string         `json:"name_ja"`
+		NameEn           string         `json:"name_en"`
+		PrimaryMuscle    string         `json:"primary_muscle"`
+		SecondaryMuscles []string       `json:"secondary_muscles"`
+		Equipment        string         `json:"equipment"`
+		Modality         string         `json:"modality"`
+		Aliases          []CatalogAlias `json:"aliases"`
+	}
+
+	CatalogEntries []CatalogEntry
+
+	CatalogAlias struct {
+		Alias  string `json:"alias"`
+		Locale string `json:"locale"`
+	}
+)
+
+func NewCatalogEntry() *CatalogEntry {
+	return &CatalogEntry{}
+}
+
+func (r *CatalogEntry) CatalogEntryFromModel(m *model.ExerciseCatalogImpl, aliases *model.ExerciseAliases) *CatalogEntry {
+	r.ID = m.ID
+	r.NameJa = m.NameJa
+	r.NameEn = m.NameEn
+	r.PrimaryMuscle = m.PrimaryMuscle
+	r.SecondaryMuscles = m.SecondaryMuscleList()
+	r.Equipment = m.Equipment
+	r.Modality = model.NormalizeModality(m.Modality)
+	r.Aliases = []CatalogAlias{}
+	if aliases != nil {
+		for _, a := range *aliases {
+			r.Aliases = append(r.Aliases, CatalogAlias{Alias: a.Alias, Locale: a.Locale})
+		}
+	}
+	return r
+}
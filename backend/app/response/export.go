@@ -0,0 +1,150 @@
+package response
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+)
+
+// ExportColumns CSVのヘッダ。ExportedSet.CSVRecordの並びと揃える
+var ExportColumns = []string{
+	"date", "session_id", "status", "exercise_id", "exercise_name", "catalog_id", "modality",
+	"set_id", "set_number", "set_type", "weight", "unit", "reps",
+	"duration_seconds", "distance_meters", "rpe", "rir", "rest_seconds", "tempo", "performed_at", "notes",
+}
+
+type (
+	// ExportedSet エクスポートする1セット分の記録を表す。記録のない項目は省略する
+	ExportedSet struct {
+		Date            string   `json:"date"`
+		SessionID       int64    `json:"session_id"`
+		Status          string   `json:"status"`
+		ExerciseID      int64    `json:"exercise_id"`
+		ExerciseName    string   `json:"exercise_name"`
+		CatalogID       int64    `json:"catalog_id,omitempty"`
+		Modality        string   `json:"modality"`
+		SetID           int64    `json:"set_id"`
+		SetNumber       int64    `json:"set_number"`
+		SetType         string   `json:"set_type"`
+		Weight          float64  `json:"weight"`
+		Unit            string   `json:"unit"`
+		Reps            int64    `json:"reps"`
+		DurationSeconds *int64   `json:"duration_seconds,omitempty"`
+		DistanceMeters  *float64 `json:"distance_meters,omitempty"`
+		RPE             *float64 `json:"rpe,omitempty"`
+		RIR             *int64   `json:"rir,omitempty"`
+		RestSeconds     *int64   `json:"rest_seconds,omitempty"`
+		Tempo           string   `json:"tempo,omitempty"`
+		PerformedAt     string   `json:"performed_at,omitempty"`
+		Notes           string   `json:"notes,omitempty"`
+	}
+)
+
+func NewExportedSet() *ExportedSet {
+	return &ExportedSet{}
+}
+
+// ExportedSetFromModel エクスポートする記録に変換。重量は指定の単位で出力する
+func (r *ExportedSet) ExportedSetFromModel(m *model.SetExport, unit units.Unit) *ExportedSet {
+	*r = ExportedSet{
+		Date:         m.TrainingDate.Format("2006-01-02"),
+		SessionID:    m.SessionID,
+		Status:       m.SessionStatus,
+		ExerciseID:   m.ExerciseID,
+		ExerciseName: m.ExerciseName,
+		CatalogID:    m.CatalogID,
+		Modality:     model.NormalizeModality(m.Modality),
+		SetID:        m.SetID,
+		SetNumber:    m.SetNumber,
+		SetType:      m.SetType,
+		Weight:       units.Weight(m.Weight, units.Unit(m.Unit), unit),
+		Unit:         string(unit),
+		Reps:         m.Reps,
+		Tempo:        m.Tempo,
+		Notes:        m.Notes,
+	}
+	if m.DurationSeconds.Valid {
+		durationSeconds := m.DurationSeconds.Int64
+		r.DurationSeconds = &durationSeconds
+	}
+	if m.DistanceMeters.Valid {
+		distanceMeters := m.DistanceMeters.Float64
+		r.DistanceMeters = &distanceMeters
+	}
+	if m.RPE.Valid {
+		rpe := m.RPE.Float64
+		r.RPE = &rpe
+	}
+	if m.RIR.Valid {
+		rir := m.RIR.Int64
+		r.RIR = &rir
+	}
+	if m.RestSeconds.Valid {
+		restSeconds := m.RestSeconds.Int64
+		r.RestSeconds = &restSeconds
+	}
+	if m.PerformedAt.Valid {
+		r.PerformedAt = m.PerformedAt.Time.Format(time.RFC3339)
+	}
+	return r
+}
+
+// CSVRecord ExportColumnsの並びでCSVの1行に変換。記録のない項目は空欄とする
+func (r *ExportedSet) CSVRecord() []string {
+	return []string{
+		r.Date,
+		strconv.FormatInt(r.SessionID, 10),
+		r.Status,
+		strconv.FormatInt(r.ExerciseID, 10),
+		csvText(r.ExerciseName),
+		catalogID(r.CatalogID),
+		r.Modality,
+		strconv.FormatInt(r.SetID, 10),
+		strconv.FormatInt(r.SetNumber, 10),
+		r.SetType,
+		strconv.FormatFloat(r.Weight, 'f', -1, 64),
+		r.Unit,
+		strconv.FormatInt(r.Reps, 10),
+		optionalInt(r.DurationSeconds),
+		optionalFloat(r.DistanceMeters),
+		optionalFloat(r.RPE),
+		optionalInt(r.RIR),
+		optionalInt(r.RestSeconds),
+		csvText(r.Tempo),
+		r.PerformedAt,
+		csvText(r.Notes),
+	}
+}
+
+// csvText 表計算ソフトで数式として解釈される先頭の文字の前に'を付ける
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// catalogID カタログに紐づかない種目は空欄とする
+func catalogID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+func optionalInt(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
+
+func optionalFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
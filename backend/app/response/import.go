@@ -0,0 +1,52 @@
+package response
+
+type (
+	// ImportResult 他のアプリの記録の取り込み結果を表す。dry-runの場合は取り込む予定の内容とする
+	ImportResult struct {
+		Format string `json:"format"`
+		DryRun bool   `json:"dry_run"`
+		// 取り込んだ(dry-runでは取り込む)セッション・セットの数
+		Imported int `json:"imported"`
+		Sets     int `json:"sets"`
+		// 取り込み済みのため読み飛ばしたセッションの数
+		Duplicates int                `json:"duplicates"`
+		Sessions   []ImportedSession  `json:"sessions"`
+		Exercises  []ImportedExercise `json:"exercises"`
+		Errors     []ImportError      `json:"errors"`
+	}
+
+	// ImportedSession 取り込むセッションを表す。IDは取り込んだ場合のみ設定する
+	ImportedSession struct {
+		ID        int64  `json:"id,omitempty"`
+		Date      string `json:"date"`
+		Name      string `json:"name"`
+		Exercises int    `json:"exercises"`
+		Sets      int    `json:"sets"`
+		Duplicate bool   `json:"duplicate"`
+	}
+
+	// ImportedExercise 取り込み元の種目名と対応づけた種目を表す
+	// Mappedは指定の対応づけを使った場合、CatalogIDが0の場合はカタログに紐づかない自由入力の種目とする
+	ImportedExercise struct {
+		SourceName   string `json:"source_name"`
+		ExerciseName string `json:"exercise_name"`
+		CatalogID    int64  `json:"catalog_id,omitempty"`
+		Modality     string `json:"modality"`
+		Mapped       bool   `json:"mapped"`
+		Sets         int    `json:"sets"`
+	}
+
+	// ImportError 取り込めない行とその理由を表す
+	ImportError struct {
+		Line    int    `json:"line"`
+		Message string `json:"message"`
+	}
+)
+
+func NewImportResult() *ImportResult {
+	return &ImportResult{
+		Sessions:  []ImportedSession{},
+		Exercises: []ImportedExercise{},
+		Errors:    []ImportError{},
+	}
+}
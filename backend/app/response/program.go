@@ -0,0 +1,235 @@
+package response
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+)
+
+// 予定の日の実施状況
+const (
+	// AdherenceCompleted 紐づけたセッションを実施済み
+	AdherenceCompleted = "completed"
+	// AdherenceMissed 予定の日を過ぎても実施していない
+	AdherenceMissed = "missed"
+	// AdherenceUpcoming 予定の日が基準日以降で、まだ実施していない
+	AdherenceUpcoming = "upcoming"
+)
+
+type (
+	// Program トレーニングプログラムを表す
+	Program struct {
+		ID    int64        `json:"program_id"`
+		Name  string       `json:"name"`
+		Weeks int64        `json:"weeks"`
+		Days  []ProgramDay `json:"days"`
+	}
+
+	Programs []Program
+
+	// ProgramDay プログラムの予定の日と種目を表す
+	ProgramDay struct {
+		ID        int64             `json:"program_day_id"`
+		Week      int64             `json:"week"`
+		Day       int64             `json:"day"`
+		Name      string            `json:"name"`
+		Exercises []ProgramExercise `json:"exercises"`
+	}
+
+	ProgramExercise struct {
+		ID           int64        `json:"program_exercise_id"`
+		Position     int64        `json:"position"`
+		CatalogID    int64        `json:"catalog_id,omitempty"`
+		ExerciseName string       `json:"exercise_name"`
+		Modality     string       `json:"modality"`
+		Sets         []ProgramSet `json:"sets"`
+	}
+
+	// ProgramSet 予定のセット。重量はトレーニングマックスに対する割合(%)
+	ProgramSet struct {
+		SetNumber  int64    `json:"set_number"`
+		SetType    string   `json:"set_type"`
+		Percentage *float64 `json:"percentage,omitempty"`
+		Reps       int64    `json:"reps"`
+	}
+
+	// Enrollment プログラムへの参加を表す
+	Enrollment struct {
+		ID            int64         `json:"enrollment_id"`
+		ProgramID     int64         `json:"program_id"`
+		StartDate     string        `json:"start_date"`
+		EndDate       string        `json:"end_date"`
+		TrainingMaxes TrainingMaxes `json:"training_maxes"`
+	}
+
+	Enrollments []Enrollment
+
+	// TrainingMax 種目ごとのトレーニングマックスを表す
+	TrainingMax struct {
+		CatalogID    int64   `json:"catalog_id,omitempty"`
+		ExerciseName string  `json:"exercise_name,omitempty"`
+		Weight       float64 `json:"weight"`
+		Unit         string  `json:"unit"`
+		// 単位の変換元として保存したkgの重量と入力時の単位を保持する
+		Kilograms   float64 `json:"-"`
+		EnteredUnit string  `json:"-"`
+	}
+
+	TrainingMaxes []TrainingMax
+
+	// PlannedSession 指定の日の予定を表す。予定のない日はRestDayとする
+	PlannedSession struct {
+		EnrollmentID int64  `json:"enrollment_id"`
+		Date         string `json:"date"`
+		Week         int64  `json:"week"`
+		Day          int64  `json:"day"`
+		RestDay      bool   `json:"rest_day"`
+		ProgramDayID int64  `json:"program_day_id,omitempty"`
+		Name         string `json:"name,omitempty"`
+		// 予定の日に紐づけたセッションのID
+		SessionID int64             `json:"session_id,omitempty"`
+		Exercises []PlannedExercise `json:"exercises"`
+	}
+
+	// PlannedExercise 予定の種目と、トレーニングマックスから求めた目標セットを表す
+	PlannedExercise struct {
+		CatalogID    int64      `json:"catalog_id,omitempty"`
+		ExerciseName string     `json:"exercise_name"`
+		Modality     string     `json:"modality"`
+		Sets         TargetSets `json:"sets"`
+	}
+
+	// Adherence プログラムの予定の日ごとの実施状況と実施率を表す
+	// 実施率は基準日より前の予定の日と実施済みの日のうち、実施済みの割合
+	Adherence struct {
+		EnrollmentID int64          `json:"enrollment_id"`
+		AsOf         string         `json:"as_of"`
+		Planned      int64          `json:"planned"`
+		Completed    int64          `json:"completed"`
+		Missed       int64          `json:"missed"`
+		Upcoming     int64          `json:"upcoming"`
+		Rate         float64        `json:"rate"`
+		Days         []AdherenceDay `json:"days"`
+	}
+
+	AdherenceDay struct {
+		Date         string `json:"date"`
+		Week         int64  `json:"week"`
+		Day          int64  `json:"day"`
+		ProgramDayID int64  `json:"program_day_id"`
+		Name         string `json:"name"`
+		Status       string `json:"status"`
+		SessionID    int64  `json:"session_id,omitempty"`
+	}
+)
+
+func NewProgram() *Program {
+	return &Program{}
+}
+
+func NewEnrollment() *Enrollment {
+	return &Enrollment{}
+}
+
+func (r *Program) ProgramFromModel(program *model.ProgramImpl, days *model.ProgramDays, exercisesByDay map[int64]*model.ProgramExercises, setsByExercise map[int64]*model.ProgramSets) *Program {
+	r.ID = program.ID
+	r.Name = program.Name
+	r.Weeks = program.Weeks
+	r.Days = []ProgramDay{}
+	if days == nil {
+		return r
+	}
+	for _, day := range *days {
+		d := ProgramDay{
+			ID:        day.ID,
+			Week:      day.Week,
+			Day:       day.Day,
+			Name:      day.Name,
+			Exercises: []ProgramExercise{},
+		}
+		if exercises, ok := exercisesByDay[day.ID]; ok {
+			for _, exercise := range *exercises {
+				e := ProgramExercise{
+					ID:           exercise.ID,
+					Position:     exercise.Position,
+					CatalogID:    exercise.CatalogID.Int64,
+					ExerciseName: exercise.ExerciseName,
+					Modality:     model.NormalizeModality(exercise.Modality),
+					Sets:         []ProgramSet{},
+				}
+				if sets, ok := setsByExercise[exercise.ID]; ok {
+					for _, set := range *sets {
+						e.Sets = append(e.Sets, programSetFromModel(set.PlannedSet))
+					}
+				}
+				d.Exercises = append(d.Exercises, e)
+			}
+		}
+		r.Days = append(r.Days, d)
+	}
+	return r
+}
+
+func programSetFromModel(planned model.PlannedSet) ProgramSet {
+	r := ProgramSet{
+		SetNumber: planned.SetNumber,
+		SetType:   planned.SetType,
+		Reps:      planned.Reps,
+	}
+	if planned.Percentage.Valid {
+		percentage := planned.Percentage.Float64
+		r.Percentage = &percentage
+	}
+	return r
+}
+
+func (r *Enrollment) EnrollmentFromModel(enrollment *model.ProgramEnrollmentImpl, program *model.ProgramImpl, maxes *model.TrainingMaxes) *Enrollment {
+	r.ID = enrollment.ID
+	r.ProgramID = enrollment.ProgramID
+	r.StartDate = enrollment.StartDate.Format("2006-01-02")
+	r.EndDate = enrollment.StartDate.AddDate(0, 0, int(program.Weeks)*model.DaysPerWeek-1).Format("2006-01-02")
+	r.TrainingMaxes = TrainingMaxes{}
+	if maxes == nil {
+		return r
+	}
+	for _, tm := range *maxes {
+		r.TrainingMaxes = append(r.TrainingMaxes, TrainingMax{
+			CatalogID:    tm.CatalogID,
+			ExerciseName: tm.ExerciseName,
+			Kilograms:    tm.Weight,
+			EnteredUnit:  tm.Unit,
+		})
+	}
+	r.TrainingMaxes.ApplyUnit(units.DefaultUnit)
+	return r
+}
+
+// ApplyUnit トレーニングマックスの重量を指定の単位に変換
+func (r *Enrollment) ApplyUnit(unit units.Unit) *Enrollment {
+	r.TrainingMaxes.ApplyUnit(unit)
+	return r
+}
+
+// ApplyUnit 一覧の各参加の重量を指定の単位に変換
+func (r Enrollments) ApplyUnit(unit units.Unit) Enrollments {
+	for i := range r {
+		r[i].ApplyUnit(unit)
+	}
+	return r
+}
+
+// ApplyUnit 各トレーニングマックスの重量を指定の単位に変換
+func (r TrainingMaxes) ApplyUnit(unit units.Unit) TrainingMaxes {
+	for i := range r {
+		r[i].Unit = string(unit)
+		r[i].Weight = units.Weight(r[i].Kilograms, units.Unit(r[i].EnteredUnit), unit)
+	}
+	return r
+}
+
+// ApplyUnit 予定の目標セットの重量を指定の単位に変換
+func (r *PlannedSession) ApplyUnit(unit units.Unit) *PlannedSession {
+	for i := range r.Exercises {
+		r.Exercises[i].Sets.ApplyUnit(unit)
+	}
+	return r
+}
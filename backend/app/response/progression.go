@@ -0,0 +1,135 @@
+package response
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+)
+
+// 次のセッションの提案の種類
+const (
+	// SuggestionStart 記録がないため回数のみを提案する
+	SuggestionStart = "start"
+	// SuggestionIncrease 重量を上げる
+	SuggestionIncrease = "increase"
+	// SuggestionAddReps 同じ重量で回数を増やす
+	SuggestionAddReps = "add_reps"
+	// SuggestionRepeat 同じ重量・回数をもう一度行う
+	SuggestionRepeat = "repeat"
+	// SuggestionDecrease RPEが目標より高かったため重量を下げる
+	SuggestionDecrease = "decrease"
+	// SuggestionDeload 連続で失敗したため重量を下げてやり直す
+	SuggestionDeload = "deload"
+)
+
+type (
+	// ProgressionRule 種目ごとの進め方を表す
+	ProgressionRule struct {
+		ID               int64    `json:"rule_id"`
+		ExerciseName     string   `json:"exercise_name"`
+		CatalogID        int64    `json:"catalog_id,omitempty"`
+		Strategy         string   `json:"strategy"`
+		Increment        float64  `json:"increment"`
+		Unit             string   `json:"unit"`
+		Sets             int64    `json:"sets"`
+		MinReps          int64    `json:"min_reps"`
+		MaxReps          int64    `json:"max_reps"`
+		TargetRPE        *float64 `json:"target_rpe,omitempty"`
+		DeloadAfter      int64    `json:"deload_after"`
+		DeloadPercentage float64  `json:"deload_percentage"`
+		// 単位の変換元として保存したkgの増分と入力時の単位を保持する
+		Kilograms   float64 `json:"-"`
+		EnteredUnit string  `json:"-"`
+	}
+
+	ProgressionRules []ProgressionRule
+
+	// ProgressionSuggestion 種目の次のセッションで行うメインセットの提案を表す
+	// 提案の根拠とした直近のセッションと、直近から連続で失敗したセッション数をあわせて返却する
+	ProgressionSuggestion struct {
+		ExerciseName        string         `json:"exercise_name"`
+		CatalogID           int64          `json:"catalog_id,omitempty"`
+		Strategy            string         `json:"strategy"`
+		Action              string         `json:"action"`
+		LastSessionID       int64          `json:"last_session_id,omitempty"`
+		LastTrainingDate    string         `json:"last_training_date,omitempty"`
+		ConsecutiveFailures int64          `json:"consecutive_failures"`
+		Unit                string         `json:"unit"`
+		Sets                []SuggestedSet `json:"sets"`
+	}
+
+	// SuggestedSet 提案するセットを表す。記録がない場合の重量は0
+	SuggestedSet struct {
+		SetNumber int64    `json:"set_number"`
+		Weight    float64  `json:"weight"`
+		Unit      string   `json:"unit"`
+		Reps      int64    `json:"reps"`
+		RPE       *float64 `json:"rpe,omitempty"`
+		// 単位の変換元として求めたkgの重量と進め方の単位を保持する
+		Kilograms   float64 `json:"-"`
+		EnteredUnit string  `json:"-"`
+	}
+)
+
+func NewProgressionRule() *ProgressionRule {
+	return &ProgressionRule{}
+}
+
+func (r *ProgressionRule) ProgressionRuleFromModel(m *model.ProgressionRuleImpl) *ProgressionRule {
+	r.ID = m.ID
+	r.ExerciseName = m.DisplayName
+	if r.ExerciseName == "" {
+		r.ExerciseName = m.ExerciseName
+	}
+	r.CatalogID = m.CatalogID
+	r.Strategy = m.Strategy
+	r.Kilograms = m.Increment
+	r.EnteredUnit = m.Unit
+	r.Sets = m.Sets
+	r.MinReps = m.MinReps
+	r.MaxReps = m.MaxReps
+	if m.TargetRPE.Valid {
+		targetRPE := m.TargetRPE.Float64
+		r.TargetRPE = &targetRPE
+	}
+	r.DeloadAfter = m.DeloadAfter
+	r.DeloadPercentage = m.DeloadPercentage
+	return r.ApplyUnit(units.DefaultUnit)
+}
+
+// ApplyUnit 重量の増分を指定の単位に変換
+func (r *ProgressionRule) ApplyUnit(unit units.Unit) *ProgressionRule {
+	r.Unit = string(unit)
+	r.Increment = units.Weight(r.Kilograms, units.Unit(r.EnteredUnit), unit)
+	return r
+}
+
+// ApplyUnit 各進め方の重量の増分を指定の単位に変換
+func (r ProgressionRules) ApplyUnit(unit units.Unit) ProgressionRules {
+	for i := range r {
+		r[i].ApplyUnit(unit)
+	}
+	return r
+}
+
+func ProgressionRulesFromModel(m *model.ProgressionRules) ProgressionRules {
+	r := ProgressionRules{}
+	for _, rule := range *m {
+		r = append(r, *NewProgressionRule().ProgressionRuleFromModel(&rule))
+	}
+	return r
+}
+
+func NewProgressionSuggestion() *ProgressionSuggestion {
+	return &ProgressionSuggestion{Unit: string(units.DefaultUnit), Sets: []SuggestedSet{}}
+}
+
+// ApplyUnit 提案する各セットの重量を指定の単位に変換
+func (r *ProgressionSuggestion) ApplyUnit(unit units.Unit) *ProgressionSuggestion {
+	r.Unit = string(unit)
+	for i := range r.Sets {
+		set := &r.Sets[i]
+		set.Unit = string(unit)
+		set.Weight = units.Weight(set.Kilograms, units.Unit(set.EnteredUnit), unit)
+	}
+	return r
+}
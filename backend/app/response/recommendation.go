@@ -0,0 +1,36 @@
+package response
+
+type (
+	// TrainingMenu 提案したトレーニングメニューを表す
+	TrainingMenu struct {
+		// 保存した提案のID。セッションの作成に使う
+		ID    int64  `json:"id,omitempty"`
+		Title string `json:"title"`
+		// メニュー全体の所要時間(分)
+		EstimatedMinutes int            `json:"estimated_minutes"`
+		Exercises        []MenuExercise `json:"exercises"`
+		Notes            string         `json:"notes"`
+	}
+
+	// MenuExercise 提案したメニューの種目を表す
+	MenuExercise struct {
+		Name        string `json:"name"`
+		Sets        int    `json:"sets"`
+		Reps        int    `json:"reps"`
+		RestSeconds int    `json:"rest_seconds"`
+		// 休憩を含めた種目の所要時間(分)
+		EstimatedMinutes int    `json:"estimated_minutes"`
+		Notes            string `json:"notes"`
+	}
+
+	// MenuDelta 生成中のメニューの出力の差分を表す
+	// 不正な出力を修正させる場合はAttemptが増えるため、それまでの差分を破棄してつなぎ直す
+	MenuDelta struct {
+		Attempt int    `json:"attempt"`
+		Content string `json:"content"`
+	}
+)
+
+func NewTrainingMenu() *TrainingMenu {
+	return &TrainingMenu{}
+}
@@ -0,0 +1,76 @@
+package response
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+)
+
+type (
+	PersonalRecord struct {
+		ExerciseName string  `json:"exercise_name"`
+		CatalogID    int64   `json:"catalog_id,omitempty"`
+		RecordType   string  `json:"record_type"`
+		Value        float64 `json:"value"`
+		Weight       float64 `json:"weight"`
+		Unit         string  `json:"unit"`
+		Reps         int64   `json:"reps"`
+		SessionID    int64   `json:"session_id"`
+		SetID        int64   `json:"set_id,omitempty"`
+		AchievedOn   string  `json:"achieved_on"`
+		// 単位の変換元として保存したkgの重量と入力時の単位を保持する
+		Kilograms   float64 `json:"-"`
+		EnteredUnit string  `json:"-"`
+	}
+
+	PersonalRecords []PersonalRecord
+)
+
+func NewPersonalRecord() *PersonalRecord {
+	return &PersonalRecord{}
+}
+
+func (r *PersonalRecord) PersonalRecordFromModel(m *model.PersonalRecordImpl) *PersonalRecord {
+	r.ExerciseName = m.DisplayName
+	r.CatalogID = m.CatalogID
+	r.RecordType = m.RecordType
+	r.Value = m.Value
+	r.Kilograms = m.Weight
+	r.EnteredUnit = m.Unit
+	r.Reps = m.Reps
+	r.SessionID = m.SessionID
+	r.SetID = m.SetID
+	r.AchievedOn = m.AchievedOn.Format("2006-01-02")
+	r.Unit = string(units.Kilogram)
+	r.ApplyUnit(units.DefaultUnit)
+	return r
+}
+
+// ApplyUnit 重量を指定の単位に変換。回数の記録は変換しない
+func (r *PersonalRecord) ApplyUnit(unit units.Unit) *PersonalRecord {
+	from := units.Unit(r.Unit)
+	r.Unit = string(unit)
+	r.Weight = units.Weight(r.Kilograms, units.Unit(r.EnteredUnit), unit)
+	switch r.RecordType {
+	case model.RecordTypeMaxWeight:
+		r.Value = r.Weight
+	case model.RecordTypeEstimatedOneRepMax, model.RecordTypeSessionVolume:
+		r.Value = units.Convert(r.Value, from, unit)
+	}
+	return r
+}
+
+// ApplyUnit 各記録の重量を指定の単位に変換
+func (r PersonalRecords) ApplyUnit(unit units.Unit) PersonalRecords {
+	for i := range r {
+		r[i].ApplyUnit(unit)
+	}
+	return r
+}
+
+func PersonalRecordsFromModel(m *model.PersonalRecords) PersonalRecords {
+	r := PersonalRecords{}
+	for _, record := range *m {
+		r = append(r, *NewPersonalRecord().PersonalRecordFromModel(&record))
+	}
+	return r
+}
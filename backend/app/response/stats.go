@@ -0,0 +1,156 @@
+package response
+
+import (
+	"math"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+)
+
+type (
+	ExerciseProgress struct {
+		ExerciseName string          `json:"exercise_name"`
+		CatalogID    int64           `json:"catalog_id,omitempty"`
+		Bucket       string          `json:"bucket"`
+		Formula      string          `json:"formula"`
+		Unit         string          `json:"unit"`
+		Points       []ProgressPoint `json:"points"`
+	}
+
+	// ProgressPoint 集計単位ごとの推移の1点を表す
+	ProgressPoint struct {
+		Date               string  `json:"date"`
+		Sessions           int64   `json:"sessions"`
+		TopSet             TopSet  `json:"top_set"`
+		EstimatedOneRepMax float64 `json:"e1rm"`
+		Volume             float64 `json:"volume"`
+		Reps               int64   `json:"reps"`
+		// 時間の種目・有酸素種目の時間(秒)と距離(m)の合計
+		DurationSeconds int64   `json:"duration_seconds,omitempty"`
+		DistanceMeters  float64 `json:"distance_meters,omitempty"`
+	}
+
+	// TopSet 最も重い重量で行ったセットを表す
+	TopSet struct {
+		Weight float64 `json:"weight"`
+		Reps   int64   `json:"reps"`
+		// 単位の変換元として保存したkgの重量と入力時の単位を保持する
+		Kilograms   float64 `json:"-"`
+		EnteredUnit string  `json:"-"`
+	}
+)
+
+func NewExerciseProgress() *ExerciseProgress {
+	return &ExerciseProgress{Unit: string(units.DefaultUnit)}
+}
+
+// NewTopSet 保存したkgの重量から既定の単位のトップセットを作成
+func NewTopSet(kilograms float64, entered string, reps int64) TopSet {
+	return TopSet{
+		Weight:      units.Weight(kilograms, units.Unit(entered), units.DefaultUnit),
+		Reps:        reps,
+		Kilograms:   kilograms,
+		EnteredUnit: entered,
+	}
+}
+
+// ApplyUnit 各点の重量・推定1RM・ボリュームを指定の単位に変換
+func (r *ExerciseProgress) ApplyUnit(unit units.Unit) *ExerciseProgress {
+	from := units.Unit(r.Unit)
+	r.Unit = string(unit)
+	for i := range r.Points {
+		point := &r.Points[i]
+		point.TopSet.Weight = units.Weight(point.TopSet.Kilograms, units.Unit(point.TopSet.EnteredUnit), unit)
+		point.EstimatedOneRepMax = units.Convert(point.EstimatedOneRepMax, from, unit)
+		point.Volume = units.Convert(point.Volume, from, unit)
+	}
+	return r
+}
+
+type (
+	// WeeklyMuscleVolume 部位ごとの週間ボリュームを表す
+	WeeklyMuscleVolume struct {
+		TimeZone string         `json:"time_zone"`
+		Unit     string         `json:"unit"`
+		From     string         `json:"from"`
+		To       string         `json:"to"`
+		Weeks    []WeeklyVolume `json:"weeks"`
+	}
+
+	// WeeklyVolume ISO週ごとの部位別の集計を表す
+	WeeklyVolume struct {
+		Week      string         `json:"week"`
+		WeekStart string         `json:"week_start"`
+		Muscles   []MuscleVolume `json:"muscles"`
+	}
+
+	// MuscleVolume 部位ごとのセット数・回数・ボリューム・時間・距離を表す
+	MuscleVolume struct {
+		Muscle          string  `json:"muscle"`
+		Sets            int64   `json:"sets"`
+		SecondarySets   int64   `json:"secondary_sets"`
+		Reps            int64   `json:"reps"`
+		Volume          float64 `json:"volume"`
+		DurationSeconds int64   `json:"duration_seconds,omitempty"`
+		DistanceMeters  float64 `json:"distance_meters,omitempty"`
+	}
+)
+
+func NewWeeklyMuscleVolume() *WeeklyMuscleVolume {
+	return &WeeklyMuscleVolume{Unit: string(units.DefaultUnit)}
+}
+
+// ApplyUnit 部位ごとのボリュームを指定の単位に変換
+func (r *WeeklyMuscleVolume) ApplyUnit(unit units.Unit) *WeeklyMuscleVolume {
+	from := units.Unit(r.Unit)
+	r.Unit = string(unit)
+	for i := range r.Weeks {
+		for j := range r.Weeks[i].Muscles {
+			muscle := &r.Weeks[i].Muscles[j]
+			muscle.Volume = units.Convert(muscle.Volume, from, unit)
+		}
+	}
+	return r
+}
+
+type (
+	// WeeklyTargetCompletion 実施済みのセッションの目標セットの達成率を期間全体と週ごとに表す
+	WeeklyTargetCompletion struct {
+		TimeZone string `json:"time_zone"`
+		From     string `json:"from"`
+		To       string `json:"to"`
+		TargetCompletion
+		Weeks []WeeklyCompletion `json:"weeks"`
+	}
+
+	// WeeklyCompletion ISO週ごとの目標セットの達成率を表す
+	WeeklyCompletion struct {
+		Week      string `json:"week"`
+		WeekStart string `json:"week_start"`
+		TargetCompletion
+	}
+
+	// TargetCompletion 目標セットの件数と実施結果ごとの件数を表す
+	// 達成率は目標どおり・目標を上回ったセットの割合とする
+	TargetCompletion struct {
+		Targets   int64   `json:"targets"`
+		Completed int64   `json:"completed"`
+		Missed    int64   `json:"missed"`
+		Exceeded  int64   `json:"exceeded"`
+		Rate      float64 `json:"rate"`
+	}
+)
+
+func NewWeeklyTargetCompletion() *WeeklyTargetCompletion {
+	return &WeeklyTargetCompletion{Weeks: []WeeklyCompletion{}}
+}
+
+// Add 目標セットの比較結果を件数に加え、達成率を計算し直す
+func (r *TargetCompletion) Add(comparison *TargetComparison) {
+	r.Targets += comparison.Targets
+	r.Completed += comparison.Completed
+	r.Missed += comparison.Missed
+	r.Exceeded += comparison.Exceeded
+	if r.Targets > 0 {
+		r.Rate = math.Round(float64(r.Completed+r.Exceeded)/float64(r.Targets)*1000) / 1000
+	}
+}
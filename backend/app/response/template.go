@@ -0,0 +1,71 @@
+package response
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+)
+
+type (
+	// Template ワークアウトのテンプレートを表す
+	Template struct {
+		ID        int64              `json:"template_id"`
+		Name      string             `json:"name"`
+		Exercises []TemplateExercise `json:"exercises"`
+	}
+
+	Templates []Template
+
+	// TemplateExercise テンプレートの種目と目標セットを表す
+	TemplateExercise struct {
+		ID           int64      `json:"template_exercise_id"`
+		Position     int64      `json:"position"`
+		CatalogID    int64      `json:"catalog_id,omitempty"`
+		ExerciseName string     `json:"exercise_name"`
+		Modality     string     `json:"modality"`
+		Sets         TargetSets `json:"sets"`
+	}
+)
+
+func NewTemplate() *Template {
+	return &Template{}
+}
+
+func (r *Template) TemplateFromModel(template *model.WorkoutTemplateImpl, exercises *model.TemplateExercises, setsByExercise map[int64]*model.TemplateSets) *Template {
+	r.ID = template.ID
+	r.Name = template.Name
+	r.Exercises = []TemplateExercise{}
+	if exercises == nil {
+		return r
+	}
+	for _, exercise := range *exercises {
+		e := TemplateExercise{
+			ID:           exercise.ID,
+			Position:     exercise.Position,
+			CatalogID:    exercise.CatalogID.Int64,
+			ExerciseName: exercise.ExerciseName,
+			Modality:     model.NormalizeModality(exercise.Modality),
+			Sets:         TargetSets{},
+		}
+		if sets, ok := setsByExercise[exercise.ID]; ok {
+			e.Sets = TargetSetsFromModel(sets.Targets())
+		}
+		r.Exercises = append(r.Exercises, e)
+	}
+	return r
+}
+
+// ApplyUnit 目標セットの重量を指定の単位に変換
+func (r *Template) ApplyUnit(unit units.Unit) *Template {
+	for i := range r.Exercises {
+		r.Exercises[i].Sets.ApplyUnit(unit)
+	}
+	return r
+}
+
+// ApplyUnit 一覧の各テンプレートの重量を指定の単位に変換
+func (r Templates) ApplyUnit(unit units.Unit) Templates {
+	for i := range r {
+		r[i].ApplyUnit(unit)
+	}
+	return r
+}
@@ -0,0 +1,53 @@
+package response
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+)
+
+type (
+	User struct {
+		ID    int64  `json:"id"`
+		Email string `json:"email"`
+		Name  string `json:"name"`
+		// 重量を表示する単位
+		WeightUnit string `json:"weight_unit"`
+		// 権限。adminのみ種目カタログを編集できる
+		Role string `json:"role"`
+	}
+
+	// Auth 認証結果としてトークンとユーザーを返却する
+	Auth struct {
+		Token string `json:"token"`
+		User  User   `json:"user"`
+	}
+)
+
+func NewUser() *User {
+	return &User{}
+}
+
+func NewAuth() *Auth {
+	return &Auth{}
+}
+
+func (r *User) UserFromModel(m *model.UserImpl) *User {
+	r.ID = m.ID
+	r.Email = m.Email
+	r.Name = m.Name
+	r.WeightUnit = m.WeightUnit
+	if r.WeightUnit == "" {
+		r.WeightUnit = string(units.DefaultUnit)
+	}
+	r.Role = m.Role
+	if r.Role == "" {
+		r.Role = model.UserRoleMember
+	}
+	return r
+}
+
+func (r *Auth) AuthFromModel(token string, m *model.UserImpl) *Auth {
+	r.Token = token
+	r.User = *NewUser().UserFromModel(m)
+	return r
+}
@@ -1,7 +1,11 @@
 package response
 
 import (
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/metrics"
 	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
 )
 
 type (
@@ -9,15 +13,44 @@ type (
 		ID     int64  `json:"id"`
 		Date   string `json:"date"`
 		UserID int64  `json:"user_id"`
+		// テンプレートから作成した場合のテンプレートID
+		TemplateID int64 `json:"template_id,omitempty"`
+		// プログラムの予定の日として実施した場合の参加IDと予定の日のID
+		EnrollmentID int64 `json:"enrollment_id,omitempty"`
+		ProgramDayID int64 `json:"program_day_id,omitempty"`
+		SessionLifecycle
+		Exercises Exercises `json:"exercises,omitempty"`
 	}
 
 	WorkoutSessions []WorkoutSession
 
+	// SessionLifecycle セッションの状態・開始/終了日時・所要時間(秒)・メモ・評価を表す
+	SessionLifecycle struct {
+		Status          string `json:"status"`
+		StartedAt       string `json:"started_at,omitempty"`
+		FinishedAt      string `json:"finished_at,omitempty"`
+		DurationSeconds *int64 `json:"duration_seconds,omitempty"`
+		Notes           string `json:"notes,omitempty"`
+		Rating          *int64 `json:"rating,omitempty"`
+	}
+
 	Exercise struct {
 		ID           int64  `json:"exercise_id"`
 		SessionID    int64  `json:"session_id"`
+		CatalogID    int64  `json:"catalog_id,omitempty"`
 		ExerciseName string `json:"exercise_name"`
+		Modality     string `json:"modality"`
 		Sets         Sets   `json:"sets"`
+		// 目標セット。前回の重量・回数を目安として事前に入れたもの
+		TargetSets TargetSets `json:"target_sets,omitempty"`
+		// 目標セットと実施したセットの比較。目標セットがない場合は省略する
+		Comparison *TargetComparison `json:"comparison,omitempty"`
+		// セットのボリュームの合計と推定1RMの最大値
+		Volume             float64 `json:"volume"`
+		EstimatedOneRepMax float64 `json:"e1rm"`
+		// セットの時間・距離の合計
+		DurationSeconds int64   `json:"duration_seconds,omitempty"`
+		DistanceMeters  float64 `json:"distance_meters,omitempty"`
 	}
 
 	Exercises []Exercise
@@ -27,16 +60,81 @@ type (
 		ExerciseID int64   `json:"exercise_id"`
 		SetNumber  int64   `json:"set_number"`
 		Weight     float64 `json:"weight"`
+		Unit       string  `json:"unit"`
 		Reps       int64   `json:"reps"`
+		// 時間の種目・有酸素種目の記録。ペースは1kmあたりの秒数
+		DurationSeconds  *int64   `json:"duration_seconds,omitempty"`
+		DistanceMeters   *float64 `json:"distance_meters,omitempty"`
+		PaceSecondsPerKm float64  `json:"pace_seconds_per_km,omitempty"`
+		// 重量x回数のボリュームと推定1RM。ウォームアップと対象外の記録方法は0とする
+		Volume             float64  `json:"volume"`
+		EstimatedOneRepMax float64  `json:"e1rm"`
+		SetType            string   `json:"set_type"`
+		RPE                *float64 `json:"rpe,omitempty"`
+		RIR                *int64   `json:"rir,omitempty"`
+		RestSeconds        *int64   `json:"rest_seconds,omitempty"`
+		Tempo              string   `json:"tempo,omitempty"`
+		PerformedAt        string   `json:"performed_at,omitempty"`
+		Notes              string   `json:"notes,omitempty"`
+		// 単位の変換元として保存したkgの重量と入力時の単位、エクササイズの記録方法を保持する
+		Kilograms   float64 `json:"-"`
+		EnteredUnit string  `json:"-"`
+		Modality    string  `json:"-"`
 	}
 
 	Sets []Set
 
+	// TargetSet 目標とするセットの重量・回数・時間・距離・RPEを表す
+	TargetSet struct {
+		SetNumber       int64    `json:"set_number"`
+		SetType         string   `json:"set_type"`
+		Weight          float64  `json:"weight"`
+		Unit            string   `json:"unit"`
+		Reps            int64    `json:"reps"`
+		DurationSeconds *int64   `json:"duration_seconds,omitempty"`
+		DistanceMeters  *float64 `json:"distance_meters,omitempty"`
+		RPE             *float64 `json:"rpe,omitempty"`
+		// 単位の変換元として保存したkgの重量と入力時の単位を保持する
+		Kilograms   float64 `json:"-"`
+		EnteredUnit string  `json:"-"`
+	}
+
+	TargetSets []TargetSet
+
+	// TargetComparison エクササイズの目標セットに対する実施結果(completed, missed, exceeded)を表す
+	TargetComparison struct {
+		Status    string `json:"status"`
+		Targets   int64  `json:"targets"`
+		Completed int64  `json:"completed"`
+		Missed    int64  `json:"missed"`
+		Exceeded  int64  `json:"exceeded"`
+		// 目標にないセット番号で実施したウォームアップ以外のセット数
+		ExtraSets int64           `json:"extra_sets"`
+		Sets      []SetComparison `json:"sets"`
+	}
+
+	// SetComparison 目標セット1件に対する実施結果を表す。実施しなかった場合set_idは省略する
+	SetComparison struct {
+		SetNumber int64  `json:"set_number"`
+		Status    string `json:"status"`
+		SetID     int64  `json:"set_id,omitempty"`
+	}
+
 	GetWorkoutSession struct {
-		ID        int64     `json:"id"`
-		Date      string    `json:"date"`
-		UserID    int64     `json:"user_id"`
+		ID     int64  `json:"id"`
+		Date   string `json:"date"`
+		UserID int64  `json:"user_id"`
+		// テンプレートから作成した場合のテンプレートID
+		TemplateID int64 `json:"template_id,omitempty"`
+		// プログラムの予定の日として実施した場合の参加IDと予定の日のID
+		EnrollmentID int64 `json:"enrollment_id,omitempty"`
+		ProgramDayID int64 `json:"program_day_id,omitempty"`
+		SessionLifecycle
 		Exercises Exercises `json:"exercises"`
+		// エクササイズのボリュームの合計と推定1RMの計算式・重量の単位
+		Volume  float64 `json:"volume"`
+		Formula string  `json:"formula"`
+		Unit    string  `json:"unit"`
 	}
 )
 
@@ -52,10 +150,18 @@ func NewExercise() *Exercise {
 	return &Exercise{}
 }
 
+func NewSet() *Set {
+	return &Set{}
+}
+
 func (r *WorkoutSession) WorkoutSessionFromModel(m *model.WorkoutSessionImpl) *WorkoutSession {
 	r.ID = m.ID
 	r.Date = m.Date.Format("2006-01-02")
 	r.UserID = m.UserID
+	r.TemplateID = m.TemplateID.Int64
+	r.EnrollmentID = m.EnrollmentID.Int64
+	r.ProgramDayID = m.ProgramDayID.Int64
+	r.SessionLifecycle = sessionLifecycleFromModel(m)
 	return r
 }
 
@@ -63,15 +169,133 @@ func (r *GetWorkoutSession) GetWorkoutSessionFromModel(workoutSession *model.Wor
 	r.ID = workoutSession.ID
 	r.Date = workoutSession.Date.Format("2006-01-02")
 	r.UserID = workoutSession.UserID
+	r.TemplateID = workoutSession.TemplateID.Int64
+	r.EnrollmentID = workoutSession.EnrollmentID.Int64
+	r.ProgramDayID = workoutSession.ProgramDayID.Int64
+	r.SessionLifecycle = sessionLifecycleFromModel(workoutSession)
 	r.Exercises = exercises
+	r.Unit = string(units.DefaultUnit)
+	r.ApplyFormula(metrics.DefaultFormula)
+	return r
+}
+
+// sessionLifecycleFromModel セッションの状態を変換し、開始・終了日時から所要時間を求める
+func sessionLifecycleFromModel(m *model.WorkoutSessionImpl) SessionLifecycle {
+	r := SessionLifecycle{
+		Status: m.Status,
+		Notes:  m.Notes,
+	}
+	if m.StartedAt.Valid {
+		r.StartedAt = m.StartedAt.Time.Format(time.RFC3339)
+	}
+	if m.FinishedAt.Valid {
+		r.FinishedAt = m.FinishedAt.Time.Format(time.RFC3339)
+	}
+	if d, ok := m.Duration(); ok {
+		seconds := int64(d.Seconds())
+		r.DurationSeconds = &seconds
+	}
+	if m.Rating.Valid {
+		rating := m.Rating.Int64
+		r.Rating = &rating
+	}
 	return r
 }
 
+// ApplyFormula 指定の計算式で推定1RMを計算し直し、ボリュームを集計
+func (r *GetWorkoutSession) ApplyFormula(formula metrics.Formula) *GetWorkoutSession {
+	r.Formula = string(formula)
+	r.Volume = r.Exercises.ApplyFormula(formula)
+	return r
+}
+
+// ApplyUnit 重量を指定の単位に変換
+func (r *GetWorkoutSession) ApplyUnit(unit units.Unit) *GetWorkoutSession {
+	r.Unit = string(unit)
+	r.Volume = r.Exercises.ApplyUnit(unit)
+	return r
+}
+
+// ApplyFormula 一覧の各セッションのエクササイズに計算式を適用
+func (r WorkoutSessions) ApplyFormula(formula metrics.Formula) WorkoutSessions {
+	for i := range r {
+		r[i].Exercises.ApplyFormula(formula)
+	}
+	return r
+}
+
+// ApplyUnit 一覧の各セッションの重量を指定の単位に変換
+func (r WorkoutSessions) ApplyUnit(unit units.Unit) WorkoutSessions {
+	for i := range r {
+		r[i].Exercises.ApplyUnit(unit)
+	}
+	return r
+}
+
+// ApplyFormula 各エクササイズに計算式を適用し、ボリュームの合計を返却
+func (r Exercises) ApplyFormula(formula metrics.Formula) float64 {
+	var volume float64
+	for i := range r {
+		volume += r[i].ApplyFormula(formula).Volume
+	}
+	return volume
+}
+
+// ApplyUnit 各エクササイズの重量を指定の単位に変換し、ボリュームの合計を返却
+func (r Exercises) ApplyUnit(unit units.Unit) float64 {
+	var volume float64
+	for i := range r {
+		volume += r[i].ApplyUnit(unit).Volume
+	}
+	return volume
+}
+
 func (r *Exercise) ExerciseFromModel(exercise *model.ExerciseImpl, sets *model.Sets) *Exercise {
 	r.ID = exercise.ID
 	r.SessionID = exercise.SessionID
+	r.CatalogID = exercise.CatalogID.Int64
 	r.ExerciseName = exercise.ExerciseName
+	r.Modality = model.NormalizeModality(exercise.Modality)
 	r.Sets = *r.SetFromModel(sets)
+	r.Sets.ApplyModality(r.Modality)
+	r.ApplyFormula(metrics.DefaultFormula)
+	return r
+}
+
+// ApplyFormula 指定の計算式で各セットの推定1RMを計算し直し、ボリュームを集計
+func (r *Exercise) ApplyFormula(formula metrics.Formula) *Exercise {
+	r.Sets.ApplyFormula(formula)
+	return r.aggregate()
+}
+
+// ApplyUnit 各セット・目標セットの重量を指定の単位に変換し、ボリュームを集計
+func (r *Exercise) ApplyUnit(unit units.Unit) *Exercise {
+	r.Sets.ApplyUnit(unit)
+	r.TargetSets.ApplyUnit(unit)
+	return r.aggregate()
+}
+
+// aggregate セットのボリューム・時間・距離の合計と推定1RMの最大値を集計
+func (r *Exercise) aggregate() *Exercise {
+	r.Volume = 0
+	r.EstimatedOneRepMax = 0
+	r.DurationSeconds = 0
+	r.DistanceMeters = 0
+	for _, set := range r.Sets {
+		r.Volume += set.Volume
+		if set.EstimatedOneRepMax > r.EstimatedOneRepMax {
+			r.EstimatedOneRepMax = set.EstimatedOneRepMax
+		}
+		if set.SetType == model.SetTypeWarmup {
+			continue
+		}
+		if set.DurationSeconds != nil {
+			r.DurationSeconds += *set.DurationSeconds
+		}
+		if set.DistanceMeters != nil {
+			r.DistanceMeters += *set.DistanceMeters
+		}
+	}
 	return r
 }
 
@@ -81,13 +305,184 @@ func (r *Exercise) SetFromModel(sets *model.Sets) *Sets {
 		return &responseSets
 	}
 	for _, set := range *sets {
-		responseSets = append(responseSets, Set{
-			ID:         set.ID,
-			ExerciseID: set.ExerciseID,
-			SetNumber:  set.SetNumber,
-			Weight:     set.Weight,
-			Reps:       set.Reps,
-		})
+		responseSets = append(responseSets, *NewSet().SetFromModel(&set))
 	}
 	return &responseSets
 }
+
+func (r *Set) SetFromModel(set *model.SetImpl) *Set {
+	r.ID = set.ID
+	r.ExerciseID = set.ExerciseID
+	r.SetNumber = set.SetNumber
+	r.Kilograms = set.Weight
+	r.EnteredUnit = set.Unit
+	r.Reps = set.Reps
+	r.SetType = set.SetType
+	// モデルのフィールドを参照し続けないよう値をコピーする
+	if set.DurationSeconds.Valid {
+		durationSeconds := set.DurationSeconds.Int64
+		r.DurationSeconds = &durationSeconds
+	}
+	if set.DistanceMeters.Valid {
+		distanceMeters := set.DistanceMeters.Float64
+		r.DistanceMeters = &distanceMeters
+	}
+	r.PaceSecondsPerKm = metrics.Pace(set.DurationSeconds.Int64, set.DistanceMeters.Float64)
+	if set.RPE.Valid {
+		rpe := set.RPE.Float64
+		r.RPE = &rpe
+	}
+	if set.RIR.Valid {
+		rir := set.RIR.Int64
+		r.RIR = &rir
+	}
+	if set.RestSeconds.Valid {
+		restSeconds := set.RestSeconds.Int64
+		r.RestSeconds = &restSeconds
+	}
+	r.Tempo = set.Tempo
+	if set.PerformedAt.Valid {
+		r.PerformedAt = set.PerformedAt.Time.Format(time.RFC3339)
+	}
+	r.Notes = set.Notes
+	r.Unit = string(units.DefaultUnit)
+	r.Weight = units.Weight(r.Kilograms, units.Unit(r.EnteredUnit), units.DefaultUnit)
+	r.ApplyFormula(metrics.DefaultFormula)
+	return r
+}
+
+// ApplyFormula 各セットに計算式を適用
+func (r Sets) ApplyFormula(formula metrics.Formula) Sets {
+	for i := range r {
+		r[i].ApplyFormula(formula)
+	}
+	return r
+}
+
+// ApplyUnit 各セットの重量を指定の単位に変換
+func (r Sets) ApplyUnit(unit units.Unit) Sets {
+	for i := range r {
+		r[i].ApplyUnit(unit)
+	}
+	return r
+}
+
+// ApplyModality 各セットにエクササイズの記録方法を設定
+func (r Sets) ApplyModality(modality string) Sets {
+	for i := range r {
+		r[i].ApplyModality(modality)
+	}
+	return r
+}
+
+// ApplyFormula 指定の計算式で推定1RMを計算
+// 単位をまたいでも値が揃うよう、保存したkgの重量から求めて表示する単位に変換する
+// 自重種目は加重分のみをボリュームとし、推定1RMはウェイト種目のみ求める
+func (r *Set) ApplyFormula(formula metrics.Formula) *Set {
+	r.Volume = 0
+	r.EstimatedOneRepMax = 0
+	if r.SetType == model.SetTypeWarmup {
+		return r
+	}
+	unit := units.Unit(r.Unit)
+	if model.CountsLoadVolume(r.Modality) {
+		r.Volume = units.Convert(metrics.Volume(r.Kilograms, r.Reps), units.Kilogram, unit)
+	}
+	if model.EstimatesOneRepMax(r.Modality) {
+		r.EstimatedOneRepMax = units.Convert(metrics.EstimateOneRepMax(formula, r.Kilograms, r.Reps), units.Kilogram, unit)
+	}
+	return r
+}
+
+// ApplyModality エクササイズの記録方法を設定し、対象外のボリューム・推定1RMを0にする
+func (r *Set) ApplyModality(modality string) *Set {
+	r.Modality = modality
+	if !model.CountsLoadVolume(modality) {
+		r.Volume = 0
+	}
+	if !model.EstimatesOneRepMax(modality) {
+		r.EstimatedOneRepMax = 0
+	}
+	return r
+}
+
+// ApplyUnit 重量を指定の単位に変換
+func (r *Set) ApplyUnit(unit units.Unit) *Set {
+	from := units.Unit(r.Unit)
+	r.Unit = string(unit)
+	r.Weight = units.Weight(r.Kilograms, units.Unit(r.EnteredUnit), unit)
+	r.Volume = units.Convert(r.Volume, from, unit)
+	r.EstimatedOneRepMax = units.Convert(r.EstimatedOneRepMax, from, unit)
+	return r
+}
+
+// TargetSetsFromModel 目標セットをレスポンスに変換
+func TargetSetsFromModel(targets []model.SetTarget) TargetSets {
+	r := TargetSets{}
+	for _, target := range targets {
+		r = append(r, *NewTargetSet().TargetSetFromModel(target))
+	}
+	return r
+}
+
+func NewTargetSet() *TargetSet {
+	return &TargetSet{}
+}
+
+func (r *TargetSet) TargetSetFromModel(target model.SetTarget) *TargetSet {
+	r.SetNumber = target.SetNumber
+	r.SetType = target.SetType
+	r.Kilograms = target.Weight
+	r.EnteredUnit = target.Unit
+	r.Reps = target.Reps
+	if target.DurationSeconds.Valid {
+		durationSeconds := target.DurationSeconds.Int64
+		r.DurationSeconds = &durationSeconds
+	}
+	if target.DistanceMeters.Valid {
+		distanceMeters := target.DistanceMeters.Float64
+		r.DistanceMeters = &distanceMeters
+	}
+	if target.RPE.Valid {
+		rpe := target.RPE.Float64
+		r.RPE = &rpe
+	}
+	return r.ApplyUnit(units.DefaultUnit)
+}
+
+// TargetComparisonFromModel 目標セットの比較結果をレスポンスに変換
+func TargetComparisonFromModel(comparison model.TargetComparison) *TargetComparison {
+	r := &TargetComparison{
+		Status:    comparison.Status,
+		Targets:   int64(len(comparison.Results)),
+		ExtraSets: comparison.ExtraSets,
+		Sets:      make([]SetComparison, 0, len(comparison.Results)),
+	}
+	for _, result := range comparison.Results {
+		switch result.Status {
+		case model.TargetCompleted:
+			r.Completed++
+		case model.TargetMissed:
+			r.Missed++
+		case model.TargetExceeded:
+			r.Exceeded++
+		}
+		r.Sets = append(r.Sets, SetComparison{SetNumber: result.SetNumber, Status: result.Status, SetID: result.SetID})
+	}
+	return r
+}
+
+// ApplyUnit 各目標セットの重量を指定の単位に変換
+func (r TargetSets) ApplyUnit(unit units.Unit) TargetSets {
+	for i := range r {
+		r[i].ApplyUnit(unit)
+	}
+	return r
+}
+
+// ApplyUnit 重量を指定の単位に変換
+func (r *TargetSet) ApplyUnit(unit units.Unit) *TargetSet {
+	r.Unit = string(unit)
+	r.Weight = units.Weight(r.Kilograms, units.Unit(r.EnteredUnit), unit)
+	return r
+}
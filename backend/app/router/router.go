@@ -3,6 +3,7 @@ package router
 import (
 	"log"
 
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/auth"
 	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/handler"
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo"
@@ -17,19 +18,113 @@ func Init(e *echo.Echo) {
 
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"http://localhost:3000"}, // フロントエンドのオリジン
-		AllowMethods: []string{echo.GET, echo.POST, echo.PUT, echo.DELETE, echo.OPTIONS},
+		AllowMethods: []string{echo.GET, echo.POST, echo.PUT, echo.PATCH, echo.DELETE, echo.OPTIONS},
 	}))
 
+	// ユーザー登録・ログインは認証不要
+	userHandler := handler.NewUser()
+	e.POST("/signup", userHandler.Signup)
+	e.POST("/login", userHandler.Login)
+
+	// 以降のルーティングは認証済みユーザーのみ
+	authenticated := auth.Middleware()
+
+	// ユーザー情報・設定のルーティングを設定
+	e.GET("/me", userHandler.Me, authenticated)
+	e.PATCH("/me/preferences", userHandler.UpdatePreferences, authenticated)
+
 	// ワークアウトのハンドラを取得
 	workoutHandler := handler.NewWorkout()
 
 	// ワークアウトのルーティングを設定
-	e.GET("/workouts", workoutHandler.List)
-	e.GET("/workouts/:id", workoutHandler.Get)
-	e.POST("/workouts", workoutHandler.CreateWorkoutSession)
-	e.POST("/workouts/:id/exercises", workoutHandler.CreateExercise)
-	e.POST("/workouts/:id/exercises/:exercise_id/sets", workoutHandler.CreateSet)
-
-	recommendationHandler := handler.NewRecommendation()
-	e.POST("/recommendations", recommendationHandler.ProposeTrainingMenu)
+	e.GET("/workouts", workoutHandler.List, authenticated)
+	e.GET("/workouts/:id", workoutHandler.Get, authenticated)
+	e.POST("/workouts", workoutHandler.CreateWorkoutSession, authenticated)
+	e.POST("/workouts/log", workoutHandler.CreateWorkoutLog, authenticated)
+	e.POST("/workouts/:id/exercises", workoutHandler.CreateExercise, authenticated)
+	e.POST("/workouts/:id/exercises/:exercise_id/sets", workoutHandler.CreateSet, authenticated)
+	e.PUT("/workouts/:id", workoutHandler.UpdateWorkoutSession, authenticated)
+	e.PATCH("/workouts/:id", workoutHandler.UpdateWorkoutSession, authenticated)
+	e.DELETE("/workouts/:id", workoutHandler.DeleteWorkoutSession, authenticated)
+	e.POST("/workouts/:id/start", workoutHandler.StartWorkoutSession, authenticated)
+	e.POST("/workouts/:id/finish", workoutHandler.FinishWorkoutSession, authenticated)
+	e.POST("/workouts/:id/reopen", workoutHandler.ReopenWorkoutSession, authenticated)
+	e.PUT("/workouts/:id/exercises/:exercise_id", workoutHandler.UpdateExercise, authenticated)
+	e.PATCH("/workouts/:id/exercises/:exercise_id", workoutHandler.UpdateExercise, authenticated)
+	e.DELETE("/workouts/:id/exercises/:exercise_id", workoutHandler.DeleteExercise, authenticated)
+	e.PUT("/workouts/:id/exercises/:exercise_id/sets/:set_id", workoutHandler.UpdateSet, authenticated)
+	e.PATCH("/workouts/:id/exercises/:exercise_id/sets/:set_id", workoutHandler.UpdateSet, authenticated)
+	e.DELETE("/workouts/:id/exercises/:exercise_id/sets/:set_id", workoutHandler.DeleteSet, authenticated)
+	e.PUT("/workouts/:id/exercises/:exercise_id/targets", workoutHandler.ReplaceTargetSets, authenticated)
+
+	// テンプレートのルーティングを設定
+	templateHandler := handler.NewTemplate()
+	e.GET("/templates", templateHandler.List, authenticated)
+	e.GET("/templates/:id", templateHandler.Get, authenticated)
+	e.POST("/templates", templateHandler.Create, authenticated)
+	e.POST("/workouts/:id/template", templateHandler.CreateFromWorkout, authenticated)
+	e.PUT("/templates/:id", templateHandler.Update, authenticated)
+	e.DELETE("/templates/:id", templateHandler.Delete, authenticated)
+	e.POST("/templates/:id/workouts", templateHandler.Instantiate, authenticated)
+
+	// トレーニングプログラムのルーティングを設定
+	programHandler := handler.NewProgram()
+	e.GET("/programs", programHandler.List, authenticated)
+	e.GET("/programs/:id", programHandler.Get, authenticated)
+	e.POST("/programs", programHandler.Create, authenticated)
+	e.DELETE("/programs/:id", programHandler.Delete, authenticated)
+	e.POST("/programs/:id/enrollments", programHandler.Enroll, authenticated)
+	e.GET("/enrollments", programHandler.ListEnrollments, authenticated)
+	e.DELETE("/enrollments/:id", programHandler.DeleteEnrollment, authenticated)
+	e.GET("/enrollments/:id/plan", programHandler.PlannedSession, authenticated)
+	e.POST("/enrollments/:id/workouts", programHandler.CreatePlannedSession, authenticated)
+	e.PUT("/enrollments/:id/workouts/:session_id", programHandler.LinkSession, authenticated)
+	e.GET("/enrollments/:id/adherence", programHandler.Adherence, authenticated)
+
+	// 種目カタログのルーティングを設定
+	catalogHandler := handler.NewCatalog()
+	e.GET("/exercises", catalogHandler.List, authenticated)
+	e.GET("/exercises/:id", catalogHandler.Get, authenticated)
+	e.POST("/exercises", catalogHandler.Create, authenticated)
+	e.PUT("/exercises/:id", catalogHandler.Update, authenticated)
+	e.PATCH("/exercises/:id", catalogHandler.Update, authenticated)
+	e.DELETE("/exercises/:id", catalogHandler.Delete, authenticated)
+
+	// 自己ベストのルーティングを設定
+	recordHandler := handler.NewRecord()
+	e.GET("/records", recordHandler.List, authenticated)
+
+	// 統計のルーティングを設定
+	statsHandler := handler.NewStats()
+	e.GET("/stats/exercises/:exercise/progress", statsHandler.ExerciseProgress, authenticated)
+
+	// 重量・回数の進め方のルーティングを設定
+	progressionHandler := handler.NewProgression()
+	e.GET("/progression/rules", progressionHandler.ListRules, authenticated)
+	e.PUT("/progression/rules", progressionHandler.SaveRule, authenticated)
+	e.DELETE("/progression/rules/:id", progressionHandler.DeleteRule, authenticated)
+	e.GET("/progression/exercises/:exercise/suggestion", progressionHandler.Suggest, authenticated)
+
+	// 分析のルーティングを設定
+	analyticsHandler := handler.NewAnalytics()
+	e.GET("/analytics/muscles/weekly", analyticsHandler.WeeklyMuscleVolume, authenticated)
+	e.GET("/analytics/targets/weekly", analyticsHandler.WeeklyTargetCompletion, authenticated)
+
+	// エクスポートのルーティングを設定
+	exportHandler := handler.NewExport()
+	e.GET("/export/workouts.csv", exportHandler.WorkoutsCSV, authenticated)
+	e.GET("/export/workouts.jsonl", exportHandler.WorkoutsJSONLines, authenticated)
+
+	// 他のアプリの記録の取り込みのルーティングを設定
+	importHandler := handler.NewImport()
+	e.POST("/import/workouts", importHandler.Workouts, authenticated)
+
+	// メニュー提案のプロバイダはLLM_*の環境変数で設定する
+	recommendationHandler, err := handler.NewRecommendation()
+	if err != nil {
+		log.Fatalf("Error initializing recommendation. check LLM_* settings in app/.env: %v", err)
+	}
+	e.POST("/recommendations", recommendationHandler.ProposeTrainingMenu, authenticated)
+	e.POST("/recommendations/stream", recommendationHandler.ProposeTrainingMenuStream, authenticated)
+	e.POST("/recommendations/workouts", recommendationHandler.CreateWorkout, authenticated)
 }
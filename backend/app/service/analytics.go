@@ -0,0 +1,332 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/metrics"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+)
+
+// MuscleOther 部位を判別できない種目の部位
+const MuscleOther = "other"
+
+// defaultAnalyticsWeeks 期間の指定がない場合に集計する週数
+const defaultAnalyticsWeeks = 8
+
+// muscleKeywords カタログに紐づかない種目の名前から部位を推定するキーワード
+// 先に一致したものを採用するため、「レッグカール」や"rower"のように他のキーワードを含むものを先に並べる
+// 英字のキーワードは"narrow"の"row"のような単語の途中には一致させず、単語の先頭からのみ一致させる
+var muscleKeywords = []struct {
+	keyword string
+	muscle  string
+}{
+	{"レッグカール", "hamstrings"},
+	{"leg curl", "hamstrings"},
+	{"ルーマニアン", "hamstrings"},
+	{"romanian", "hamstrings"},
+	{"rdl", "hamstrings"},
+	{"リストカール", "forearms"},
+	{"wrist curl", "forearms"},
+	{"レッグエクステンション", "quads"},
+	{"leg extension", "quads"},
+	{"レッグプレス", "quads"},
+	{"leg press", "quads"},
+	{"スクワット", "quads"},
+	{"squat", "quads"},
+	{"ランジ", "quads"},
+	{"lunge", "quads"},
+	{"ヒップスラスト", "glutes"},
+	{"hip thrust", "glutes"},
+	{"カーフ", "calves"},
+	{"calf", "calves"},
+	{"シュラッグ", "traps"},
+	{"shrug", "traps"},
+	{"デッド", "back"},
+	{"dead", "back"},
+	{"懸垂", "back"},
+	{"チンニング", "back"},
+	{"プルダウン", "back"},
+	{"pulldown", "back"},
+	{"pull-up", "back"},
+	{"pull up", "back"},
+	{"ロウ", "back"},
+	{"ローイング", "back"},
+	{"rower", "cardio"},
+	{"row", "back"},
+	{"ショルダー", "shoulders"},
+	{"shoulder", "shoulders"},
+	{"サイドレイズ", "shoulders"},
+	{"lateral raise", "shoulders"},
+	{"overhead", "shoulders"},
+	{"ohp", "shoulders"},
+	{"プッシュダウン", "triceps"},
+	{"pushdown", "triceps"},
+	{"トライセプス", "triceps"},
+	{"triceps", "triceps"},
+	{"フレンチプレス", "triceps"},
+	{"スカルクラッシャー", "triceps"},
+	{"skull", "triceps"},
+	{"ディップ", "triceps"},
+	{"dip", "triceps"},
+	{"カール", "biceps"},
+	{"curl", "biceps"},
+	{"ベンチ", "chest"},
+	{"bench", "chest"},
+	{"チェスト", "chest"},
+	{"chest", "chest"},
+	{"フライ", "chest"},
+	{"fly", "chest"},
+	{"腕立て", "chest"},
+	{"push-up", "chest"},
+	{"腹筋", "core"},
+	{"クランチ", "core"},
+	{"crunch", "core"},
+	{"プランク", "core"},
+	{"plank", "core"},
+	{"ランニング", "cardio"},
+	{"running", "cardio"},
+	{"ジョギング", "cardio"},
+	{"jogging", "cardio"},
+	{"treadmill", "cardio"},
+	{"サイクリング", "cardio"},
+	{"cycling", "cardio"},
+	{"バイク", "cardio"},
+	{"bike", "cardio"},
+}
+
+type (
+	// Analytics トレーニングの分析のサービスを表す
+	Analytics interface {
+		WeeklyMuscleVolume(userId int64, from time.Time, to time.Time, loc *time.Location) (*response.WeeklyMuscleVolume, error)
+		WeeklyTargetCompletion(userId int64, from time.Time, to time.Time, loc *time.Location) (*response.WeeklyTargetCompletion, error)
+	}
+
+	// AnalyticsImpl トレーニングの分析のサービスを表す
+	AnalyticsImpl struct {
+		Set       model.Set
+		TargetSet model.TargetSet
+		// 期間の指定がない場合の基準日を返却する。テストで差し替えるため
+		Now func() time.Time
+	}
+)
+
+func NewAnalytics() Analytics {
+	return &AnalyticsImpl{
+		Set:       model.NewSet(),
+		TargetSet: model.NewTargetSet(),
+		Now:       time.Now,
+	}
+}
+
+// WeeklyMuscleVolume 部位ごとのセット数・回数・ボリュームをISO週ごとに集計
+// 週の区切りはlocのタイムゾーンでの月曜日とし、期間の指定がない場合は直近の8週を集計する
+// 主動筋のセットをSets、補助筋として関与したセットをSecondarySetsとして数え、回数・ボリューム・時間・距離は主動筋にのみ計上する
+// ボリュームは重量x回数を負荷とする記録方法のセットのみ計上する
+func (s *AnalyticsImpl) WeeklyMuscleVolume(userId int64, from time.Time, to time.Time, loc *time.Location) (*response.WeeklyMuscleVolume, error) {
+	from, to, loc, err := s.weeklyPeriod(from, to, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	// training_dateは日付のみのため、DBにはタイムゾーンを除いた日付で問い合わせる
+	history, err := s.Set.LoadHistory(model.SetHistoryFilter{
+		UserID: userId,
+		From:   dateIn(from, time.UTC),
+		To:     dateIn(to, time.UTC),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r := response.NewWeeklyMuscleVolume()
+	r.TimeZone = loc.String()
+	r.From = from.Format("2006-01-02")
+	r.To = to.Format("2006-01-02")
+	r.Weeks = weeklyMuscleVolumes(history, loc)
+	return r, nil
+}
+
+// WeeklyTargetCompletion 実施済みのセッションの目標セットの達成率をISO週ごとに集計
+// 週の区切り・期間の指定がない場合の扱いはWeeklyMuscleVolumeと同じとする
+func (s *AnalyticsImpl) WeeklyTargetCompletion(userId int64, from time.Time, to time.Time, loc *time.Location) (*response.WeeklyTargetCompletion, error) {
+	from, to, loc, err := s.weeklyPeriod(from, to, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := s.TargetSet.LoadHistory(model.SetHistoryFilter{
+		UserID: userId,
+		From:   dateIn(from, time.UTC),
+		To:     dateIn(to, time.UTC),
+		Status: model.SessionStatusCompleted,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sets, err := s.Set.LoadByExerciseIDs(targets.ExerciseIDs())
+	if err != nil {
+		return nil, err
+	}
+
+	r := response.NewWeeklyTargetCompletion()
+	r.TimeZone = loc.String()
+	r.From = from.Format("2006-01-02")
+	r.To = to.Format("2006-01-02")
+	setsByExercise := sets.GroupByExerciseID()
+	// 目標セットは日付・エクササイズ順のため、連続する同じエクササイズの目標セットをまとめて比較する
+	for i := 0; i < len(*targets); {
+		first := (*targets)[i]
+		exerciseTargets := []model.SetTarget{}
+		for ; i < len(*targets) && (*targets)[i].ExerciseID == first.ExerciseID; i++ {
+			exerciseTargets = append(exerciseTargets, (*targets)[i].SetTarget)
+		}
+		exerciseSets, ok := setsByExercise[first.ExerciseID]
+		if !ok {
+			exerciseSets = model.NewSets()
+		}
+		comparison := response.TargetComparisonFromModel(model.CompareTargets(first.Modality, exerciseTargets, exerciseSets))
+
+		start := bucketStart(dateIn(first.TrainingDate, loc), BucketWeek)
+		year, week := start.ISOWeek()
+		label := fmt.Sprintf("%04d-W%02d", year, week)
+		if len(r.Weeks) == 0 || r.Weeks[len(r.Weeks)-1].Week != label {
+			r.Weeks = append(r.Weeks, response.WeeklyCompletion{Week: label, WeekStart: start.Format("2006-01-02")})
+		}
+		r.Weeks[len(r.Weeks)-1].Add(comparison)
+		r.Add(comparison)
+	}
+	return r, nil
+}
+
+// weeklyPeriod 週ごとの集計期間をlocのタイムゾーンの日付に揃える。期間の指定がない場合は直近の8週とする
+func (s *AnalyticsImpl) weeklyPeriod(from time.Time, to time.Time, loc *time.Location) (time.Time, time.Time, *time.Location, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if to.IsZero() {
+		to = s.Now().In(loc)
+	}
+	to = dateIn(to, loc)
+	if from.IsZero() {
+		from = bucketStart(to, BucketWeek).AddDate(0, 0, -7*(defaultAnalyticsWeeks-1))
+	}
+	from = dateIn(from, loc)
+	if from.After(to) {
+		return time.Time{}, time.Time{}, nil, fmt.Errorf("from must not be after to: %w", ErrInvalidArgument)
+	}
+	return from, to, loc, nil
+}
+
+// weeklyMuscleVolumes 日付順のセット履歴をISO週・部位ごとにまとめる。ウォームアップと記録のないセットは集計しない
+func weeklyMuscleVolumes(history *model.SetHistories, loc *time.Location) []response.WeeklyVolume {
+	weeks := []response.WeeklyVolume{}
+	var muscles map[string]*response.MuscleVolume
+	flush := func() {
+		if len(weeks) == 0 {
+			return
+		}
+		weeks[len(weeks)-1].Muscles = sortMuscleVolumes(muscles)
+	}
+	get := func(muscle string) *response.MuscleVolume {
+		if _, ok := muscles[muscle]; !ok {
+			muscles[muscle] = &response.MuscleVolume{Muscle: muscle}
+		}
+		return muscles[muscle]
+	}
+
+	for _, set := range *history {
+		if set.IsWarmup() || (set.Reps <= 0 && set.DurationSeconds <= 0 && set.DistanceMeters <= 0) {
+			continue
+		}
+		start := bucketStart(dateIn(set.TrainingDate, loc), BucketWeek)
+		year, week := start.ISOWeek()
+		label := fmt.Sprintf("%04d-W%02d", year, week)
+		if len(weeks) == 0 || weeks[len(weeks)-1].Week != label {
+			flush()
+			weeks = append(weeks, response.WeeklyVolume{Week: label, WeekStart: start.Format("2006-01-02")})
+			muscles = map[string]*response.MuscleVolume{}
+		}
+
+		primary, secondary := setMuscles(&set)
+		volume := get(primary)
+		volume.Sets++
+		volume.Reps += set.Reps
+		if model.CountsLoadVolume(set.Modality) {
+			volume.Volume += metrics.Volume(set.Weight, set.Reps)
+		}
+		volume.DurationSeconds += set.DurationSeconds
+		volume.DistanceMeters += set.DistanceMeters
+		for _, muscle := range secondary {
+			get(muscle).SecondarySets++
+		}
+	}
+	flush()
+	return weeks
+}
+
+// setMuscles セットの主動筋と補助筋を返却
+// カタログに紐づく種目はカタログの部位を、紐づかない種目は名前から推定した部位を用いる
+func setMuscles(set *model.SetHistory) (string, []string) {
+	if set.PrimaryMuscle != "" {
+		catalog := model.ExerciseCatalogImpl{PrimaryMuscle: set.PrimaryMuscle, SecondaryMuscles: set.SecondaryMuscles}
+		return catalog.PrimaryMuscle, catalog.SecondaryMuscleList()
+	}
+	return muscleFromName(set.ExerciseName), nil
+}
+
+// muscleFromName 種目名のキーワードから部位を推定。判別できない場合はMuscleOtherを返却
+func muscleFromName(name string) string {
+	name = strings.ToLower(name)
+	for _, k := range muscleKeywords {
+		if containsKeyword(name, k.keyword) {
+			return k.muscle
+		}
+	}
+	return MuscleOther
+}
+
+// containsKeyword 名前がキーワードを含むか判定。英字のキーワードは単語の先頭から始まる場合のみ一致とする
+func containsKeyword(name string, keyword string) bool {
+	if !isASCIIWordByte(keyword[0]) {
+		return strings.Contains(name, keyword)
+	}
+	for offset := 0; ; {
+		i := strings.Index(name[offset:], keyword)
+		if i < 0 {
+			return false
+		}
+		i += offset
+		if i == 0 || !isASCIIWordByte(name[i-1]) {
+			return true
+		}
+		offset = i + 1
+	}
+}
+
+// isASCIIWordByte 英字・数字のバイトか判定
+func isASCIIWordByte(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9'
+}
+
+// sortMuscleVolumes 部位をカタログの部位の順に並べ、判別できない部位は最後にする
+func sortMuscleVolumes(muscles map[string]*response.MuscleVolume) []response.MuscleVolume {
+	sorted := []response.MuscleVolume{}
+	for _, muscle := range append(append([]string{}, model.MuscleGroups...), MuscleOther) {
+		if v, ok := muscles[muscle]; ok {
+			// 丸めたボリュームの合計で生じる誤差を除く
+			v.Volume = math.Round(v.Volume*100) / 100
+			v.DistanceMeters = math.Round(v.DistanceMeters*100) / 100
+			sorted = append(sorted, *v)
+		}
+	}
+	return sorted
+}
+
+// dateIn 日付の年月日をそのままに指定のタイムゾーンの0時として返却
+func dateIn(date time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model/mock_model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyticsWeeklyMuscleVolume(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		Set model.Set
+		Now func() time.Time
+	}
+	type args struct {
+		from time.Time
+		to   time.Time
+		loc  *time.Location
+	}
+	tokyo := time.FixedZone("Asia/Tokyo", 9*60*60)
+	// 2024-01-01は月曜日(ISO週 2024-W01)
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := &model.SetHistories{
+		{SetID: int64(1), SessionID: int64(1), TrainingDate: monday, Weight: float64(100), Reps: int64(5), CatalogID: int64(1), ExerciseName: "ベンチプレス", PrimaryMuscle: "chest", SecondaryMuscles: "shoulders,triceps"},
+		{SetID: int64(2), SessionID: int64(1), TrainingDate: monday, Weight: float64(100), Reps: int64(0), CatalogID: int64(1), ExerciseName: "ベンチプレス", PrimaryMuscle: "chest", SecondaryMuscles: "shoulders,triceps"},
+		{SetID: int64(3), SessionID: int64(1), TrainingDate: monday, Weight: float64(20.5), Reps: int64(10), ExerciseName: "ケーブルプッシュダウン"},
+		{SetID: int64(4), SessionID: int64(2), TrainingDate: monday.AddDate(0, 0, 6), Weight: float64(0), Reps: int64(12), ExerciseName: "謎のマシン"},
+		{SetID: int64(5), SessionID: int64(3), TrainingDate: monday.AddDate(0, 0, 7), Weight: float64(140), Reps: int64(3), ExerciseName: "Back Squat"},
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.WeeklyMuscleVolume, err error)
+	}{
+		{
+			testCase: "正常系(カタログの部位と名前から推定した部位で週ごとに集計)",
+			args: args{
+				from: monday,
+				to:   monday.AddDate(0, 0, 13),
+				loc:  tokyo,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadHistory(model.SetHistoryFilter{UserID: int64(1), From: monday, To: monday.AddDate(0, 0, 13)}).Return(history, nil)
+				return fields{
+					Set: Set,
+				}
+			},
+			assertion: func(r *response.WeeklyMuscleVolume, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "Asia/Tokyo", r.TimeZone)
+				assert.Equal(t, "2024-01-01", r.From)
+				assert.Equal(t, "2024-01-14", r.To)
+				assert.Equal(t, []response.WeeklyVolume{
+					{
+						Week:      "2024-W01",
+						WeekStart: "2024-01-01",
+						Muscles: []response.MuscleVolume{
+							{Muscle: "chest", Sets: int64(1), Reps: int64(5), Volume: float64(500)},
+							{Muscle: "shoulders", SecondarySets: int64(1)},
+							{Muscle: "triceps", Sets: int64(1), SecondarySets: int64(1), Reps: int64(10), Volume: float64(205)},
+							{Muscle: "other", Sets: int64(1), Reps: int64(12)},
+						},
+					},
+					{
+						Week:      "2024-W02",
+						WeekStart: "2024-01-08",
+						Muscles: []response.MuscleVolume{
+							{Muscle: "quads", Sets: int64(1), Reps: int64(3), Volume: float64(420)},
+						},
+					},
+				}, r.Weeks)
+			},
+		},
+		{
+			testCase: "正常系(記録方法ごとにボリューム・時間・距離を集計)",
+			args: args{
+				from: monday,
+				to:   monday,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadHistory(model.SetHistoryFilter{UserID: int64(1), From: monday, To: monday}).Return(&model.SetHistories{
+					{SetID: int64(1), SessionID: int64(1), TrainingDate: monday, Weight: float64(10), Reps: int64(8), Modality: model.ModalityBodyweight, CatalogID: int64(18), PrimaryMuscle: "back", SecondaryMuscles: "biceps"},
+					{SetID: int64(2), SessionID: int64(1), TrainingDate: monday, Weight: float64(20), Reps: int64(10), Modality: model.ModalityAssisted, CatalogID: int64(36), PrimaryMuscle: "back"},
+					{SetID: int64(3), SessionID: int64(1), TrainingDate: monday, DurationSeconds: int64(60), Modality: model.ModalityTimed, CatalogID: int64(33), PrimaryMuscle: "core"},
+					{SetID: int64(4), SessionID: int64(1), TrainingDate: monday, DurationSeconds: int64(1500), DistanceMeters: float64(5000), Modality: model.ModalityDistance, CatalogID: int64(38), PrimaryMuscle: "cardio"},
+				}, nil)
+				return fields{
+					Set: Set,
+				}
+			},
+			assertion: func(r *response.WeeklyMuscleVolume, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []response.MuscleVolume{
+					// アシスト重量はボリュームに計上しない
+					{Muscle: "back", Sets: int64(2), Reps: int64(18), Volume: float64(80)},
+					{Muscle: "biceps", SecondarySets: int64(1)},
+					{Muscle: "core", Sets: int64(1), DurationSeconds: int64(60)},
+					{Muscle: "cardio", Sets: int64(1), DurationSeconds: int64(1500), DistanceMeters: float64(5000)},
+				}, r.Weeks[0].Muscles)
+			},
+		},
+		{
+			testCase: "正常系(期間の指定がない場合はタイムゾーンでの今日までの8週)",
+			args: args{
+				loc: tokyo,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				// UTCでは2024-01-14(日)だが東京では2024-01-15(月)
+				now := time.Date(2024, 1, 14, 20, 0, 0, 0, time.UTC)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadHistory(model.SetHistoryFilter{UserID: int64(1), From: time.Date(2023, 11, 27, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)}).Return(&model.SetHistories{}, nil)
+				return fields{
+					Set: Set,
+					Now: func() time.Time { return now },
+				}
+			},
+			assertion: func(r *response.WeeklyMuscleVolume, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "2023-11-27", r.From)
+				assert.Equal(t, "2024-01-15", r.To)
+				assert.Equal(t, []response.WeeklyVolume{}, r.Weeks)
+			},
+		},
+		{
+			testCase: "エラー(開始日が終了日より後)",
+			args: args{
+				from: monday.AddDate(0, 0, 1),
+				to:   monday,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r *response.WeeklyMuscleVolume, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := tt.fields(ctrl)
+			s := &AnalyticsImpl{
+				Set: f.Set,
+				Now: f.Now,
+			}
+			tt.assertion(s.WeeklyMuscleVolume(int64(1), tt.args.from, tt.args.to, tt.args.loc))
+		})
+	}
+}
+
+func TestAnalyticsWeeklyTargetCompletion(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		Set       model.Set
+		TargetSet model.TargetSet
+	}
+	type args struct {
+		from time.Time
+		to   time.Time
+	}
+	// 2024-01-01は月曜日(ISO週 2024-W01)
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	target := model.SetTarget{Weight: float64(100), Unit: "kg", Reps: int64(5)}
+	withNumber := func(target model.SetTarget, setNumber int64) model.SetTarget {
+		target.SetNumber = setNumber
+		return target
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.WeeklyTargetCompletion, err error)
+	}{
+		{
+			testCase: "正常系(エクササイズごとに比較して週ごとに集計)",
+			args: args{
+				from: monday,
+				to:   monday.AddDate(0, 0, 13),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				TargetSet := mock_model.NewMockTargetSet(ctrl)
+				TargetSet.EXPECT().LoadHistory(model.SetHistoryFilter{UserID: int64(1), From: monday, To: monday.AddDate(0, 0, 13), Status: model.SessionStatusCompleted}).Return(&model.TargetSetHistories{
+					{TargetSetID: int64(1), ExerciseID: int64(1), SessionID: int64(1), TrainingDate: monday, SetTarget: withNumber(target, 1)},
+					{TargetSetID: int64(2), ExerciseID: int64(1), SessionID: int64(1), TrainingDate: monday, SetTarget: withNumber(target, 2)},
+					{TargetSetID: int64(3), ExerciseID: int64(2), SessionID: int64(2), TrainingDate: monday.AddDate(0, 0, 7), SetTarget: withNumber(target, 1)},
+					{TargetSetID: int64(4), ExerciseID: int64(3), SessionID: int64(2), TrainingDate: monday.AddDate(0, 0, 7), Modality: model.ModalityAssisted, SetTarget: model.SetTarget{SetNumber: 1, Weight: float64(20), Reps: int64(8)}},
+				}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadByExerciseIDs([]int64{1, 2, 3}).Return(&model.Sets{
+					{ID: int64(1), ExerciseID: int64(1), SetNumber: int64(1), Weight: float64(100), Reps: int64(5)},
+					{ID: int64(2), ExerciseID: int64(1), SetNumber: int64(2), Weight: float64(100), Reps: int64(4)},
+					{ID: int64(3), ExerciseID: int64(2), SetNumber: int64(1), Weight: float64(102.5), Reps: int64(5)},
+					{ID: int64(4), ExerciseID: int64(3), SetNumber: int64(1), Weight: float64(15), Reps: int64(8)},
+				}, nil)
+				return fields{
+					Set:       Set,
+					TargetSet: TargetSet,
+				}
+			},
+			assertion: func(r *response.WeeklyTargetCompletion, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "2024-01-01", r.From)
+				assert.Equal(t, "2024-01-14", r.To)
+				assert.Equal(t, response.TargetCompletion{Targets: 4, Completed: 1, Missed: 1, Exceeded: 2, Rate: 0.75}, r.TargetCompletion)
+				assert.Equal(t, []response.WeeklyCompletion{
+					{Week: "2024-W01", WeekStart: "2024-01-01", TargetCompletion: response.TargetCompletion{Targets: 2, Completed: 1, Missed: 1, Rate: 0.5}},
+					// アシスト重量は軽いほど目標を上回る
+					{Week: "2024-W02", WeekStart: "2024-01-08", TargetCompletion: response.TargetCompletion{Targets: 2, Exceeded: 2, Rate: 1}},
+				}, r.Weeks)
+			},
+		},
+		{
+			testCase: "正常系(セットを記録していないエクササイズは未達)",
+			args: args{
+				from: monday,
+				to:   monday,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				TargetSet := mock_model.NewMockTargetSet(ctrl)
+				TargetSet.EXPECT().LoadHistory(model.SetHistoryFilter{UserID: int64(1), From: monday, To: monday, Status: model.SessionStatusCompleted}).Return(&model.TargetSetHistories{
+					{TargetSetID: int64(1), ExerciseID: int64(1), SessionID: int64(1), TrainingDate: monday, SetTarget: withNumber(target, 1)},
+				}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadByExerciseIDs([]int64{1}).Return(model.NewSets(), nil)
+				return fields{
+					Set:       Set,
+					TargetSet: TargetSet,
+				}
+			},
+			assertion: func(r *response.WeeklyTargetCompletion, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, response.TargetCompletion{Targets: 1, Missed: 1}, r.TargetCompletion)
+			},
+		},
+		{
+			testCase: "エラー(開始日が終了日より後)",
+			args: args{
+				from: monday.AddDate(0, 0, 1),
+				to:   monday,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r *response.WeeklyTargetCompletion, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := tt.fields(ctrl)
+			s := &AnalyticsImpl{
+				Set:       f.Set,
+				TargetSet: f.TargetSet,
+			}
+			tt.assertion(s.WeeklyTargetCompletion(int64(1), tt.args.from, tt.args.to, nil))
+		})
+	}
+}
+
+func TestMuscleFromName(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "インクラインベンチプレス", want: "chest"},
+		{name: "Lying Leg Curl", want: "hamstrings"},
+		{name: "ダンベルカール", want: "biceps"},
+		{name: "ルーマニアンデッドリフト", want: "hamstrings"},
+		{name: "Barbell Row", want: "back"},
+		{name: "Rower", want: "cardio"},
+		{name: "Narrow Grip Bench Press", want: "chest"},
+		{name: "Deadlift", want: "back"},
+		{name: "謎のマシン", want: MuscleOther},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, muscleFromName(tt.name))
+		})
+	}
+}
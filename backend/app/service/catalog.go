@@ -0,0 +1,325 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+)
+
+type (
+	// Catalog 種目カタログのサービスを表す
+	Catalog interface {
+		Search(filter model.ExerciseCatalogFilter) (response.CatalogEntries, error)
+		Get(id int64) (*response.CatalogEntry, error)
+		Create(userId int64, entry form.CreateCatalogEntry) (*response.CatalogEntry, error)
+		Update(userId int64, id int64, attrs map[string]interface{}, aliases *[]form.CatalogAlias) (*response.CatalogEntry, error)
+		Delete(userId int64, id int64) error
+	}
+
+	// CatalogImpl 種目カタログのサービスを表す
+	CatalogImpl struct {
+		ExerciseCatalog  model.ExerciseCatalog
+		ExerciseAlias    model.ExerciseAlias
+		Exercise         model.Exercise
+		TemplateExercise model.TemplateExercise
+		ProgramExercise  model.ProgramExercise
+		TrainingMax      model.TrainingMax
+		ProgressionRule  model.ProgressionRule
+		Set              model.Set
+		PersonalRecord   model.PersonalRecord
+		User             model.User
+		Transaction      db.Transactor
+	}
+)
+
+func NewCatalog() Catalog {
+	return &CatalogImpl{
+		ExerciseCatalog:  model.NewExerciseCatalog(),
+		ExerciseAlias:    model.NewExerciseAlias(),
+		Exercise:         model.NewExercise(),
+		TemplateExercise: model.NewTemplateExercise(),
+		ProgramExercise:  model.NewProgramExercise(),
+		TrainingMax:      model.NewTrainingMax(),
+		ProgressionRule:  model.NewProgressionRule(),
+		Set:              model.NewSet(),
+		PersonalRecord:   model.NewPersonalRecord(),
+		User:             model.NewUser(),
+		Transaction:      db.NewTransactor("training_db"),
+	}
+}
+
+// Search 種目カタログを検索
+func (s *CatalogImpl) Search(filter model.ExerciseCatalogFilter) (response.CatalogEntries, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	catalogs, err := s.ExerciseCatalog.Search(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases, err := s.ExerciseAlias.LoadByCatalogIDs(catalogs.IDs())
+	if err != nil {
+		return nil, err
+	}
+	aliasesByCatalog := aliases.GroupByCatalogID()
+
+	entries := response.CatalogEntries{}
+	for _, catalog := range *catalogs {
+		entries = append(entries, *response.NewCatalogEntry().CatalogEntryFromModel(&catalog, aliasesByCatalog[catalog.ID]))
+	}
+	return entries, nil
+}
+
+// Get 種目の詳細を取得
+func (s *CatalogImpl) Get(id int64) (*response.CatalogEntry, error) {
+	catalog, err := s.loadCatalog(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.entryFromModel(catalog)
+}
+
+// Create 種目を別名とあわせて1トランザクションで作成。管理者のみ作成できる
+func (s *CatalogImpl) Create(userId int64, entry form.CreateCatalogEntry) (*response.CatalogEntry, error) {
+	if err := s.requireAdmin(userId); err != nil {
+		return nil, err
+	}
+	if err := validateMuscles(entry.PrimaryMuscle, entry.SecondaryMuscles); err != nil {
+		return nil, err
+	}
+	if entry.Modality != "" {
+		if err := validateModality(entry.Modality); err != nil {
+			return nil, err
+		}
+	}
+	names := []string{entry.NameJa, entry.NameEn}
+	for _, a := range entry.Aliases {
+		names = append(names, a.Alias)
+	}
+	if err := s.checkNamesAvailable(0, names); err != nil {
+		return nil, err
+	}
+
+	var catalog *model.ExerciseCatalogImpl
+	err := s.Transaction(func(tx dbr.SessionRunner) error {
+		var err error
+		catalog, err = s.ExerciseCatalog.CreateTx(tx, entry.NameJa, entry.NameEn, entry.PrimaryMuscle, entry.SecondaryMuscles, entry.Equipment, entry.Modality)
+		if err != nil {
+			return err
+		}
+		return s.createAliasesTx(tx, catalog.ID, entry.Aliases)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.entryFromModel(catalog)
+}
+
+// Update 種目を更新。aliasesが指定された場合は別名を置き換える。管理者のみ更新できる
+func (s *CatalogImpl) Update(userId int64, id int64, attrs map[string]interface{}, aliases *[]form.CatalogAlias) (*response.CatalogEntry, error) {
+	if err := s.requireAdmin(userId); err != nil {
+		return nil, err
+	}
+	catalog, err := s.loadCatalog(id)
+	if err != nil {
+		return nil, err
+	}
+
+	primary := catalog.PrimaryMuscle
+	if v, ok := attrs["primary_muscle"].(string); ok {
+		primary = v
+	}
+	secondary := catalog.SecondaryMuscleList()
+	if v, ok := attrs["secondary_muscles"].([]string); ok {
+		secondary = v
+		attrs["secondary_muscles"] = model.JoinMuscles(v)
+	}
+	if err := validateMuscles(primary, secondary); err != nil {
+		return nil, err
+	}
+	if v, ok := attrs["modality"].(string); ok {
+		if err := validateModality(v); err != nil {
+			return nil, err
+		}
+	}
+
+	var names []string
+	for _, key := range []string{"name_ja", "name_en"} {
+		if v, ok := attrs[key].(string); ok {
+			names = append(names, v)
+		}
+	}
+	if aliases != nil {
+		for _, a := range *aliases {
+			names = append(names, a.Alias)
+		}
+	}
+	if err := s.checkNamesAvailable(id, names); err != nil {
+		return nil, err
+	}
+
+	err = s.Transaction(func(tx dbr.SessionRunner) error {
+		if len(attrs) > 0 {
+			if _, err := s.ExerciseCatalog.UpdateTx(tx, catalog.ID, attrs); err != nil {
+				return err
+			}
+		}
+		if aliases == nil {
+			return nil
+		}
+		if _, err := s.ExerciseAlias.DeleteByCatalogIDTx(tx, id); err != nil {
+			return err
+		}
+		return s.createAliasesTx(tx, id, *aliases)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Get(id)
+}
+
+// Delete 種目を削除。管理者のみ削除できる
+// 種目を参照する記録済みのエクササイズ・テンプレートとプログラムの種目は、名前を残してカタログとの紐づけを外す
+// 種目ごとのトレーニングマックスはプログラムの種目の名前に、進め方はカタログの日本語名に付け替える
+// 紐づけを外したエクササイズは名前で区別する種目になるため、カタログの種目として集計していた自己ベストを消し、名前の種目として集計し直す
+func (s *CatalogImpl) Delete(userId int64, id int64) error {
+	if err := s.requireAdmin(userId); err != nil {
+		return err
+	}
+	catalog, err := s.loadCatalog(id)
+	if err != nil {
+		return err
+	}
+
+	return s.Transaction(func(tx dbr.SessionRunner) error {
+		owners, err := s.Exercise.LoadOwnersByCatalogIDTx(tx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := s.Exercise.DetachCatalogTx(tx, id); err != nil {
+			return err
+		}
+		if _, err := s.TemplateExercise.DetachCatalogTx(tx, id); err != nil {
+			return err
+		}
+		// トレーニングマックスはプログラムの種目の名前を使うため、プログラムの種目より先に付け替える
+		if _, err := s.TrainingMax.DetachCatalogTx(tx, id); err != nil {
+			return err
+		}
+		if _, err := s.ProgramExercise.DetachCatalogTx(tx, id); err != nil {
+			return err
+		}
+		if _, err := s.ProgressionRule.DetachCatalogTx(tx, id, catalog.NameJa); err != nil {
+			return err
+		}
+		if _, err := s.ExerciseCatalog.DeleteTx(tx, id); err != nil {
+			return err
+		}
+
+		cleared := map[int64]bool{}
+		for _, owner := range *owners {
+			if !cleared[owner.UserID] {
+				cleared[owner.UserID] = true
+				if err := s.PersonalRecord.ReplaceTx(tx, owner.UserID, model.ExerciseKey{CatalogID: id}, model.NewPersonalRecords()); err != nil {
+					return err
+				}
+			}
+			if _, err := refreshPersonalRecordsTx(s.Set, s.PersonalRecord, tx, owner.UserID, model.ExerciseKey{ExerciseName: owner.ExerciseName}, 0, 0); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// requireAdmin 種目カタログは全ユーザーで共有するため、編集できるのは管理者に限る
+func (s *CatalogImpl) requireAdmin(userId int64) error {
+	user, err := s.User.Load(userId)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin() {
+		return fmt.Errorf("user %d can't edit the exercise catalog: %w", userId, ErrForbidden)
+	}
+	return nil
+}
+
+func (s *CatalogImpl) createAliasesTx(tx dbr.SessionRunner, catalogId int64, aliases []form.CatalogAlias) error {
+	for _, a := range aliases {
+		if _, err := s.ExerciseAlias.CreateTx(tx, catalogId, strings.TrimSpace(a.Alias), a.Locale); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkNamesAvailable 名前・別名が他の種目と重複しないことを確認
+// 重複を許すと名前から種目を一意に解決できなくなるため
+func (s *CatalogImpl) checkNamesAvailable(id int64, names []string) error {
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return fmt.Errorf("name must not be empty: %w", ErrInvalidArgument)
+		}
+		key := strings.ToLower(name)
+		if seen[key] {
+			return fmt.Errorf("name %q is duplicated: %w", name, ErrInvalidArgument)
+		}
+		seen[key] = true
+
+		catalog, err := s.ExerciseCatalog.Resolve(name)
+		if err != nil {
+			return err
+		}
+		if catalog.ID != 0 && catalog.ID != id {
+			return fmt.Errorf("name %q is already used by catalog %d: %w", name, catalog.ID, ErrConflict)
+		}
+	}
+	return nil
+}
+
+func (s *CatalogImpl) entryFromModel(catalog *model.ExerciseCatalogImpl) (*response.CatalogEntry, error) {
+	aliases, err := s.ExerciseAlias.LoadByCatalogIDs([]int64{catalog.ID})
+	if err != nil {
+		return nil, err
+	}
+	return response.NewCatalogEntry().CatalogEntryFromModel(catalog, aliases), nil
+}
+
+// loadCatalog 種目を読み込み、存在しない場合はErrNotFoundを返却
+func (s *CatalogImpl) loadCatalog(id int64) (*model.ExerciseCatalogImpl, error) {
+	catalog, err := s.ExerciseCatalog.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if catalog.ID == 0 {
+		return nil, fmt.Errorf("catalog %d: %w", id, ErrNotFound)
+	}
+	return catalog, nil
+}
+
+// validateMuscles 部位がカタログで扱う部位の一覧に含まれることを確認
+func validateMuscles(primary string, secondary []string) error {
+	for _, muscle := range append([]string{primary}, secondary...) {
+		if !isMuscleGroup(muscle) {
+			return fmt.Errorf("unknown muscle group %q: %w", muscle, ErrInvalidArgument)
+		}
+	}
+	return nil
+}
+
+func isMuscleGroup(muscle string) bool {
+	for _, m := range model.MuscleGroups {
+		if m == muscle {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,558 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model/mock_model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/gocraft/dbr/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogSearch(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		ExerciseCatalog model.ExerciseCatalog
+		ExerciseAlias   model.ExerciseAlias
+	}
+	tests := []struct {
+		testCase  string
+		filter    model.ExerciseCatalogFilter
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r response.CatalogEntries, err error)
+	}{
+		{
+			testCase: "正常系",
+			filter:   model.ExerciseCatalogFilter{Query: " ベンチ ", Muscle: "chest"},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Search(model.ExerciseCatalogFilter{Query: "ベンチ", Muscle: "chest"}).Return(&model.ExerciseCatalogs{
+					{ID: int64(1), NameJa: "ベンチプレス", NameEn: "Bench Press", PrimaryMuscle: "chest", SecondaryMuscles: "triceps,shoulders"},
+					{ID: int64(2), NameJa: "インクラインベンチプレス", NameEn: "Incline Bench Press", PrimaryMuscle: "chest"},
+				}, nil)
+				ExerciseAlias := mock_model.NewMockExerciseAlias(ctrl)
+				ExerciseAlias.EXPECT().LoadByCatalogIDs([]int64{1, 2}).Return(&model.ExerciseAliases{
+					{ID: int64(1), CatalogID: int64(1), Alias: "ベンチ", Locale: "ja"},
+					{ID: int64(2), CatalogID: int64(1), Alias: "bench", Locale: "en"},
+				}, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+					ExerciseAlias:   ExerciseAlias,
+				}
+			},
+			assertion: func(r response.CatalogEntries, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, r, 2)
+				assert.Equal(t, []string{"triceps", "shoulders"}, r[0].SecondaryMuscles)
+				assert.Len(t, r[0].Aliases, 2)
+				assert.Equal(t, []string{}, r[1].SecondaryMuscles)
+				assert.Empty(t, r[1].Aliases)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			s := &CatalogImpl{
+				ExerciseCatalog: fields.ExerciseCatalog,
+				ExerciseAlias:   fields.ExerciseAlias,
+			}
+			tt.assertion(s.Search(tt.filter))
+		})
+	}
+}
+
+func TestCatalogCreate(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		ExerciseCatalog model.ExerciseCatalog
+		ExerciseAlias   model.ExerciseAlias
+	}
+	entry := form.CreateCatalogEntry{
+		NameJa:           "ケーブルクロスオーバー",
+		NameEn:           "Cable Crossover",
+		PrimaryMuscle:    "chest",
+		SecondaryMuscles: []string{"shoulders"},
+		Equipment:        "cable",
+		Aliases:          []form.CatalogAlias{{Alias: "ケーブルフライ", Locale: "ja"}},
+	}
+	tests := []struct {
+		testCase  string
+		userId    int64
+		entry     form.CreateCatalogEntry
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.CatalogEntry, err error)
+	}{
+		{
+			testCase: "正常系",
+			userId:   adminUserId,
+			entry:    entry,
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve(gomock.Any()).Return(&model.ExerciseCatalogImpl{}, nil).Times(3)
+				ExerciseCatalog.EXPECT().CreateTx(gomock.Any(), "ケーブルクロスオーバー", "Cable Crossover", "chest", []string{"shoulders"}, "cable", "").
+					Return(&model.ExerciseCatalogImpl{ID: int64(36), NameJa: "ケーブルクロスオーバー", NameEn: "Cable Crossover", PrimaryMuscle: "chest", SecondaryMuscles: "shoulders", Equipment: "cable"}, nil)
+				ExerciseAlias := mock_model.NewMockExerciseAlias(ctrl)
+				ExerciseAlias.EXPECT().CreateTx(gomock.Any(), int64(36), "ケーブルフライ", "ja").Return(&model.ExerciseAliasImpl{ID: int64(1), CatalogID: int64(36), Alias: "ケーブルフライ", Locale: "ja"}, nil)
+				ExerciseAlias.EXPECT().LoadByCatalogIDs([]int64{36}).Return(&model.ExerciseAliases{{ID: int64(1), CatalogID: int64(36), Alias: "ケーブルフライ", Locale: "ja"}}, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+					ExerciseAlias:   ExerciseAlias,
+				}
+			},
+			assertion: func(r *response.CatalogEntry, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(36), r.ID)
+				assert.Equal(t, []string{"shoulders"}, r.SecondaryMuscles)
+				assert.Equal(t, []response.CatalogAlias{{Alias: "ケーブルフライ", Locale: "ja"}}, r.Aliases)
+			},
+		},
+		{
+			testCase: "エラー(別名が既存の種目と重複)",
+			userId:   adminUserId,
+			entry:    entry,
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("ケーブルクロスオーバー").Return(&model.ExerciseCatalogImpl{}, nil)
+				ExerciseCatalog.EXPECT().Resolve("Cable Crossover").Return(&model.ExerciseCatalogImpl{}, nil)
+				ExerciseCatalog.EXPECT().Resolve("ケーブルフライ").Return(&model.ExerciseCatalogImpl{ID: int64(5)}, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.CatalogEntry, err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(管理者以外は作成できない)",
+			userId:   int64(2),
+			entry:    entry,
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r *response.CatalogEntry, err error) {
+				assert.ErrorIs(t, err, ErrForbidden)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(未知の部位)",
+			userId:   adminUserId,
+			entry: form.CreateCatalogEntry{
+				NameJa:           "ケーブルクロスオーバー",
+				NameEn:           "Cable Crossover",
+				PrimaryMuscle:    "chest",
+				SecondaryMuscles: []string{"wings"},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r *response.CatalogEntry, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			s := &CatalogImpl{
+				ExerciseCatalog: fields.ExerciseCatalog,
+				ExerciseAlias:   fields.ExerciseAlias,
+				User:            catalogUsers(ctrl),
+				Transaction:     noTransaction,
+			}
+			tt.assertion(s.Create(tt.userId, tt.entry))
+		})
+	}
+}
+
+func TestCatalogUpdate(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		ExerciseCatalog model.ExerciseCatalog
+		ExerciseAlias   model.ExerciseAlias
+	}
+	catalog := &model.ExerciseCatalogImpl{ID: int64(1), NameJa: "ベンチプレス", NameEn: "Bench Press", PrimaryMuscle: "chest", SecondaryMuscles: "shoulders", Equipment: "barbell", Modality: model.ModalityWeighted}
+	tests := []struct {
+		testCase  string
+		userId    int64
+		attrs     map[string]interface{}
+		aliases   *[]form.CatalogAlias
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.CatalogEntry, err error)
+	}{
+		{
+			testCase: "正常系(別名を置き換える)",
+			userId:   adminUserId,
+			attrs:    map[string]interface{}{"name_en": "Barbell Bench Press"},
+			aliases:  &[]form.CatalogAlias{{Alias: "ベンチ", Locale: "ja"}},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(1)).Return(catalog, nil).Times(2)
+				ExerciseCatalog.EXPECT().Resolve("Barbell Bench Press").Return(&model.ExerciseCatalogImpl{}, nil)
+				// 自身の別名と同じ名前は重複としない
+				ExerciseCatalog.EXPECT().Resolve("ベンチ").Return(&model.ExerciseCatalogImpl{ID: int64(1)}, nil)
+				ExerciseAlias := mock_model.NewMockExerciseAlias(ctrl)
+				gomock.InOrder(
+					ExerciseCatalog.EXPECT().UpdateTx(gomock.Any(), int64(1), map[string]interface{}{"name_en": "Barbell Bench Press"}).Return(true, nil),
+					ExerciseAlias.EXPECT().DeleteByCatalogIDTx(gomock.Any(), int64(1)).Return(int64(2), nil),
+					ExerciseAlias.EXPECT().CreateTx(gomock.Any(), int64(1), "ベンチ", "ja").Return(&model.ExerciseAliasImpl{ID: int64(3), CatalogID: int64(1), Alias: "ベンチ", Locale: "ja"}, nil),
+				)
+				ExerciseAlias.EXPECT().LoadByCatalogIDs([]int64{1}).Return(&model.ExerciseAliases{{ID: int64(3), CatalogID: int64(1), Alias: "ベンチ", Locale: "ja"}}, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+					ExerciseAlias:   ExerciseAlias,
+				}
+			},
+			assertion: func(r *response.CatalogEntry, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), r.ID)
+				assert.Equal(t, []response.CatalogAlias{{Alias: "ベンチ", Locale: "ja"}}, r.Aliases)
+			},
+		},
+		{
+			testCase: "エラー(管理者以外は更新できない)",
+			userId:   int64(2),
+			attrs:    map[string]interface{}{"name_en": "Barbell Bench Press"},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r *response.CatalogEntry, err error) {
+				assert.ErrorIs(t, err, ErrForbidden)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(未知の部位)",
+			userId:   adminUserId,
+			attrs:    map[string]interface{}{"primary_muscle": "wings"},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(1)).Return(catalog, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.CatalogEntry, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(未知の記録方法)",
+			userId:   adminUserId,
+			attrs:    map[string]interface{}{"modality": "flying"},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(1)).Return(catalog, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.CatalogEntry, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(名前が他の種目と重複)",
+			userId:   adminUserId,
+			attrs:    map[string]interface{}{"name_ja": "インクラインベンチプレス"},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(1)).Return(catalog, nil)
+				ExerciseCatalog.EXPECT().Resolve("インクラインベンチプレス").Return(&model.ExerciseCatalogImpl{ID: int64(2)}, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.CatalogEntry, err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(別名が他の種目と重複)",
+			userId:   adminUserId,
+			attrs:    map[string]interface{}{},
+			aliases:  &[]form.CatalogAlias{{Alias: "スクワット", Locale: "ja"}},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(1)).Return(catalog, nil)
+				ExerciseCatalog.EXPECT().Resolve("スクワット").Return(&model.ExerciseCatalogImpl{ID: int64(5)}, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.CatalogEntry, err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			s := &CatalogImpl{
+				ExerciseCatalog: fields.ExerciseCatalog,
+				ExerciseAlias:   fields.ExerciseAlias,
+				User:            catalogUsers(ctrl),
+				Transaction:     noTransaction,
+			}
+			tt.assertion(s.Update(tt.userId, int64(1), tt.attrs, tt.aliases))
+		})
+	}
+}
+
+func TestCatalogDelete(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		ExerciseCatalog  model.ExerciseCatalog
+		Exercise         model.Exercise
+		TemplateExercise model.TemplateExercise
+		ProgramExercise  model.ProgramExercise
+		TrainingMax      model.TrainingMax
+		ProgressionRule  model.ProgressionRule
+		Set              model.Set
+		PersonalRecord   model.PersonalRecord
+	}
+	tests := []struct {
+		testCase  string
+		userId    int64
+		id        int64
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(err error)
+	}{
+		{
+			testCase: "正常系",
+			userId:   adminUserId,
+			id:       int64(1),
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(1)).Return(&model.ExerciseCatalogImpl{ID: int64(1), NameJa: "ベンチプレス"}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().LoadOwnersByCatalogIDTx(gomock.Any(), int64(1)).Return(&model.ExerciseOwners{}, nil)
+				Exercise.EXPECT().DetachCatalogTx(gomock.Any(), int64(1)).Return(int64(0), nil)
+				TemplateExercise := mock_model.NewMockTemplateExercise(ctrl)
+				TemplateExercise.EXPECT().DetachCatalogTx(gomock.Any(), int64(1)).Return(int64(0), nil)
+				ExerciseCatalog.EXPECT().DeleteTx(gomock.Any(), int64(1)).Return(true, nil)
+				ProgramExercise, TrainingMax, ProgressionRule := catalogProgramReferences(ctrl, int64(1), "ベンチプレス")
+				return fields{
+					ExerciseCatalog:  ExerciseCatalog,
+					Exercise:         Exercise,
+					TemplateExercise: TemplateExercise,
+					ProgramExercise:  ProgramExercise,
+					TrainingMax:      TrainingMax,
+					ProgressionRule:  ProgressionRule,
+				}
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "正常系(テンプレートが参照する種目は名前を残して紐づけを外す)",
+			userId:   adminUserId,
+			id:       int64(1),
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(1)).Return(&model.ExerciseCatalogImpl{ID: int64(1), NameJa: "ベンチプレス"}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().LoadOwnersByCatalogIDTx(gomock.Any(), int64(1)).Return(&model.ExerciseOwners{}, nil)
+				Exercise.EXPECT().DetachCatalogTx(gomock.Any(), int64(1)).Return(int64(0), nil)
+				TemplateExercise := mock_model.NewMockTemplateExercise(ctrl)
+				// 外部キーのあるテンプレートの種目から先に紐づけを外してからカタログを削除する
+				gomock.InOrder(
+					TemplateExercise.EXPECT().DetachCatalogTx(gomock.Any(), int64(1)).Return(int64(2), nil),
+					ExerciseCatalog.EXPECT().DeleteTx(gomock.Any(), int64(1)).Return(true, nil),
+				)
+				ProgramExercise, TrainingMax, ProgressionRule := catalogProgramReferences(ctrl, int64(1), "ベンチプレス")
+				return fields{
+					ExerciseCatalog:  ExerciseCatalog,
+					Exercise:         Exercise,
+					TemplateExercise: TemplateExercise,
+					ProgramExercise:  ProgramExercise,
+					TrainingMax:      TrainingMax,
+					ProgressionRule:  ProgressionRule,
+				}
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "正常系(プログラムが参照する種目はトレーニングマックスと進め方を名前の種目に付け替える)",
+			userId:   adminUserId,
+			id:       int64(1),
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(1)).Return(&model.ExerciseCatalogImpl{ID: int64(1), NameJa: "ベンチプレス"}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().LoadOwnersByCatalogIDTx(gomock.Any(), int64(1)).Return(&model.ExerciseOwners{}, nil)
+				Exercise.EXPECT().DetachCatalogTx(gomock.Any(), int64(1)).Return(int64(0), nil)
+				TemplateExercise := mock_model.NewMockTemplateExercise(ctrl)
+				TemplateExercise.EXPECT().DetachCatalogTx(gomock.Any(), int64(1)).Return(int64(0), nil)
+				ProgramExercise := mock_model.NewMockProgramExercise(ctrl)
+				TrainingMax := mock_model.NewMockTrainingMax(ctrl)
+				ProgressionRule := mock_model.NewMockProgressionRule(ctrl)
+				// トレーニングマックスはプログラムの種目の名前を使うため、プログラムの種目より先に付け替える
+				gomock.InOrder(
+					TrainingMax.EXPECT().DetachCatalogTx(gomock.Any(), int64(1)).Return(int64(1), nil),
+					ProgramExercise.EXPECT().DetachCatalogTx(gomock.Any(), int64(1)).Return(int64(2), nil),
+					ExerciseCatalog.EXPECT().DeleteTx(gomock.Any(), int64(1)).Return(true, nil),
+				)
+				ProgressionRule.EXPECT().DetachCatalogTx(gomock.Any(), int64(1), "ベンチプレス").Return(int64(1), nil)
+				return fields{
+					ExerciseCatalog:  ExerciseCatalog,
+					Exercise:         Exercise,
+					TemplateExercise: TemplateExercise,
+					ProgramExercise:  ProgramExercise,
+					TrainingMax:      TrainingMax,
+					ProgressionRule:  ProgressionRule,
+				}
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "正常系(記録済みの種目は自己ベストを名前の種目として集計し直す)",
+			userId:   adminUserId,
+			id:       int64(1),
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(1)).Return(&model.ExerciseCatalogImpl{ID: int64(1), NameJa: "ベンチプレス"}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().LoadOwnersByCatalogIDTx(gomock.Any(), int64(1)).Return(&model.ExerciseOwners{
+					{UserID: int64(1), ExerciseName: "ベンチプレス"},
+					{UserID: int64(1), ExerciseName: "ベンチ"},
+					{UserID: int64(2), ExerciseName: "ベンチプレス"},
+				}, nil)
+				Exercise.EXPECT().DetachCatalogTx(gomock.Any(), int64(1)).Return(int64(3), nil)
+				TemplateExercise := mock_model.NewMockTemplateExercise(ctrl)
+				TemplateExercise.EXPECT().DetachCatalogTx(gomock.Any(), int64(1)).Return(int64(0), nil)
+				ExerciseCatalog.EXPECT().DeleteTx(gomock.Any(), int64(1)).Return(true, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				PersonalRecord := mock_model.NewMockPersonalRecord(ctrl)
+				for _, userId := range []int64{1, 2} {
+					PersonalRecord.EXPECT().ReplaceTx(gomock.Any(), userId, model.ExerciseKey{CatalogID: int64(1)}, model.NewPersonalRecords()).Return(nil)
+				}
+				for _, owner := range []model.ExerciseOwner{{UserID: 1, ExerciseName: "ベンチプレス"}, {UserID: 1, ExerciseName: "ベンチ"}, {UserID: 2, ExerciseName: "ベンチプレス"}} {
+					key := model.ExerciseKey{ExerciseName: owner.ExerciseName}
+					PersonalRecord.EXPECT().LoadByKeyTx(gomock.Any(), owner.UserID, key).Return(model.NewPersonalRecords(), nil)
+					Set.EXPECT().LoadHistoryTx(gomock.Any(), model.SetHistoryFilter{UserID: owner.UserID, Key: key}).Return(&model.SetHistories{
+						{SetID: int64(1), SessionID: int64(1), TrainingDate: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), Weight: float64(100), Reps: int64(5), ExerciseName: owner.ExerciseName},
+					}, nil)
+					PersonalRecord.EXPECT().ReplaceTx(gomock.Any(), owner.UserID, key, gomock.Any()).DoAndReturn(
+						func(_ dbr.SessionRunner, _ int64, _ model.ExerciseKey, records *model.PersonalRecords) error {
+							assert.NotEmpty(t, *records)
+							return nil
+						})
+				}
+				ProgramExercise, TrainingMax, ProgressionRule := catalogProgramReferences(ctrl, int64(1), "ベンチプレス")
+				return fields{
+					ExerciseCatalog:  ExerciseCatalog,
+					Exercise:         Exercise,
+					TemplateExercise: TemplateExercise,
+					ProgramExercise:  ProgramExercise,
+					TrainingMax:      TrainingMax,
+					ProgressionRule:  ProgressionRule,
+					Set:              Set,
+					PersonalRecord:   PersonalRecord,
+				}
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "エラー(管理者以外は削除できない)",
+			userId:   int64(2),
+			id:       int64(1),
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrForbidden)
+			},
+		},
+		{
+			testCase: "エラー(存在しない種目)",
+			userId:   adminUserId,
+			id:       int64(99),
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(99)).Return(&model.ExerciseCatalogImpl{}, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			s := &CatalogImpl{
+				ExerciseCatalog:  fields.ExerciseCatalog,
+				Exercise:         fields.Exercise,
+				TemplateExercise: fields.TemplateExercise,
+				ProgramExercise:  fields.ProgramExercise,
+				TrainingMax:      fields.TrainingMax,
+				ProgressionRule:  fields.ProgressionRule,
+				Set:              fields.Set,
+				PersonalRecord:   fields.PersonalRecord,
+				User:             catalogUsers(ctrl),
+				Transaction:      noTransaction,
+			}
+			tt.assertion(s.Delete(tt.userId, tt.id))
+		})
+	}
+}
+
+// adminUserId 種目カタログのテストで管理者とするユーザー
+const adminUserId = int64(1)
+
+// catalogUsers 種目カタログのテストのユーザー。adminUserIdのみ管理者とする
+func catalogUsers(ctrl *gomock.Controller) model.User {
+	User := mock_model.NewMockUser(ctrl)
+	User.EXPECT().Load(gomock.Any()).DoAndReturn(func(id int64) (*model.UserImpl, error) {
+		if id == adminUserId {
+			return &model.UserImpl{ID: id, Role: model.UserRoleAdmin}, nil
+		}
+		return &model.UserImpl{ID: id, Role: model.UserRoleMember}, nil
+	}).AnyTimes()
+	return User
+}
+
+// catalogProgramReferences 種目カタログの削除のテストで、プログラムからの参照がない種目のモデル
+func catalogProgramReferences(ctrl *gomock.Controller, id int64, nameJa string) (model.ProgramExercise, model.TrainingMax, model.ProgressionRule) {
+	ProgramExercise := mock_model.NewMockProgramExercise(ctrl)
+	ProgramExercise.EXPECT().DetachCatalogTx(gomock.Any(), id).Return(int64(0), nil)
+	TrainingMax := mock_model.NewMockTrainingMax(ctrl)
+	TrainingMax.EXPECT().DetachCatalogTx(gomock.Any(), id).Return(int64(0), nil)
+	ProgressionRule := mock_model.NewMockProgressionRule(ctrl)
+	ProgressionRule.EXPECT().DetachCatalogTx(gomock.Any(), id, nameJa).Return(int64(0), nil)
+	return ProgramExercise, TrainingMax, ProgressionRule
+}
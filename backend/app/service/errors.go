@@ -0,0 +1,18 @@
+package service
+
+import "errors"
+
+var (
+	// ErrNotFound 対象のリソースが存在しない
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidArgument 引数が不正
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrConflict 既に存在するリソースと競合する
+	ErrConflict = errors.New("conflict")
+	// ErrUnauthorized 認証に失敗した
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden 認証済みだが操作する権限がない
+	ErrForbidden = errors.New("forbidden")
+	// ErrUpstream 外部サービスの呼び出しに失敗した、または応答が不正
+	ErrUpstream = errors.New("upstream error")
+)
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+)
+
+const (
+	// ExportCSV ヘッダ付きのCSV
+	ExportCSV = "csv"
+	// ExportJSONLines 1行に1セットのJSONを書くJSON Lines
+	ExportJSONLines = "jsonl"
+)
+
+// exportFlushRows この行数を書くごとにクライアントへ送り出す
+const exportFlushRows = 500
+
+type (
+	// Export トレーニング履歴のエクスポートのサービスを表す
+	Export interface {
+		Workouts(ctx context.Context, w io.Writer, format string, userId int64, exercise string, from time.Time, to time.Time, unit units.Unit) error
+	}
+
+	// ExportImpl トレーニング履歴のエクスポートのサービスを表す
+	ExportImpl struct {
+		Set             model.Set
+		ExerciseCatalog model.ExerciseCatalog
+	}
+
+	// exportWriter 1セットずつ書き出す形式ごとの書き込み先を表す
+	exportWriter interface {
+		Write(set *response.ExportedSet) error
+		Flush() error
+	}
+
+	csvExportWriter struct {
+		w      *csv.Writer
+		header bool
+	}
+
+	jsonLinesExportWriter struct {
+		w   *bufio.Writer
+		enc *json.Encoder
+	}
+)
+
+func NewExport() Export {
+	return &ExportImpl{
+		Set:             model.NewSet(),
+		ExerciseCatalog: model.NewExerciseCatalog(),
+	}
+}
+
+// Workouts ユーザーのセットを1行に1セットでwに書き出す
+// 種目・期間の指定がない場合は全件を対象とし、重量はunitで出力する
+// DBから読み出したセットを順に書き出し、一定の行数ごとにクライアントへ送り出す。引数の検証に失敗した場合はwに何も書かない
+func (s *ExportImpl) Workouts(ctx context.Context, w io.Writer, format string, userId int64, exercise string, from time.Time, to time.Time, unit units.Unit) error {
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return fmt.Errorf("from must not be after to: %w", ErrInvalidArgument)
+	}
+
+	var out exportWriter
+	switch format {
+	case ExportCSV:
+		out = &csvExportWriter{w: csv.NewWriter(w)}
+	case ExportJSONLines:
+		buffered := bufio.NewWriter(w)
+		enc := json.NewEncoder(buffered)
+		enc.SetEscapeHTML(false)
+		out = &jsonLinesExportWriter{w: buffered, enc: enc}
+	default:
+		return fmt.Errorf("format %q: %w", format, ErrInvalidArgument)
+	}
+
+	filter := model.SetHistoryFilter{UserID: userId, From: from, To: to}
+	if strings.TrimSpace(exercise) != "" {
+		key, _, err := resolveExerciseKey(s.ExerciseCatalog, exercise)
+		if err != nil {
+			return err
+		}
+		filter.Key = key
+	}
+
+	rows := 0
+	set := response.NewExportedSet()
+	err := s.Set.EachExport(ctx, filter, func(m *model.SetExport) error {
+		if err := out.Write(set.ExportedSetFromModel(m, unit)); err != nil {
+			return err
+		}
+		rows++
+		if rows%exportFlushRows == 0 {
+			return flushExport(w, out)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// セットがない場合もCSVのヘッダは書き出す
+	if rows == 0 {
+		if err := out.Write(nil); err != nil {
+			return err
+		}
+	}
+	return flushExport(w, out)
+}
+
+// flushExport バッファをwへ書き出し、レスポンスであればクライアントへ送り出す
+func flushExport(w io.Writer, out exportWriter) error {
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// Write 最初の行の前にヘッダを書く。setがnilの場合はヘッダのみを書く
+func (e *csvExportWriter) Write(set *response.ExportedSet) error {
+	if !e.header {
+		if err := e.w.Write(response.ExportColumns); err != nil {
+			return err
+		}
+		e.header = true
+	}
+	if set == nil {
+		return nil
+	}
+	return e.w.Write(set.CSVRecord())
+}
+
+func (e *csvExportWriter) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// Write setがnilの場合は何も書かない
+func (e *jsonLinesExportWriter) Write(set *response.ExportedSet) error {
+	if set == nil {
+		return nil
+	}
+	return e.enc.Encode(set)
+}
+
+func (e *jsonLinesExportWriter) Flush() error {
+	return e.w.Flush()
+}
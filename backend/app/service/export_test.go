@@ -0,0 +1,149 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model/mock_model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/gocraft/dbr/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportWorkouts(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		Set             model.Set
+		ExerciseCatalog model.ExerciseCatalog
+	}
+	type args struct {
+		format   string
+		exercise string
+		from     time.Time
+		to       time.Time
+		unit     units.Unit
+	}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	rows := []model.SetExport{
+		{SessionID: 1, TrainingDate: from, SessionStatus: model.SessionStatusCompleted, ExerciseID: 10, ExerciseName: "ベンチプレス", CatalogID: 1, Modality: model.ModalityWeighted, SetID: 100, SetNumber: 1, Weight: 100, Unit: "kg", Reps: 5, SetDetail: model.SetDetail{SetType: model.SetTypeWorking, RPE: dbr.NewNullFloat64(8.5)}},
+		{SessionID: 1, TrainingDate: from, SessionStatus: model.SessionStatusCompleted, ExerciseID: 11, ExerciseName: "=自作マシン", SetID: 101, SetNumber: 1, Weight: 20.41, Unit: "lb", Reps: 12, SetDetail: model.SetDetail{SetType: model.SetTypeWorking, Notes: "重い, きつい"}},
+	}
+	eachExport := func(rows []model.SetExport) func(ctx context.Context, filter model.SetHistoryFilter, fn func(*model.SetExport) error) error {
+		return func(ctx context.Context, filter model.SetHistoryFilter, fn func(*model.SetExport) error) error {
+			for i := range rows {
+				if err := fn(&rows[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(out string, err error)
+	}{
+		{
+			testCase: "正常系(CSV)",
+			args:     args{format: ExportCSV, from: from, to: to, unit: units.Kilogram},
+			fields: func(ctrl *gomock.Controller) fields {
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().EachExport(gomock.Any(), model.SetHistoryFilter{UserID: int64(1), From: from, To: to}, gomock.Any()).DoAndReturn(eachExport(rows))
+				return fields{Set: Set}
+			},
+			assertion: func(out string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "date,session_id,status,exercise_id,exercise_name,catalog_id,modality,set_id,set_number,set_type,weight,unit,reps,duration_seconds,distance_meters,rpe,rir,rest_seconds,tempo,performed_at,notes\n"+
+					"2024-01-01,1,completed,10,ベンチプレス,1,weighted,100,1,working,100,kg,5,,,8.5,,,,,\n"+
+					"2024-01-01,1,completed,11,'=自作マシン,,weighted,101,1,working,20.5,kg,12,,,,,,,,\"重い, きつい\"\n", out)
+			},
+		},
+		{
+			testCase: "正常系(JSON Lines、種目を指定)",
+			args:     args{format: ExportJSONLines, exercise: "ベンチ", unit: units.Pound},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("ベンチ").Return(&model.ExerciseCatalogImpl{ID: int64(1), NameJa: "ベンチプレス"}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().EachExport(gomock.Any(), model.SetHistoryFilter{UserID: int64(1), Key: model.ExerciseKey{CatalogID: int64(1)}}, gomock.Any()).DoAndReturn(eachExport(rows[:1]))
+				return fields{Set: Set, ExerciseCatalog: ExerciseCatalog}
+			},
+			assertion: func(out string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, `{"date":"2024-01-01","session_id":1,"status":"completed","exercise_id":10,"exercise_name":"ベンチプレス","catalog_id":1,"modality":"weighted","set_id":100,"set_number":1,"set_type":"working","weight":220,"unit":"lb","reps":5,"rpe":8.5}`+"\n", out)
+			},
+		},
+		{
+			testCase: "正常系(セットがない場合はCSVのヘッダのみ)",
+			args:     args{format: ExportCSV, unit: units.Kilogram},
+			fields: func(ctrl *gomock.Controller) fields {
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().EachExport(gomock.Any(), model.SetHistoryFilter{UserID: int64(1)}, gomock.Any()).DoAndReturn(eachExport(nil))
+				return fields{Set: Set}
+			},
+			assertion: func(out string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "date,session_id,status,exercise_id,exercise_name,catalog_id,modality,set_id,set_number,set_type,weight,unit,reps,duration_seconds,distance_meters,rpe,rir,rest_seconds,tempo,performed_at,notes\n", out)
+			},
+		},
+		{
+			testCase: "エラー(期間の開始が終了より後)",
+			args:     args{format: ExportCSV, from: to, to: from},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(out string, err error) {
+				assert.True(t, errors.Is(err, ErrInvalidArgument))
+				assert.Empty(t, out)
+			},
+		},
+		{
+			testCase: "エラー(種目が見つからない)",
+			args:     args{format: ExportCSV, exercise: "99"},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(99)).Return(&model.ExerciseCatalogImpl{}, nil)
+				return fields{ExerciseCatalog: ExerciseCatalog}
+			},
+			assertion: func(out string, err error) {
+				assert.True(t, errors.Is(err, ErrNotFound))
+				assert.Empty(t, out)
+			},
+		},
+		{
+			testCase: "エラー(読み込みの途中で失敗)",
+			args:     args{format: ExportJSONLines},
+			fields: func(ctrl *gomock.Controller) fields {
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().EachExport(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+				return fields{Set: Set}
+			},
+			assertion: func(out string, err error) {
+				assert.EqualError(t, err, "db error")
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := tt.fields(ctrl)
+			s := &ExportImpl{
+				Set:             f.Set,
+				ExerciseCatalog: f.ExerciseCatalog,
+			}
+			var buf bytes.Buffer
+			err := s.Workouts(context.Background(), &buf, tt.args.format, int64(1), tt.args.exercise, tt.args.from, tt.args.to, tt.args.unit)
+			tt.assertion(buf.String(), err)
+		})
+	}
+}
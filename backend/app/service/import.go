@@ -0,0 +1,363 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/importer"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+)
+
+// equipmentSuffix StrongやHevyの種目名の末尾に付く"(Barbell)"のような器具の表記
+var equipmentSuffix = regexp.MustCompile(`\s*\([^()]*\)\s*$`)
+
+type (
+	// Import 他のアプリの記録の取り込みのサービスを表す
+	Import interface {
+		Workouts(userId int64, r io.Reader, format string, unit units.Unit, mapping map[string]string, dryRun bool) (*response.ImportResult, error)
+	}
+
+	// ImportImpl 他のアプリの記録の取り込みのサービスを表す
+	ImportImpl struct {
+		WorkoutSession  model.WorkoutSession
+		Exercise        model.Exercise
+		Set             model.Set
+		ExerciseCatalog model.ExerciseCatalog
+		PersonalRecord  model.PersonalRecord
+		Transaction     db.Transactor
+	}
+
+	// importedExercise 取り込み元の種目名から解決した種目を表す
+	importedExercise struct {
+		name      string
+		catalogID int64
+		modality  string
+		mapped    bool
+	}
+)
+
+func NewImport() Import {
+	return &ImportImpl{
+		WorkoutSession:  model.NewWorkoutSession(),
+		Exercise:        model.NewExercise(),
+		Set:             model.NewSet(),
+		ExerciseCatalog: model.NewExerciseCatalog(),
+		PersonalRecord:  model.NewPersonalRecord(),
+		Transaction:     db.NewTransactor("training_db"),
+	}
+}
+
+// Workouts Strong・Hevy・FitNotesのCSVを読み込み、実施済みのセッションとして取り込む
+// formatが空の場合はヘッダから形式を判別し、重量の単位を記録しない形式ではunitの単位とみなす
+// mappingで取り込み元の種目名を種目名・別名・カタログIDに対応づけ、指定のない種目は名前・別名からカタログの種目を探す
+// 取り込み済みのセッションは読み飛ばし、取り込めない行が1つでもあれば何も取り込まない
+// dryRunの場合は取り込む内容と取り込めない行を返却し、何も保存しない
+func (s *ImportImpl) Workouts(userId int64, r io.Reader, format string, unit units.Unit, mapping map[string]string, dryRun bool) (*response.ImportResult, error) {
+	if err := validateImportFormat(format); err != nil {
+		return nil, err
+	}
+	format, sessions, err := importer.Parse(r, format, unit)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidArgument)
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("no sets to import: %w", ErrInvalidArgument)
+	}
+
+	result := response.NewImportResult()
+	result.Format = format
+	result.DryRun = dryRun
+
+	// 取り込み元の種目名ごとにカタログの種目を解決する。記録方法の推定には全セッションのセットを使う
+	var sourceNames []string
+	sourceSets := map[string][]importer.Set{}
+	for _, session := range sessions {
+		for _, e := range session.Exercises {
+			if _, ok := sourceSets[e.Name]; !ok {
+				sourceNames = append(sourceNames, e.Name)
+			}
+			sourceSets[e.Name] = append(sourceSets[e.Name], e.Sets...)
+		}
+	}
+	mappings := normalizeMapping(mapping)
+	exercises := map[string]importedExercise{}
+	for _, name := range sourceNames {
+		if name == "" {
+			continue
+		}
+		exercise, err := s.resolveExercise(name, mappings)
+		if err != nil {
+			return nil, err
+		}
+		exercise.modality = importedModality(exercise.modality, sourceSets[name])
+		exercises[name] = exercise
+	}
+
+	keys := make([]string, len(sessions))
+	for i, session := range sessions {
+		keys[i] = session.Key
+	}
+	imported, err := s.WorkoutSession.LoadImportKeys(userId, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// 保存する前にすべての行を検証する
+	details := make([][][]model.SetDetail, len(sessions))
+	exerciseSets := map[string]int{}
+	for i, session := range sessions {
+		preview := response.ImportedSession{
+			Date:      session.Date.Format("2006-01-02"),
+			Name:      session.Name,
+			Exercises: len(session.Exercises),
+			Duplicate: imported[session.Key],
+		}
+		for _, e := range session.Exercises {
+			preview.Sets += len(e.Sets)
+		}
+		result.Sessions = append(result.Sessions, preview)
+		if preview.Duplicate {
+			result.Duplicates++
+			continue
+		}
+		result.Imported++
+		result.Sets += preview.Sets
+
+		if notes := importedSessionNotes(session); utf8.RuneCountInString(notes) > maxSessionNotesLength {
+			result.Errors = append(result.Errors, response.ImportError{Line: session.Line, Message: fmt.Sprintf("workout notes must be at most %d characters", maxSessionNotesLength)})
+		}
+		details[i] = make([][]model.SetDetail, len(session.Exercises))
+		for j, e := range session.Exercises {
+			if e.Name == "" {
+				result.Errors = append(result.Errors, response.ImportError{Line: e.Sets[0].Line, Message: "exercise name is required"})
+				continue
+			}
+			exercise := exercises[e.Name]
+			exerciseSets[e.Name] += len(e.Sets)
+			for _, set := range e.Sets {
+				detail, err := importedSetDetail(set)
+				if err == nil {
+					err = validateSetMeasure(exercise.modality, set.Weight, set.Reps, detail)
+				}
+				if err != nil {
+					result.Errors = append(result.Errors, response.ImportError{Line: set.Line, Message: fmt.Sprintf("%s: %v", e.Name, err)})
+					continue
+				}
+				details[i][j] = append(details[i][j], detail)
+			}
+		}
+	}
+	for _, name := range sourceNames {
+		exercise, ok := exercises[name]
+		if !ok {
+			continue
+		}
+		result.Exercises = append(result.Exercises, response.ImportedExercise{
+			SourceName:   name,
+			ExerciseName: exercise.name,
+			CatalogID:    exercise.catalogID,
+			Modality:     exercise.modality,
+			Mapped:       exercise.mapped,
+			Sets:         exerciseSets[name],
+		})
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if len(result.Errors) > 0 {
+		first := result.Errors[0]
+		return nil, fmt.Errorf("%d rows can't be imported (line %d: %s): %w", len(result.Errors), first.Line, first.Message, ErrInvalidArgument)
+	}
+
+	var names []string
+	var catalogIds []int64
+	err = s.Transaction(func(tx dbr.SessionRunner) error {
+		for i, session := range sessions {
+			if imported[session.Key] {
+				continue
+			}
+			workoutSession, err := s.WorkoutSession.CreateImportedTx(tx, importedSession(userId, session))
+			if err != nil {
+				return err
+			}
+			result.Sessions[i].ID = workoutSession.ID
+
+			for j, e := range session.Exercises {
+				resolved := exercises[e.Name]
+				exercise, err := s.Exercise.CreateTx(tx, workoutSession.ID, resolved.name, resolved.catalogID, resolved.modality)
+				if err != nil {
+					return err
+				}
+				names = append(names, resolved.name)
+				catalogIds = append(catalogIds, resolved.catalogID)
+
+				for k, set := range e.Sets {
+					if _, err := s.Set.CreateTx(tx, exercise.ID, set.SetNumber, units.ToKilograms(set.Weight, set.Unit), set.Reps, string(set.Unit), details[i][j][k]); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		// 取り込んだ種目の自己ベストを取り込みと同じトランザクションで集計し直す
+		for _, key := range exerciseKeys(names, catalogIds) {
+			if _, err := refreshPersonalRecordsTx(s.Set, s.PersonalRecord, tx, userId, key, 0, 0); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// validateImportFormat 空文字はヘッダから判別するものとして扱う
+func validateImportFormat(format string) error {
+	if format == "" {
+		return nil
+	}
+	for _, f := range importer.Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown format %q: %w", format, ErrInvalidArgument)
+}
+
+// resolveExercise 取り込み元の種目名を種目に対応づける
+// 対応づけの指定があれば種目名・別名・カタログIDとして解決し、なければ取り込み元の名前、器具の表記を除いた名前の順に探す
+// カタログの種目に紐づいた場合はカタログの日本語名を種目名とする
+func (s *ImportImpl) resolveExercise(source string, mappings map[string]string) (importedExercise, error) {
+	target, mapped := mappings[normalizeSourceName(source)]
+	if !mapped {
+		target = source
+	}
+
+	var catalog *model.ExerciseCatalogImpl
+	var err error
+	if id, parseErr := strconv.ParseInt(target, 10, 64); mapped && parseErr == nil {
+		if catalog, err = s.ExerciseCatalog.Load(id); err != nil {
+			return importedExercise{}, err
+		}
+		if catalog.ID == 0 {
+			return importedExercise{}, fmt.Errorf("%s: catalog %d: %w", source, id, ErrInvalidArgument)
+		}
+	} else {
+		if catalog, err = s.ExerciseCatalog.Resolve(target); err != nil {
+			return importedExercise{}, err
+		}
+		if base := equipmentSuffix.ReplaceAllString(target, ""); catalog.ID == 0 && !mapped && base != target && base != "" {
+			if catalog, err = s.ExerciseCatalog.Resolve(base); err != nil {
+				return importedExercise{}, err
+			}
+		}
+	}
+
+	if catalog.ID == 0 {
+		return importedExercise{name: target, mapped: mapped}, nil
+	}
+	return importedExercise{name: catalog.NameJa, catalogID: catalog.ID, modality: catalog.Modality, mapped: mapped}, nil
+}
+
+// normalizeMapping 取り込み元の種目名の大文字・小文字や前後の空白の違いを無視して引けるようにする
+func normalizeMapping(mapping map[string]string) map[string]string {
+	normalized := make(map[string]string, len(mapping))
+	for source, target := range mapping {
+		if target = strings.TrimSpace(target); target != "" {
+			normalized[normalizeSourceName(source)] = target
+		}
+	}
+	return normalized
+}
+
+func normalizeSourceName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// importedModality カタログに紐づかない種目の記録方法をセットの記録から推定する
+// 距離があれば有酸素種目、重量がなく回数があれば自重種目、時間のみであれば時間の種目とし、それ以外はウェイト種目とする
+func importedModality(catalogModality string, sets []importer.Set) string {
+	if catalogModality != "" {
+		return model.NormalizeModality(catalogModality)
+	}
+
+	var distance, weight, reps, duration bool
+	for _, set := range sets {
+		distance = distance || set.DistanceMeters > 0
+		weight = weight || set.Weight != 0
+		reps = reps || set.Reps > 0
+		duration = duration || set.DurationSeconds > 0
+	}
+	switch {
+	case distance:
+		return model.ModalityDistance
+	case !weight && reps:
+		return model.ModalityBodyweight
+	case !weight && duration:
+		return model.ModalityTimed
+	}
+	return model.ModalityWeighted
+}
+
+// importedSetDetail 取り込むセットの種類や強度、時間・距離を検証して変換
+func importedSetDetail(set importer.Set) (model.SetDetail, error) {
+	detail := model.SetDetail{
+		SetType: set.SetType,
+		Notes:   strings.TrimSpace(set.Notes),
+	}
+	if set.DurationSeconds != 0 {
+		detail.DurationSeconds = dbr.NewNullInt64(set.DurationSeconds)
+	}
+	if set.DistanceMeters != 0 {
+		detail.DistanceMeters = dbr.NewNullFloat64(set.DistanceMeters)
+	}
+	if set.RPE != 0 {
+		if err := validateRPE(set.RPE); err != nil {
+			return detail, err
+		}
+		detail.RPE = dbr.NewNullFloat64(set.RPE)
+	}
+	if utf8.RuneCountInString(detail.Notes) > maxSetNotesLength {
+		return detail, fmt.Errorf("notes must be at most %d characters: %w", maxSetNotesLength, ErrInvalidArgument)
+	}
+	return detail, nil
+}
+
+// importedSession 取り込むセッションを実施済みのセッションに変換。ワークアウト名はメモの先頭に残す
+func importedSession(userId int64, session importer.Session) *model.WorkoutSessionImpl {
+	m := &model.WorkoutSessionImpl{
+		Date:      session.Date,
+		UserID:    userId,
+		Notes:     importedSessionNotes(session),
+		ImportKey: dbr.NewNullString(session.Key),
+	}
+	if !session.StartedAt.IsZero() {
+		m.StartedAt = dbr.NewNullTime(session.StartedAt)
+	}
+	if !session.FinishedAt.IsZero() {
+		m.FinishedAt = dbr.NewNullTime(session.FinishedAt)
+	}
+	return m
+}
+
+func importedSessionNotes(session importer.Session) string {
+	var notes []string
+	for _, s := range []string{session.Name, session.Notes} {
+		if s = strings.TrimSpace(s); s != "" {
+			notes = append(notes, s)
+		}
+	}
+	return strings.Join(notes, "\n")
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model/mock_model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/gocraft/dbr/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportWorkouts(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		WorkoutSession  model.WorkoutSession
+		Exercise        model.Exercise
+		Set             model.Set
+		ExerciseCatalog model.ExerciseCatalog
+		PersonalRecord  model.PersonalRecord
+	}
+	type args struct {
+		csv     string
+		format  string
+		mapping map[string]string
+		dryRun  bool
+	}
+	fitNotes := "Date,Exercise,Category,Weight (kgs),Reps,Distance,Distance Unit,Time,Comment\n" +
+		"2024-01-01,Bench Press (Barbell),Chest,100,5,,,,\n" +
+		"2024-01-01,My Curl,Arms,20,10,,,,\n" +
+		"2024-01-01,Pull Up,Back,,8,,,,\n" +
+		"2024-01-02,Bench Press (Barbell),Chest,100,5,,,,\n"
+	mapping := map[string]string{" my curl ": "アームカール"}
+	// 2日目のセッションは取り込み済みとする
+	importedSecond := func(userId int64, keys []string) (map[string]bool, error) {
+		return map[string]bool{keys[1]: true}, nil
+	}
+	catalog := func(ctrl *gomock.Controller) model.ExerciseCatalog {
+		ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+		ExerciseCatalog.EXPECT().Resolve("Bench Press (Barbell)").Return(&model.ExerciseCatalogImpl{}, nil)
+		ExerciseCatalog.EXPECT().Resolve("Bench Press").Return(&model.ExerciseCatalogImpl{ID: int64(1), NameJa: "ベンチプレス", Modality: model.ModalityWeighted}, nil)
+		ExerciseCatalog.EXPECT().Resolve("アームカール").Return(&model.ExerciseCatalogImpl{ID: int64(5), NameJa: "アームカール"}, nil)
+		ExerciseCatalog.EXPECT().Resolve("Pull Up").Return(&model.ExerciseCatalogImpl{}, nil)
+		return ExerciseCatalog
+	}
+	exercises := []response.ImportedExercise{
+		{SourceName: "Bench Press (Barbell)", ExerciseName: "ベンチプレス", CatalogID: int64(1), Modality: model.ModalityWeighted, Sets: 1},
+		{SourceName: "My Curl", ExerciseName: "アームカール", CatalogID: int64(5), Modality: model.ModalityWeighted, Mapped: true, Sets: 1},
+		{SourceName: "Pull Up", ExerciseName: "Pull Up", Modality: model.ModalityBodyweight, Sets: 1},
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.ImportResult, err error)
+	}{
+		{
+			testCase: "正常系(dry-runでは保存せずに取り込む内容を返却)",
+			args:     args{csv: fitNotes, mapping: mapping, dryRun: true},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().LoadImportKeys(int64(1), gomock.Len(2)).DoAndReturn(importedSecond)
+				return fields{WorkoutSession: WorkoutSession, ExerciseCatalog: catalog(ctrl)}
+			},
+			assertion: func(r *response.ImportResult, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &response.ImportResult{
+					Format:     "fitnotes",
+					DryRun:     true,
+					Imported:   1,
+					Sets:       3,
+					Duplicates: 1,
+					Sessions: []response.ImportedSession{
+						{Date: "2024-01-01", Exercises: 3, Sets: 3},
+						{Date: "2024-01-02", Exercises: 1, Sets: 1, Duplicate: true},
+					},
+					Exercises: exercises,
+					Errors:    []response.ImportError{},
+				}, r)
+			},
+		},
+		{
+			testCase: "正常系(取り込み済みのセッションを除いて1トランザクションで保存)",
+			args:     args{csv: fitNotes, format: "fitnotes", mapping: mapping},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().LoadImportKeys(int64(1), gomock.Len(2)).DoAndReturn(importedSecond)
+				WorkoutSession.EXPECT().CreateImportedTx(nil, gomock.Any()).DoAndReturn(func(tx dbr.SessionRunner, m *model.WorkoutSessionImpl) (*model.WorkoutSessionImpl, error) {
+					assert.Equal(t, "2024-01-01", m.Date.Format("2006-01-02"))
+					assert.Equal(t, int64(1), m.UserID)
+					assert.True(t, m.ImportKey.Valid)
+					m.ID = int64(10)
+					return m, nil
+				})
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().CreateTx(nil, int64(10), "ベンチプレス", int64(1), model.ModalityWeighted).Return(&model.ExerciseImpl{ID: int64(100)}, nil)
+				Exercise.EXPECT().CreateTx(nil, int64(10), "アームカール", int64(5), model.ModalityWeighted).Return(&model.ExerciseImpl{ID: int64(101)}, nil)
+				Exercise.EXPECT().CreateTx(nil, int64(10), "Pull Up", int64(0), model.ModalityBodyweight).Return(&model.ExerciseImpl{ID: int64(102)}, nil)
+				working := model.SetDetail{SetType: model.SetTypeWorking}
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().CreateTx(nil, int64(100), int64(1), float64(100), int64(5), "kg", working).Return(&model.SetImpl{}, nil)
+				Set.EXPECT().CreateTx(nil, int64(101), int64(1), float64(20), int64(10), "kg", working).Return(&model.SetImpl{}, nil)
+				Set.EXPECT().CreateTx(nil, int64(102), int64(1), float64(0), int64(8), "kg", working).Return(&model.SetImpl{}, nil)
+				// 取り込んだ3種目の自己ベストを集計し直す
+				Set.EXPECT().LoadHistoryTx(gomock.Any(), gomock.Any()).Return(&model.SetHistories{}, nil).Times(3)
+				PersonalRecord := mock_model.NewMockPersonalRecord(ctrl)
+				PersonalRecord.EXPECT().LoadByKeyTx(gomock.Any(), int64(1), gomock.Any()).Return(&model.PersonalRecords{}, nil).Times(3)
+				PersonalRecord.EXPECT().ReplaceTx(nil, int64(1), gomock.Any(), gomock.Any()).Return(nil).Times(3)
+				return fields{
+					WorkoutSession:  WorkoutSession,
+					Exercise:        Exercise,
+					Set:             Set,
+					ExerciseCatalog: catalog(ctrl),
+					PersonalRecord:  PersonalRecord,
+				}
+			},
+			assertion: func(r *response.ImportResult, err error) {
+				assert.NoError(t, err)
+				assert.False(t, r.DryRun)
+				assert.Equal(t, 1, r.Imported)
+				assert.Equal(t, int64(10), r.Sessions[0].ID)
+				assert.Equal(t, int64(0), r.Sessions[1].ID)
+			},
+		},
+		{
+			testCase: "エラー(取り込めない行がある場合は何も保存しない)",
+			args: args{csv: "Date,Exercise,Category,Weight (kgs),Reps\n" +
+				"2024-01-01,Bench Press,Chest,100,5\n" +
+				"2024-01-01,Bench Press,Chest,100,0\n"},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("Bench Press").Return(&model.ExerciseCatalogImpl{ID: int64(1), NameJa: "ベンチプレス", Modality: model.ModalityWeighted}, nil)
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().LoadImportKeys(int64(1), gomock.Len(1)).Return(map[string]bool{}, nil)
+				return fields{WorkoutSession: WorkoutSession, ExerciseCatalog: ExerciseCatalog}
+			},
+			assertion: func(r *response.ImportResult, err error) {
+				assert.True(t, errors.Is(err, ErrInvalidArgument))
+				assert.Contains(t, err.Error(), "line 3")
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(対応づけたカタログIDが存在しない)",
+			args:     args{csv: "Date,Exercise,Category,Weight (kgs),Reps\n2024-01-01,Bench Press,Chest,100,5\n", mapping: map[string]string{"Bench Press": "99"}, dryRun: true},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(99)).Return(&model.ExerciseCatalogImpl{}, nil)
+				return fields{ExerciseCatalog: ExerciseCatalog}
+			},
+			assertion: func(r *response.ImportResult, err error) {
+				assert.True(t, errors.Is(err, ErrInvalidArgument))
+			},
+		},
+		{
+			testCase: "エラー(未対応の形式)",
+			args:     args{csv: fitNotes, format: "myfitnesspal"},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r *response.ImportResult, err error) {
+				assert.True(t, errors.Is(err, ErrInvalidArgument))
+			},
+		},
+		{
+			testCase: "エラー(形式を判別できない)",
+			args:     args{csv: "foo,bar\n1,2\n"},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r *response.ImportResult, err error) {
+				assert.True(t, errors.Is(err, ErrInvalidArgument))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := tt.fields(ctrl)
+			s := &ImportImpl{
+				WorkoutSession:  f.WorkoutSession,
+				Exercise:        f.Exercise,
+				Set:             f.Set,
+				ExerciseCatalog: f.ExerciseCatalog,
+				PersonalRecord:  f.PersonalRecord,
+				Transaction:     noTransaction,
+			}
+			tt.assertion(s.Workouts(int64(1), strings.NewReader(tt.args.csv), tt.args.format, units.Kilogram, tt.args.mapping, tt.args.dryRun))
+		})
+	}
+}
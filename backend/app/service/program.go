@@ -0,0 +1,821 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+)
+
+const (
+	// プログラム名の最大文字数
+	maxProgramNameLength = 100
+	// プログラムの最大週数
+	maxProgramWeeks = 52
+	// トレーニングマックスに対する重量の割合(%)の上限
+	maxProgramPercentage = 150
+)
+
+type (
+	// Program トレーニングプログラムのサービスを表す
+	Program interface {
+		List(userId int64) (response.Programs, error)
+		Get(userId int64, id int64) (*response.Program, error)
+		Create(userId int64, f form.SaveProgram) (*response.Program, error)
+		Delete(userId int64, id int64) error
+		Enroll(userId int64, programId int64, startDate time.Time, trainingMaxes []form.TrainingMax) (*response.Enrollment, error)
+		ListEnrollments(userId int64) (response.Enrollments, error)
+		DeleteEnrollment(userId int64, id int64) error
+		PlannedSession(userId int64, enrollmentId int64, date time.Time) (*response.PlannedSession, error)
+		CreatePlannedSession(userId int64, enrollmentId int64, date time.Time) (*response.GetWorkoutSession, error)
+		LinkSession(userId int64, enrollmentId int64, sessionId int64, date time.Time) (*response.WorkoutSession, error)
+		Adherence(userId int64, enrollmentId int64, asOf time.Time) (*response.Adherence, error)
+	}
+
+	// ProgramImpl トレーニングプログラムのサービスを表す
+	ProgramImpl struct {
+		Program           model.Program
+		ProgramDay        model.ProgramDay
+		ProgramExercise   model.ProgramExercise
+		ProgramSet        model.ProgramSet
+		ProgramEnrollment model.ProgramEnrollment
+		TrainingMax       model.TrainingMax
+		WorkoutSession    model.WorkoutSession
+		Exercise          model.Exercise
+		TargetSet         model.TargetSet
+		ExerciseCatalog   model.ExerciseCatalog
+		Transaction       db.Transactor
+		Now               func() time.Time
+	}
+
+	// programPlan プログラムの予定の日・種目・セットを表す
+	programPlan struct {
+		days           *model.ProgramDays
+		exercisesByDay map[int64]*model.ProgramExercises
+		setsByExercise map[int64]*model.ProgramSets
+	}
+
+	// programDayEntry 作成する予定の日と種目を表す
+	programDayEntry struct {
+		week      int64
+		day       int64
+		name      string
+		exercises []programExerciseEntry
+	}
+
+	// programExerciseEntry 作成する予定の種目とセットを表す
+	programExerciseEntry struct {
+		exerciseName string
+		catalogId    int64
+		modality     string
+		sets         []model.PlannedSet
+	}
+)
+
+func NewProgram() Program {
+	return &ProgramImpl{
+		Program:           model.NewProgram(),
+		ProgramDay:        model.NewProgramDay(),
+		ProgramExercise:   model.NewProgramExercise(),
+		ProgramSet:        model.NewProgramSet(),
+		ProgramEnrollment: model.NewProgramEnrollment(),
+		TrainingMax:       model.NewTrainingMax(),
+		WorkoutSession:    model.NewWorkoutSession(),
+		Exercise:          model.NewExercise(),
+		TargetSet:         model.NewTargetSet(),
+		ExerciseCatalog:   model.NewExerciseCatalog(),
+		Transaction:       db.NewTransactor("training_db"),
+		Now:               time.Now,
+	}
+}
+
+// List ユーザーのプログラムを予定の日・種目・セットとあわせて名前順に取得
+func (s *ProgramImpl) List(userId int64) (response.Programs, error) {
+	programs, err := s.Program.LoadByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// プログラム数に関わらずクエリ数が一定になるようまとめて読み込む
+	plan, err := s.loadPlan(programs.IDs())
+	if err != nil {
+		return nil, err
+	}
+	daysByProgram := plan.days.GroupByProgramID()
+
+	r := response.Programs{}
+	for _, program := range *programs {
+		r = append(r, *response.NewProgram().ProgramFromModel(&program, daysByProgram[program.ID], plan.exercisesByDay, plan.setsByExercise))
+	}
+	return r, nil
+}
+
+// Get プログラムを予定の日・種目・セットとあわせて取得
+func (s *ProgramImpl) Get(userId int64, id int64) (*response.Program, error) {
+	program, err := s.loadProgram(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.programResponse(program)
+}
+
+// Create 予定の日・種目・セットを指定してプログラムを作成
+func (s *ProgramImpl) Create(userId int64, f form.SaveProgram) (*response.Program, error) {
+	name, err := s.validateProgramName(userId, f.Name)
+	if err != nil {
+		return nil, err
+	}
+	if f.Weeks <= 0 || f.Weeks > maxProgramWeeks {
+		return nil, fmt.Errorf("weeks must be between 1 and %d: %w", maxProgramWeeks, ErrInvalidArgument)
+	}
+	entries, err := s.dayEntriesFromForm(f.Weeks, f.Days)
+	if err != nil {
+		return nil, err
+	}
+
+	var program *model.ProgramImpl
+	err = s.Transaction(func(tx dbr.SessionRunner) error {
+		var err error
+		program, err = s.Program.CreateTx(tx, userId, name, f.Weeks)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			day, err := s.ProgramDay.CreateTx(tx, program.ID, entry.week, entry.day, entry.name)
+			if err != nil {
+				return err
+			}
+			for i, e := range entry.exercises {
+				exercise, err := s.ProgramExercise.CreateTx(tx, day.ID, int64(i+1), e.exerciseName, e.catalogId, e.modality)
+				if err != nil {
+					return err
+				}
+				for _, set := range e.sets {
+					if _, err := s.ProgramSet.CreateTx(tx, exercise.ID, set); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.programResponse(program)
+}
+
+// Delete プログラムを予定の日・種目・セットごと削除。参加がある場合は記録を残すため削除しない
+// 参加はDeleteEnrollmentで先にやめておく
+func (s *ProgramImpl) Delete(userId int64, id int64) error {
+	program, err := s.loadProgram(userId, id)
+	if err != nil {
+		return err
+	}
+
+	enrollments, err := s.ProgramEnrollment.LoadByProgramID(program.ID)
+	if err != nil {
+		return err
+	}
+	if len(*enrollments) != 0 {
+		return fmt.Errorf("program %d has enrollments: %w", program.ID, ErrConflict)
+	}
+
+	return s.Transaction(func(tx dbr.SessionRunner) error {
+		if _, err := s.ProgramSet.DeleteByProgramIDTx(tx, program.ID); err != nil {
+			return err
+		}
+		if _, err := s.ProgramExercise.DeleteByProgramIDTx(tx, program.ID); err != nil {
+			return err
+		}
+		if _, err := s.ProgramDay.DeleteByProgramIDTx(tx, program.ID); err != nil {
+			return err
+		}
+		if _, err := s.Program.DeleteTx(tx, program.ID); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// Enroll 開始日とトレーニングマックスを指定してプログラムに参加
+// 割合で重量を指定した種目は全てトレーニングマックスが必要で、プログラムにない種目は指定できない
+func (s *ProgramImpl) Enroll(userId int64, programId int64, startDate time.Time, trainingMaxes []form.TrainingMax) (*response.Enrollment, error) {
+	program, err := s.loadProgram(userId, programId)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.loadPlan([]int64{program.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	required := plan.percentageExercises()
+	maxes := model.TrainingMaxes{}
+	given := map[model.ExerciseKey]bool{}
+	for _, f := range trainingMaxes {
+		exerciseName, catalogId, _, err := resolveCatalog(s.ExerciseCatalog, f.CatalogID, f.ExerciseName)
+		if err != nil {
+			return nil, err
+		}
+		key := templateEntry{exerciseName: exerciseName, catalogId: catalogId}.key()
+		if _, ok := required[key]; !ok {
+			return nil, fmt.Errorf("%s is not planned by percentage in the program: %w", exerciseName, ErrInvalidArgument)
+		}
+		if given[key] {
+			return nil, fmt.Errorf("training max for %s is given more than once: %w", exerciseName, ErrInvalidArgument)
+		}
+		if f.Weight <= 0 {
+			return nil, fmt.Errorf("training max for %s must be positive: %w", exerciseName, ErrInvalidArgument)
+		}
+		given[key] = true
+
+		unit := enteredUnit(f.Unit)
+		maxes = append(maxes, model.TrainingMaxImpl{
+			CatalogID:    key.CatalogID,
+			ExerciseName: key.ExerciseName,
+			Weight:       units.ToKilograms(f.Weight, unit),
+			Unit:         string(unit),
+		})
+	}
+	for _, key := range plan.exerciseKeys() {
+		if _, ok := required[key]; ok && !given[key] {
+			return nil, fmt.Errorf("training max for %s is required: %w", required[key], ErrInvalidArgument)
+		}
+	}
+
+	var enrollment *model.ProgramEnrollmentImpl
+	err = s.Transaction(func(tx dbr.SessionRunner) error {
+		var err error
+		enrollment, err = s.ProgramEnrollment.CreateTx(tx, userId, program.ID, startDate)
+		if err != nil {
+			return err
+		}
+		for _, tm := range maxes {
+			if _, err := s.TrainingMax.CreateTx(tx, enrollment.ID, tm.Key(), tm.Weight, tm.Unit); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.NewEnrollment().EnrollmentFromModel(enrollment, program, &maxes), nil
+}
+
+// ListEnrollments ユーザーのプログラムへの参加を開始日の新しい順に取得
+func (s *ProgramImpl) ListEnrollments(userId int64) (response.Enrollments, error) {
+	enrollments, err := s.ProgramEnrollment.LoadByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 参加数に関わらずクエリ数が一定になるようまとめて読み込む
+	programs, err := s.Program.LoadByIDs(enrollments.ProgramIDs())
+	if err != nil {
+		return nil, err
+	}
+	programsById := programs.ByID()
+	maxes, err := s.TrainingMax.LoadByEnrollmentIDs(enrollments.IDs())
+	if err != nil {
+		return nil, err
+	}
+	maxesByEnrollment := maxes.GroupByEnrollmentID()
+
+	r := response.Enrollments{}
+	for _, enrollment := range *enrollments {
+		program, ok := programsById[enrollment.ProgramID]
+		if !ok {
+			return nil, fmt.Errorf("program not found. id %d: %w", enrollment.ProgramID, ErrNotFound)
+		}
+		r = append(r, *response.NewEnrollment().EnrollmentFromModel(&enrollment, program, maxesByEnrollment[enrollment.ID]))
+	}
+	return r, nil
+}
+
+// DeleteEnrollment プログラムへの参加をやめる。参加で実施したセッションは記録として残し、プログラムとの紐づけを外す
+func (s *ProgramImpl) DeleteEnrollment(userId int64, id int64) error {
+	enrollment, _, err := s.loadEnrollment(userId, id)
+	if err != nil {
+		return err
+	}
+
+	return s.Transaction(func(tx dbr.SessionRunner) error {
+		if _, err := s.WorkoutSession.UnlinkEnrollmentTx(tx, enrollment.ID); err != nil {
+			return err
+		}
+		if _, err := s.TrainingMax.DeleteByEnrollmentIDTx(tx, enrollment.ID); err != nil {
+			return err
+		}
+		if _, err := s.ProgramEnrollment.DeleteTx(tx, enrollment.ID); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// PlannedSession 指定の日の予定を、トレーニングマックスから求めた目標セットとあわせて取得
+// 予定のない日は休養日とし、プログラムの期間外の日はErrInvalidArgumentとする
+func (s *ProgramImpl) PlannedSession(userId int64, enrollmentId int64, date time.Time) (*response.PlannedSession, error) {
+	enrollment, program, err := s.loadEnrollment(userId, enrollmentId)
+	if err != nil {
+		return nil, err
+	}
+	week, day, err := programPosition(enrollment.StartDate, date, program.Weeks)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &response.PlannedSession{
+		EnrollmentID: enrollment.ID,
+		Date:         date.Format("2006-01-02"),
+		Week:         week,
+		Day:          day,
+		Exercises:    []response.PlannedExercise{},
+	}
+
+	plan, err := s.loadPlan([]int64{program.ID})
+	if err != nil {
+		return nil, err
+	}
+	programDay := plan.days.Find(week, day)
+	if programDay == nil {
+		r.RestDay = true
+		return r, nil
+	}
+	r.ProgramDayID = programDay.ID
+	r.Name = programDay.Name
+
+	entries, err := s.plannedEntries(enrollment, plan, programDay)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		r.Exercises = append(r.Exercises, response.PlannedExercise{
+			CatalogID:    entry.catalogId,
+			ExerciseName: entry.exerciseName,
+			Modality:     entry.modality,
+			Sets:         response.TargetSetsFromModel(entry.targets),
+		})
+	}
+
+	linked, err := s.linkedSessions(userId, enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if session, ok := linked[programDay.ID]; ok {
+		r.SessionID = session.ID
+	}
+	return r, nil
+}
+
+// CreatePlannedSession 指定の日の予定から下書きのセッションを作成し、予定の日に紐づける
+// 種目ごとにトレーニングマックスから求めた重量を目標セットとして入れておく
+func (s *ProgramImpl) CreatePlannedSession(userId int64, enrollmentId int64, date time.Time) (*response.GetWorkoutSession, error) {
+	enrollment, program, err := s.loadEnrollment(userId, enrollmentId)
+	if err != nil {
+		return nil, err
+	}
+	week, day, err := programPosition(enrollment.StartDate, date, program.Weeks)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.loadPlan([]int64{program.ID})
+	if err != nil {
+		return nil, err
+	}
+	programDay := plan.days.Find(week, day)
+	if programDay == nil {
+		return nil, fmt.Errorf("%s is a rest day: %w", date.Format("2006-01-02"), ErrInvalidArgument)
+	}
+
+	linked, err := s.linkedSessions(userId, enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if session, ok := linked[programDay.ID]; ok {
+		return nil, fmt.Errorf("session %d is already linked to the planned day: %w", session.ID, ErrConflict)
+	}
+
+	entries, err := s.plannedEntries(enrollment, plan, programDay)
+	if err != nil {
+		return nil, err
+	}
+
+	return createPlannedWorkout(s.Transaction, s.Exercise, s.TargetSet, entries, func(tx dbr.SessionRunner) (*model.WorkoutSessionImpl, error) {
+		return s.WorkoutSession.CreatePlannedTx(tx, date, userId, enrollment.ID, programDay.ID)
+	})
+}
+
+// LinkSession 記録したセッションをプログラムの予定の日に紐づける
+// dateが未指定の場合はセッションの日付の予定とし、予定の日に別のセッションが紐づいている場合はErrConflictとする
+// 記録してから紐づけられるよう実施済みのセッションも紐づけられるが、実施率の履歴を書き換えないよう
+// セッションの日付以外の予定や、別の予定の日に紐づけ直す場合はErrConflictとし、再開してから紐づけさせる
+func (s *ProgramImpl) LinkSession(userId int64, enrollmentId int64, sessionId int64, date time.Time) (*response.WorkoutSession, error) {
+	enrollment, program, err := s.loadEnrollment(userId, enrollmentId)
+	if err != nil {
+		return nil, err
+	}
+
+	workoutSession, err := s.WorkoutSession.Load(sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if workoutSession.ID != sessionId || workoutSession.ID == 0 || workoutSession.UserID != userId {
+		return nil, fmt.Errorf("workout session not found. id %d: %w", sessionId, ErrNotFound)
+	}
+	if date.IsZero() {
+		date = workoutSession.Date
+	}
+	if workoutSession.IsLocked() && date.Format("2006-01-02") != workoutSession.Date.Format("2006-01-02") {
+		return nil, fmt.Errorf("workout session %d is completed. reopen it to link to %s: %w", workoutSession.ID, date.Format("2006-01-02"), ErrConflict)
+	}
+
+	week, day, err := programPosition(enrollment.StartDate, date, program.Weeks)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.loadPlan([]int64{program.ID})
+	if err != nil {
+		return nil, err
+	}
+	programDay := plan.days.Find(week, day)
+	if programDay == nil {
+		return nil, fmt.Errorf("%s is a rest day: %w", date.Format("2006-01-02"), ErrInvalidArgument)
+	}
+
+	linked, err := s.linkedSessions(userId, enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if session, ok := linked[programDay.ID]; ok && session.ID != workoutSession.ID {
+		return nil, fmt.Errorf("session %d is already linked to the planned day: %w", session.ID, ErrConflict)
+	}
+	if workoutSession.IsLocked() && workoutSession.ProgramDayID.Valid &&
+		(workoutSession.EnrollmentID.Int64 != enrollment.ID || workoutSession.ProgramDayID.Int64 != programDay.ID) {
+		return nil, fmt.Errorf("workout session %d is completed. reopen it to link to another planned day: %w", workoutSession.ID, ErrConflict)
+	}
+
+	if _, err := s.WorkoutSession.LinkProgramDay(workoutSession.ID, enrollment.ID, programDay.ID); err != nil {
+		return nil, err
+	}
+
+	workoutSession.EnrollmentID = dbr.NewNullInt64(enrollment.ID)
+	workoutSession.ProgramDayID = dbr.NewNullInt64(programDay.ID)
+	return response.NewWorkoutSession().WorkoutSessionFromModel(workoutSession), nil
+}
+
+// Adherence 予定の日ごとの実施状況と実施率を取得。asOfが未指定の場合は今日を基準日とする
+// 紐づけたセッションを実施済みにした日を実施、基準日より前で実施していない日を未実施とする
+func (s *ProgramImpl) Adherence(userId int64, enrollmentId int64, asOf time.Time) (*response.Adherence, error) {
+	if asOf.IsZero() {
+		asOf = s.Now()
+	}
+	asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+
+	enrollment, program, err := s.loadEnrollment(userId, enrollmentId)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.loadPlan([]int64{program.ID})
+	if err != nil {
+		return nil, err
+	}
+	linked, err := s.linkedSessions(userId, enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &response.Adherence{
+		EnrollmentID: enrollment.ID,
+		AsOf:         asOf.Format("2006-01-02"),
+		Days:         []response.AdherenceDay{},
+	}
+	for _, programDay := range *plan.days {
+		date := enrollment.StartDate.AddDate(0, 0, int(programDay.Offset()))
+		d := response.AdherenceDay{
+			Date:         date.Format("2006-01-02"),
+			Week:         programDay.Week,
+			Day:          programDay.Day,
+			ProgramDayID: programDay.ID,
+			Name:         programDay.Name,
+		}
+		session, ok := linked[programDay.ID]
+		if ok {
+			d.SessionID = session.ID
+		}
+		switch {
+		case ok && session.Status == model.SessionStatusCompleted:
+			d.Status = response.AdherenceCompleted
+			r.Completed++
+		case daysBetween(date, asOf) > 0:
+			d.Status = response.AdherenceMissed
+			r.Missed++
+		default:
+			d.Status = response.AdherenceUpcoming
+			r.Upcoming++
+		}
+		r.Planned++
+		r.Days = append(r.Days, d)
+	}
+	if due := r.Completed + r.Missed; due != 0 {
+		r.Rate = math.Round(float64(r.Completed)/float64(due)*1000) / 1000
+	}
+	return r, nil
+}
+
+// programPosition 開始日から数えた指定の日の週・日を返却。プログラムの期間外の日はErrInvalidArgumentとする
+func programPosition(startDate time.Time, date time.Time, weeks int64) (int64, int64, error) {
+	offset := daysBetween(startDate, date)
+	if offset < 0 || offset >= weeks*model.DaysPerWeek {
+		return 0, 0, fmt.Errorf("%s is outside the program: %w", date.Format("2006-01-02"), ErrInvalidArgument)
+	}
+	return offset/model.DaysPerWeek + 1, offset%model.DaysPerWeek + 1, nil
+}
+
+// daysBetween fromからtoまでの日数。時刻とタイムゾーンは無視して日付のみで数える
+func daysBetween(from time.Time, to time.Time) int64 {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int64(to.Sub(from).Hours()) / 24
+}
+
+// plannedWeight トレーニングマックスに割合を掛けた重量を、トレーニングマックスの入力時の単位のプレートで組める重量に丸めてkgで返却
+func plannedWeight(tm model.TrainingMaxImpl, percentage float64) (float64, string) {
+	unit := enteredUnit(tm.Unit)
+	weight := units.RoundToPlate(units.Weight(tm.Weight, unit, unit)*percentage/100, unit)
+	return units.ToKilograms(weight, unit), string(unit)
+}
+
+// plannedEntries 予定の日の種目のセットを、トレーニングマックスから重量を求めた目標セットに変換
+func (s *ProgramImpl) plannedEntries(enrollment *model.ProgramEnrollmentImpl, plan *programPlan, programDay *model.ProgramDayImpl) ([]templateEntry, error) {
+	maxes, err := s.TrainingMax.LoadByEnrollmentID(enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+	maxesByKey := maxes.ByKey()
+
+	entries := []templateEntry{}
+	exercises, ok := plan.exercisesByDay[programDay.ID]
+	if !ok {
+		return entries, nil
+	}
+	for _, exercise := range *exercises {
+		entry := templateEntry{
+			exerciseName: exercise.ExerciseName,
+			catalogId:    exercise.CatalogID.Int64,
+			modality:     exercise.Modality,
+		}
+		sets, ok := plan.setsByExercise[exercise.ID]
+		if !ok {
+			entries = append(entries, entry)
+			continue
+		}
+		for _, set := range *sets {
+			target := model.SetTarget{
+				SetNumber: set.SetNumber,
+				SetType:   set.SetType,
+				Reps:      set.Reps,
+			}
+			if set.Percentage.Valid {
+				tm, ok := maxesByKey[exercise.Key()]
+				if !ok {
+					return nil, fmt.Errorf("training max for %s: %w", exercise.ExerciseName, ErrNotFound)
+				}
+				target.Weight, target.Unit = plannedWeight(tm, set.Percentage.Float64)
+			}
+			entry.targets = append(entry.targets, target)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// linkedSessions 参加の予定の日に紐づけたセッションを予定の日のIDごとに返却
+// 同じ予定の日に複数のセッションがある場合は実施済みのセッションを優先する
+func (s *ProgramImpl) linkedSessions(userId int64, enrollmentId int64) (map[int64]model.WorkoutSessionImpl, error) {
+	sessions, err := s.WorkoutSession.LoadByFilter(model.WorkoutSessionFilter{
+		UserID:       userId,
+		EnrollmentID: enrollmentId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	linked := map[int64]model.WorkoutSessionImpl{}
+	for _, session := range *sessions {
+		if !session.ProgramDayID.Valid {
+			continue
+		}
+		if existing, ok := linked[session.ProgramDayID.Int64]; ok && existing.Status == model.SessionStatusCompleted {
+			continue
+		}
+		linked[session.ProgramDayID.Int64] = session
+	}
+	return linked, nil
+}
+
+// loadPlan プログラムの予定の日・種目・セットをまとめて読み込み
+func (s *ProgramImpl) loadPlan(programIds []int64) (*programPlan, error) {
+	days, err := s.ProgramDay.LoadByProgramIDs(programIds)
+	if err != nil {
+		return nil, err
+	}
+	exercises, err := s.ProgramExercise.LoadByProgramDayIDs(days.IDs())
+	if err != nil {
+		return nil, err
+	}
+	sets, err := s.ProgramSet.LoadByProgramExerciseIDs(exercises.IDs())
+	if err != nil {
+		return nil, err
+	}
+	return &programPlan{
+		days:           days,
+		exercisesByDay: exercises.GroupByProgramDayID(),
+		setsByExercise: sets.GroupByProgramExerciseID(),
+	}, nil
+}
+
+// exerciseKeys プログラムの種目を予定の日・並び順に重複なく返却
+func (p *programPlan) exerciseKeys() []model.ExerciseKey {
+	var keys []model.ExerciseKey
+	seen := map[model.ExerciseKey]bool{}
+	for _, day := range *p.days {
+		exercises, ok := p.exercisesByDay[day.ID]
+		if !ok {
+			continue
+		}
+		for _, exercise := range *exercises {
+			if key := exercise.Key(); !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// percentageExercises 割合で重量を指定したセットがある種目を、種目名とあわせて返却
+func (p *programPlan) percentageExercises() map[model.ExerciseKey]string {
+	required := map[model.ExerciseKey]string{}
+	for _, exercises := range p.exercisesByDay {
+		for _, exercise := range *exercises {
+			sets, ok := p.setsByExercise[exercise.ID]
+			if !ok {
+				continue
+			}
+			for _, set := range *sets {
+				if set.Percentage.Valid {
+					required[exercise.Key()] = exercise.ExerciseName
+					break
+				}
+			}
+		}
+	}
+	return required
+}
+
+// programResponse プログラムの予定の日・種目・セットを読み込んでレスポンスに変換
+func (s *ProgramImpl) programResponse(program *model.ProgramImpl) (*response.Program, error) {
+	plan, err := s.loadPlan([]int64{program.ID})
+	if err != nil {
+		return nil, err
+	}
+	return response.NewProgram().ProgramFromModel(program, plan.days, plan.exercisesByDay, plan.setsByExercise), nil
+}
+
+// dayEntriesFromForm フォームで指定された予定の日を検証し、種目をカタログに紐づける
+func (s *ProgramImpl) dayEntriesFromForm(weeks int64, days []form.ProgramDay) ([]programDayEntry, error) {
+	entries := make([]programDayEntry, 0, len(days))
+	seen := map[[2]int64]bool{}
+	for _, d := range days {
+		if d.Week <= 0 || d.Week > weeks {
+			return nil, fmt.Errorf("week must be between 1 and %d: %w", weeks, ErrInvalidArgument)
+		}
+		if d.Day <= 0 || d.Day > model.DaysPerWeek {
+			return nil, fmt.Errorf("day must be between 1 and %d: %w", model.DaysPerWeek, ErrInvalidArgument)
+		}
+		if seen[[2]int64{d.Week, d.Day}] {
+			return nil, fmt.Errorf("week %d day %d is given more than once: %w", d.Week, d.Day, ErrInvalidArgument)
+		}
+		seen[[2]int64{d.Week, d.Day}] = true
+
+		entry := programDayEntry{week: d.Week, day: d.Day, name: strings.TrimSpace(d.Name)}
+		for _, e := range d.Exercises {
+			exerciseName, catalogId, catalogModality, err := resolveCatalog(s.ExerciseCatalog, e.CatalogID, e.ExerciseName)
+			if err != nil {
+				return nil, err
+			}
+			modality, err := exerciseModality(e.Modality, catalogModality)
+			if err != nil {
+				return nil, err
+			}
+
+			exercise := programExerciseEntry{exerciseName: exerciseName, catalogId: catalogId, modality: modality}
+			for _, st := range e.Sets {
+				set, err := plannedSetFromForm(st, modality)
+				if err != nil {
+					return nil, fmt.Errorf("week %d day %d %s set %d: %w", d.Week, d.Day, exerciseName, st.SetNumber, err)
+				}
+				exercise.sets = append(exercise.sets, set)
+			}
+			entry.exercises = append(entry.exercises, exercise)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// plannedSetFromForm 予定のセットを検証。割合は推定1RMを求める記録方法の種目のみ指定できる
+func plannedSetFromForm(f form.ProgramSet, modality string) (model.PlannedSet, error) {
+	if f.SetNumber <= 0 {
+		return model.PlannedSet{}, fmt.Errorf("set_number must be positive: %w", ErrInvalidArgument)
+	}
+	if f.Reps < 0 {
+		return model.PlannedSet{}, fmt.Errorf("reps must not be negative: %w", ErrInvalidArgument)
+	}
+
+	set := model.PlannedSet{
+		SetNumber: f.SetNumber,
+		SetType:   f.SetType,
+		Reps:      f.Reps,
+	}
+	if set.SetType == "" {
+		set.SetType = model.SetTypeWorking
+	}
+	if err := validateSetType(set.SetType); err != nil {
+		return model.PlannedSet{}, err
+	}
+	if f.Percentage != nil {
+		if !model.EstimatesOneRepMax(modality) {
+			return model.PlannedSet{}, fmt.Errorf("percentage is not available for %s exercises: %w", modality, ErrInvalidArgument)
+		}
+		if *f.Percentage <= 0 || *f.Percentage > maxProgramPercentage {
+			return model.PlannedSet{}, fmt.Errorf("percentage must be between 0 and %d: %w", maxProgramPercentage, ErrInvalidArgument)
+		}
+		set.Percentage = dbr.NewNullFloat64(*f.Percentage)
+	}
+	return set, nil
+}
+
+// validateProgramName プログラム名を検証し、前後の空白を除いて返却
+// 同じユーザーのプログラムと同じ名前の場合はErrConflictとする
+func (s *ProgramImpl) validateProgramName(userId int64, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("name is required: %w", ErrInvalidArgument)
+	}
+	if utf8.RuneCountInString(name) > maxProgramNameLength {
+		return "", fmt.Errorf("name must be at most %d characters: %w", maxProgramNameLength, ErrInvalidArgument)
+	}
+
+	existing, err := s.Program.LoadByName(userId, name)
+	if err != nil {
+		return "", err
+	}
+	if existing.ID != 0 {
+		return "", fmt.Errorf("program %q already exists: %w", name, ErrConflict)
+	}
+	return name, nil
+}
+
+// loadProgram ユーザーのプログラムを読み込み、存在しなければErrNotFoundを返却
+func (s *ProgramImpl) loadProgram(userId int64, id int64) (*model.ProgramImpl, error) {
+	program, err := s.Program.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if program.ID != id || program.ID == 0 || program.UserID != userId {
+		return nil, fmt.Errorf("program not found. id %d: %w", id, ErrNotFound)
+	}
+	return program, nil
+}
+
+// loadEnrollment ユーザーの参加をプログラムとあわせて読み込み、存在しなければErrNotFoundを返却
+func (s *ProgramImpl) loadEnrollment(userId int64, id int64) (*model.ProgramEnrollmentImpl, *model.ProgramImpl, error) {
+	enrollment, err := s.ProgramEnrollment.Load(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if enrollment.ID != id || enrollment.ID == 0 || enrollment.UserID != userId {
+		return nil, nil, fmt.Errorf("enrollment not found. id %d: %w", id, ErrNotFound)
+	}
+	program, err := s.Program.Load(enrollment.ProgramID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if program.ID == 0 {
+		return nil, nil, fmt.Errorf("program not found. id %d: %w", enrollment.ProgramID, ErrNotFound)
+	}
+	return enrollment, program, nil
+}
@@ -0,0 +1,884 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model/mock_model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/gocraft/dbr/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgramCreate(t *testing.T) {
+	t.Parallel()
+	percentage := func(v float64) *float64 { return &v }
+	type fields struct {
+		Program         model.Program
+		ProgramDay      model.ProgramDay
+		ProgramExercise model.ProgramExercise
+		ProgramSet      model.ProgramSet
+		ExerciseCatalog model.ExerciseCatalog
+	}
+	type args struct {
+		f form.SaveProgram
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.Program, err error)
+	}{
+		{
+			testCase: "正常系",
+			args: args{
+				f: form.SaveProgram{
+					Name:  " 5/3/1 ",
+					Weeks: int64(2),
+					Days: []form.ProgramDay{
+						{Week: int64(1), Day: int64(1), Name: "スクワット", Exercises: []form.ProgramExercise{
+							{ExerciseName: "スクワット", Sets: []form.ProgramSet{
+								{SetNumber: int64(1), Percentage: percentage(65), Reps: int64(5)},
+								{SetNumber: int64(2), SetType: model.SetTypeAMRAP, Percentage: percentage(85), Reps: int64(5)},
+							}},
+						}},
+					},
+				},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				Program := mock_model.NewMockProgram(ctrl)
+				Program.EXPECT().LoadByName(int64(1), "5/3/1").Return(&model.ProgramImpl{}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("スクワット").Return(&model.ExerciseCatalogImpl{ID: int64(2), NameJa: "スクワット", Modality: model.ModalityWeighted}, nil)
+				ProgramDay := mock_model.NewMockProgramDay(ctrl)
+				ProgramExercise := mock_model.NewMockProgramExercise(ctrl)
+				ProgramSet := mock_model.NewMockProgramSet(ctrl)
+				gomock.InOrder(
+					Program.EXPECT().CreateTx(gomock.Any(), int64(1), "5/3/1", int64(2)).Return(&model.ProgramImpl{ID: int64(3), UserID: int64(1), Name: "5/3/1", Weeks: int64(2)}, nil),
+					ProgramDay.EXPECT().CreateTx(gomock.Any(), int64(3), int64(1), int64(1), "スクワット").Return(&model.ProgramDayImpl{ID: int64(5)}, nil),
+					ProgramExercise.EXPECT().CreateTx(gomock.Any(), int64(5), int64(1), "スクワット", int64(2), model.ModalityWeighted).Return(&model.ProgramExerciseImpl{ID: int64(10)}, nil),
+					// セットの種類の指定がない場合はworkingとする
+					ProgramSet.EXPECT().CreateTx(gomock.Any(), int64(10), model.PlannedSet{SetNumber: int64(1), SetType: model.SetTypeWorking, Percentage: dbr.NewNullFloat64(65), Reps: int64(5)}).Return(&model.ProgramSetImpl{}, nil),
+					ProgramSet.EXPECT().CreateTx(gomock.Any(), int64(10), model.PlannedSet{SetNumber: int64(2), SetType: model.SetTypeAMRAP, Percentage: dbr.NewNullFloat64(85), Reps: int64(5)}).Return(&model.ProgramSetImpl{}, nil),
+				)
+				ProgramDay.EXPECT().LoadByProgramIDs([]int64{3}).Return(&model.ProgramDays{
+					{ID: int64(5), ProgramID: int64(3), Week: int64(1), Day: int64(1), Name: "スクワット"},
+				}, nil)
+				ProgramExercise.EXPECT().LoadByProgramDayIDs([]int64{5}).Return(&model.ProgramExercises{
+					{ID: int64(10), ProgramDayID: int64(5), Position: int64(1), CatalogID: dbr.NewNullInt64(2), ExerciseName: "スクワット", Modality: model.ModalityWeighted},
+				}, nil)
+				ProgramSet.EXPECT().LoadByProgramExerciseIDs([]int64{10}).Return(&model.ProgramSets{
+					{ID: int64(1), ProgramExerciseID: int64(10), PlannedSet: model.PlannedSet{SetNumber: int64(1), SetType: model.SetTypeWorking, Percentage: dbr.NewNullFloat64(65), Reps: int64(5)}},
+					{ID: int64(2), ProgramExerciseID: int64(10), PlannedSet: model.PlannedSet{SetNumber: int64(2), SetType: model.SetTypeAMRAP, Percentage: dbr.NewNullFloat64(85), Reps: int64(5)}},
+				}, nil)
+				return fields{
+					Program:         Program,
+					ProgramDay:      ProgramDay,
+					ProgramExercise: ProgramExercise,
+					ProgramSet:      ProgramSet,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.Program, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(3), r.ID)
+				assert.Equal(t, int64(2), r.Weeks)
+				if assert.Len(t, r.Days, 1) && assert.Len(t, r.Days[0].Exercises, 1) && assert.Len(t, r.Days[0].Exercises[0].Sets, 2) {
+					assert.Equal(t, float64(85), *r.Days[0].Exercises[0].Sets[1].Percentage)
+				}
+			},
+		},
+		{
+			testCase: "エラー(同じ名前のプログラムがある)",
+			args: args{
+				f: form.SaveProgram{Name: "5/3/1", Weeks: int64(4)},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				Program := mock_model.NewMockProgram(ctrl)
+				Program.EXPECT().LoadByName(int64(1), "5/3/1").Return(&model.ProgramImpl{ID: int64(2)}, nil)
+				return fields{
+					Program: Program,
+				}
+			},
+			assertion: func(r *response.Program, err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(週数が上限を超える)",
+			args: args{
+				f: form.SaveProgram{Name: "5/3/1", Weeks: int64(53)},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				Program := mock_model.NewMockProgram(ctrl)
+				Program.EXPECT().LoadByName(int64(1), "5/3/1").Return(&model.ProgramImpl{}, nil)
+				return fields{
+					Program: Program,
+				}
+			},
+			assertion: func(r *response.Program, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(予定の日がプログラムの週数を超える)",
+			args: args{
+				f: form.SaveProgram{Name: "5/3/1", Weeks: int64(2), Days: []form.ProgramDay{{Week: int64(3), Day: int64(1)}}},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				Program := mock_model.NewMockProgram(ctrl)
+				Program.EXPECT().LoadByName(int64(1), "5/3/1").Return(&model.ProgramImpl{}, nil)
+				return fields{
+					Program: Program,
+				}
+			},
+			assertion: func(r *response.Program, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(同じ週・日の予定が複数ある)",
+			args: args{
+				f: form.SaveProgram{Name: "5/3/1", Weeks: int64(2), Days: []form.ProgramDay{
+					{Week: int64(1), Day: int64(3)},
+					{Week: int64(1), Day: int64(3)},
+				}},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				Program := mock_model.NewMockProgram(ctrl)
+				Program.EXPECT().LoadByName(int64(1), "5/3/1").Return(&model.ProgramImpl{}, nil)
+				return fields{
+					Program: Program,
+				}
+			},
+			assertion: func(r *response.Program, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(自重の種目に割合を指定)",
+			args: args{
+				f: form.SaveProgram{Name: "5/3/1", Weeks: int64(1), Days: []form.ProgramDay{
+					{Week: int64(1), Day: int64(1), Exercises: []form.ProgramExercise{
+						{ExerciseName: "懸垂", Sets: []form.ProgramSet{{SetNumber: int64(1), Percentage: percentage(80), Reps: int64(8)}}},
+					}},
+				}},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				Program := mock_model.NewMockProgram(ctrl)
+				Program.EXPECT().LoadByName(int64(1), "5/3/1").Return(&model.ProgramImpl{}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("懸垂").Return(&model.ExerciseCatalogImpl{ID: int64(8), Modality: model.ModalityBodyweight}, nil)
+				return fields{
+					Program:         Program,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.Program, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := tt.fields(ctrl)
+			s := &ProgramImpl{
+				Program:         f.Program,
+				ProgramDay:      f.ProgramDay,
+				ProgramExercise: f.ProgramExercise,
+				ProgramSet:      f.ProgramSet,
+				ExerciseCatalog: f.ExerciseCatalog,
+				Transaction:     noTransaction,
+			}
+			tt.assertion(s.Create(int64(1), tt.args.f))
+		})
+	}
+}
+
+func TestProgramDelete(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	Program := mock_model.NewMockProgram(ctrl)
+	Program.EXPECT().Load(int64(3)).Return(&model.ProgramImpl{ID: int64(3), UserID: int64(1)}, nil)
+	ProgramEnrollment := mock_model.NewMockProgramEnrollment(ctrl)
+	ProgramEnrollment.EXPECT().LoadByProgramID(int64(3)).Return(&model.ProgramEnrollments{{ID: int64(4), ProgramID: int64(3)}}, nil)
+	s := &ProgramImpl{
+		Program:           Program,
+		ProgramEnrollment: ProgramEnrollment,
+		Transaction:       noTransaction,
+	}
+
+	// 参加があるプログラムは削除できない
+	assert.ErrorIs(t, s.Delete(int64(1), int64(3)), ErrConflict)
+}
+
+func TestProgramListEnrollments(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	startDate := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	ProgramEnrollment := mock_model.NewMockProgramEnrollment(ctrl)
+	ProgramEnrollment.EXPECT().LoadByUserID(int64(1)).Return(&model.ProgramEnrollments{
+		{ID: int64(5), UserID: int64(1), ProgramID: int64(3), StartDate: startDate.AddDate(0, 0, 14)},
+		{ID: int64(4), UserID: int64(1), ProgramID: int64(3), StartDate: startDate},
+		{ID: int64(2), UserID: int64(1), ProgramID: int64(6), StartDate: startDate.AddDate(0, 0, -7)},
+	}, nil)
+	// 参加数に関わらずプログラムとトレーニングマックスはそれぞれ1回で読み込む
+	Program := mock_model.NewMockProgram(ctrl)
+	Program.EXPECT().LoadByIDs([]int64{3, 6}).Return(&model.Programs{
+		{ID: int64(3), UserID: int64(1), Name: "5/3/1", Weeks: int64(2)},
+		{ID: int64(6), UserID: int64(1), Name: "GZCLP", Weeks: int64(1)},
+	}, nil).Times(1)
+	Program.EXPECT().Load(gomock.Any()).Times(0)
+	TrainingMax := mock_model.NewMockTrainingMax(ctrl)
+	TrainingMax.EXPECT().LoadByEnrollmentIDs([]int64{5, 4, 2}).Return(&model.TrainingMaxes{
+		{ID: int64(1), EnrollmentID: int64(4), CatalogID: int64(2), Weight: float64(100), Unit: "kg"},
+		{ID: int64(2), EnrollmentID: int64(5), CatalogID: int64(2), Weight: float64(105), Unit: "kg"},
+	}, nil).Times(1)
+	TrainingMax.EXPECT().LoadByEnrollmentID(gomock.Any()).Times(0)
+	s := &ProgramImpl{
+		Program:           Program,
+		ProgramEnrollment: ProgramEnrollment,
+		TrainingMax:       TrainingMax,
+	}
+
+	r, err := s.ListEnrollments(int64(1))
+	assert.NoError(t, err)
+	if assert.Len(t, r, 3) {
+		assert.Equal(t, int64(5), r[0].ID)
+		if assert.Len(t, r[0].TrainingMaxes, 1) {
+			assert.Equal(t, float64(105), r[0].TrainingMaxes[0].Kilograms)
+		}
+		assert.Equal(t, "2026-10-18", r[1].EndDate)
+		assert.Len(t, r[1].TrainingMaxes, 1)
+		// トレーニングマックスのない参加は空の一覧とする
+		assert.Equal(t, "2026-10-04", r[2].EndDate)
+		assert.NotNil(t, r[2].TrainingMaxes)
+		assert.Empty(t, r[2].TrainingMaxes)
+	}
+}
+
+func TestProgramDeleteEnrollment(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		ProgramEnrollment model.ProgramEnrollment
+		TrainingMax       model.TrainingMax
+		WorkoutSession    model.WorkoutSession
+	}
+	tests := []struct {
+		testCase  string
+		userId    int64
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(err error)
+	}{
+		{
+			testCase: "正常系",
+			userId:   int64(1),
+			fields: func(ctrl *gomock.Controller) fields {
+				ProgramEnrollment := mock_model.NewMockProgramEnrollment(ctrl)
+				ProgramEnrollment.EXPECT().Load(int64(4)).Return(&model.ProgramEnrollmentImpl{ID: int64(4), UserID: int64(1), ProgramID: int64(3)}, nil)
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				TrainingMax := mock_model.NewMockTrainingMax(ctrl)
+				// 外部キーのあるセッションとトレーニングマックスを先に片付けてから参加を削除する
+				gomock.InOrder(
+					WorkoutSession.EXPECT().UnlinkEnrollmentTx(gomock.Any(), int64(4)).Return(int64(2), nil),
+					TrainingMax.EXPECT().DeleteByEnrollmentIDTx(gomock.Any(), int64(4)).Return(int64(1), nil),
+					ProgramEnrollment.EXPECT().DeleteTx(gomock.Any(), int64(4)).Return(true, nil),
+				)
+				return fields{
+					ProgramEnrollment: ProgramEnrollment,
+					TrainingMax:       TrainingMax,
+					WorkoutSession:    WorkoutSession,
+				}
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "エラー(他のユーザーの参加)",
+			userId:   int64(2),
+			fields: func(ctrl *gomock.Controller) fields {
+				ProgramEnrollment := mock_model.NewMockProgramEnrollment(ctrl)
+				ProgramEnrollment.EXPECT().Load(int64(4)).Return(&model.ProgramEnrollmentImpl{ID: int64(4), UserID: int64(1), ProgramID: int64(3)}, nil)
+				return fields{
+					ProgramEnrollment: ProgramEnrollment,
+				}
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			Program := mock_model.NewMockProgram(ctrl)
+			Program.EXPECT().Load(int64(3)).Return(&model.ProgramImpl{ID: int64(3), UserID: int64(1), Weeks: int64(2)}, nil).AnyTimes()
+			s := &ProgramImpl{
+				Program:           Program,
+				ProgramEnrollment: fields.ProgramEnrollment,
+				TrainingMax:       fields.TrainingMax,
+				WorkoutSession:    fields.WorkoutSession,
+				Transaction:       noTransaction,
+			}
+			tt.assertion(s.DeleteEnrollment(tt.userId, int64(4)))
+		})
+	}
+}
+
+// squatPlan スクワットを割合で、懸垂を回数のみで指定した1週目1日目の予定を返す
+func squatPlan(ctrl *gomock.Controller) (model.ProgramDay, model.ProgramExercise, model.ProgramSet) {
+	ProgramDay := mock_model.NewMockProgramDay(ctrl)
+	ProgramDay.EXPECT().LoadByProgramIDs([]int64{3}).Return(&model.ProgramDays{
+		{ID: int64(5), ProgramID: int64(3), Week: int64(1), Day: int64(1), Name: "A"},
+		{ID: int64(6), ProgramID: int64(3), Week: int64(1), Day: int64(3), Name: "B"},
+	}, nil).AnyTimes()
+	ProgramExercise := mock_model.NewMockProgramExercise(ctrl)
+	ProgramExercise.EXPECT().LoadByProgramDayIDs([]int64{5, 6}).Return(&model.ProgramExercises{
+		{ID: int64(10), ProgramDayID: int64(5), Position: int64(1), CatalogID: dbr.NewNullInt64(2), ExerciseName: "スクワット", Modality: model.ModalityWeighted},
+		{ID: int64(11), ProgramDayID: int64(5), Position: int64(2), ExerciseName: "懸垂", Modality: model.ModalityBodyweight},
+	}, nil).AnyTimes()
+	ProgramSet := mock_model.NewMockProgramSet(ctrl)
+	ProgramSet.EXPECT().LoadByProgramExerciseIDs([]int64{10, 11}).Return(&model.ProgramSets{
+		{ProgramExerciseID: int64(10), PlannedSet: model.PlannedSet{SetNumber: int64(1), SetType: model.SetTypeWorking, Percentage: dbr.NewNullFloat64(65), Reps: int64(5)}},
+		{ProgramExerciseID: int64(10), PlannedSet: model.PlannedSet{SetNumber: int64(2), SetType: model.SetTypeAMRAP, Percentage: dbr.NewNullFloat64(72.5), Reps: int64(5)}},
+		{ProgramExerciseID: int64(11), PlannedSet: model.PlannedSet{SetNumber: int64(1), SetType: model.SetTypeWorking, Reps: int64(8)}},
+	}, nil).AnyTimes()
+	return ProgramDay, ProgramExercise, ProgramSet
+}
+
+func TestProgramEnroll(t *testing.T) {
+	t.Parallel()
+	startDate := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	type fields struct {
+		ProgramEnrollment model.ProgramEnrollment
+		TrainingMax       model.TrainingMax
+		ExerciseCatalog   model.ExerciseCatalog
+	}
+	type args struct {
+		trainingMaxes []form.TrainingMax
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.Enrollment, err error)
+	}{
+		{
+			testCase: "正常系",
+			args: args{
+				trainingMaxes: []form.TrainingMax{{CatalogID: int64(2), Weight: float64(225), Unit: "lb"}},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(2)).Return(&model.ExerciseCatalogImpl{ID: int64(2), NameJa: "スクワット", Modality: model.ModalityWeighted}, nil)
+				ProgramEnrollment := mock_model.NewMockProgramEnrollment(ctrl)
+				ProgramEnrollment.EXPECT().CreateTx(gomock.Any(), int64(1), int64(3), startDate).Return(&model.ProgramEnrollmentImpl{ID: int64(4), UserID: int64(1), ProgramID: int64(3), StartDate: startDate}, nil)
+				TrainingMax := mock_model.NewMockTrainingMax(ctrl)
+				// ポンドで指定した重量はkgに変換して保存する
+				TrainingMax.EXPECT().CreateTx(gomock.Any(), int64(4), model.ExerciseKey{CatalogID: int64(2)}, float64(102.058), "lb").Return(&model.TrainingMaxImpl{}, nil)
+				return fields{
+					ProgramEnrollment: ProgramEnrollment,
+					TrainingMax:       TrainingMax,
+					ExerciseCatalog:   ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.Enrollment, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(4), r.ID)
+				assert.Equal(t, "2026-10-05", r.StartDate)
+				// 2週間のプログラムは14日目に終わる
+				assert.Equal(t, "2026-10-18", r.EndDate)
+				if assert.Len(t, r.TrainingMaxes, 1) {
+					r.ApplyUnit("lb")
+					assert.Equal(t, float64(225), r.TrainingMaxes[0].Weight)
+				}
+			},
+		},
+		{
+			testCase: "エラー(割合で指定した種目のトレーニングマックスがない)",
+			args:     args{},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r *response.Enrollment, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(割合で指定していない種目のトレーニングマックス)",
+			args: args{
+				trainingMaxes: []form.TrainingMax{
+					{CatalogID: int64(2), Weight: float64(100)},
+					{ExerciseName: "懸垂", Weight: float64(20)},
+				},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(2)).Return(&model.ExerciseCatalogImpl{ID: int64(2), NameJa: "スクワット", Modality: model.ModalityWeighted}, nil)
+				ExerciseCatalog.EXPECT().Resolve("懸垂").Return(&model.ExerciseCatalogImpl{}, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.Enrollment, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := tt.fields(ctrl)
+			Program := mock_model.NewMockProgram(ctrl)
+			Program.EXPECT().Load(int64(3)).Return(&model.ProgramImpl{ID: int64(3), UserID: int64(1), Weeks: int64(2)}, nil)
+			ProgramDay, ProgramExercise, ProgramSet := squatPlan(ctrl)
+			s := &ProgramImpl{
+				Program:           Program,
+				ProgramDay:        ProgramDay,
+				ProgramExercise:   ProgramExercise,
+				ProgramSet:        ProgramSet,
+				ProgramEnrollment: f.ProgramEnrollment,
+				TrainingMax:       f.TrainingMax,
+				ExerciseCatalog:   f.ExerciseCatalog,
+				Transaction:       noTransaction,
+			}
+			tt.assertion(s.Enroll(int64(1), int64(3), startDate, tt.args.trainingMaxes))
+		})
+	}
+}
+
+func TestProgramPlannedSession(t *testing.T) {
+	t.Parallel()
+	startDate := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	type args struct {
+		date time.Time
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		assertion func(r *response.PlannedSession, err error)
+	}{
+		{
+			testCase: "正常系",
+			args: args{
+				date: startDate,
+			},
+			assertion: func(r *response.PlannedSession, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), r.Week)
+				assert.Equal(t, int64(1), r.Day)
+				assert.False(t, r.RestDay)
+				assert.Equal(t, int64(5), r.ProgramDayID)
+				assert.Equal(t, int64(20), r.SessionID)
+				if assert.Len(t, r.Exercises, 2) && assert.Len(t, r.Exercises[0].Sets, 2) {
+					// トレーニングマックス225lbの65%と72.5%を、lbのプレートで組める重量に丸める
+					r.ApplyUnit("lb")
+					assert.Equal(t, float64(146), r.Exercises[0].Sets[0].Weight)
+					assert.Equal(t, float64(163), r.Exercises[0].Sets[1].Weight)
+					assert.Equal(t, model.SetTypeAMRAP, r.Exercises[0].Sets[1].SetType)
+					// 割合を指定していないセットは回数のみ
+					assert.Equal(t, float64(0), r.Exercises[1].Sets[0].Weight)
+					assert.Equal(t, int64(8), r.Exercises[1].Sets[0].Reps)
+				}
+			},
+		},
+		{
+			testCase: "正常系(休養日)",
+			args: args{
+				// 2週目の2日目
+				date: startDate.AddDate(0, 0, 8),
+			},
+			assertion: func(r *response.PlannedSession, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(2), r.Week)
+				assert.Equal(t, int64(2), r.Day)
+				assert.True(t, r.RestDay)
+				assert.Empty(t, r.Exercises)
+			},
+		},
+		{
+			testCase: "エラー(プログラムの期間外)",
+			args: args{
+				date: startDate.AddDate(0, 0, 14),
+			},
+			assertion: func(r *response.PlannedSession, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ProgramEnrollment := mock_model.NewMockProgramEnrollment(ctrl)
+			ProgramEnrollment.EXPECT().Load(int64(4)).Return(&model.ProgramEnrollmentImpl{ID: int64(4), UserID: int64(1), ProgramID: int64(3), StartDate: startDate}, nil)
+			Program := mock_model.NewMockProgram(ctrl)
+			Program.EXPECT().Load(int64(3)).Return(&model.ProgramImpl{ID: int64(3), UserID: int64(1), Weeks: int64(2)}, nil)
+			ProgramDay, ProgramExercise, ProgramSet := squatPlan(ctrl)
+			TrainingMax := mock_model.NewMockTrainingMax(ctrl)
+			TrainingMax.EXPECT().LoadByEnrollmentID(int64(4)).Return(&model.TrainingMaxes{
+				{EnrollmentID: int64(4), CatalogID: int64(2), Weight: float64(102.058), Unit: "lb"},
+			}, nil).AnyTimes()
+			WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+			WorkoutSession.EXPECT().LoadByFilter(model.WorkoutSessionFilter{UserID: int64(1), EnrollmentID: int64(4)}).Return(&model.WorkoutSessions{
+				{ID: int64(20), EnrollmentID: dbr.NewNullInt64(4), ProgramDayID: dbr.NewNullInt64(5)},
+			}, nil).AnyTimes()
+			s := &ProgramImpl{
+				Program:           Program,
+				ProgramDay:        ProgramDay,
+				ProgramExercise:   ProgramExercise,
+				ProgramSet:        ProgramSet,
+				ProgramEnrollment: ProgramEnrollment,
+				TrainingMax:       TrainingMax,
+				WorkoutSession:    WorkoutSession,
+			}
+			tt.assertion(s.PlannedSession(int64(1), int64(4), tt.args.date))
+		})
+	}
+}
+
+func TestProgramCreatePlannedSession(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	startDate := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+
+	ProgramEnrollment := mock_model.NewMockProgramEnrollment(ctrl)
+	ProgramEnrollment.EXPECT().Load(int64(4)).Return(&model.ProgramEnrollmentImpl{ID: int64(4), UserID: int64(1), ProgramID: int64(3), StartDate: startDate}, nil)
+	Program := mock_model.NewMockProgram(ctrl)
+	Program.EXPECT().Load(int64(3)).Return(&model.ProgramImpl{ID: int64(3), UserID: int64(1), Weeks: int64(2)}, nil)
+	ProgramDay, ProgramExercise, ProgramSet := squatPlan(ctrl)
+	TrainingMax := mock_model.NewMockTrainingMax(ctrl)
+	TrainingMax.EXPECT().LoadByEnrollmentID(int64(4)).Return(&model.TrainingMaxes{
+		{EnrollmentID: int64(4), CatalogID: int64(2), Weight: float64(100), Unit: "kg"},
+	}, nil)
+	WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+	WorkoutSession.EXPECT().LoadByFilter(model.WorkoutSessionFilter{UserID: int64(1), EnrollmentID: int64(4)}).Return(&model.WorkoutSessions{}, nil)
+	Exercise := mock_model.NewMockExercise(ctrl)
+	TargetSet := mock_model.NewMockTargetSet(ctrl)
+	gomock.InOrder(
+		WorkoutSession.EXPECT().CreatePlannedTx(gomock.Any(), startDate, int64(1), int64(4), int64(5)).Return(&model.WorkoutSessionImpl{ID: int64(30), Date: startDate, UserID: int64(1), EnrollmentID: dbr.NewNullInt64(4), ProgramDayID: dbr.NewNullInt64(5), Status: model.SessionStatusDraft}, nil),
+		Exercise.EXPECT().CreateTx(gomock.Any(), int64(30), "スクワット", int64(2), model.ModalityWeighted).Return(&model.ExerciseImpl{ID: int64(40), SessionID: int64(30), ExerciseName: "スクワット", Modality: model.ModalityWeighted}, nil),
+		TargetSet.EXPECT().CreateTx(gomock.Any(), int64(40), model.SetTarget{SetNumber: int64(1), SetType: model.SetTypeWorking, Weight: float64(65), Unit: "kg", Reps: int64(5)}).Return(&model.TargetSetImpl{}, nil),
+		// 100kgの72.5%はkgのプレートの刻みに丸める
+		TargetSet.EXPECT().CreateTx(gomock.Any(), int64(40), model.SetTarget{SetNumber: int64(2), SetType: model.SetTypeAMRAP, Weight: float64(72.5), Unit: "kg", Reps: int64(5)}).Return(&model.TargetSetImpl{}, nil),
+		Exercise.EXPECT().CreateTx(gomock.Any(), int64(30), "懸垂", int64(0), model.ModalityBodyweight).Return(&model.ExerciseImpl{ID: int64(41), SessionID: int64(30), ExerciseName: "懸垂", Modality: model.ModalityBodyweight}, nil),
+		TargetSet.EXPECT().CreateTx(gomock.Any(), int64(41), model.SetTarget{SetNumber: int64(1), SetType: model.SetTypeWorking, Reps: int64(8)}).Return(&model.TargetSetImpl{}, nil),
+	)
+	s := &ProgramImpl{
+		Program:           Program,
+		ProgramDay:        ProgramDay,
+		ProgramExercise:   ProgramExercise,
+		ProgramSet:        ProgramSet,
+		ProgramEnrollment: ProgramEnrollment,
+		TrainingMax:       TrainingMax,
+		WorkoutSession:    WorkoutSession,
+		Exercise:          Exercise,
+		TargetSet:         TargetSet,
+		Transaction:       noTransaction,
+	}
+
+	r, err := s.CreatePlannedSession(int64(1), int64(4), startDate)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), r.EnrollmentID)
+	assert.Equal(t, int64(5), r.ProgramDayID)
+	if assert.Len(t, r.Exercises, 2) {
+		assert.Len(t, r.Exercises[0].TargetSets, 2)
+	}
+}
+
+func TestProgramLinkSession(t *testing.T) {
+	t.Parallel()
+	startDate := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	type fields struct {
+		WorkoutSession model.WorkoutSession
+	}
+	type args struct {
+		sessionId int64
+		date      time.Time
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.WorkoutSession, err error)
+	}{
+		{
+			testCase: "正常系(セッションの日付の予定に紐づける)",
+			args: args{
+				sessionId: int64(21),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(21)).Return(&model.WorkoutSessionImpl{ID: int64(21), UserID: int64(1), Date: startDate.AddDate(0, 0, 2), Status: model.SessionStatusCompleted}, nil)
+				WorkoutSession.EXPECT().LoadByFilter(model.WorkoutSessionFilter{UserID: int64(1), EnrollmentID: int64(4)}).Return(&model.WorkoutSessions{}, nil)
+				WorkoutSession.EXPECT().LinkProgramDay(int64(21), int64(4), int64(6)).Return(true, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+				}
+			},
+			assertion: func(r *response.WorkoutSession, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(4), r.EnrollmentID)
+				assert.Equal(t, int64(6), r.ProgramDayID)
+			},
+		},
+		{
+			testCase: "エラー(休養日)",
+			args: args{
+				sessionId: int64(21),
+				date:      startDate.AddDate(0, 0, 1),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(21)).Return(&model.WorkoutSessionImpl{ID: int64(21), UserID: int64(1), Date: startDate}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+				}
+			},
+			assertion: func(r *response.WorkoutSession, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(セッションの日付が休養日)",
+			args: args{
+				sessionId: int64(21),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(21)).Return(&model.WorkoutSessionImpl{ID: int64(21), UserID: int64(1), Date: startDate.AddDate(0, 0, 1), Status: model.SessionStatusCompleted}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+				}
+			},
+			assertion: func(r *response.WorkoutSession, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(実施済みのセッションを別の日付の予定に紐づける)",
+			args: args{
+				sessionId: int64(21),
+				date:      startDate,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(21)).Return(&model.WorkoutSessionImpl{ID: int64(21), UserID: int64(1), Date: startDate.AddDate(0, 0, 2), Status: model.SessionStatusCompleted}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+				}
+			},
+			assertion: func(r *response.WorkoutSession, err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(実施済みのセッションを別の予定の日に紐づけ直す)",
+			args: args{
+				sessionId: int64(21),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(21)).Return(&model.WorkoutSessionImpl{ID: int64(21), UserID: int64(1), Date: startDate.AddDate(0, 0, 2), Status: model.SessionStatusCompleted, EnrollmentID: dbr.NewNullInt64(4), ProgramDayID: dbr.NewNullInt64(5)}, nil)
+				WorkoutSession.EXPECT().LoadByFilter(model.WorkoutSessionFilter{UserID: int64(1), EnrollmentID: int64(4)}).Return(&model.WorkoutSessions{
+					{ID: int64(21), EnrollmentID: dbr.NewNullInt64(4), ProgramDayID: dbr.NewNullInt64(5)},
+				}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+				}
+			},
+			assertion: func(r *response.WorkoutSession, err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(予定の日に別のセッションが紐づいている)",
+			args: args{
+				sessionId: int64(21),
+				date:      startDate,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(21)).Return(&model.WorkoutSessionImpl{ID: int64(21), UserID: int64(1), Date: startDate}, nil)
+				WorkoutSession.EXPECT().LoadByFilter(model.WorkoutSessionFilter{UserID: int64(1), EnrollmentID: int64(4)}).Return(&model.WorkoutSessions{
+					{ID: int64(20), EnrollmentID: dbr.NewNullInt64(4), ProgramDayID: dbr.NewNullInt64(5)},
+				}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+				}
+			},
+			assertion: func(r *response.WorkoutSession, err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(他人のセッション)",
+			args: args{
+				sessionId: int64(22),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(22)).Return(&model.WorkoutSessionImpl{ID: int64(22), UserID: int64(2), Date: startDate}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+				}
+			},
+			assertion: func(r *response.WorkoutSession, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.Nil(t, r)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := tt.fields(ctrl)
+			ProgramEnrollment := mock_model.NewMockProgramEnrollment(ctrl)
+			ProgramEnrollment.EXPECT().Load(int64(4)).Return(&model.ProgramEnrollmentImpl{ID: int64(4), UserID: int64(1), ProgramID: int64(3), StartDate: startDate}, nil)
+			Program := mock_model.NewMockProgram(ctrl)
+			Program.EXPECT().Load(int64(3)).Return(&model.ProgramImpl{ID: int64(3), UserID: int64(1), Weeks: int64(2)}, nil)
+			ProgramDay, ProgramExercise, ProgramSet := squatPlan(ctrl)
+			s := &ProgramImpl{
+				Program:           Program,
+				ProgramDay:        ProgramDay,
+				ProgramExercise:   ProgramExercise,
+				ProgramSet:        ProgramSet,
+				ProgramEnrollment: ProgramEnrollment,
+				WorkoutSession:    f.WorkoutSession,
+			}
+			tt.assertion(s.LinkSession(int64(1), int64(4), tt.args.sessionId, tt.args.date))
+		})
+	}
+}
+
+func TestProgramAdherence(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	startDate := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+
+	ProgramEnrollment := mock_model.NewMockProgramEnrollment(ctrl)
+	ProgramEnrollment.EXPECT().Load(int64(4)).Return(&model.ProgramEnrollmentImpl{ID: int64(4), UserID: int64(1), ProgramID: int64(3), StartDate: startDate}, nil)
+	Program := mock_model.NewMockProgram(ctrl)
+	Program.EXPECT().Load(int64(3)).Return(&model.ProgramImpl{ID: int64(3), UserID: int64(1), Weeks: int64(2)}, nil)
+	ProgramDay := mock_model.NewMockProgramDay(ctrl)
+	ProgramDay.EXPECT().LoadByProgramIDs([]int64{3}).Return(&model.ProgramDays{
+		{ID: int64(5), Week: int64(1), Day: int64(1)},
+		{ID: int64(6), Week: int64(1), Day: int64(3)},
+		{ID: int64(7), Week: int64(1), Day: int64(5)},
+		{ID: int64(8), Week: int64(2), Day: int64(1)},
+	}, nil)
+	ProgramExercise := mock_model.NewMockProgramExercise(ctrl)
+	ProgramExercise.EXPECT().LoadByProgramDayIDs([]int64{5, 6, 7, 8}).Return(&model.ProgramExercises{}, nil)
+	ProgramSet := mock_model.NewMockProgramSet(ctrl)
+	ProgramSet.EXPECT().LoadByProgramExerciseIDs([]int64{}).Return(&model.ProgramSets{}, nil)
+	WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+	WorkoutSession.EXPECT().LoadByFilter(model.WorkoutSessionFilter{UserID: int64(1), EnrollmentID: int64(4)}).Return(&model.WorkoutSessions{
+		{ID: int64(20), ProgramDayID: dbr.NewNullInt64(5), Status: model.SessionStatusCompleted},
+		// 実施済みにしていないセッションは実施としない
+		{ID: int64(21), ProgramDayID: dbr.NewNullInt64(6), Status: model.SessionStatusInProgress},
+	}, nil)
+	s := &ProgramImpl{
+		Program:           Program,
+		ProgramDay:        ProgramDay,
+		ProgramExercise:   ProgramExercise,
+		ProgramSet:        ProgramSet,
+		ProgramEnrollment: ProgramEnrollment,
+		WorkoutSession:    WorkoutSession,
+		Now: func() time.Time {
+			// 1週目の5日目
+			return time.Date(2026, 10, 9, 18, 0, 0, 0, time.UTC)
+		},
+	}
+
+	r, err := s.Adherence(int64(1), int64(4), time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-10-09", r.AsOf)
+	assert.Equal(t, int64(4), r.Planned)
+	assert.Equal(t, int64(1), r.Completed)
+	assert.Equal(t, int64(1), r.Missed)
+	// 基準日の予定はまだ未実施としない
+	assert.Equal(t, int64(2), r.Upcoming)
+	assert.Equal(t, 0.5, r.Rate)
+	if assert.Len(t, r.Days, 4) {
+		assert.Equal(t, "2026-10-07", r.Days[1].Date)
+		assert.Equal(t, response.AdherenceMissed, r.Days[1].Status)
+		assert.Equal(t, int64(21), r.Days[1].SessionID)
+		assert.Equal(t, response.AdherenceUpcoming, r.Days[2].Status)
+	}
+}
+
+func TestProgramPosition(t *testing.T) {
+	t.Parallel()
+	startDate := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		testCase string
+		date     time.Time
+		week     int64
+		day      int64
+		err      error
+	}{
+		{testCase: "正常系(開始日)", date: startDate, week: 1, day: 1},
+		{testCase: "正常系(時刻は無視する)", date: startDate.Add(23 * time.Hour), week: 1, day: 1},
+		{testCase: "正常系(2週目)", date: startDate.AddDate(0, 0, 9), week: 2, day: 3},
+		{testCase: "正常系(最終日)", date: startDate.AddDate(0, 0, 27), week: 4, day: 7},
+		{testCase: "エラー(開始日より前)", date: startDate.AddDate(0, 0, -1), err: ErrInvalidArgument},
+		{testCase: "エラー(終了日より後)", date: startDate.AddDate(0, 0, 28), err: ErrInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			week, day, err := programPosition(startDate, tt.date, int64(4))
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.week, week)
+			assert.Equal(t, tt.day, day)
+		})
+	}
+}
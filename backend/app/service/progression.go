@@ -0,0 +1,375 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+)
+
+// 進め方の指定がない場合の既定値
+const (
+	defaultProgressionSets             = 3
+	defaultProgressionTargetRPE        = 8
+	defaultProgressionDeloadAfter      = 3
+	defaultProgressionDeloadPercentage = 10
+	maxProgressionSets                 = 20
+	maxDeloadPercentage                = 50
+)
+
+// progressionWeightEpsilon 単位変換による誤差を同じ重量とみなす重量(kg)
+const progressionWeightEpsilon = 0.01
+
+type (
+	// Progression 重量・回数の進め方と次のセッションの提案のサービスを表す
+	Progression interface {
+		ListRules(userId int64) (response.ProgressionRules, error)
+		SaveRule(userId int64, f form.SaveProgressionRule) (*response.ProgressionRule, error)
+		DeleteRule(userId int64, id int64) error
+		Suggest(userId int64, exercise string) (*response.ProgressionSuggestion, error)
+	}
+
+	// ProgressionImpl 重量・回数の進め方と次のセッションの提案のサービスを表す
+	ProgressionImpl struct {
+		ProgressionRule model.ProgressionRule
+		Set             model.Set
+		ExerciseCatalog model.ExerciseCatalog
+		Transaction     db.Transactor
+	}
+
+	// progressionSession 提案の根拠とするセッションの、最も重い重量で行ったメインセットを表す
+	progressionSession struct {
+		sessionId int64
+		date      string
+		weight    float64
+		sets      []model.SetHistory
+	}
+)
+
+func NewProgression() Progression {
+	return &ProgressionImpl{
+		ProgressionRule: model.NewProgressionRule(),
+		Set:             model.NewSet(),
+		ExerciseCatalog: model.NewExerciseCatalog(),
+		Transaction:     db.NewTransactor("training_db"),
+	}
+}
+
+// defaultProgressionRule 進め方を設定していない種目は2.5kgずつ上げる5回3セットとする
+func defaultProgressionRule() model.ProgressionRuleImpl {
+	return model.ProgressionRuleImpl{
+		Strategy:         model.ProgressionLinear,
+		Increment:        2.5,
+		Unit:             string(units.Kilogram),
+		Sets:             defaultProgressionSets,
+		MinReps:          5,
+		MaxReps:          5,
+		DeloadAfter:      defaultProgressionDeloadAfter,
+		DeloadPercentage: defaultProgressionDeloadPercentage,
+	}
+}
+
+// ListRules ユーザーの種目ごとの進め方を一覧で取得
+func (s *ProgressionImpl) ListRules(userId int64) (response.ProgressionRules, error) {
+	rules, err := s.ProgressionRule.LoadByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+	return response.ProgressionRulesFromModel(rules), nil
+}
+
+// SaveRule 種目の進め方を保存。同じ種目の進め方がある場合は置き換える
+// 重量の増分はフォームで指定された単位で受け取り、kgに変換して保存する
+func (s *ProgressionImpl) SaveRule(userId int64, f form.SaveProgressionRule) (*response.ProgressionRule, error) {
+	exerciseName, catalogId, catalogModality, err := resolveCatalog(s.ExerciseCatalog, f.CatalogID, f.ExerciseName)
+	if err != nil {
+		return nil, err
+	}
+	if catalogModality != "" && !model.CountsLoadVolume(catalogModality) {
+		return nil, fmt.Errorf("progression is not supported for %s exercises: %w", catalogModality, ErrInvalidArgument)
+	}
+
+	rule, err := progressionRuleFromForm(f)
+	if err != nil {
+		return nil, err
+	}
+	key := exerciseKeys([]string{exerciseName}, []int64{catalogId})[0]
+	rule.UserID = userId
+	rule.CatalogID = key.CatalogID
+	rule.ExerciseName = key.ExerciseName
+
+	var saved *model.ProgressionRuleImpl
+	err = s.Transaction(func(tx dbr.SessionRunner) error {
+		saved, err = s.ProgressionRule.ReplaceTx(tx, rule)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 一覧表示用の種目名をあわせて読み込む
+	saved, err = s.ProgressionRule.Load(saved.ID)
+	if err != nil {
+		return nil, err
+	}
+	return response.NewProgressionRule().ProgressionRuleFromModel(saved), nil
+}
+
+// DeleteRule 種目の進め方を削除。削除後の提案は既定の進め方とする
+func (s *ProgressionImpl) DeleteRule(userId int64, id int64) error {
+	rule, err := s.ProgressionRule.Load(id)
+	if err != nil {
+		return err
+	}
+	if rule.ID != id || rule.ID == 0 || rule.UserID != userId {
+		return fmt.Errorf("progression rule not found. id %d: %w", id, ErrNotFound)
+	}
+
+	_, err = s.ProgressionRule.Delete(id)
+	return err
+}
+
+// Suggest 種目のセット履歴から次のセッションで行うメインセットを提案
+// 進め方を設定していない種目は既定の進め方とする
+func (s *ProgressionImpl) Suggest(userId int64, exercise string) (*response.ProgressionSuggestion, error) {
+	key, exerciseName, err := resolveExerciseKey(s.ExerciseCatalog, exercise)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := s.ProgressionRule.LoadByKey(userId, key)
+	if err != nil {
+		return nil, err
+	}
+	if rule.ID == 0 {
+		d := defaultProgressionRule()
+		rule = &d
+	}
+
+	history, err := s.Set.LoadHistory(model.SetHistoryFilter{UserID: userId, Key: key})
+	if err != nil {
+		return nil, err
+	}
+	if n := len(*history); n > 0 && !model.CountsLoadVolume((*history)[n-1].Modality) {
+		return nil, fmt.Errorf("progression is not supported for %s exercises: %w", (*history)[n-1].Modality, ErrInvalidArgument)
+	}
+
+	r := suggestProgression(*rule, progressionSessions(history))
+	r.ExerciseName = exerciseName
+	r.CatalogID = key.CatalogID
+	return r, nil
+}
+
+// progressionRuleFromForm 進め方を検証し、未指定の項目を既定値で埋める
+func progressionRuleFromForm(f form.SaveProgressionRule) (model.ProgressionRuleImpl, error) {
+	unit := enteredUnit(f.Unit)
+	rule := model.ProgressionRuleImpl{
+		Strategy:         f.Strategy,
+		Increment:        units.ToKilograms(f.Increment, unit),
+		Unit:             string(unit),
+		Sets:             f.Sets,
+		MinReps:          f.MinReps,
+		MaxReps:          f.MaxReps,
+		DeloadAfter:      defaultProgressionDeloadAfter,
+		DeloadPercentage: defaultProgressionDeloadPercentage,
+	}
+
+	if err := validateProgressionStrategy(rule.Strategy); err != nil {
+		return model.ProgressionRuleImpl{}, err
+	}
+	if f.Increment <= 0 {
+		return model.ProgressionRuleImpl{}, fmt.Errorf("increment must be positive: %w", ErrInvalidArgument)
+	}
+	if rule.Sets == 0 {
+		rule.Sets = defaultProgressionSets
+	}
+	if rule.Sets < 0 || rule.Sets > maxProgressionSets {
+		return model.ProgressionRuleImpl{}, fmt.Errorf("sets must be between 1 and %d: %w", maxProgressionSets, ErrInvalidArgument)
+	}
+	if rule.MinReps <= 0 {
+		return model.ProgressionRuleImpl{}, fmt.Errorf("min_reps must be positive: %w", ErrInvalidArgument)
+	}
+	if rule.MaxReps == 0 {
+		rule.MaxReps = rule.MinReps
+	}
+	if rule.MaxReps < rule.MinReps {
+		return model.ProgressionRuleImpl{}, fmt.Errorf("max_reps must not be less than min_reps: %w", ErrInvalidArgument)
+	}
+
+	if rule.Strategy == model.ProgressionRPE {
+		targetRPE := float64(defaultProgressionTargetRPE)
+		if f.TargetRPE != nil {
+			targetRPE = *f.TargetRPE
+		}
+		if err := validateRPE(targetRPE); err != nil {
+			return model.ProgressionRuleImpl{}, err
+		}
+		rule.TargetRPE = dbr.NewNullFloat64(targetRPE)
+	} else if f.TargetRPE != nil {
+		return model.ProgressionRuleImpl{}, fmt.Errorf("target_rpe is only for rpe strategy: %w", ErrInvalidArgument)
+	}
+
+	if f.DeloadAfter != nil {
+		if *f.DeloadAfter < 0 {
+			return model.ProgressionRuleImpl{}, fmt.Errorf("deload_after must not be negative: %w", ErrInvalidArgument)
+		}
+		rule.DeloadAfter = *f.DeloadAfter
+	}
+	if f.DeloadPercentage != nil {
+		if *f.DeloadPercentage <= 0 || *f.DeloadPercentage > maxDeloadPercentage {
+			return model.ProgressionRuleImpl{}, fmt.Errorf("deload_percentage must be greater than 0 and at most %d: %w", maxDeloadPercentage, ErrInvalidArgument)
+		}
+		rule.DeloadPercentage = *f.DeloadPercentage
+	}
+	return rule, nil
+}
+
+func validateProgressionStrategy(strategy string) error {
+	for _, s := range model.ProgressionStrategies {
+		if strategy == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown strategy %q: %w", strategy, ErrInvalidArgument)
+}
+
+// progressionSessions 日付順のセット履歴をセッションごとにまとめ、最も重い重量で行ったメインセットを残す
+// ウォームアップと回数の記録がないセットは除く
+func progressionSessions(history *model.SetHistories) []progressionSession {
+	sessions := []progressionSession{}
+	for _, set := range *history {
+		if set.IsWarmup() || set.Reps <= 0 {
+			continue
+		}
+		if len(sessions) == 0 || sessions[len(sessions)-1].sessionId != set.SessionID {
+			sessions = append(sessions, progressionSession{sessionId: set.SessionID, date: set.TrainingDate.Format("2006-01-02"), weight: set.Weight})
+		}
+		session := &sessions[len(sessions)-1]
+		switch {
+		case set.Weight > session.weight+progressionWeightEpsilon:
+			session.weight = set.Weight
+			session.sets = []model.SetHistory{set}
+		case set.Weight >= session.weight-progressionWeightEpsilon:
+			session.sets = append(session.sets, set)
+		}
+	}
+	return sessions
+}
+
+// minReps セッションのメインセットの最小の回数
+func (p *progressionSession) minReps() int64 {
+	var reps int64
+	for i, set := range p.sets {
+		if i == 0 || set.Reps < reps {
+			reps = set.Reps
+		}
+	}
+	return reps
+}
+
+// failed 進め方のセット数・回数をこなせなかったセッションかどうか
+// RPEによる進め方では、RPEが目標より1以上高かった場合も失敗とする
+func (p *progressionSession) failed(rule model.ProgressionRuleImpl) bool {
+	if int64(len(p.sets)) < rule.Sets || p.minReps() < rule.MinReps {
+		return true
+	}
+	if rule.Strategy == model.ProgressionRPE {
+		for _, set := range p.sets {
+			if set.RPE > 0 && set.RPE >= rule.TargetRPE.Float64+1 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// suggestProgression 進め方とセッションごとのメインセットから次のセッションのメインセットを提案
+// 直近から連続でdeload_after回失敗した場合は、進め方に関わらず重量をdeload_percentage%下げて目標の回数からやり直す
+func suggestProgression(rule model.ProgressionRuleImpl, sessions []progressionSession) *response.ProgressionSuggestion {
+	r := response.NewProgressionSuggestion()
+	r.Strategy = rule.Strategy
+	if len(sessions) == 0 {
+		r.Action = response.SuggestionStart
+		r.Sets = suggestedSets(rule, 0, rule.MinReps)
+		return r.ApplyUnit(units.DefaultUnit)
+	}
+
+	last := sessions[len(sessions)-1]
+	r.LastSessionID = last.sessionId
+	r.LastTrainingDate = last.date
+	for i := len(sessions) - 1; i >= 0 && sessions[i].failed(rule); i-- {
+		r.ConsecutiveFailures++
+	}
+
+	weight, reps := last.weight, rule.MinReps
+	switch {
+	case rule.DeloadAfter > 0 && r.ConsecutiveFailures >= rule.DeloadAfter:
+		r.Action = response.SuggestionDeload
+		weight = last.weight * (1 - rule.DeloadPercentage/100)
+	case rule.Strategy == model.ProgressionRPE:
+		r.Action, weight = autoregulatedWeight(rule, last)
+	case r.ConsecutiveFailures > 0:
+		r.Action = response.SuggestionRepeat
+	case rule.Strategy == model.ProgressionDouble && last.minReps() < rule.MaxReps:
+		// 回数の範囲の上限に届くまでは同じ重量で1回ずつ増やす
+		r.Action = response.SuggestionAddReps
+		reps = last.minReps() + 1
+	default:
+		r.Action = response.SuggestionIncrease
+		weight = last.weight + rule.Increment
+	}
+
+	r.Sets = suggestedSets(rule, roundToRulePlate(weight, rule), reps)
+	return r.ApplyUnit(units.DefaultUnit)
+}
+
+// autoregulatedWeight 直近のセッションのRPEから推定した1RMをもとに、目標の回数・RPEになる重量を求める
+// 余力の回数(10-RPE)を回数に加えてEpley式で推定する。RPEの記録がない場合は同じ重量とする
+func autoregulatedWeight(rule model.ProgressionRuleImpl, last progressionSession) (string, float64) {
+	var e1rm float64
+	for _, set := range last.sets {
+		if set.RPE <= 0 {
+			continue
+		}
+		if v := set.Weight * (1 + (float64(set.Reps)+10-set.RPE)/30); v > e1rm {
+			e1rm = v
+		}
+	}
+	if e1rm == 0 {
+		return response.SuggestionRepeat, last.weight
+	}
+
+	weight := e1rm / (1 + (float64(rule.MinReps)+10-rule.TargetRPE.Float64)/30)
+	switch rounded := roundToRulePlate(weight, rule); {
+	case rounded > last.weight+progressionWeightEpsilon:
+		return response.SuggestionIncrease, weight
+	case rounded < last.weight-progressionWeightEpsilon:
+		return response.SuggestionDecrease, weight
+	default:
+		return response.SuggestionRepeat, last.weight
+	}
+}
+
+// roundToRulePlate kgの重量を進め方の単位のプレートの刻みに丸めてkgで返却
+func roundToRulePlate(weight float64, rule model.ProgressionRuleImpl) float64 {
+	unit := enteredUnit(rule.Unit)
+	return units.ToKilograms(units.RoundToPlate(units.Convert(weight, units.Kilogram, unit), unit), unit)
+}
+
+// suggestedSets 同じ重量・回数のメインセットを進め方のセット数だけ並べる
+func suggestedSets(rule model.ProgressionRuleImpl, weight float64, reps int64) []response.SuggestedSet {
+	sets := make([]response.SuggestedSet, 0, rule.Sets)
+	for i := int64(1); i <= rule.Sets; i++ {
+		set := response.SuggestedSet{SetNumber: i, Kilograms: weight, EnteredUnit: rule.Unit, Reps: reps}
+		if rule.TargetRPE.Valid {
+			targetRPE := rule.TargetRPE.Float64
+			set.RPE = &targetRPE
+		}
+		sets = append(sets, set)
+	}
+	return sets
+}
@@ -0,0 +1,472 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model/mock_model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/gocraft/dbr/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// progressionHistory 同じ重量で指定の回数・RPEのセットを行ったセッションのセット履歴を作成
+func progressionHistory(sessionId int64, date time.Time, weight float64, rpe float64, reps ...int64) model.SetHistories {
+	history := model.SetHistories{}
+	for i, r := range reps {
+		history = append(history, model.SetHistory{
+			SetID:        sessionId*10 + int64(i),
+			SessionID:    sessionId,
+			TrainingDate: date,
+			SetNumber:    int64(i + 1),
+			Weight:       weight,
+			Unit:         "kg",
+			Reps:         r,
+			SetType:      model.SetTypeWorking,
+			RPE:          rpe,
+			Modality:     model.ModalityWeighted,
+		})
+	}
+	return history
+}
+
+func TestSuggestProgression(t *testing.T) {
+	t.Parallel()
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	linear := defaultProgressionRule()
+	double := model.ProgressionRuleImpl{Strategy: model.ProgressionDouble, Increment: 2.5, Unit: "kg", Sets: 3, MinReps: 8, MaxReps: 12, DeloadAfter: 3, DeloadPercentage: 10}
+	rpe := model.ProgressionRuleImpl{Strategy: model.ProgressionRPE, Increment: 2.5, Unit: "kg", Sets: 3, MinReps: 5, MaxReps: 5, TargetRPE: dbr.NewNullFloat64(8), DeloadAfter: 3, DeloadPercentage: 10}
+	concat := func(histories ...model.SetHistories) *model.SetHistories {
+		r := model.SetHistories{}
+		for _, h := range histories {
+			r = append(r, h...)
+		}
+		return &r
+	}
+
+	tests := []struct {
+		testCase string
+		rule     model.ProgressionRuleImpl
+		history  *model.SetHistories
+		action   string
+		weight   float64
+		reps     int64
+		failures int64
+	}{
+		{
+			testCase: "正常系(記録がない場合は回数のみ)",
+			rule:     linear,
+			history:  &model.SetHistories{},
+			action:   response.SuggestionStart,
+			reps:     5,
+		},
+		{
+			testCase: "正常系(linearで目標をこなしたら重量を上げる)",
+			rule:     linear,
+			history: concat(
+				model.SetHistories{{SessionID: 1, TrainingDate: monday, Weight: 60, Reps: 5, SetType: model.SetTypeWarmup}},
+				progressionHistory(1, monday, 100, 0, 5, 5, 5),
+			),
+			action: response.SuggestionIncrease,
+			weight: 102.5,
+			reps:   5,
+		},
+		{
+			testCase: "正常系(linearで回数が届かなければ同じ重量)",
+			rule:     linear,
+			history:  concat(progressionHistory(1, monday, 100, 0, 5, 5, 5), progressionHistory(2, monday.AddDate(0, 0, 2), 102.5, 0, 5, 5, 4)),
+			action:   response.SuggestionRepeat,
+			weight:   102.5,
+			reps:     5,
+			failures: 1,
+		},
+		{
+			testCase: "正常系(linearでセット数が足りなければ同じ重量)",
+			rule:     linear,
+			history:  concat(progressionHistory(1, monday, 100, 0, 5, 5)),
+			action:   response.SuggestionRepeat,
+			weight:   100,
+			reps:     5,
+			failures: 1,
+		},
+		{
+			testCase: "正常系(連続で失敗したらディロード)",
+			rule:     linear,
+			history: concat(
+				progressionHistory(1, monday, 100, 0, 5, 5, 4),
+				progressionHistory(2, monday.AddDate(0, 0, 2), 100, 0, 5, 4, 4),
+				progressionHistory(3, monday.AddDate(0, 0, 4), 100, 0, 5, 5, 3),
+			),
+			action:   response.SuggestionDeload,
+			weight:   90,
+			reps:     5,
+			failures: 3,
+		},
+		{
+			testCase: "正常系(doubleで上限に届くまでは回数を増やす)",
+			rule:     double,
+			history:  concat(progressionHistory(1, monday, 40, 0, 10, 10, 9)),
+			action:   response.SuggestionAddReps,
+			weight:   40,
+			reps:     10,
+		},
+		{
+			testCase: "正常系(doubleで上限に届いたら重量を上げて下限に戻す)",
+			rule:     double,
+			history:  concat(progressionHistory(1, monday, 40, 0, 12, 12, 12)),
+			action:   response.SuggestionIncrease,
+			weight:   42.5,
+			reps:     8,
+		},
+		{
+			testCase: "正常系(rpeで目標より軽ければ重量を上げる)",
+			rule:     rpe,
+			history:  concat(progressionHistory(1, monday, 100, 7, 5, 5, 5)),
+			action:   response.SuggestionIncrease,
+			weight:   102.5,
+			reps:     5,
+		},
+		{
+			testCase: "正常系(rpeで目標より重ければ重量を下げる)",
+			rule:     rpe,
+			history:  concat(progressionHistory(1, monday, 100, 9, 5, 5, 5)),
+			action:   response.SuggestionDecrease,
+			weight:   97.5,
+			reps:     5,
+			failures: 1,
+		},
+		{
+			testCase: "正常系(rpeでRPEの記録がなければ同じ重量)",
+			rule:     rpe,
+			history:  concat(progressionHistory(1, monday, 100, 0, 5, 5, 5)),
+			action:   response.SuggestionRepeat,
+			weight:   100,
+			reps:     5,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			r := suggestProgression(tt.rule, progressionSessions(tt.history))
+			assert.Equal(t, tt.action, r.Action)
+			assert.Equal(t, tt.failures, r.ConsecutiveFailures)
+			if assert.Len(t, r.Sets, int(tt.rule.Sets)) {
+				for i, set := range r.Sets {
+					assert.Equal(t, int64(i+1), set.SetNumber)
+					assert.Equal(t, tt.weight, set.Weight)
+					assert.Equal(t, tt.reps, set.Reps)
+				}
+			}
+		})
+	}
+}
+
+func TestSuggestProgressionPound(t *testing.T) {
+	t.Parallel()
+	// 100lbで5回3セットこなしたら5lb上げる
+	rule := model.ProgressionRuleImpl{Strategy: model.ProgressionLinear, Increment: units.ToKilograms(5, units.Pound), Unit: "lb", Sets: 3, MinReps: 5, MaxReps: 5}
+	history := progressionHistory(1, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), units.ToKilograms(100, units.Pound), 0, 5, 5, 5)
+
+	r := suggestProgression(rule, progressionSessions(&history)).ApplyUnit(units.Pound)
+	assert.Equal(t, response.SuggestionIncrease, r.Action)
+	assert.Equal(t, float64(105), r.Sets[0].Weight)
+	assert.Equal(t, "lb", r.Sets[0].Unit)
+}
+
+func TestProgressionSuggest(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		ProgressionRule model.ProgressionRule
+		Set             model.Set
+		ExerciseCatalog model.ExerciseCatalog
+	}
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		testCase  string
+		exercise  string
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.ProgressionSuggestion, err error)
+	}{
+		{
+			testCase: "正常系(設定した進め方で提案)",
+			exercise: "ベンチ",
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("ベンチ").Return(&model.ExerciseCatalogImpl{ID: int64(1), NameJa: "ベンチプレス"}, nil)
+				ProgressionRule := mock_model.NewMockProgressionRule(ctrl)
+				ProgressionRule.EXPECT().LoadByKey(int64(1), model.ExerciseKey{CatalogID: 1}).Return(&model.ProgressionRuleImpl{
+					ID: 1, UserID: 1, CatalogID: 1, Strategy: model.ProgressionLinear, Increment: 5, Unit: "kg", Sets: 5, MinReps: 5, MaxReps: 5, DeloadAfter: 3, DeloadPercentage: 10,
+				}, nil)
+				history := progressionHistory(3, monday, 80, 0, 5, 5, 5, 5, 5)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadHistory(model.SetHistoryFilter{UserID: 1, Key: model.ExerciseKey{CatalogID: 1}}).Return(&history, nil)
+				return fields{
+					ProgressionRule: ProgressionRule,
+					Set:             Set,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.ProgressionSuggestion, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "ベンチプレス", r.ExerciseName)
+				assert.Equal(t, int64(1), r.CatalogID)
+				assert.Equal(t, response.SuggestionIncrease, r.Action)
+				assert.Equal(t, int64(3), r.LastSessionID)
+				assert.Equal(t, "2024-01-01", r.LastTrainingDate)
+				if assert.Len(t, r.Sets, 5) {
+					assert.Equal(t, float64(85), r.Sets[0].Weight)
+				}
+			},
+		},
+		{
+			testCase: "正常系(進め方の設定がない場合は既定の進め方)",
+			exercise: "謎のマシン",
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("謎のマシン").Return(&model.ExerciseCatalogImpl{}, nil)
+				ProgressionRule := mock_model.NewMockProgressionRule(ctrl)
+				ProgressionRule.EXPECT().LoadByKey(int64(1), model.ExerciseKey{ExerciseName: "謎のマシン"}).Return(&model.ProgressionRuleImpl{}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadHistory(model.SetHistoryFilter{UserID: 1, Key: model.ExerciseKey{ExerciseName: "謎のマシン"}}).Return(&model.SetHistories{}, nil)
+				return fields{
+					ProgressionRule: ProgressionRule,
+					Set:             Set,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.ProgressionSuggestion, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, model.ProgressionLinear, r.Strategy)
+				assert.Equal(t, response.SuggestionStart, r.Action)
+				assert.Len(t, r.Sets, 3)
+			},
+		},
+		{
+			testCase: "エラー(時間で記録する種目)",
+			exercise: "プランク",
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("プランク").Return(&model.ExerciseCatalogImpl{ID: int64(33), NameJa: "プランク"}, nil)
+				ProgressionRule := mock_model.NewMockProgressionRule(ctrl)
+				ProgressionRule.EXPECT().LoadByKey(int64(1), model.ExerciseKey{CatalogID: 33}).Return(&model.ProgressionRuleImpl{}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadHistory(model.SetHistoryFilter{UserID: 1, Key: model.ExerciseKey{CatalogID: 33}}).Return(&model.SetHistories{
+					{SetID: 1, SessionID: 1, TrainingDate: monday, DurationSeconds: 60, Modality: model.ModalityTimed},
+				}, nil)
+				return fields{
+					ProgressionRule: ProgressionRule,
+					Set:             Set,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.ProgressionSuggestion, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(存在しないカタログID)",
+			exercise: "999",
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(999)).Return(&model.ExerciseCatalogImpl{}, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.ProgressionSuggestion, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			f := tt.fields(ctrl)
+			s := &ProgressionImpl{
+				ProgressionRule: f.ProgressionRule,
+				Set:             f.Set,
+				ExerciseCatalog: f.ExerciseCatalog,
+			}
+			tt.assertion(s.Suggest(int64(1), tt.exercise))
+		})
+	}
+}
+
+func TestProgressionSaveRule(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		ProgressionRule model.ProgressionRule
+		ExerciseCatalog model.ExerciseCatalog
+	}
+	targetRPE := float64(8.5)
+	deloadAfter := int64(0)
+	tests := []struct {
+		testCase  string
+		form      form.SaveProgressionRule
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.ProgressionRule, err error)
+	}{
+		{
+			testCase: "正常系(未指定の項目は既定値)",
+			form:     form.SaveProgressionRule{CatalogID: 1, Strategy: model.ProgressionRPE, Increment: 5, Unit: "lb", MinReps: 3, TargetRPE: &targetRPE, DeloadAfter: &deloadAfter},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(1)).Return(&model.ExerciseCatalogImpl{ID: 1, NameJa: "ベンチプレス", Modality: model.ModalityWeighted}, nil)
+				rule := model.ProgressionRuleImpl{
+					UserID: 1, CatalogID: 1, Strategy: model.ProgressionRPE, Increment: 2.268, Unit: "lb", Sets: 3, MinReps: 3, MaxReps: 3,
+					TargetRPE: dbr.NewNullFloat64(8.5), DeloadAfter: 0, DeloadPercentage: 10,
+				}
+				saved := rule
+				saved.ID = 4
+				saved.DisplayName = "ベンチプレス"
+				ProgressionRule := mock_model.NewMockProgressionRule(ctrl)
+				ProgressionRule.EXPECT().ReplaceTx(gomock.Any(), rule).Return(&model.ProgressionRuleImpl{ID: 4}, nil)
+				ProgressionRule.EXPECT().Load(int64(4)).Return(&saved, nil)
+				return fields{
+					ProgressionRule: ProgressionRule,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.ProgressionRule, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(4), r.ID)
+				assert.Equal(t, "ベンチプレス", r.ExerciseName)
+				assert.Equal(t, float64(5), r.ApplyUnit(units.Pound).Increment)
+			},
+		},
+		{
+			testCase: "エラー(回数の範囲の上限が下限より小さい)",
+			form:     form.SaveProgressionRule{ExerciseName: "謎のマシン", Strategy: model.ProgressionDouble, Increment: 2.5, MinReps: 10, MaxReps: 8},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("謎のマシン").Return(&model.ExerciseCatalogImpl{}, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.ProgressionRule, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(rpe以外で目標のRPEを指定)",
+			form:     form.SaveProgressionRule{ExerciseName: "謎のマシン", Strategy: model.ProgressionLinear, Increment: 2.5, MinReps: 5, TargetRPE: &targetRPE},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("謎のマシン").Return(&model.ExerciseCatalogImpl{}, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.ProgressionRule, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(距離で記録する種目)",
+			form:     form.SaveProgressionRule{CatalogID: 38, Strategy: model.ProgressionLinear, Increment: 2.5, MinReps: 5},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(38)).Return(&model.ExerciseCatalogImpl{ID: 38, NameJa: "ランニング", Modality: model.ModalityDistance}, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.ProgressionRule, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(保存に失敗)",
+			form:     form.SaveProgressionRule{ExerciseName: "謎のマシン", Strategy: model.ProgressionLinear, Increment: 2.5, MinReps: 5},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("謎のマシン").Return(&model.ExerciseCatalogImpl{}, nil)
+				ProgressionRule := mock_model.NewMockProgressionRule(ctrl)
+				ProgressionRule.EXPECT().ReplaceTx(gomock.Any(), gomock.Any()).Return(nil, errors.New("couldn't create progression_rules"))
+				return fields{
+					ProgressionRule: ProgressionRule,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.ProgressionRule, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			f := tt.fields(ctrl)
+			s := &ProgressionImpl{
+				ProgressionRule: f.ProgressionRule,
+				ExerciseCatalog: f.ExerciseCatalog,
+				Transaction:     noTransaction,
+			}
+			tt.assertion(s.SaveRule(int64(1), tt.form))
+		})
+	}
+}
+
+func TestProgressionDeleteRule(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		testCase  string
+		id        int64
+		fields    func(ctrl *gomock.Controller) model.ProgressionRule
+		assertion func(err error)
+	}{
+		{
+			testCase: "正常系",
+			id:       int64(4),
+			fields: func(ctrl *gomock.Controller) model.ProgressionRule {
+				ProgressionRule := mock_model.NewMockProgressionRule(ctrl)
+				ProgressionRule.EXPECT().Load(int64(4)).Return(&model.ProgressionRuleImpl{ID: 4, UserID: 1}, nil)
+				ProgressionRule.EXPECT().Delete(int64(4)).Return(true, nil)
+				return ProgressionRule
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "エラー(他人の進め方)",
+			id:       int64(5),
+			fields: func(ctrl *gomock.Controller) model.ProgressionRule {
+				ProgressionRule := mock_model.NewMockProgressionRule(ctrl)
+				ProgressionRule.EXPECT().Load(int64(5)).Return(&model.ProgressionRuleImpl{ID: 5, UserID: 2}, nil)
+				return ProgressionRule
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			s := &ProgressionImpl{
+				ProgressionRule: tt.fields(ctrl),
+			}
+			tt.assertion(s.DeleteRule(int64(1), tt.id))
+		})
+	}
+}
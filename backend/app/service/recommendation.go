@@ -2,78 +2,288 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"os"
+	"strings"
+	"time"
 
-	openai "github.com/sashabaranov/go-openai"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/llm"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
 )
 
+const (
+	// maxMenuRepairs 不正なメニューを返した場合に修正を求める最大の回数
+	maxMenuRepairs = 2
+	// メニューの種目数・セット数・回数・休憩時間の上限
+	maxMenuExercises   = 15
+	maxMenuSets        = 10
+	maxMenuReps        = 100
+	maxMenuRestSeconds = 600
+)
+
+// menuSystemPrompt メニューをJSONのみで返すよう、スキーマとあわせて指示する
+const menuSystemPrompt = `あなたはプロのパーソナルトレーナーです。
+トレーニングメニューは次のスキーマに従うJSONオブジェクトのみで返してください。説明文やコードブロックは付けないでください。
+{
+  "title": "メニューの名前",
+  "estimated_minutes": メニュー全体の所要時間(分、整数),
+  "exercises": [
+    {
+      "name": "種目名",
+      "sets": セット数(整数),
+      "reps": 1セットの回数(整数),
+      "rest_seconds": セット間の休憩(秒、整数),
+      "estimated_minutes": 休憩を含めた種目の所要時間(分、整数),
+      "notes": "フォームや重量の選び方などの補足"
+    }
+  ],
+  "notes": "メニュー全体の補足"
+}`
+
 type (
 	// Recommendation トレーニングメニュー提案のサービスインターフェース
 	Recommendation interface {
-		ProposeTrainingMenu(goal string, parts []string, experience string, time int) (string, error)
+		ProposeTrainingMenu(ctx context.Context, userId int64, goal string, parts []string, experience string, time int) (*response.TrainingMenu, error)
+		ProposeTrainingMenuStream(ctx context.Context, userId int64, goal string, parts []string, experience string, time int, onDelta func(delta response.MenuDelta) error) (*response.TrainingMenu, error)
 	}
 
 	// RecommendationImpl トレーニングメニュー提案のサービス実装
 	RecommendationImpl struct {
-		openAIClient *openai.Client
+		Recommendation model.Recommendation
+		Set            model.Set
+		Provider       llm.Provider
+		// 記録を集計する基準日を返却する。テストで差し替えるため
+		Now func() time.Time
 	}
 )
 
-// コンストラクタ: 環境変数等からAPIキーを読み込んでクライアントを初期化
-func NewRecommendation() Recommendation {
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	client := openai.NewClient(apiKey)
-	return &RecommendationImpl{
-		openAIClient: client,
+// コンストラクタ: 環境変数の設定からメニューを生成するプロバイダを初期化
+// 設定が不正な場合はエラーを返却し、起動時に設定を見直せるようにする
+func NewRecommendation() (Recommendation, error) {
+	config, err := llm.ConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load llm config: %w", err)
+	}
+	provider, err := llm.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create llm provider: %w", err)
 	}
+	return &RecommendationImpl{
+		Recommendation: model.NewRecommendation(),
+		Set:            model.NewSet(),
+		Provider:       provider,
+		Now:            time.Now,
+	}, nil
 }
 
-// トレーニングメニュー提案ロジック
-func (s *RecommendationImpl) ProposeTrainingMenu(goal string, parts []string, experience string, time int) (string, error) {
-	prompt := buildPrompt(goal, parts, experience, time)
-
-	resp, err := s.openAIClient.CreateChatCompletion(
-		context.Background(),
-		openai.ChatCompletionRequest{
-			Model: "gpt-3.5-turbo",
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: "あなたはプロのパーソナルトレーナーです。",
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-			},
-			MaxTokens:   800, // 必要に応じて調整
-			Temperature: 0.7, // ランダム性
+// ProposeTrainingMenu 条件に合うトレーニングメニューをJSONで提案させ、検証して返却
+// スキーマに合わない、または条件を満たさないメニューが返った場合は理由を伝えて修正させ、修正できなければErrUpstreamを返却
+// 直近のトレーニング記録の要約を条件に加え、ユーザーの扱える重量や部位の疲労に合わせて提案させる
+// 検証したメニューは保存し、IDを指定してセッションを作成できるようにする
+func (s *RecommendationImpl) ProposeTrainingMenu(ctx context.Context, userId int64, goal string, parts []string, experience string, time int) (*response.TrainingMenu, error) {
+	return s.propose(ctx, userId, goal, parts, experience, time, func(ctx context.Context, messages []llm.Message, attempt int) (string, error) {
+		return s.complete(ctx, messages)
+	})
+}
+
+// ProposeTrainingMenuStream ProposeTrainingMenuと同じ条件で提案させ、生成中の出力の差分を順にonDeltaへ渡す
+// ctxがキャンセルされた場合、またはonDeltaがエラーを返した場合は生成を中断し、そのエラーを返却
+func (s *RecommendationImpl) ProposeTrainingMenuStream(ctx context.Context, userId int64, goal string, parts []string, experience string, time int, onDelta func(delta response.MenuDelta) error) (*response.TrainingMenu, error) {
+	return s.propose(ctx, userId, goal, parts, experience, time, func(ctx context.Context, messages []llm.Message, attempt int) (string, error) {
+		return s.stream(ctx, messages, attempt, onDelta)
+	})
+}
+
+// propose 記録の要約を加えた条件でgenerateにメニューを生成させ、検証できるまで修正させる
+func (s *RecommendationImpl) propose(ctx context.Context, userId int64, goal string, parts []string, experience string, time int, generate func(ctx context.Context, messages []llm.Message, attempt int) (string, error)) (*response.TrainingMenu, error) {
+	history, err := s.history(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []llm.Message{
+		{
+			Role:    llm.RoleSystem,
+			Content: menuSystemPrompt,
 		},
-	)
+		{
+			Role:    llm.RoleUser,
+			Content: buildPrompt(goal, parts, experience, time, history),
+		},
+	}
+
+	var invalid error
+	for attempt := 0; attempt <= maxMenuRepairs; attempt++ {
+		content, err := generate(ctx, messages, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		menu, err := parseTrainingMenu(content, time)
+		if err == nil {
+			return s.save(userId, menu)
+		}
+		invalid = err
+
+		// 不正な出力と理由を会話に残し、同じ条件で修正させる
+		messages = append(messages,
+			llm.Message{
+				Role:    llm.RoleAssistant,
+				Content: content,
+			},
+			llm.Message{
+				Role:    llm.RoleUser,
+				Content: fmt.Sprintf("出力が不正です(%v)。指定のスキーマに従って修正したJSONオブジェクトのみを返してください。", err),
+			},
+		)
+	}
+	return nil, fmt.Errorf("invalid training menu after %d attempts: %v: %w", maxMenuRepairs+1, invalid, ErrUpstream)
+}
+
+// history 直近の実施済みのセッションの記録を上限の文字数に収まるよう要約して返却。記録がない場合は空文字
+func (s *RecommendationImpl) history(userId int64) (string, error) {
+	today := dateIn(s.Now(), time.UTC)
+	history, err := s.Set.LoadHistory(model.SetHistoryFilter{
+		UserID: userId,
+		From:   today.AddDate(0, 0, -7*historyWeeks),
+		To:     today,
+		Status: model.SessionStatusCompleted,
+	})
+	if err != nil {
+		return "", err
+	}
+	return renderSummary(summarizeHistory(history, today), historySummaryBudget), nil
+}
+
+// save 検証したメニューを保存し、IDを設定して返却
+func (s *RecommendationImpl) save(userId int64, menu *response.TrainingMenu) (*response.TrainingMenu, error) {
+	b, err := json.Marshal(menu)
+	if err != nil {
+		return nil, err
+	}
+	recommendation, err := s.Recommendation.Create(userId, string(b))
 	if err != nil {
-		return "", fmt.Errorf("failed to call OpenAI API: %w", err)
+		return nil, err
 	}
+	menu.ID = recommendation.ID
+	return menu, nil
+}
 
-	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no response from OpenAI")
+// complete モデルにJSONオブジェクトで応答させ、その本文を返却
+func (s *RecommendationImpl) complete(ctx context.Context, messages []llm.Message) (string, error) {
+	content, err := s.Provider.Complete(ctx, llm.Request{Messages: messages, JSON: true})
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("failed to generate training menu: %v: %w", err, ErrUpstream)
 	}
+	return content, nil
+}
 
-	// モデルからの提案結果(文章)を取得
-	result := resp.Choices[0].Message.Content
-	return result, nil
+// stream モデルにJSONオブジェクトで応答させながら差分をonDeltaに渡し、その本文を返却
+// onDeltaのエラーはクライアントへの送信の失敗のため、ErrUpstreamとせずにそのまま返却
+func (s *RecommendationImpl) stream(ctx context.Context, messages []llm.Message, attempt int, onDelta func(delta response.MenuDelta) error) (string, error) {
+	var sendErr error
+	content, err := s.Provider.Stream(ctx, llm.Request{Messages: messages, JSON: true}, func(delta string) error {
+		sendErr = onDelta(response.MenuDelta{Attempt: attempt, Content: delta})
+		return sendErr
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if sendErr != nil {
+			return "", sendErr
+		}
+		return "", fmt.Errorf("failed to generate training menu: %v: %w", err, ErrUpstream)
+	}
+	return content, nil
+}
+
+// parseTrainingMenu モデルの出力をメニューとして読み取り、検証する
+// コードブロックで囲まれている場合はその中身を読み取る
+func parseTrainingMenu(content string, availableTime int) (*response.TrainingMenu, error) {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "```") {
+		content = strings.TrimPrefix(content, "```json")
+		content = strings.TrimPrefix(content, "```")
+		content = strings.TrimSuffix(strings.TrimSpace(content), "```")
+	}
+
+	menu := response.NewTrainingMenu()
+	if err := json.Unmarshal([]byte(content), menu); err != nil {
+		return nil, fmt.Errorf("not a valid JSON object: %v", err)
+	}
+	// IDは保存時に振るため、モデルの出力の値は使わない
+	menu.ID = 0
+	if err := validateTrainingMenu(menu, availableTime); err != nil {
+		return nil, err
+	}
+	return menu, nil
+}
+
+// validateTrainingMenu 種目・セット数・回数・休憩・所要時間がそろい、確保できる時間に収まるか確認
+func validateTrainingMenu(menu *response.TrainingMenu, availableTime int) error {
+	if err := validateMenuExercises(menu.Exercises); err != nil {
+		return err
+	}
+	for i, e := range menu.Exercises {
+		if e.EstimatedMinutes < 1 {
+			return fmt.Errorf("exercises[%d].estimated_minutes must be positive", i)
+		}
+	}
+	if menu.EstimatedMinutes < 1 {
+		return fmt.Errorf("estimated_minutes must be positive")
+	}
+	if availableTime > 0 && menu.EstimatedMinutes > availableTime {
+		return fmt.Errorf("estimated_minutes %d exceeds the available %d minutes", menu.EstimatedMinutes, availableTime)
+	}
+	return nil
+}
+
+// validateMenuExercises 種目ごとに名前・セット数・回数・休憩が範囲内か確認
+func validateMenuExercises(exercises []response.MenuExercise) error {
+	if len(exercises) == 0 || len(exercises) > maxMenuExercises {
+		return fmt.Errorf("exercises must have 1 to %d items", maxMenuExercises)
+	}
+	for i, e := range exercises {
+		switch {
+		case strings.TrimSpace(e.Name) == "":
+			return fmt.Errorf("exercises[%d].name is required", i)
+		case e.Sets < 1 || e.Sets > maxMenuSets:
+			return fmt.Errorf("exercises[%d].sets must be between 1 and %d", i, maxMenuSets)
+		case e.Reps < 1 || e.Reps > maxMenuReps:
+			return fmt.Errorf("exercises[%d].reps must be between 1 and %d", i, maxMenuReps)
+		case e.RestSeconds < 0 || e.RestSeconds > maxMenuRestSeconds:
+			return fmt.Errorf("exercises[%d].rest_seconds must be between 0 and %d", i, maxMenuRestSeconds)
+		}
+	}
+	return nil
 }
 
 // プロンプトを組み立てる関数
-func buildPrompt(goal string, parts []string, experience string, time int) string {
-	return fmt.Sprintf(
+// 記録の要約がある場合は、扱える重量や最近鍛えた部位を踏まえるよう指示する
+func buildPrompt(goal string, parts []string, experience string, time int, history string) string {
+	prompt := fmt.Sprintf(
 		`
 		トレーニング目的: %s
 		対象部位: %v
 		トレーニング経験: %s
 		確保できる時間: %d分
 
-		上記の条件に合わせて、適切な筋トレメニューを提案してください。具体的な種目、回数、セット数、インターバルなども含めて提示をお願いします。`,
+		上記の条件に合わせて、適切な筋トレメニューを提案してください。種目ごとにセット数、回数、インターバル、所要時間を含め、全体の所要時間は確保できる時間に収めてください。`,
 		goal, parts, experience, time,
 	)
+	if history == "" {
+		return prompt
+	}
+	return prompt + fmt.Sprintf(
+		`
+
+		以下は直近%d週間のトレーニング記録の要約です。種目ごとの補足には推定1RMをもとにした重量の目安を含め、最近鍛えた部位の回復や週ごとのセット数を考慮してください。
+%s`,
+		historyWeeks, history,
+	)
 }
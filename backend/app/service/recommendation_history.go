@@ -0,0 +1,243 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/metrics"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+)
+
+const (
+	// historyWeeks 提案に使うトレーニング記録の週数
+	historyWeeks = 4
+	// historySummaryBudget 記録の要約の上限の文字数。長い記録でもトークン数の上限に収めるため
+	historySummaryBudget = 1500
+)
+
+// summarySection 記録の要約の見出しと行を表す。行は重要なものから並べる
+type summarySection struct {
+	title string
+	lines []string
+}
+
+// summarizeHistory 日付順のセット履歴から推定1RM・部位ごとの最後に鍛えた日・週ごとのセット数・直近のセッションの要約を作成
+// 要約は上限を超えた場合に後ろから省くため、重量の提案に欠かせない推定1RMを先頭に置く
+// ウォームアップと記録のないセットは要約に含めない
+func summarizeHistory(history *model.SetHistories, today time.Time) []summarySection {
+	sets := model.SetHistories{}
+	for _, set := range *history {
+		if set.IsWarmup() || (set.Reps <= 0 && set.DurationSeconds <= 0 && set.DistanceMeters <= 0) {
+			continue
+		}
+		sets = append(sets, set)
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+
+	return []summarySection{
+		{title: "種目ごとの推定1RM(kg、直近に行った順)", lines: estimatedMaxLines(&sets)},
+		{title: "部位ごとの最後に鍛えてからの日数", lines: muscleRecencyLines(&sets, today)},
+		{title: "週ごとの部位別セット数(新しい週から)", lines: weeklySetLines(&sets)},
+		{title: "直近のセッション(新しい順)", lines: recentSessionLines(&sets)},
+	}
+}
+
+// estimatedMaxLines 重量を扱う種目の推定1RMの最大値を直近に行った種目から並べる
+func estimatedMaxLines(sets *model.SetHistories) []string {
+	type exerciseMax struct {
+		name string
+		e1rm float64
+		last time.Time
+	}
+	maxes := map[model.ExerciseKey]*exerciseMax{}
+	for _, set := range *sets {
+		if !model.EstimatesOneRepMax(set.Modality) {
+			continue
+		}
+		e1rm := metrics.EstimateOneRepMax(metrics.DefaultFormula, set.Weight, set.Reps)
+		if e1rm <= 0 {
+			continue
+		}
+		key := historyKey(&set)
+		m, ok := maxes[key]
+		if !ok {
+			m = &exerciseMax{name: set.ExerciseName}
+			maxes[key] = m
+		}
+		if e1rm > m.e1rm {
+			m.e1rm = e1rm
+		}
+		m.last = set.TrainingDate
+	}
+
+	sorted := make([]*exerciseMax, 0, len(maxes))
+	for _, m := range maxes {
+		sorted = append(sorted, m)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].last.Equal(sorted[j].last) {
+			return sorted[i].last.After(sorted[j].last)
+		}
+		return sorted[i].name < sorted[j].name
+	})
+
+	lines := make([]string, 0, len(sorted))
+	for _, m := range sorted {
+		lines = append(lines, fmt.Sprintf("%s: %s", m.name, formatNumber(m.e1rm)))
+	}
+	return lines
+}
+
+// muscleRecencyLines 主動筋として鍛えた部位ごとに最後に鍛えてからの日数を、カタログの部位の順に並べる
+func muscleRecencyLines(sets *model.SetHistories, today time.Time) []string {
+	last := map[string]time.Time{}
+	for _, set := range *sets {
+		muscle, _ := setMuscles(&set)
+		last[muscle] = set.TrainingDate
+	}
+
+	lines := []string{}
+	for _, muscle := range append(append([]string{}, model.MuscleGroups...), MuscleOther) {
+		date, ok := last[muscle]
+		if !ok {
+			continue
+		}
+		days := int(dateIn(today, time.UTC).Sub(dateIn(date, time.UTC)).Hours() / 24)
+		lines = append(lines, fmt.Sprintf("%s: %d日前", muscle, days))
+	}
+	return lines
+}
+
+// weeklySetLines 週ごとに主動筋のセット数を新しい週から並べる。補助筋としてのみ関与した部位は除く
+func weeklySetLines(sets *model.SetHistories) []string {
+	weeks := weeklyMuscleVolumes(sets, time.UTC)
+	lines := make([]string, 0, len(weeks))
+	for i := len(weeks) - 1; i >= 0; i-- {
+		muscles := make([]string, 0, len(weeks[i].Muscles))
+		for _, m := range weeks[i].Muscles {
+			if m.Sets == 0 {
+				continue
+			}
+			muscles = append(muscles, fmt.Sprintf("%s %d", m.Muscle, m.Sets))
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", weeks[i].Week, strings.Join(muscles, ", ")))
+	}
+	return lines
+}
+
+// recentSessionLines セッションごとに種目・セット数・最も良いセットを新しいセッションから並べる
+func recentSessionLines(sets *model.SetHistories) []string {
+	type exerciseSummary struct {
+		name  string
+		sets  int
+		best  string
+		score float64
+	}
+	lines := []string{}
+	for i := 0; i < len(*sets); {
+		first := (*sets)[i]
+		var exercises []*exerciseSummary
+		byExercise := map[int64]*exerciseSummary{}
+		for ; i < len(*sets) && (*sets)[i].SessionID == first.SessionID; i++ {
+			set := (*sets)[i]
+			e, ok := byExercise[set.ExerciseID]
+			if !ok {
+				e = &exerciseSummary{name: set.ExerciseName}
+				byExercise[set.ExerciseID] = e
+				exercises = append(exercises, e)
+			}
+			e.sets++
+			if score := setScore(&set); e.best == "" || score > e.score {
+				e.best = setLabel(&set)
+				e.score = score
+			}
+		}
+
+		parts := make([]string, 0, len(exercises))
+		for _, e := range exercises {
+			parts = append(parts, fmt.Sprintf("%s %dセット(最高 %s)", e.name, e.sets, e.best))
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", first.TrainingDate.Format("2006-01-02"), strings.Join(parts, ", ")))
+	}
+
+	// 履歴は日付順のため、新しいセッションを先頭にする
+	for l, r := 0, len(lines)-1; l < r; l, r = l+1, r-1 {
+		lines[l], lines[r] = lines[r], lines[l]
+	}
+	return lines
+}
+
+// renderSummary 見出しと行を上限の文字数に収まるだけ並べる
+// 収まらない行はその見出しの残りの行とあわせて省き、次の見出しの行を詰める
+func renderSummary(sections []summarySection, budget int) string {
+	var b strings.Builder
+	used := 0
+	for _, section := range sections {
+		title := section.title + "\n"
+		written := false
+		for _, line := range section.lines {
+			line = "- " + line + "\n"
+			size := utf8.RuneCountInString(line)
+			if !written {
+				size += utf8.RuneCountInString(title)
+			}
+			if used+size > budget {
+				break
+			}
+			if !written {
+				b.WriteString(title)
+				written = true
+			}
+			b.WriteString(line)
+			used += size
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// historyKey 推定1RMを集計する種目の単位を返却
+func historyKey(set *model.SetHistory) model.ExerciseKey {
+	if set.CatalogID != 0 {
+		return model.ExerciseKey{CatalogID: set.CatalogID}
+	}
+	return model.ExerciseKey{ExerciseName: set.ExerciseName}
+}
+
+// setScore 同じ種目のセットを比べる値を返却
+// 重量のあるセットは推定1RM、ないセットとアシストのセットは回数・時間・距離のうち記録のあるものとする
+func setScore(set *model.SetHistory) float64 {
+	if model.CountsLoadVolume(set.Modality) {
+		if e1rm := metrics.EstimateOneRepMax(metrics.DefaultFormula, set.Weight, set.Reps); e1rm > 0 {
+			return e1rm
+		}
+	}
+	return float64(set.Reps) + float64(set.DurationSeconds) + set.DistanceMeters
+}
+
+// setLabel セットの内容を記録方法に合わせて短く表す
+func setLabel(set *model.SetHistory) string {
+	switch model.NormalizeModality(set.Modality) {
+	case model.ModalityTimed:
+		return fmt.Sprintf("%d秒", set.DurationSeconds)
+	case model.ModalityDistance:
+		return fmt.Sprintf("%sm", formatNumber(set.DistanceMeters))
+	case model.ModalityAssisted:
+		return fmt.Sprintf("アシスト%skg×%d", formatNumber(set.Weight), set.Reps)
+	case model.ModalityBodyweight:
+		if set.Weight <= 0 {
+			return fmt.Sprintf("%d回", set.Reps)
+		}
+	}
+	return fmt.Sprintf("%skg×%d", formatNumber(set.Weight), set.Reps)
+}
+
+// formatNumber 小数点以下の不要な0を除いて表す
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/llm"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model/mock_model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// recommendationNow 記録を集計する基準日を固定する
+func recommendationNow() time.Time {
+	return time.Date(2026, 10, 17, 21, 0, 0, 0, time.UTC)
+}
+
+func TestRecommendationProposeTrainingMenu(t *testing.T) {
+	t.Parallel()
+	valid := `{"title":"胸の日","estimated_minutes":40,"exercises":[{"name":"ベンチプレス","sets":3,"reps":8,"rest_seconds":120,"estimated_minutes":20,"notes":"8回で限界の重量"}],"notes":"最後にストレッチ"}`
+	menu := &response.TrainingMenu{
+		ID:               int64(7),
+		Title:            "胸の日",
+		EstimatedMinutes: 40,
+		Exercises: []response.MenuExercise{
+			{Name: "ベンチプレス", Sets: 3, Reps: 8, RestSeconds: 120, EstimatedMinutes: 20, Notes: "8回で限界の重量"},
+		},
+		Notes: "最後にストレッチ",
+	}
+	tests := []struct {
+		testCase string
+		client   *llm.Fake
+		// 直近の記録。nilの場合は記録なし
+		history *model.SetHistories
+		// 検証したメニューを保存するかどうか
+		saved     bool
+		assertion func(client *llm.Fake, r *response.TrainingMenu, err error)
+	}{
+		{
+			testCase: "正常系",
+			saved:    true,
+			client:   llm.NewFake(valid),
+			assertion: func(client *llm.Fake, r *response.TrainingMenu, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, menu, r)
+				if assert.Len(t, client.Requests(), 1) {
+					assert.True(t, client.Requests()[0].JSON)
+					assert.NotContains(t, client.Requests()[0].Messages[1].Content, "トレーニング記録")
+				}
+			},
+		},
+		{
+			testCase: "正常系(直近の記録の要約をプロンプトに含める)",
+			saved:    true,
+			client:   llm.NewFake(valid),
+			history: &model.SetHistories{
+				{SetID: 1, ExerciseID: 1, SessionID: 1, TrainingDate: time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), SetNumber: 1, Weight: 140, Reps: 3, SetType: model.SetTypeWorking, Modality: model.ModalityWeighted, CatalogID: 8, ExerciseName: "スクワット", PrimaryMuscle: "quads"},
+			},
+			assertion: func(client *llm.Fake, r *response.TrainingMenu, err error) {
+				assert.NoError(t, err)
+				if assert.Len(t, client.Requests(), 1) {
+					prompt := client.Requests()[0].Messages[1].Content
+					assert.Contains(t, prompt, "直近4週間のトレーニング記録")
+					assert.Contains(t, prompt, "- スクワット: 154")
+					assert.Contains(t, prompt, "- quads: 3日前")
+					assert.Contains(t, prompt, "- 2026-10-14: スクワット 1セット(最高 140kg×3)")
+				}
+			},
+		},
+		{
+			testCase: "正常系(コードブロックで囲まれた出力)",
+			saved:    true,
+			client:   llm.NewFake("```json\n" + valid + "\n```"),
+			assertion: func(client *llm.Fake, r *response.TrainingMenu, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, menu, r)
+			},
+		},
+		{
+			testCase: "正常系(スキーマに合わない出力を理由とあわせて修正させる)",
+			saved:    true,
+			client:   llm.NewFake(`{"title":"胸の日","exercises":[{"name":"ベンチプレス","sets":3,"reps":"8-12"}]}`, valid),
+			assertion: func(client *llm.Fake, r *response.TrainingMenu, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, menu, r)
+				if assert.Len(t, client.Requests(), 2) {
+					messages := client.Requests()[1].Messages
+					if assert.Len(t, messages, 4) {
+						assert.Equal(t, llm.RoleAssistant, messages[2].Role)
+						assert.Contains(t, messages[2].Content, `"8-12"`)
+						assert.Contains(t, messages[3].Content, "not a valid JSON object")
+					}
+				}
+			},
+		},
+		{
+			testCase: "エラー(修正させても確保できる時間を超える)",
+			client: llm.NewFake(
+				`{"title":"胸の日","estimated_minutes":90,"exercises":[{"name":"ベンチプレス","sets":3,"reps":8,"rest_seconds":120,"estimated_minutes":20}]}`,
+				`{"title":"胸の日","estimated_minutes":80,"exercises":[{"name":"ベンチプレス","sets":3,"reps":8,"rest_seconds":120,"estimated_minutes":20}]}`,
+				`{"title":"胸の日","estimated_minutes":70,"exercises":[{"name":"ベンチプレス","sets":3,"reps":8,"rest_seconds":120,"estimated_minutes":20}]}`,
+			),
+			assertion: func(client *llm.Fake, r *response.TrainingMenu, err error) {
+				assert.True(t, errors.Is(err, ErrUpstream))
+				assert.Contains(t, err.Error(), "exceeds the available 60 minutes")
+				assert.Len(t, client.Requests(), maxMenuRepairs+1)
+			},
+		},
+		{
+			testCase: "エラー(APIの呼び出しに失敗)",
+			client:   &llm.Fake{Err: errors.New("rate limited")},
+			assertion: func(client *llm.Fake, r *response.TrainingMenu, err error) {
+				assert.True(t, errors.Is(err, ErrUpstream))
+				assert.Len(t, client.Requests(), 1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			Recommendation := mock_model.NewMockRecommendation(ctrl)
+			if tt.saved {
+				Recommendation.EXPECT().Create(int64(1), gomock.Any()).Return(&model.RecommendationImpl{ID: int64(7)}, nil)
+			}
+			history := tt.history
+			if history == nil {
+				history = &model.SetHistories{}
+			}
+			Set := mock_model.NewMockSet(ctrl)
+			Set.EXPECT().LoadHistory(model.SetHistoryFilter{
+				UserID: int64(1),
+				From:   time.Date(2026, 9, 19, 0, 0, 0, 0, time.UTC),
+				To:     time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC),
+				Status: model.SessionStatusCompleted,
+			}).Return(history, nil)
+			s := &RecommendationImpl{Recommendation: Recommendation, Set: Set, Provider: tt.client, Now: recommendationNow}
+			r, err := s.ProposeTrainingMenu(context.Background(), int64(1), "筋肥大", []string{"胸"}, "中級者", 60)
+			tt.assertion(tt.client, r, err)
+		})
+	}
+}
+
+// TestRecommendationProposeTrainingMenuWithStubServer OpenAI互換のローカルのサーバーに設定どおりの依頼を送ることを確認
+func TestRecommendationProposeTrainingMenuWithStubServer(t *testing.T) {
+	t.Parallel()
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"index": 0, "message": map[string]string{"role": llm.RoleAssistant, "content": llm.FakeMenu}},
+			},
+		}))
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	Recommendation := mock_model.NewMockRecommendation(ctrl)
+	Recommendation.EXPECT().Create(int64(1), gomock.Any()).Return(&model.RecommendationImpl{ID: int64(3)}, nil)
+	Set := mock_model.NewMockSet(ctrl)
+	Set.EXPECT().LoadHistory(gomock.Any()).Return(&model.SetHistories{}, nil)
+	s := &RecommendationImpl{
+		Recommendation: Recommendation,
+		Set:            Set,
+		Now:            recommendationNow,
+		Provider:       llm.NewOpenAI(llm.Config{Model: "local-model", BaseURL: server.URL, Timeout: time.Second, MaxTokens: 500, Temperature: 0.2}),
+	}
+
+	r, err := s.ProposeTrainingMenu(context.Background(), int64(1), "筋力向上", []string{"脚"}, "初心者", 30)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(3), r.ID)
+		assert.Len(t, r.Exercises, 2)
+	}
+	assert.Equal(t, "local-model", received["model"])
+	assert.Equal(t, float64(500), received["max_tokens"])
+	assert.InDelta(t, 0.2, received["temperature"], 0.001)
+	assert.Equal(t, map[string]interface{}{"type": "json_object"}, received["response_format"])
+}
+
+func TestValidateTrainingMenu(t *testing.T) {
+	t.Parallel()
+
+	exercise := response.MenuExercise{Name: "スクワット", Sets: 3, Reps: 5, RestSeconds: 180, EstimatedMinutes: 15}
+	assert.NoError(t, validateTrainingMenu(&response.TrainingMenu{EstimatedMinutes: 30, Exercises: []response.MenuExercise{exercise}}, 30))
+	assert.EqualError(t, validateTrainingMenu(&response.TrainingMenu{EstimatedMinutes: 30}, 30), "exercises must have 1 to 15 items")
+
+	exercise.Sets = 0
+	assert.EqualError(t, validateTrainingMenu(&response.TrainingMenu{EstimatedMinutes: 30, Exercises: []response.MenuExercise{exercise}}, 30), "exercises[0].sets must be between 1 and 10")
+}
+
+func TestSummarizeHistory(t *testing.T) {
+	t.Parallel()
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	history := &model.SetHistories{
+		{SetID: 1, ExerciseID: 1, SessionID: 1, TrainingDate: day(5), SetNumber: 1, Weight: 60, Reps: 10, SetType: model.SetTypeWarmup, Modality: model.ModalityWeighted, CatalogID: 1, ExerciseName: "ベンチプレス", PrimaryMuscle: "chest", SecondaryMuscles: "triceps,shoulders"},
+		{SetID: 2, ExerciseID: 1, SessionID: 1, TrainingDate: day(5), SetNumber: 2, Weight: 90, Reps: 5, SetType: model.SetTypeWorking, Modality: model.ModalityWeighted, CatalogID: 1, ExerciseName: "ベンチプレス", PrimaryMuscle: "chest", SecondaryMuscles: "triceps,shoulders"},
+		{SetID: 3, ExerciseID: 2, SessionID: 1, TrainingDate: day(5), SetNumber: 1, Reps: 12, SetType: model.SetTypeWorking, Modality: model.ModalityBodyweight, ExerciseName: "懸垂"},
+		{SetID: 4, ExerciseID: 2, SessionID: 1, TrainingDate: day(5), SetNumber: 2, Reps: 9, SetType: model.SetTypeWorking, Modality: model.ModalityBodyweight, ExerciseName: "懸垂"},
+		{SetID: 5, ExerciseID: 3, SessionID: 2, TrainingDate: day(14), SetNumber: 1, Weight: 140, Reps: 3, SetType: model.SetTypeWorking, Modality: model.ModalityWeighted, CatalogID: 8, ExerciseName: "スクワット", PrimaryMuscle: "quads"},
+		{SetID: 6, ExerciseID: 3, SessionID: 2, TrainingDate: day(14), SetNumber: 2, Weight: 120, Reps: 8, SetType: model.SetTypeWorking, Modality: model.ModalityWeighted, CatalogID: 8, ExerciseName: "スクワット", PrimaryMuscle: "quads"},
+	}
+
+	sections := summarizeHistory(history, day(17))
+	assert.Equal(t, []summarySection{
+		{title: "種目ごとの推定1RM(kg、直近に行った順)", lines: []string{"スクワット: 154", "ベンチプレス: 105"}},
+		{title: "部位ごとの最後に鍛えてからの日数", lines: []string{"chest: 12日前", "back: 12日前", "quads: 3日前"}},
+		{title: "週ごとの部位別セット数(新しい週から)", lines: []string{"2026-W42: quads 2", "2026-W41: chest 1, back 2"}},
+		{title: "直近のセッション(新しい順)", lines: []string{
+			"2026-10-14: スクワット 2セット(最高 140kg×3)",
+			"2026-10-05: ベンチプレス 1セット(最高 90kg×5), 懸垂 2セット(最高 12回)",
+		}},
+	}, sections)
+
+	assert.Nil(t, summarizeHistory(&model.SetHistories{(*history)[0]}, day(17)))
+}
+
+func TestRenderSummary(t *testing.T) {
+	t.Parallel()
+	sections := []summarySection{
+		{title: "推定1RM", lines: []string{"スクワット: 154", "ベンチプレス(ナロー): 105"}},
+		{title: "日数", lines: []string{"quads: 3日前"}},
+		{title: "セッション", lines: []string{"2026-10-14: スクワット 2セット(最高 120kg×8)"}},
+	}
+
+	assert.Equal(t, "推定1RM\n- スクワット: 154\n- ベンチプレス(ナロー): 105\n日数\n- quads: 3日前\nセッション\n- 2026-10-14: スクワット 2セット(最高 120kg×8)", renderSummary(sections, 1000))
+	// 収まらない行は省き、後ろの見出しの短い行を詰める
+	assert.Equal(t, "推定1RM\n- スクワット: 154\n日数\n- quads: 3日前", renderSummary(sections, 37))
+	assert.Equal(t, "", renderSummary(sections, 5))
+}
+
+func TestRecommendationProposeTrainingMenuStream(t *testing.T) {
+	t.Parallel()
+	valid := `{"title":"脚の日","estimated_minutes":30,"exercises":[{"name":"スクワット","sets":5,"reps":5,"rest_seconds":180,"estimated_minutes":25}]}`
+	tests := []struct {
+		testCase string
+		client   *llm.Fake
+		// onDeltaが返すエラー
+		sendErr error
+		// 検証したメニューを保存するかどうか
+		saved     bool
+		assertion func(deltas []response.MenuDelta, r *response.TrainingMenu, err error)
+	}{
+		{
+			testCase: "正常系",
+			client:   llm.NewFake(valid),
+			saved:    true,
+			assertion: func(deltas []response.MenuDelta, r *response.TrainingMenu, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(7), r.ID)
+				content := ""
+				for _, delta := range deltas {
+					assert.Equal(t, 0, delta.Attempt)
+					content += delta.Content
+				}
+				assert.Equal(t, valid, content)
+			},
+		},
+		{
+			testCase: "正常系(修正させた出力は修正の回数を増やして送る)",
+			client:   llm.NewFake(`{"title":"脚の日"}`, valid),
+			saved:    true,
+			assertion: func(deltas []response.MenuDelta, r *response.TrainingMenu, err error) {
+				assert.NoError(t, err)
+				if assert.NotEmpty(t, deltas) {
+					assert.Equal(t, response.MenuDelta{Attempt: 0, Content: `{"title":"脚の日"}`}, deltas[0])
+					assert.Equal(t, 1, deltas[len(deltas)-1].Attempt)
+				}
+			},
+		},
+		{
+			testCase: "エラー(クライアントへの送信に失敗した場合は生成を中断)",
+			client:   llm.NewFake(valid),
+			sendErr:  errors.New("broken pipe"),
+			assertion: func(deltas []response.MenuDelta, r *response.TrainingMenu, err error) {
+				assert.EqualError(t, err, "broken pipe")
+				assert.False(t, errors.Is(err, ErrUpstream))
+				assert.Len(t, deltas, 1)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(生成に失敗)",
+			client:   &llm.Fake{Err: errors.New("rate limited")},
+			assertion: func(deltas []response.MenuDelta, r *response.TrainingMenu, err error) {
+				assert.True(t, errors.Is(err, ErrUpstream))
+				assert.Empty(t, deltas)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			Recommendation := mock_model.NewMockRecommendation(ctrl)
+			if tt.saved {
+				Recommendation.EXPECT().Create(int64(1), gomock.Any()).Return(&model.RecommendationImpl{ID: int64(7)}, nil)
+			}
+			Set := mock_model.NewMockSet(ctrl)
+			Set.EXPECT().LoadHistory(gomock.Any()).Return(&model.SetHistories{}, nil)
+			s := &RecommendationImpl{Recommendation: Recommendation, Set: Set, Provider: tt.client, Now: recommendationNow}
+
+			var deltas []response.MenuDelta
+			r, err := s.ProposeTrainingMenuStream(context.Background(), int64(1), "筋力向上", []string{"脚"}, "中級者", 60, func(delta response.MenuDelta) error {
+				deltas = append(deltas, delta)
+				return tt.sendErr
+			})
+			tt.assertion(deltas, r, err)
+		})
+	}
+}
+
+func TestRecommendationProposeTrainingMenuStreamCanceled(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	Set := mock_model.NewMockSet(ctrl)
+	Set.EXPECT().LoadHistory(gomock.Any()).Return(&model.SetHistories{}, nil)
+	s := &RecommendationImpl{Recommendation: mock_model.NewMockRecommendation(ctrl), Set: Set, Provider: llm.NewFake(), Now: recommendationNow}
+
+	// クライアントが切断した場合はコンテキストのエラーを返し、残りの差分は送らない
+	ctx, cancel := context.WithCancel(context.Background())
+	sent := 0
+	r, err := s.ProposeTrainingMenuStream(ctx, int64(1), "筋力向上", []string{"脚"}, "中級者", 60, func(delta response.MenuDelta) error {
+		sent++
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, r)
+	assert.Equal(t, 1, sent)
+}
+
+// 環境変数を書き換えるため並列にしない
+func TestNewRecommendation(t *testing.T) {
+	tests := []struct {
+		testCase  string
+		env       map[string]string
+		assertion func(s Recommendation, err error)
+	}{
+		{
+			testCase: "正常系",
+			env:      map[string]string{"LLM_PROVIDER": llm.ProviderFake},
+			assertion: func(s Recommendation, err error) {
+				assert.NoError(t, err)
+				assert.NotNil(t, s)
+			},
+		},
+		{
+			testCase: "エラー(不明なプロバイダ)",
+			env:      map[string]string{"LLM_PROVIDER": "unknown"},
+			assertion: func(s Recommendation, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, s)
+			},
+		},
+		{
+			testCase: "エラー(不正なタイムアウト)",
+			env:      map[string]string{"LLM_PROVIDER": llm.ProviderFake, "LLM_TIMEOUT": "soon"},
+			assertion: func(s Recommendation, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, s)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testCase, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			tt.assertion(NewRecommendation())
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/metrics"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/gocraft/dbr/v2"
+)
+
+type (
+	// Record 自己ベストのサービスを表す
+	Record interface {
+		List(userId int64) (response.PersonalRecords, error)
+	}
+
+	// RecordImpl 自己ベストのサービスを表す
+	RecordImpl struct {
+		PersonalRecord model.PersonalRecord
+	}
+)
+
+func NewRecord() Record {
+	return &RecordImpl{
+		PersonalRecord: model.NewPersonalRecord(),
+	}
+}
+
+// List ユーザーの現在の自己ベストを一覧で取得
+func (s *RecordImpl) List(userId int64) (response.PersonalRecords, error) {
+	records, err := s.PersonalRecord.LoadByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+	return response.PersonalRecordsFromModel(records), nil
+}
+
+// recordKey 種類と重量の区分で自己ベストを区別する
+type recordKey struct {
+	recordType  string
+	weightClass float64
+}
+
+// computePersonalRecords 種目のセット履歴から自己ベストを集計
+// 履歴は日付順に渡される前提で、同じ値の場合は先に達成した記録を残す。ウォームアップは集計しない
+// アシスト・時間・距離の種目は重量x回数で比較できないため集計せず、自重種目は加重ごとの回数とボリュームのみ集計する
+func computePersonalRecords(history *model.SetHistories) *model.PersonalRecords {
+	best := map[recordKey]*model.PersonalRecordImpl{}
+	var order []recordKey
+	update := func(key recordKey, record model.PersonalRecordImpl) {
+		current, ok := best[key]
+		if !ok {
+			order = append(order, key)
+		}
+		if !ok || record.Value > current.Value {
+			best[key] = &record
+		}
+	}
+
+	volumes := map[int64]*model.PersonalRecordImpl{}
+	var sessions []int64
+	for _, set := range *history {
+		if set.Reps <= 0 || set.IsWarmup() || !model.CountsLoadVolume(set.Modality) {
+			continue
+		}
+		record := model.PersonalRecordImpl{
+			Weight:     set.Weight,
+			Unit:       set.Unit,
+			Reps:       set.Reps,
+			SessionID:  set.SessionID,
+			SetID:      set.SetID,
+			AchievedOn: set.TrainingDate,
+		}
+
+		if set.Weight > 0 && model.EstimatesOneRepMax(set.Modality) {
+			maxWeight := record
+			maxWeight.RecordType = model.RecordTypeMaxWeight
+			maxWeight.Value = set.Weight
+			update(recordKey{recordType: maxWeight.RecordType}, maxWeight)
+
+			e1rm := record
+			e1rm.RecordType = model.RecordTypeEstimatedOneRepMax
+			// 記録は計算式を揃えて比較するため既定の計算式で集計する
+			e1rm.Value = metrics.EstimateOneRepMax(metrics.DefaultFormula, set.Weight, set.Reps)
+			update(recordKey{recordType: e1rm.RecordType}, e1rm)
+		}
+
+		repsAtWeight := record
+		repsAtWeight.RecordType = model.RecordTypeRepsAtWeight
+		repsAtWeight.WeightClass = set.Weight
+		repsAtWeight.Value = float64(set.Reps)
+		update(recordKey{recordType: repsAtWeight.RecordType, weightClass: set.Weight}, repsAtWeight)
+
+		if _, ok := volumes[set.SessionID]; !ok {
+			volumes[set.SessionID] = &model.PersonalRecordImpl{
+				RecordType: model.RecordTypeSessionVolume,
+				SessionID:  set.SessionID,
+				AchievedOn: set.TrainingDate,
+			}
+			sessions = append(sessions, set.SessionID)
+		}
+		volumes[set.SessionID].Value += metrics.Volume(set.Weight, set.Reps)
+	}
+
+	for _, sessionId := range sessions {
+		if volumes[sessionId].Value > 0 {
+			update(recordKey{recordType: model.RecordTypeSessionVolume}, *volumes[sessionId])
+		}
+	}
+
+	records := model.NewPersonalRecords()
+	for _, key := range order {
+		if record, ok := best[key]; ok {
+			*records = append(*records, *record)
+		}
+	}
+	return records
+}
+
+// refreshPersonalRecordsTx トランザクション内で種目の自己ベストをセット履歴から集計し直す
+// セットの書き込みと同じトランザクションで呼び出し、書き込み途中の履歴から集計した記録を書き込みとあわせて確定させる
+// ワークアウトの記録と取り込みの両方から使うため、セットと自己ベストのモデルを受け取る
+// 指定のセット(セッション)で更新された自己ベストを返却
+func refreshPersonalRecordsTx(set model.Set, personalRecord model.PersonalRecord, tx dbr.SessionRunner, userId int64, key model.ExerciseKey, sessionId int64, setId int64) (*model.PersonalRecords, error) {
+	before, err := personalRecord.LoadByKeyTx(tx, userId, key)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := set.LoadHistoryTx(tx, model.SetHistoryFilter{UserID: userId, Key: key})
+	if err != nil {
+		return nil, err
+	}
+	after := computePersonalRecords(history)
+
+	if err := personalRecord.ReplaceTx(tx, userId, key, after); err != nil {
+		return nil, err
+	}
+
+	return improvedRecords(before, after, sessionId, setId), nil
+}
+
+// countsForRecords セットが自己ベストの集計対象かどうか。computePersonalRecordsと同じ条件で判定する
+// 集計対象でないセットの書き込みでは自己ベストが変わらないため、集計し直さずに済ませる
+func countsForRecords(modality string, set *model.SetImpl) bool {
+	return set.Reps > 0 && set.SetType != model.SetTypeWarmup && model.CountsLoadVolume(modality)
+}
+
+// improvedRecords 集計し直した自己ベストのうち、指定のセットで更新されたものを返却
+// セッションボリュームはセット単位ではないため、指定のセットを含むセッションで更新されたものを対象とする
+func improvedRecords(before *model.PersonalRecords, after *model.PersonalRecords, sessionId int64, setId int64) *model.PersonalRecords {
+	previous := map[recordKey]float64{}
+	for _, record := range *before {
+		previous[recordKey{recordType: record.RecordType, weightClass: record.WeightClass}] = record.Value
+	}
+
+	improved := model.NewPersonalRecords()
+	for _, record := range *after {
+		if record.RecordType == model.RecordTypeSessionVolume {
+			if record.SessionID != sessionId {
+				continue
+			}
+		} else if record.SetID != setId {
+			continue
+		}
+		value, ok := previous[recordKey{recordType: record.RecordType, weightClass: record.WeightClass}]
+		if ok && record.Value <= value {
+			continue
+		}
+		*improved = append(*improved, record)
+	}
+	return improved
+}
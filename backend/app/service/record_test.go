@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model/mock_model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordList(t *testing.T) {
+	t.Parallel()
+	date := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		testCase  string
+		fields    func(ctrl *gomock.Controller) model.PersonalRecord
+		assertion func(r response.PersonalRecords, err error)
+	}{
+		{
+			testCase: "正常系",
+			fields: func(ctrl *gomock.Controller) model.PersonalRecord {
+				PersonalRecord := mock_model.NewMockPersonalRecord(ctrl)
+				PersonalRecord.EXPECT().LoadByUserID(int64(1)).Return(&model.PersonalRecords{
+					{UserID: int64(1), CatalogID: int64(1), RecordType: model.RecordTypeMaxWeight, Value: float64(100), Weight: float64(100), Reps: int64(1), SessionID: int64(3), SetID: int64(9), AchievedOn: date, DisplayName: "ベンチプレス"},
+				}, nil)
+				return PersonalRecord
+			},
+			assertion: func(r response.PersonalRecords, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, response.PersonalRecords{
+					{ExerciseName: "ベンチプレス", CatalogID: int64(1), RecordType: model.RecordTypeMaxWeight, Value: float64(100), Weight: float64(100), Unit: "kg", Reps: int64(1), SessionID: int64(3), SetID: int64(9), AchievedOn: "2020-01-01", Kilograms: float64(100)},
+				}, r)
+			},
+		},
+		{
+			testCase: "エラー",
+			fields: func(ctrl *gomock.Controller) model.PersonalRecord {
+				PersonalRecord := mock_model.NewMockPersonalRecord(ctrl)
+				PersonalRecord.EXPECT().LoadByUserID(int64(1)).Return(nil, errors.New("couldn't load personal_records"))
+				return PersonalRecord
+			},
+			assertion: func(r response.PersonalRecords, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			s := &RecordImpl{
+				PersonalRecord: tt.fields(ctrl),
+			}
+			tt.assertion(s.List(int64(1)))
+		})
+	}
+}
+
+func TestComputePersonalRecords(t *testing.T) {
+	t.Parallel()
+	day1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 7)
+	history := &model.SetHistories{
+		{SetID: int64(1), SessionID: int64(1), TrainingDate: day1, Weight: float64(100), Reps: int64(5)},
+		{SetID: int64(2), SessionID: int64(1), TrainingDate: day1, Weight: float64(100), Reps: int64(5)},
+		{SetID: int64(3), SessionID: int64(2), TrainingDate: day2, Weight: float64(100), Reps: int64(3)},
+		{SetID: int64(4), SessionID: int64(2), TrainingDate: day2, Weight: float64(110), Reps: int64(1)},
+		{SetID: int64(5), SessionID: int64(2), TrainingDate: day2, Weight: float64(60), Reps: int64(0)},
+		// ウォームアップは自己ベストに含めない
+		{SetID: int64(6), SessionID: int64(2), TrainingDate: day2, Weight: float64(120), Reps: int64(10), SetType: model.SetTypeWarmup},
+		// アシスト重量は自己ベストに含めない
+		{SetID: int64(7), SessionID: int64(2), TrainingDate: day2, Weight: float64(130), Reps: int64(10), Modality: model.ModalityAssisted},
+	}
+
+	got := computePersonalRecords(history)
+
+	byKey := map[recordKey]model.PersonalRecordImpl{}
+	for _, record := range *got {
+		byKey[recordKey{recordType: record.RecordType, weightClass: record.WeightClass}] = record
+	}
+	assert.Len(t, *got, 5)
+	// 最大重量は2回目のセッションの110kg
+	assert.Equal(t, int64(4), byKey[recordKey{recordType: model.RecordTypeMaxWeight}].SetID)
+	// 推定1RMは100kgx5回(116.67)が110kgx1回を上回り、同値の場合は先に達成したセット
+	assert.Equal(t, int64(1), byKey[recordKey{recordType: model.RecordTypeEstimatedOneRepMax}].SetID)
+	assert.Equal(t, float64(116.67), byKey[recordKey{recordType: model.RecordTypeEstimatedOneRepMax}].Value)
+	assert.Equal(t, float64(5), byKey[recordKey{recordType: model.RecordTypeRepsAtWeight, weightClass: 100}].Value)
+	assert.Equal(t, float64(1), byKey[recordKey{recordType: model.RecordTypeRepsAtWeight, weightClass: 110}].Value)
+	// セッションボリュームは1回目のセッションの1000kg
+	assert.Equal(t, int64(1), byKey[recordKey{recordType: model.RecordTypeSessionVolume}].SessionID)
+	assert.Equal(t, float64(1000), byKey[recordKey{recordType: model.RecordTypeSessionVolume}].Value)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/metrics"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+)
+
+// 推移を集計する単位
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+)
+
+type (
+	// Stats 統計のサービスを表す
+	Stats interface {
+		ExerciseProgress(userId int64, exercise string, from time.Time, to time.Time, bucket string, formula metrics.Formula) (*response.ExerciseProgress, error)
+	}
+
+	// StatsImpl 統計のサービスを表す
+	StatsImpl struct {
+		Set             model.Set
+		ExerciseCatalog model.ExerciseCatalog
+	}
+)
+
+func NewStats() Stats {
+	return &StatsImpl{
+		Set:             model.NewSet(),
+		ExerciseCatalog: model.NewExerciseCatalog(),
+	}
+}
+
+// ExerciseProgress 種目の推移を集計単位ごとに集計
+// exerciseにはカタログID、または名前(別名を含む)を指定する
+func (s *StatsImpl) ExerciseProgress(userId int64, exercise string, from time.Time, to time.Time, bucket string, formula metrics.Formula) (*response.ExerciseProgress, error) {
+	if bucket == "" {
+		bucket = BucketDay
+	}
+	if bucket != BucketDay && bucket != BucketWeek && bucket != BucketMonth {
+		return nil, fmt.Errorf("unknown bucket %q: %w", bucket, ErrInvalidArgument)
+	}
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return nil, fmt.Errorf("from must not be after to: %w", ErrInvalidArgument)
+	}
+
+	key, exerciseName, err := s.resolveExerciseKey(exercise)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.Set.LoadHistory(model.SetHistoryFilter{UserID: userId, Key: key, From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	progress := response.NewExerciseProgress()
+	progress.ExerciseName = exerciseName
+	progress.CatalogID = key.CatalogID
+	progress.Bucket = bucket
+	progress.Formula = string(formula)
+	progress.Points = progressPoints(history, bucket, formula)
+	return progress, nil
+}
+
+// resolveExerciseKey カタログIDまたは名前から集計する種目を解決
+func (s *StatsImpl) resolveExerciseKey(exercise string) (model.ExerciseKey, string, error) {
+	return resolveExerciseKey(s.ExerciseCatalog, exercise)
+}
+
+// resolveExerciseKey カタログIDまたは名前から集計する種目を解決
+// カタログにない名前は自由入力の種目として扱う
+func resolveExerciseKey(exerciseCatalog model.ExerciseCatalog, exercise string) (model.ExerciseKey, string, error) {
+	exercise = strings.TrimSpace(exercise)
+	if exercise == "" {
+		return model.ExerciseKey{}, "", fmt.Errorf("exercise is required: %w", ErrInvalidArgument)
+	}
+
+	if id, err := strconv.ParseInt(exercise, 10, 64); err == nil {
+		catalog, err := exerciseCatalog.Load(id)
+		if err != nil {
+			return model.ExerciseKey{}, "", err
+		}
+		if catalog.ID == 0 {
+			return model.ExerciseKey{}, "", fmt.Errorf("catalog %d: %w", id, ErrNotFound)
+		}
+		return model.ExerciseKey{CatalogID: catalog.ID}, catalog.NameJa, nil
+	}
+
+	catalog, err := exerciseCatalog.Resolve(exercise)
+	if err != nil {
+		return model.ExerciseKey{}, "", err
+	}
+	if catalog.ID != 0 {
+		return model.ExerciseKey{CatalogID: catalog.ID}, catalog.NameJa, nil
+	}
+	return model.ExerciseKey{ExerciseName: exercise}, exercise, nil
+}
+
+// progressPoints 日付順のセット履歴を集計単位ごとにまとめる。ウォームアップは集計しない
+// トップセット・推定1RM・ボリュームは記録方法が対象とするセットのみ集計し、時間・距離は合計する
+func progressPoints(history *model.SetHistories, bucket string, formula metrics.Formula) []response.ProgressPoint {
+	points := []response.ProgressPoint{}
+	var sessions map[int64]bool
+	for _, set := range *history {
+		if set.IsWarmup() {
+			continue
+		}
+		date := bucketStart(set.TrainingDate, bucket).Format("2006-01-02")
+		if len(points) == 0 || points[len(points)-1].Date != date {
+			points = append(points, response.ProgressPoint{Date: date})
+			sessions = map[int64]bool{}
+		}
+		point := &points[len(points)-1]
+
+		if !sessions[set.SessionID] {
+			sessions[set.SessionID] = true
+			point.Sessions++
+		}
+		if model.EstimatesOneRepMax(set.Modality) {
+			if set.Weight > point.TopSet.Kilograms || (set.Weight == point.TopSet.Kilograms && set.Reps > point.TopSet.Reps) {
+				point.TopSet = response.NewTopSet(set.Weight, set.Unit, set.Reps)
+			}
+			if e1rm := metrics.EstimateOneRepMax(formula, set.Weight, set.Reps); e1rm > point.EstimatedOneRepMax {
+				point.EstimatedOneRepMax = e1rm
+			}
+		}
+		if model.CountsLoadVolume(set.Modality) {
+			point.Volume += metrics.Volume(set.Weight, set.Reps)
+		}
+		point.Reps += set.Reps
+		point.DurationSeconds += set.DurationSeconds
+		point.DistanceMeters += set.DistanceMeters
+	}
+	return points
+}
+
+// bucketStart 日付が属する集計単位の開始日を返却。週はISO週に合わせて月曜始まりとする
+func bucketStart(date time.Time, bucket string) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	switch bucket {
+	case BucketWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case BucketMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	default:
+		return day
+	}
+}
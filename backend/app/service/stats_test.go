@@ -0,0 +1,139 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/metrics"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model/mock_model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsExerciseProgress(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		Set             model.Set
+		ExerciseCatalog model.ExerciseCatalog
+	}
+	type args struct {
+		exercise string
+		from     time.Time
+		to       time.Time
+		bucket   string
+	}
+	// 2024-01-01は月曜日
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := &model.SetHistories{
+		{SetID: int64(1), SessionID: int64(1), TrainingDate: monday, SetNumber: int64(1), Weight: float64(100), Reps: int64(5)},
+		{SetID: int64(2), SessionID: int64(1), TrainingDate: monday, SetNumber: int64(2), Weight: float64(100), Reps: int64(3)},
+		{SetID: int64(3), SessionID: int64(2), TrainingDate: monday.AddDate(0, 0, 3), SetNumber: int64(1), Weight: float64(105), Reps: int64(2)},
+		{SetID: int64(4), SessionID: int64(3), TrainingDate: monday.AddDate(0, 0, 7), SetNumber: int64(1), Weight: float64(102.5), Reps: int64(5)},
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.ExerciseProgress, err error)
+	}{
+		{
+			testCase: "正常系(別名から週ごとに集計)",
+			args: args{
+				exercise: "ベンチ",
+				from:     monday,
+				to:       monday.AddDate(0, 1, 0),
+				bucket:   BucketWeek,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("ベンチ").Return(&model.ExerciseCatalogImpl{ID: int64(1), NameJa: "ベンチプレス"}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadHistory(model.SetHistoryFilter{UserID: int64(1), Key: model.ExerciseKey{CatalogID: int64(1)}, From: monday, To: monday.AddDate(0, 1, 0)}).Return(history, nil)
+				return fields{
+					Set:             Set,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.ExerciseProgress, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "ベンチプレス", r.ExerciseName)
+				assert.Equal(t, int64(1), r.CatalogID)
+				assert.Equal(t, []response.ProgressPoint{
+					{Date: "2024-01-01", Sessions: int64(2), TopSet: response.TopSet{Weight: float64(105), Reps: int64(2), Kilograms: float64(105)}, EstimatedOneRepMax: float64(116.67), Volume: float64(1010), Reps: int64(10)},
+					{Date: "2024-01-08", Sessions: int64(1), TopSet: response.TopSet{Weight: float64(102.5), Reps: int64(5), Kilograms: float64(102.5)}, EstimatedOneRepMax: float64(119.58), Volume: float64(512.5), Reps: int64(5)},
+				}, r.Points)
+			},
+		},
+		{
+			testCase: "正常系(カタログにない名前を月ごとに集計)",
+			args: args{
+				exercise: "自作マシン",
+				bucket:   BucketMonth,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("自作マシン").Return(&model.ExerciseCatalogImpl{}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadHistory(model.SetHistoryFilter{UserID: int64(1), Key: model.ExerciseKey{ExerciseName: "自作マシン"}}).Return(history, nil)
+				return fields{
+					Set:             Set,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.ExerciseProgress, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "自作マシン", r.ExerciseName)
+				assert.Len(t, r.Points, 1)
+				assert.Equal(t, "2024-01-01", r.Points[0].Date)
+				assert.Equal(t, int64(3), r.Points[0].Sessions)
+			},
+		},
+		{
+			testCase: "エラー(存在しないカタログID)",
+			args: args{
+				exercise: "99",
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(99)).Return(&model.ExerciseCatalogImpl{}, nil)
+				return fields{
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.ExerciseProgress, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(期間が逆転)",
+			args: args{
+				exercise: "ベンチ",
+				from:     monday.AddDate(0, 0, 1),
+				to:       monday,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r *response.ExerciseProgress, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			s := &StatsImpl{
+				Set:             fields.Set,
+				ExerciseCatalog: fields.ExerciseCatalog,
+			}
+			tt.assertion(s.ExerciseProgress(int64(1), tt.args.exercise, tt.args.from, tt.args.to, tt.args.bucket, metrics.Epley))
+		})
+	}
+}
@@ -0,0 +1,483 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
+)
+
+// テンプレートからセッションを作成する際の種目の作成元
+const (
+	// TemplateSourceTemplate テンプレートの種目を作成する。目標セットは前回の記録があればそれを使う
+	TemplateSourceTemplate = "template"
+	// TemplateSourceLastSession テンプレートから作成した直近のセッションの種目・セットを繰り返す
+	TemplateSourceLastSession = "last_session"
+)
+
+// テンプレート名の最大文字数
+const maxTemplateNameLength = 100
+
+type (
+	// Template ワークアウトのテンプレートのサービスを表す
+	Template interface {
+		List(userId int64) (response.Templates, error)
+		Get(userId int64, id int64) (*response.Template, error)
+		Create(userId int64, f form.SaveTemplate) (*response.Template, error)
+		CreateFromWorkout(userId int64, sessionId int64, name string) (*response.Template, error)
+		Update(userId int64, id int64, f form.SaveTemplate) (*response.Template, error)
+		Delete(userId int64, id int64) error
+		Instantiate(userId int64, id int64, date time.Time, source string) (*response.GetWorkoutSession, error)
+	}
+
+	// TemplateImpl ワークアウトのテンプレートのサービスを表す
+	TemplateImpl struct {
+		WorkoutTemplate  model.WorkoutTemplate
+		TemplateExercise model.TemplateExercise
+		TemplateSet      model.TemplateSet
+		WorkoutSession   model.WorkoutSession
+		Exercise         model.Exercise
+		Set              model.Set
+		TargetSet        model.TargetSet
+		ExerciseCatalog  model.ExerciseCatalog
+		Transaction      db.Transactor
+	}
+
+	// templateEntry 保存・作成する種目と目標セットを表す
+	templateEntry struct {
+		exerciseName string
+		catalogId    int64
+		modality     string
+		targets      []model.SetTarget
+	}
+)
+
+func NewTemplate() Template {
+	return &TemplateImpl{
+		WorkoutTemplate:  model.NewWorkoutTemplate(),
+		TemplateExercise: model.NewTemplateExercise(),
+		TemplateSet:      model.NewTemplateSet(),
+		WorkoutSession:   model.NewWorkoutSession(),
+		Exercise:         model.NewExercise(),
+		Set:              model.NewSet(),
+		TargetSet:        model.NewTargetSet(),
+		ExerciseCatalog:  model.NewExerciseCatalog(),
+		Transaction:      db.NewTransactor("training_db"),
+	}
+}
+
+// List ユーザーのテンプレートを種目・目標セットとあわせて名前順に取得
+func (s *TemplateImpl) List(userId int64) (response.Templates, error) {
+	templates, err := s.WorkoutTemplate.LoadByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// テンプレート数に関わらずクエリ数が一定になるようまとめて読み込む
+	exercises, err := s.TemplateExercise.LoadByTemplateIDs(templates.IDs())
+	if err != nil {
+		return nil, err
+	}
+	sets, err := s.TemplateSet.LoadByTemplateExerciseIDs(exercises.IDs())
+	if err != nil {
+		return nil, err
+	}
+	exercisesByTemplate := exercises.GroupByTemplateID()
+	setsByExercise := sets.GroupByTemplateExerciseID()
+
+	r := response.Templates{}
+	for _, template := range *templates {
+		r = append(r, *response.NewTemplate().TemplateFromModel(&template, exercisesByTemplate[template.ID], setsByExercise))
+	}
+	return r, nil
+}
+
+// Get テンプレートを種目・目標セットとあわせて取得
+func (s *TemplateImpl) Get(userId int64, id int64) (*response.Template, error) {
+	template, err := s.loadTemplate(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.templateResponse(template)
+}
+
+// Create 種目・目標セットを指定してテンプレートを作成
+func (s *TemplateImpl) Create(userId int64, f form.SaveTemplate) (*response.Template, error) {
+	name, err := s.validateTemplateName(userId, 0, f.Name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.entriesFromForm(f.Exercises)
+	if err != nil {
+		return nil, err
+	}
+	return s.createTemplate(userId, name, entries)
+}
+
+// CreateFromWorkout 実施したセッションの種目・セットを目標セットとしてテンプレートに保存
+func (s *TemplateImpl) CreateFromWorkout(userId int64, sessionId int64, name string) (*response.Template, error) {
+	name, err := s.validateTemplateName(userId, 0, name)
+	if err != nil {
+		return nil, err
+	}
+
+	workoutSession, err := s.WorkoutSession.Load(sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if workoutSession.ID != sessionId || workoutSession.ID == 0 || workoutSession.UserID != userId {
+		return nil, fmt.Errorf("workout session not found. id %d: %w", sessionId, ErrNotFound)
+	}
+
+	entries, err := s.sessionEntries(workoutSession.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.createTemplate(userId, name, entries)
+}
+
+// Update テンプレートの名前を変更し、種目・目標セットを置き換える
+func (s *TemplateImpl) Update(userId int64, id int64, f form.SaveTemplate) (*response.Template, error) {
+	template, err := s.loadTemplate(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	name, err := s.validateTemplateName(userId, template.ID, f.Name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.entriesFromForm(f.Exercises)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Transaction(func(tx dbr.SessionRunner) error {
+		if _, err := s.TemplateSet.DeleteByTemplateIDTx(tx, template.ID); err != nil {
+			return err
+		}
+		if _, err := s.TemplateExercise.DeleteByTemplateIDTx(tx, template.ID); err != nil {
+			return err
+		}
+		if _, err := s.WorkoutTemplate.UpdateTx(tx, template.ID, map[string]interface{}{"name": name}); err != nil {
+			return err
+		}
+		return s.saveEntriesTx(tx, template.ID, entries)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	template.Name = name
+	return s.templateResponse(template)
+}
+
+// Delete テンプレートを種目・目標セットごと削除。テンプレートから作成したセッションは残す
+func (s *TemplateImpl) Delete(userId int64, id int64) error {
+	template, err := s.loadTemplate(userId, id)
+	if err != nil {
+		return err
+	}
+
+	return s.Transaction(func(tx dbr.SessionRunner) error {
+		if _, err := s.TemplateSet.DeleteByTemplateIDTx(tx, template.ID); err != nil {
+			return err
+		}
+		if _, err := s.TemplateExercise.DeleteByTemplateIDTx(tx, template.ID); err != nil {
+			return err
+		}
+		if _, err := s.WorkoutTemplate.DeleteTx(tx, template.ID); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// Instantiate テンプレートから下書きのセッションを作成し、種目ごとに目標セットを入れておく
+// 目標セットはテンプレートから作成した直近の実施済みセッションの重量・回数を目安とし、
+// その種目を実施していない場合はテンプレートの目標セットとする
+func (s *TemplateImpl) Instantiate(userId int64, id int64, date time.Time, source string) (*response.GetWorkoutSession, error) {
+	if source == "" {
+		source = TemplateSourceTemplate
+	}
+	if source != TemplateSourceTemplate && source != TemplateSourceLastSession {
+		return nil, fmt.Errorf("unknown source %q: %w", source, ErrInvalidArgument)
+	}
+
+	template, err := s.loadTemplate(userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	lastSessions, err := s.WorkoutSession.LoadByFilter(model.WorkoutSessionFilter{
+		UserID:     userId,
+		TemplateID: template.ID,
+		Status:     model.SessionStatusCompleted,
+		Desc:       true,
+		Limit:      1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var lastEntries []templateEntry
+	if len(*lastSessions) != 0 {
+		if lastEntries, err = s.sessionEntries((*lastSessions)[0].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	var entries []templateEntry
+	switch source {
+	case TemplateSourceLastSession:
+		if len(*lastSessions) == 0 {
+			return nil, fmt.Errorf("no completed session from template %d: %w", template.ID, ErrNotFound)
+		}
+		entries = lastEntries
+	default:
+		if entries, err = s.templateEntries(template.ID); err != nil {
+			return nil, err
+		}
+		entries = withPreviousTargets(entries, lastEntries)
+	}
+
+	return createPlannedWorkout(s.Transaction, s.Exercise, s.TargetSet, entries, func(tx dbr.SessionRunner) (*model.WorkoutSessionImpl, error) {
+		return s.WorkoutSession.CreateFromTemplateTx(tx, date, userId, template.ID)
+	})
+}
+
+// withPreviousTargets テンプレートの種目の目標セットを前回のセッションで実施したセットに置き換える
+// 同じ種目を複数回実施した場合は最初のエクササイズのセットを使う
+func withPreviousTargets(entries []templateEntry, previous []templateEntry) []templateEntry {
+	previousTargets := map[model.ExerciseKey][]model.SetTarget{}
+	for _, entry := range previous {
+		key := entry.key()
+		if _, ok := previousTargets[key]; !ok && len(entry.targets) != 0 {
+			previousTargets[key] = entry.targets
+		}
+	}
+	for i := range entries {
+		if targets, ok := previousTargets[entries[i].key()]; ok {
+			entries[i].targets = targets
+		}
+	}
+	return entries
+}
+
+// key 記録を集計する種目の単位を返却
+func (e templateEntry) key() model.ExerciseKey {
+	if e.catalogId != 0 {
+		return model.ExerciseKey{CatalogID: e.catalogId}
+	}
+	return model.ExerciseKey{ExerciseName: e.exerciseName}
+}
+
+// createTemplate テンプレートを種目・目標セットとあわせて1トランザクションで作成
+func (s *TemplateImpl) createTemplate(userId int64, name string, entries []templateEntry) (*response.Template, error) {
+	var template *model.WorkoutTemplateImpl
+	err := s.Transaction(func(tx dbr.SessionRunner) error {
+		var err error
+		template, err = s.WorkoutTemplate.CreateTx(tx, userId, name)
+		if err != nil {
+			return err
+		}
+		return s.saveEntriesTx(tx, template.ID, entries)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.templateResponse(template)
+}
+
+// saveEntriesTx トランザクション内でテンプレートの種目・目標セットを並び順に作成
+func (s *TemplateImpl) saveEntriesTx(tx dbr.SessionRunner, templateId int64, entries []templateEntry) error {
+	for i, entry := range entries {
+		exercise, err := s.TemplateExercise.CreateTx(tx, templateId, int64(i+1), entry.exerciseName, entry.catalogId, entry.modality)
+		if err != nil {
+			return err
+		}
+		for _, target := range entry.targets {
+			if _, err := s.TemplateSet.CreateTx(tx, exercise.ID, target); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// templateResponse テンプレートの種目・目標セットを読み込んでレスポンスに変換
+func (s *TemplateImpl) templateResponse(template *model.WorkoutTemplateImpl) (*response.Template, error) {
+	exercises, err := s.TemplateExercise.LoadByTemplateIDs([]int64{template.ID})
+	if err != nil {
+		return nil, err
+	}
+	sets, err := s.TemplateSet.LoadByTemplateExerciseIDs(exercises.IDs())
+	if err != nil {
+		return nil, err
+	}
+	return response.NewTemplate().TemplateFromModel(template, exercises, sets.GroupByTemplateExerciseID()), nil
+}
+
+// templateEntries テンプレートの種目・目標セットを読み込み
+func (s *TemplateImpl) templateEntries(templateId int64) ([]templateEntry, error) {
+	exercises, err := s.TemplateExercise.LoadByTemplateIDs([]int64{templateId})
+	if err != nil {
+		return nil, err
+	}
+	sets, err := s.TemplateSet.LoadByTemplateExerciseIDs(exercises.IDs())
+	if err != nil {
+		return nil, err
+	}
+	setsByExercise := sets.GroupByTemplateExerciseID()
+
+	entries := make([]templateEntry, 0, len(*exercises))
+	for _, exercise := range *exercises {
+		entry := templateEntry{
+			exerciseName: exercise.ExerciseName,
+			catalogId:    exercise.CatalogID.Int64,
+			modality:     exercise.Modality,
+		}
+		if exerciseSets, ok := setsByExercise[exercise.ID]; ok {
+			entry.targets = exerciseSets.Targets()
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// sessionEntries セッションで実施した種目・セットを目標セットとして読み込み
+func (s *TemplateImpl) sessionEntries(sessionId int64) ([]templateEntry, error) {
+	exercises, err := s.Exercise.LoadBySessionID(sessionId)
+	if err != nil {
+		return nil, err
+	}
+	sets, err := s.Set.LoadByExerciseIDs(exercises.IDs())
+	if err != nil {
+		return nil, err
+	}
+	setsByExercise := sets.GroupByExerciseID()
+
+	entries := make([]templateEntry, 0, len(*exercises))
+	for _, exercise := range *exercises {
+		entry := templateEntry{
+			exerciseName: exercise.ExerciseName,
+			catalogId:    exercise.CatalogID.Int64,
+			modality:     exercise.Modality,
+		}
+		if exerciseSets, ok := setsByExercise[exercise.ID]; ok {
+			entry.targets = exerciseSets.Targets()
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// entriesFromForm フォームで指定された種目をカタログに紐づけ、目標セットを検証
+func (s *TemplateImpl) entriesFromForm(exercises []form.TemplateExercise) ([]templateEntry, error) {
+	entries := make([]templateEntry, 0, len(exercises))
+	for _, e := range exercises {
+		exerciseName, catalogId, catalogModality, err := resolveCatalog(s.ExerciseCatalog, e.CatalogID, e.ExerciseName)
+		if err != nil {
+			return nil, err
+		}
+		modality, err := exerciseModality(e.Modality, catalogModality)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := templateEntry{exerciseName: exerciseName, catalogId: catalogId, modality: modality}
+		for _, st := range e.Sets {
+			target, err := targetFromForm(st)
+			if err != nil {
+				return nil, fmt.Errorf("%s set %d: %w", exerciseName, st.SetNumber, err)
+			}
+			entry.targets = append(entry.targets, target)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// targetFromForm 目標セットを検証し、重量をkgに変換
+// 目標は重量・回数などの一部のみの指定を許すため、記録方法に応じた必須項目は検証しない
+func targetFromForm(f form.TemplateSet) (model.SetTarget, error) {
+	if f.SetNumber <= 0 {
+		return model.SetTarget{}, fmt.Errorf("set_number must be positive: %w", ErrInvalidArgument)
+	}
+	if f.Weight < 0 || f.Reps < 0 {
+		return model.SetTarget{}, fmt.Errorf("weight and reps must not be negative: %w", ErrInvalidArgument)
+	}
+
+	unit := enteredUnit(f.Unit)
+	target := model.SetTarget{
+		SetNumber: f.SetNumber,
+		SetType:   f.SetType,
+		Weight:    units.ToKilograms(f.Weight, unit),
+		Unit:      string(unit),
+		Reps:      f.Reps,
+	}
+	if target.SetType == "" {
+		target.SetType = model.SetTypeWorking
+	}
+	if err := validateSetType(target.SetType); err != nil {
+		return model.SetTarget{}, err
+	}
+	if f.DurationSeconds != nil {
+		if *f.DurationSeconds <= 0 {
+			return model.SetTarget{}, fmt.Errorf("duration_seconds must be positive: %w", ErrInvalidArgument)
+		}
+		target.DurationSeconds = dbr.NewNullInt64(*f.DurationSeconds)
+	}
+	if f.DistanceMeters != nil {
+		if *f.DistanceMeters <= 0 {
+			return model.SetTarget{}, fmt.Errorf("distance_meters must be positive: %w", ErrInvalidArgument)
+		}
+		target.DistanceMeters = dbr.NewNullFloat64(*f.DistanceMeters)
+	}
+	if f.RPE != nil {
+		if err := validateRPE(*f.RPE); err != nil {
+			return model.SetTarget{}, err
+		}
+		target.RPE = dbr.NewNullFloat64(*f.RPE)
+	}
+	return target, nil
+}
+
+// validateTemplateName テンプレート名を検証し、前後の空白を除いて返却
+// 同じユーザーの別のテンプレートと同じ名前の場合はErrConflictとする
+func (s *TemplateImpl) validateTemplateName(userId int64, id int64, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("name is required: %w", ErrInvalidArgument)
+	}
+	if utf8.RuneCountInString(name) > maxTemplateNameLength {
+		return "", fmt.Errorf("name must be at most %d characters: %w", maxTemplateNameLength, ErrInvalidArgument)
+	}
+
+	existing, err := s.WorkoutTemplate.LoadByName(userId, name)
+	if err != nil {
+		return "", err
+	}
+	if existing.ID != 0 && existing.ID != id {
+		return "", fmt.Errorf("template %q already exists: %w", name, ErrConflict)
+	}
+	return name, nil
+}
+
+// loadTemplate ユーザーのテンプレートを読み込み、存在しなければErrNotFoundを返却
+// 他人のテンプレートも存在を知られないようErrNotFoundとする
+func (s *TemplateImpl) loadTemplate(userId int64, id int64) (*model.WorkoutTemplateImpl, error) {
+	template, err := s.WorkoutTemplate.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if template.ID != id || template.ID == 0 || template.UserID != userId {
+		return nil, fmt.Errorf("template not found. id %d: %w", id, ErrNotFound)
+	}
+	return template, nil
+}
@@ -0,0 +1,668 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model/mock_model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/gocraft/dbr/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateCreate(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		WorkoutTemplate  model.WorkoutTemplate
+		TemplateExercise model.TemplateExercise
+		TemplateSet      model.TemplateSet
+		ExerciseCatalog  model.ExerciseCatalog
+	}
+	type args struct {
+		f form.SaveTemplate
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.Template, err error)
+	}{
+		{
+			testCase: "正常系",
+			args: args{
+				f: form.SaveTemplate{
+					Name: " Push ",
+					Exercises: []form.TemplateExercise{
+						{ExerciseName: "ベンチ", Sets: []form.TemplateSet{
+							{SetNumber: int64(1), Weight: float64(60), Unit: "kg", Reps: int64(10)},
+							{SetNumber: int64(2), Weight: float64(135), Unit: "lb", Reps: int64(8)},
+						}},
+						{ExerciseName: "ディップス"},
+					},
+				},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().LoadByName(int64(1), "Push").Return(&model.WorkoutTemplateImpl{}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("ベンチ").Return(&model.ExerciseCatalogImpl{ID: int64(1), NameJa: "ベンチプレス", Modality: model.ModalityWeighted}, nil)
+				ExerciseCatalog.EXPECT().Resolve("ディップス").Return(&model.ExerciseCatalogImpl{ID: int64(7), NameJa: "ディップス", Modality: model.ModalityBodyweight}, nil)
+				TemplateExercise := mock_model.NewMockTemplateExercise(ctrl)
+				TemplateSet := mock_model.NewMockTemplateSet(ctrl)
+				gomock.InOrder(
+					WorkoutTemplate.EXPECT().CreateTx(gomock.Any(), int64(1), "Push").Return(&model.WorkoutTemplateImpl{ID: int64(3), UserID: int64(1), Name: "Push"}, nil),
+					TemplateExercise.EXPECT().CreateTx(gomock.Any(), int64(3), int64(1), "ベンチ", int64(1), model.ModalityWeighted).Return(&model.TemplateExerciseImpl{ID: int64(10)}, nil),
+					TemplateSet.EXPECT().CreateTx(gomock.Any(), int64(10), model.SetTarget{SetNumber: int64(1), SetType: model.SetTypeWorking, Weight: float64(60), Unit: "kg", Reps: int64(10)}).Return(&model.TemplateSetImpl{}, nil),
+					// ポンドで指定した重量はkgに変換して保存する
+					TemplateSet.EXPECT().CreateTx(gomock.Any(), int64(10), model.SetTarget{SetNumber: int64(2), SetType: model.SetTypeWorking, Weight: float64(61.235), Unit: "lb", Reps: int64(8)}).Return(&model.TemplateSetImpl{}, nil),
+					TemplateExercise.EXPECT().CreateTx(gomock.Any(), int64(3), int64(2), "ディップス", int64(7), model.ModalityBodyweight).Return(&model.TemplateExerciseImpl{ID: int64(11)}, nil),
+				)
+				TemplateExercise.EXPECT().LoadByTemplateIDs([]int64{3}).Return(&model.TemplateExercises{
+					{ID: int64(10), TemplateID: int64(3), Position: int64(1), CatalogID: dbr.NewNullInt64(1), ExerciseName: "ベンチ", Modality: model.ModalityWeighted},
+					{ID: int64(11), TemplateID: int64(3), Position: int64(2), CatalogID: dbr.NewNullInt64(7), ExerciseName: "ディップス", Modality: model.ModalityBodyweight},
+				}, nil)
+				TemplateSet.EXPECT().LoadByTemplateExerciseIDs([]int64{10, 11}).Return(&model.TemplateSets{
+					{ID: int64(1), TemplateExerciseID: int64(10), SetTarget: model.SetTarget{SetNumber: int64(1), SetType: model.SetTypeWorking, Weight: float64(60), Unit: "kg", Reps: int64(10)}},
+					{ID: int64(2), TemplateExerciseID: int64(10), SetTarget: model.SetTarget{SetNumber: int64(2), SetType: model.SetTypeWorking, Weight: float64(61.235), Unit: "lb", Reps: int64(8)}},
+				}, nil)
+				return fields{
+					WorkoutTemplate:  WorkoutTemplate,
+					TemplateExercise: TemplateExercise,
+					TemplateSet:      TemplateSet,
+					ExerciseCatalog:  ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.Template, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(3), r.ID)
+				assert.Equal(t, "Push", r.Name)
+				if assert.Len(t, r.Exercises, 2) {
+					assert.Len(t, r.Exercises[0].Sets, 2)
+					// ポンドで入力した重量はkgのプレートで組める重量で表示する
+					assert.Equal(t, float64(61), r.Exercises[0].Sets[1].Weight)
+					assert.Equal(t, model.ModalityBodyweight, r.Exercises[1].Modality)
+					assert.NotNil(t, r.Exercises[1].Sets)
+					assert.Empty(t, r.Exercises[1].Sets)
+				}
+			},
+		},
+		{
+			testCase: "エラー(同じ名前のテンプレートがある)",
+			args: args{
+				f: form.SaveTemplate{Name: "Push"},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().LoadByName(int64(1), "Push").Return(&model.WorkoutTemplateImpl{ID: int64(2), UserID: int64(1), Name: "Push"}, nil)
+				return fields{
+					WorkoutTemplate: WorkoutTemplate,
+				}
+			},
+			assertion: func(r *response.Template, err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(目標セットの重量が負)",
+			args: args{
+				f: form.SaveTemplate{
+					Name: "Push",
+					Exercises: []form.TemplateExercise{
+						{ExerciseName: "test", Sets: []form.TemplateSet{{SetNumber: int64(1), Weight: float64(-1)}}},
+					},
+				},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().LoadByName(int64(1), "Push").Return(&model.WorkoutTemplateImpl{}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("test").Return(&model.ExerciseCatalogImpl{}, nil)
+				return fields{
+					WorkoutTemplate: WorkoutTemplate,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.Template, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(名前が空)",
+			args: args{
+				f: form.SaveTemplate{Name: "  "},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r *response.Template, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			s := &TemplateImpl{
+				WorkoutTemplate:  fields.WorkoutTemplate,
+				TemplateExercise: fields.TemplateExercise,
+				TemplateSet:      fields.TemplateSet,
+				ExerciseCatalog:  fields.ExerciseCatalog,
+				Transaction:      noTransaction,
+			}
+			tt.assertion(s.Create(int64(1), tt.args.f))
+		})
+	}
+}
+
+func TestTemplateCreateFromWorkout(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		WorkoutTemplate  model.WorkoutTemplate
+		TemplateExercise model.TemplateExercise
+		TemplateSet      model.TemplateSet
+		WorkoutSession   model.WorkoutSession
+		Exercise         model.Exercise
+		Set              model.Set
+	}
+	type args struct {
+		sessionId int64
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.Template, err error)
+	}{
+		{
+			testCase: "正常系",
+			args: args{
+				sessionId: int64(1),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().LoadByName(int64(1), "Push").Return(&model.WorkoutTemplateImpl{}, nil)
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1), Status: model.SessionStatusCompleted}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().LoadBySessionID(int64(1)).Return(&model.Exercises{
+					{ID: int64(2), SessionID: int64(1), CatalogID: dbr.NewNullInt64(1), ExerciseName: "ベンチプレス", Modality: model.ModalityWeighted},
+				}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadByExerciseIDs([]int64{2}).Return(&model.Sets{
+					{ID: int64(5), ExerciseID: int64(2), SetNumber: int64(1), Weight: float64(80), Unit: "kg", Reps: int64(5), SetDetail: model.SetDetail{SetType: model.SetTypeWorking, Notes: "メモは保存しない"}},
+				}, nil)
+				TemplateExercise := mock_model.NewMockTemplateExercise(ctrl)
+				TemplateSet := mock_model.NewMockTemplateSet(ctrl)
+				gomock.InOrder(
+					WorkoutTemplate.EXPECT().CreateTx(gomock.Any(), int64(1), "Push").Return(&model.WorkoutTemplateImpl{ID: int64(3), UserID: int64(1), Name: "Push"}, nil),
+					TemplateExercise.EXPECT().CreateTx(gomock.Any(), int64(3), int64(1), "ベンチプレス", int64(1), model.ModalityWeighted).Return(&model.TemplateExerciseImpl{ID: int64(10)}, nil),
+					TemplateSet.EXPECT().CreateTx(gomock.Any(), int64(10), model.SetTarget{SetNumber: int64(1), SetType: model.SetTypeWorking, Weight: float64(80), Unit: "kg", Reps: int64(5)}).Return(&model.TemplateSetImpl{}, nil),
+				)
+				TemplateExercise.EXPECT().LoadByTemplateIDs([]int64{3}).Return(&model.TemplateExercises{}, nil)
+				TemplateSet.EXPECT().LoadByTemplateExerciseIDs([]int64{}).Return(&model.TemplateSets{}, nil)
+				return fields{
+					WorkoutTemplate:  WorkoutTemplate,
+					TemplateExercise: TemplateExercise,
+					TemplateSet:      TemplateSet,
+					WorkoutSession:   WorkoutSession,
+					Exercise:         Exercise,
+					Set:              Set,
+				}
+			},
+			assertion: func(r *response.Template, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(3), r.ID)
+			},
+		},
+		{
+			testCase: "エラー(他人のセッション)",
+			args: args{
+				sessionId: int64(2),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().LoadByName(int64(1), "Push").Return(&model.WorkoutTemplateImpl{}, nil)
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(2)).Return(&model.WorkoutSessionImpl{ID: int64(2), Date: time.Now(), UserID: int64(2)}, nil)
+				return fields{
+					WorkoutTemplate: WorkoutTemplate,
+					WorkoutSession:  WorkoutSession,
+				}
+			},
+			assertion: func(r *response.Template, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			s := &TemplateImpl{
+				WorkoutTemplate:  fields.WorkoutTemplate,
+				TemplateExercise: fields.TemplateExercise,
+				TemplateSet:      fields.TemplateSet,
+				WorkoutSession:   fields.WorkoutSession,
+				Exercise:         fields.Exercise,
+				Set:              fields.Set,
+				Transaction:      noTransaction,
+			}
+			tt.assertion(s.CreateFromWorkout(int64(1), tt.args.sessionId, "Push"))
+		})
+	}
+}
+
+func TestTemplateInstantiate(t *testing.T) {
+	t.Parallel()
+	date := time.Date(2024, 10, 7, 0, 0, 0, 0, time.UTC)
+	lastSessionFilter := model.WorkoutSessionFilter{UserID: int64(1), TemplateID: int64(3), Status: model.SessionStatusCompleted, Desc: true, Limit: 1}
+	type fields struct {
+		WorkoutTemplate  model.WorkoutTemplate
+		TemplateExercise model.TemplateExercise
+		TemplateSet      model.TemplateSet
+		WorkoutSession   model.WorkoutSession
+		Exercise         model.Exercise
+		Set              model.Set
+		TargetSet        model.TargetSet
+	}
+	type args struct {
+		id     int64
+		source string
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.GetWorkoutSession, err error)
+	}{
+		{
+			testCase: "正常系(前回の記録を目標セットにする)",
+			args: args{
+				id: int64(3),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().Load(int64(3)).Return(&model.WorkoutTemplateImpl{ID: int64(3), UserID: int64(1), Name: "Push"}, nil)
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().LoadByFilter(lastSessionFilter).Return(&model.WorkoutSessions{
+					{ID: int64(20), Date: date.AddDate(0, 0, -7), UserID: int64(1), TemplateID: dbr.NewNullInt64(3), Status: model.SessionStatusCompleted},
+				}, nil)
+				// 前回はベンチプレスのみ実施した
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().LoadBySessionID(int64(20)).Return(&model.Exercises{
+					{ID: int64(30), SessionID: int64(20), CatalogID: dbr.NewNullInt64(1), ExerciseName: "ベンチプレス", Modality: model.ModalityWeighted},
+				}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadByExerciseIDs([]int64{30}).Return(&model.Sets{
+					{ID: int64(40), ExerciseID: int64(30), SetNumber: int64(1), Weight: float64(62.5), Unit: "kg", Reps: int64(8), SetDetail: model.SetDetail{SetType: model.SetTypeWorking}},
+				}, nil)
+				TemplateExercise := mock_model.NewMockTemplateExercise(ctrl)
+				TemplateExercise.EXPECT().LoadByTemplateIDs([]int64{3}).Return(&model.TemplateExercises{
+					{ID: int64(10), TemplateID: int64(3), Position: int64(1), CatalogID: dbr.NewNullInt64(1), ExerciseName: "ベンチプレス", Modality: model.ModalityWeighted},
+					{ID: int64(11), TemplateID: int64(3), Position: int64(2), ExerciseName: "ケーブルフライ", Modality: model.ModalityWeighted},
+				}, nil)
+				TemplateSet := mock_model.NewMockTemplateSet(ctrl)
+				TemplateSet.EXPECT().LoadByTemplateExerciseIDs([]int64{10, 11}).Return(&model.TemplateSets{
+					{ID: int64(1), TemplateExerciseID: int64(10), SetTarget: model.SetTarget{SetNumber: int64(1), SetType: model.SetTypeWorking, Weight: float64(60), Unit: "kg", Reps: int64(8)}},
+					{ID: int64(2), TemplateExerciseID: int64(11), SetTarget: model.SetTarget{SetNumber: int64(1), SetType: model.SetTypeWorking, Weight: float64(15), Unit: "kg", Reps: int64(12)}},
+				}, nil)
+				TargetSet := mock_model.NewMockTargetSet(ctrl)
+				gomock.InOrder(
+					WorkoutSession.EXPECT().CreateFromTemplateTx(gomock.Any(), date, int64(1), int64(3)).Return(&model.WorkoutSessionImpl{ID: int64(21), Date: date, UserID: int64(1), TemplateID: dbr.NewNullInt64(3), Status: model.SessionStatusDraft}, nil),
+					Exercise.EXPECT().CreateTx(gomock.Any(), int64(21), "ベンチプレス", int64(1), model.ModalityWeighted).Return(&model.ExerciseImpl{ID: int64(31), SessionID: int64(21), CatalogID: dbr.NewNullInt64(1), ExerciseName: "ベンチプレス", Modality: model.ModalityWeighted}, nil),
+					TargetSet.EXPECT().CreateTx(gomock.Any(), int64(31), model.SetTarget{SetNumber: int64(1), SetType: model.SetTypeWorking, Weight: float64(62.5), Unit: "kg", Reps: int64(8)}).Return(&model.TargetSetImpl{}, nil),
+					Exercise.EXPECT().CreateTx(gomock.Any(), int64(21), "ケーブルフライ", int64(0), model.ModalityWeighted).Return(&model.ExerciseImpl{ID: int64(32), SessionID: int64(21), ExerciseName: "ケーブルフライ", Modality: model.ModalityWeighted}, nil),
+					TargetSet.EXPECT().CreateTx(gomock.Any(), int64(32), model.SetTarget{SetNumber: int64(1), SetType: model.SetTypeWorking, Weight: float64(15), Unit: "kg", Reps: int64(12)}).Return(&model.TargetSetImpl{}, nil),
+				)
+				return fields{
+					WorkoutTemplate:  WorkoutTemplate,
+					TemplateExercise: TemplateExercise,
+					TemplateSet:      TemplateSet,
+					WorkoutSession:   WorkoutSession,
+					Exercise:         Exercise,
+					Set:              Set,
+					TargetSet:        TargetSet,
+				}
+			},
+			assertion: func(r *response.GetWorkoutSession, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(21), r.ID)
+				assert.Equal(t, int64(3), r.TemplateID)
+				assert.Equal(t, model.SessionStatusDraft, r.Status)
+				if assert.Len(t, r.Exercises, 2) {
+					assert.Empty(t, r.Exercises[0].Sets)
+					assert.Equal(t, float64(62.5), r.Exercises[0].TargetSets[0].Weight)
+					assert.Equal(t, float64(15), r.Exercises[1].TargetSets[0].Weight)
+				}
+			},
+		},
+		{
+			testCase: "正常系(前回のセッションを繰り返す)",
+			args: args{
+				id:     int64(3),
+				source: TemplateSourceLastSession,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().Load(int64(3)).Return(&model.WorkoutTemplateImpl{ID: int64(3), UserID: int64(1), Name: "Push"}, nil)
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().LoadByFilter(lastSessionFilter).Return(&model.WorkoutSessions{
+					{ID: int64(20), Date: date.AddDate(0, 0, -7), UserID: int64(1), TemplateID: dbr.NewNullInt64(3), Status: model.SessionStatusCompleted},
+				}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().LoadBySessionID(int64(20)).Return(&model.Exercises{
+					{ID: int64(30), SessionID: int64(20), ExerciseName: "腕立て伏せ", Modality: model.ModalityBodyweight},
+				}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadByExerciseIDs([]int64{30}).Return(&model.Sets{
+					{ID: int64(40), ExerciseID: int64(30), SetNumber: int64(1), Unit: "kg", Reps: int64(20), SetDetail: model.SetDetail{SetType: model.SetTypeWorking}},
+				}, nil)
+				TargetSet := mock_model.NewMockTargetSet(ctrl)
+				gomock.InOrder(
+					WorkoutSession.EXPECT().CreateFromTemplateTx(gomock.Any(), date, int64(1), int64(3)).Return(&model.WorkoutSessionImpl{ID: int64(21), Date: date, UserID: int64(1), TemplateID: dbr.NewNullInt64(3), Status: model.SessionStatusDraft}, nil),
+					Exercise.EXPECT().CreateTx(gomock.Any(), int64(21), "腕立て伏せ", int64(0), model.ModalityBodyweight).Return(&model.ExerciseImpl{ID: int64(31), SessionID: int64(21), ExerciseName: "腕立て伏せ", Modality: model.ModalityBodyweight}, nil),
+					TargetSet.EXPECT().CreateTx(gomock.Any(), int64(31), model.SetTarget{SetNumber: int64(1), SetType: model.SetTypeWorking, Unit: "kg", Reps: int64(20)}).Return(&model.TargetSetImpl{}, nil),
+				)
+				return fields{
+					WorkoutTemplate: WorkoutTemplate,
+					WorkoutSession:  WorkoutSession,
+					Exercise:        Exercise,
+					Set:             Set,
+					TargetSet:       TargetSet,
+				}
+			},
+			assertion: func(r *response.GetWorkoutSession, err error) {
+				assert.NoError(t, err)
+				if assert.Len(t, r.Exercises, 1) {
+					assert.Equal(t, int64(20), r.Exercises[0].TargetSets[0].Reps)
+				}
+			},
+		},
+		{
+			testCase: "エラー(繰り返すセッションがない)",
+			args: args{
+				id:     int64(3),
+				source: TemplateSourceLastSession,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().Load(int64(3)).Return(&model.WorkoutTemplateImpl{ID: int64(3), UserID: int64(1), Name: "Push"}, nil)
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().LoadByFilter(lastSessionFilter).Return(&model.WorkoutSessions{}, nil)
+				return fields{
+					WorkoutTemplate: WorkoutTemplate,
+					WorkoutSession:  WorkoutSession,
+				}
+			},
+			assertion: func(r *response.GetWorkoutSession, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(他人のテンプレート)",
+			args: args{
+				id: int64(4),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().Load(int64(4)).Return(&model.WorkoutTemplateImpl{ID: int64(4), UserID: int64(2), Name: "Push"}, nil)
+				return fields{
+					WorkoutTemplate: WorkoutTemplate,
+				}
+			},
+			assertion: func(r *response.GetWorkoutSession, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(作成元が不正)",
+			args: args{
+				id:     int64(3),
+				source: "yesterday",
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r *response.GetWorkoutSession, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			s := &TemplateImpl{
+				WorkoutTemplate:  fields.WorkoutTemplate,
+				TemplateExercise: fields.TemplateExercise,
+				TemplateSet:      fields.TemplateSet,
+				WorkoutSession:   fields.WorkoutSession,
+				Exercise:         fields.Exercise,
+				Set:              fields.Set,
+				TargetSet:        fields.TargetSet,
+				Transaction:      noTransaction,
+			}
+			tt.assertion(s.Instantiate(int64(1), tt.args.id, date, tt.args.source))
+		})
+	}
+}
+
+func TestTemplateUpdate(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		WorkoutTemplate  model.WorkoutTemplate
+		TemplateExercise model.TemplateExercise
+		TemplateSet      model.TemplateSet
+		ExerciseCatalog  model.ExerciseCatalog
+	}
+	type args struct {
+		userId int64
+		f      form.SaveTemplate
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.Template, err error)
+	}{
+		{
+			testCase: "正常系(名前を変更して種目を置き換える)",
+			args: args{
+				userId: int64(1),
+				f: form.SaveTemplate{
+					Name:      " Pull ",
+					Exercises: []form.TemplateExercise{{ExerciseName: "懸垂"}},
+				},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().Load(int64(3)).Return(&model.WorkoutTemplateImpl{ID: int64(3), UserID: int64(1), Name: "Push"}, nil)
+				WorkoutTemplate.EXPECT().LoadByName(int64(1), "Pull").Return(&model.WorkoutTemplateImpl{}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("懸垂").Return(&model.ExerciseCatalogImpl{ID: int64(9), NameJa: "懸垂", Modality: model.ModalityBodyweight}, nil)
+				TemplateExercise := mock_model.NewMockTemplateExercise(ctrl)
+				TemplateSet := mock_model.NewMockTemplateSet(ctrl)
+				gomock.InOrder(
+					TemplateSet.EXPECT().DeleteByTemplateIDTx(gomock.Any(), int64(3)).Return(int64(2), nil),
+					TemplateExercise.EXPECT().DeleteByTemplateIDTx(gomock.Any(), int64(3)).Return(int64(1), nil),
+					WorkoutTemplate.EXPECT().UpdateTx(gomock.Any(), int64(3), map[string]interface{}{"name": "Pull"}).Return(true, nil),
+					TemplateExercise.EXPECT().CreateTx(gomock.Any(), int64(3), int64(1), "懸垂", int64(9), model.ModalityBodyweight).Return(&model.TemplateExerciseImpl{ID: int64(12)}, nil),
+				)
+				TemplateExercise.EXPECT().LoadByTemplateIDs([]int64{3}).Return(&model.TemplateExercises{
+					{ID: int64(12), TemplateID: int64(3), Position: int64(1), CatalogID: dbr.NewNullInt64(9), ExerciseName: "懸垂", Modality: model.ModalityBodyweight},
+				}, nil)
+				TemplateSet.EXPECT().LoadByTemplateExerciseIDs([]int64{12}).Return(&model.TemplateSets{}, nil)
+				return fields{
+					WorkoutTemplate:  WorkoutTemplate,
+					TemplateExercise: TemplateExercise,
+					TemplateSet:      TemplateSet,
+					ExerciseCatalog:  ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.Template, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(3), r.ID)
+				assert.Equal(t, "Pull", r.Name)
+				if assert.Len(t, r.Exercises, 1) {
+					assert.Equal(t, "懸垂", r.Exercises[0].ExerciseName)
+				}
+			},
+		},
+		{
+			testCase: "エラー(同じ名前の別のテンプレートがある)",
+			args: args{
+				userId: int64(1),
+				f:      form.SaveTemplate{Name: "Legs"},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().Load(int64(3)).Return(&model.WorkoutTemplateImpl{ID: int64(3), UserID: int64(1), Name: "Push"}, nil)
+				WorkoutTemplate.EXPECT().LoadByName(int64(1), "Legs").Return(&model.WorkoutTemplateImpl{ID: int64(4), UserID: int64(1), Name: "Legs"}, nil)
+				return fields{
+					WorkoutTemplate: WorkoutTemplate,
+				}
+			},
+			assertion: func(r *response.Template, err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(他のユーザーのテンプレート)",
+			args: args{
+				userId: int64(2),
+				f:      form.SaveTemplate{Name: "Pull"},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().Load(int64(3)).Return(&model.WorkoutTemplateImpl{ID: int64(3), UserID: int64(1), Name: "Push"}, nil)
+				return fields{
+					WorkoutTemplate: WorkoutTemplate,
+				}
+			},
+			assertion: func(r *response.Template, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			s := &TemplateImpl{
+				WorkoutTemplate:  fields.WorkoutTemplate,
+				TemplateExercise: fields.TemplateExercise,
+				TemplateSet:      fields.TemplateSet,
+				ExerciseCatalog:  fields.ExerciseCatalog,
+				Transaction:      noTransaction,
+			}
+			tt.assertion(s.Update(tt.args.userId, int64(3), tt.args.f))
+		})
+	}
+}
+
+func TestTemplateDelete(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		WorkoutTemplate  model.WorkoutTemplate
+		TemplateExercise model.TemplateExercise
+		TemplateSet      model.TemplateSet
+	}
+	tests := []struct {
+		testCase  string
+		id        int64
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(err error)
+	}{
+		{
+			testCase: "正常系",
+			id:       int64(3),
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().Load(int64(3)).Return(&model.WorkoutTemplateImpl{ID: int64(3), UserID: int64(1), Name: "Push"}, nil)
+				TemplateExercise := mock_model.NewMockTemplateExercise(ctrl)
+				TemplateSet := mock_model.NewMockTemplateSet(ctrl)
+				gomock.InOrder(
+					TemplateSet.EXPECT().DeleteByTemplateIDTx(gomock.Any(), int64(3)).Return(int64(4), nil),
+					TemplateExercise.EXPECT().DeleteByTemplateIDTx(gomock.Any(), int64(3)).Return(int64(2), nil),
+					WorkoutTemplate.EXPECT().DeleteTx(gomock.Any(), int64(3)).Return(true, nil),
+				)
+				return fields{
+					WorkoutTemplate:  WorkoutTemplate,
+					TemplateExercise: TemplateExercise,
+					TemplateSet:      TemplateSet,
+				}
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "エラー(子の削除に失敗)",
+			id:       int64(3),
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().Load(int64(3)).Return(&model.WorkoutTemplateImpl{ID: int64(3), UserID: int64(1), Name: "Push"}, nil)
+				TemplateSet := mock_model.NewMockTemplateSet(ctrl)
+				TemplateSet.EXPECT().DeleteByTemplateIDTx(gomock.Any(), int64(3)).Return(int64(0), errors.New("couldn't delete template_sets"))
+				return fields{
+					WorkoutTemplate: WorkoutTemplate,
+					TemplateSet:     TemplateSet,
+				}
+			},
+			assertion: func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			testCase: "エラー(存在しない)",
+			id:       int64(100),
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutTemplate := mock_model.NewMockWorkoutTemplate(ctrl)
+				WorkoutTemplate.EXPECT().Load(int64(100)).Return(&model.WorkoutTemplateImpl{}, nil)
+				return fields{
+					WorkoutTemplate: WorkoutTemplate,
+				}
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			s := &TemplateImpl{
+				WorkoutTemplate:  fields.WorkoutTemplate,
+				TemplateExercise: fields.TemplateExercise,
+				TemplateSet:      fields.TemplateSet,
+				Transaction:      noTransaction,
+			}
+			tt.assertion(s.Delete(int64(1), tt.id))
+		})
+	}
+}
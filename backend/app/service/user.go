@@ -0,0 +1,136 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/auth"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type (
+	// User ユーザーのサービスを表す
+	User interface {
+		Signup(email string, password string, name string) (*response.Auth, error)
+		Login(email string, password string) (*response.Auth, error)
+		Get(userId int64) (*response.User, error)
+		UpdatePreferences(userId int64, weightUnit units.Unit) (*response.User, error)
+		WeightUnit(userId int64) (units.Unit, error)
+	}
+
+	// UserImpl ユーザーのサービスを表す
+	UserImpl struct {
+		User          model.User
+		GenerateToken func(userId int64) (string, error)
+	}
+)
+
+func NewUser() User {
+	return &UserImpl{
+		User:          model.NewUser(),
+		GenerateToken: auth.GenerateToken,
+	}
+}
+
+// Signup ユーザーを登録しトークンを発行
+func (s *UserImpl) Signup(email string, password string, name string) (*response.Auth, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	existing, err := s.User.LoadByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if existing.ID != 0 {
+		return nil, fmt.Errorf("email already registered: %w", ErrConflict)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't hash password: %w", err)
+	}
+
+	user, err := s.User.Create(email, string(hash), name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issue(user)
+}
+
+// Login メールアドレスとパスワードを検証しトークンを発行
+func (s *UserImpl) Login(email string, password string) (*response.Auth, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	user, err := s.User.LoadByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	// ユーザーの存在有無が分からないよう同じエラーを返却する
+	if user.ID == 0 {
+		return nil, fmt.Errorf("invalid email or password: %w", ErrUnauthorized)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, fmt.Errorf("invalid email or password: %w", ErrUnauthorized)
+	}
+
+	return s.issue(user)
+}
+
+// Get ユーザーを取得
+func (s *UserImpl) Get(userId int64) (*response.User, error) {
+	user, err := s.loadUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	return response.NewUser().UserFromModel(user), nil
+}
+
+// UpdatePreferences ユーザーの設定を更新。重量の単位はkgかlbのみ指定できる
+func (s *UserImpl) UpdatePreferences(userId int64, weightUnit units.Unit) (*response.User, error) {
+	if weightUnit != units.Kilogram && weightUnit != units.Pound {
+		return nil, fmt.Errorf("unknown unit %q: %w", weightUnit, ErrInvalidArgument)
+	}
+	user, err := s.loadUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.User.Update(user.ID, map[string]interface{}{"weight_unit": string(weightUnit)}); err != nil {
+		return nil, err
+	}
+	user.WeightUnit = string(weightUnit)
+
+	return response.NewUser().UserFromModel(user), nil
+}
+
+// WeightUnit ユーザーが設定した重量の単位を取得。未設定の場合は既定の単位を返却
+func (s *UserImpl) WeightUnit(userId int64) (units.Unit, error) {
+	user, err := s.loadUser(userId)
+	if err != nil {
+		return "", err
+	}
+	return units.ParseUnit(user.WeightUnit)
+}
+
+// loadUser ユーザーを読み込み、存在しない場合はErrNotFoundを返却
+func (s *UserImpl) loadUser(userId int64) (*model.UserImpl, error) {
+	user, err := s.User.Load(userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, fmt.Errorf("user %d: %w", userId, ErrNotFound)
+	}
+	return user, nil
+}
+
+func (s *UserImpl) issue(user *model.UserImpl) (*response.Auth, error) {
+	token, err := s.GenerateToken(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't generate token: %w", err)
+	}
+	return response.NewAuth().AuthFromModel(token, user), nil
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model/mock_model"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeToken テスト用に固定のトークンを発行する
+func fakeToken(userId int64) (string, error) {
+	return "token", nil
+}
+
+func TestUserSignup(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		User model.User
+	}
+	type args struct {
+		email    string
+		password string
+		name     string
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.Auth, err error)
+	}{
+		{
+			testCase: "正常系",
+			args: args{
+				email:    " Test@Example.com",
+				password: "password",
+				name:     "test",
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				User := mock_model.NewMockUser(ctrl)
+				User.EXPECT().LoadByEmail("test@example.com").Return(&model.UserImpl{}, nil)
+				User.EXPECT().Create("test@example.com", gomock.Any(), "test").DoAndReturn(func(email string, passwordHash string, name string) (*model.UserImpl, error) {
+					assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte("password")))
+					return &model.UserImpl{ID: int64(1), Email: email, PasswordHash: passwordHash, Name: name}, nil
+				})
+				return fields{
+					User: User,
+				}
+			},
+			assertion: func(r *response.Auth, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "token", r.Token)
+				assert.Equal(t, int64(1), r.User.ID)
+				assert.Equal(t, "test@example.com", r.User.Email)
+			},
+		},
+		{
+			testCase: "エラー(登録済み)",
+			args: args{
+				email:    "test@example.com",
+				password: "password",
+				name:     "test",
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				User := mock_model.NewMockUser(ctrl)
+				User.EXPECT().LoadByEmail("test@example.com").Return(&model.UserImpl{ID: int64(1), Email: "test@example.com"}, nil)
+				return fields{
+					User: User,
+				}
+			},
+			assertion: func(r *response.Auth, err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー",
+			args: args{
+				email:    "test@example.com",
+				password: "password",
+				name:     "test",
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				User := mock_model.NewMockUser(ctrl)
+				User.EXPECT().LoadByEmail("test@example.com").Return(nil, errors.New("couldn't load users"))
+				return fields{
+					User: User,
+				}
+			},
+			assertion: func(r *response.Auth, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			u := &UserImpl{
+				User:          fields.User,
+				GenerateToken: fakeToken,
+			}
+			tt.assertion(u.Signup(tt.args.email, tt.args.password, tt.args.name))
+		})
+	}
+}
+
+func TestUserLogin(t *testing.T) {
+	t.Parallel()
+	hash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	type fields struct {
+		User model.User
+	}
+	type args struct {
+		email    string
+		password string
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.Auth, err error)
+	}{
+		{
+			testCase: "正常系",
+			args: args{
+				email:    "test@example.com",
+				password: "password",
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				User := mock_model.NewMockUser(ctrl)
+				User.EXPECT().LoadByEmail("test@example.com").Return(&model.UserImpl{ID: int64(1), Email: "test@example.com", PasswordHash: string(hash)}, nil)
+				return fields{
+					User: User,
+				}
+			},
+			assertion: func(r *response.Auth, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "token", r.Token)
+				assert.Equal(t, int64(1), r.User.ID)
+			},
+		},
+		{
+			testCase: "エラー(パスワード不一致)",
+			args: args{
+				email:    "test@example.com",
+				password: "wrong-password",
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				User := mock_model.NewMockUser(ctrl)
+				User.EXPECT().LoadByEmail("test@example.com").Return(&model.UserImpl{ID: int64(1), Email: "test@example.com", PasswordHash: string(hash)}, nil)
+				return fields{
+					User: User,
+				}
+			},
+			assertion: func(r *response.Auth, err error) {
+				assert.ErrorIs(t, err, ErrUnauthorized)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(未登録)",
+			args: args{
+				email:    "none@example.com",
+				password: "password",
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				User := mock_model.NewMockUser(ctrl)
+				User.EXPECT().LoadByEmail("none@example.com").Return(&model.UserImpl{}, nil)
+				return fields{
+					User: User,
+				}
+			},
+			assertion: func(r *response.Auth, err error) {
+				assert.ErrorIs(t, err, ErrUnauthorized)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			u := &UserImpl{
+				User:          fields.User,
+				GenerateToken: fakeToken,
+			}
+			tt.assertion(u.Login(tt.args.email, tt.args.password))
+		})
+	}
+}
+
+func TestUserUpdatePreferences(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		testCase   string
+		weightUnit units.Unit
+		fields     func(ctrl *gomock.Controller) model.User
+		assertion  func(r *response.User, err error)
+	}{
+		{
+			testCase:   "正常系",
+			weightUnit: units.Pound,
+			fields: func(ctrl *gomock.Controller) model.User {
+				User := mock_model.NewMockUser(ctrl)
+				User.EXPECT().Load(int64(1)).Return(&model.UserImpl{ID: int64(1), WeightUnit: "kg"}, nil)
+				User.EXPECT().Update(int64(1), map[string]interface{}{"weight_unit": "lb"}).Return(true, nil)
+				return User
+			},
+			assertion: func(r *response.User, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "lb", r.WeightUnit)
+			},
+		},
+		{
+			testCase:   "エラー(未知の単位)",
+			weightUnit: units.Unit("stone"),
+			fields: func(ctrl *gomock.Controller) model.User {
+				return mock_model.NewMockUser(ctrl)
+			},
+			assertion: func(r *response.User, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			u := &UserImpl{
+				User: tt.fields(ctrl),
+			}
+			tt.assertion(u.UpdatePreferences(int64(1), tt.weightUnit))
+		})
+	}
+}
+
+func TestUserWeightUnit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		testCase  string
+		fields    func(ctrl *gomock.Controller) model.User
+		assertion func(r units.Unit, err error)
+	}{
+		{
+			testCase: "正常系",
+			fields: func(ctrl *gomock.Controller) model.User {
+				User := mock_model.NewMockUser(ctrl)
+				User.EXPECT().Load(int64(1)).Return(&model.UserImpl{ID: int64(1), WeightUnit: "lb"}, nil)
+				return User
+			},
+			assertion: func(r units.Unit, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, units.Pound, r)
+			},
+		},
+		{
+			testCase: "正常系(未設定の場合は既定の単位)",
+			fields: func(ctrl *gomock.Controller) model.User {
+				User := mock_model.NewMockUser(ctrl)
+				User.EXPECT().Load(int64(1)).Return(&model.UserImpl{ID: int64(1)}, nil)
+				return User
+			},
+			assertion: func(r units.Unit, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, units.Kilogram, r)
+			},
+		},
+		{
+			testCase: "エラー(存在しないユーザー)",
+			fields: func(ctrl *gomock.Controller) model.User {
+				User := mock_model.NewMockUser(ctrl)
+				User.EXPECT().Load(int64(1)).Return(&model.UserImpl{}, nil)
+				return User
+			},
+			assertion: func(r units.Unit, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			u := &UserImpl{
+				User: tt.fields(ctrl),
+			}
+			tt.assertion(u.WeightUnit(int64(1)))
+		})
+	}
+}
@@ -1,62 +1,217 @@
 package service
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/metrics"
 	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
 	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/db"
+	"github.com/gocraft/dbr/v2"
 )
 
+const (
+	// 一覧取得時の件数の既定値
+	defaultListLimit = 20
+	// セットのメモの最大文字数
+	maxSetNotesLength = 1000
+	// セッションのメモの最大文字数
+	maxSessionNotesLength = 2000
+	// セッションの評価の範囲
+	minSessionRating = 1
+	maxSessionRating = 5
+)
+
+// lockedSessionAttrs 実施済みのセッションでも更新できる項目
+var lockedSessionAttrs = map[string]bool{"notes": true, "rating": true}
+
+// recordSetAttrs 自己ベストの集計に使うセットの項目
+var recordSetAttrs = map[string]bool{"weight": true, "unit": true, "reps": true, "set_type": true}
+
 type (
 	// Workout ワークアウトのサービスを表す
 	Workout interface {
-		List(id int64, date time.Time) (response.WorkoutSessions, error)
-		Get(id int64) (*response.GetWorkoutSession, error)
+		List(userId int64, filter model.WorkoutSessionFilter, cursor string, formula metrics.Formula) (response.WorkoutSessions, string, error)
+		Get(userId int64, id int64, formula metrics.Formula) (*response.GetWorkoutSession, error)
 		CreateWorkoutSession(date time.Time, userId int64) (*response.WorkoutSession, error)
-		CreateExercise(sessionId int64, exerciseName string) (*response.Exercise, error)
-		CreateSet(exerciseID int64, setNumber int64, weight float64, reps int64) (*response.Sets, error)
+		CreateExercise(userId int64, sessionId int64, catalogId int64, exerciseName string, modality string) (*response.Exercise, error)
+		CreateSet(userId int64, sessionId int64, exerciseID int64, set form.CreateSet) (*response.Sets, response.PersonalRecords, error)
+		CreateWorkoutLog(date time.Time, userId int64, exercises []form.CreateWorkoutLogExercise) (*response.GetWorkoutSession, error)
+		CreateFromRecommendation(userId int64, date time.Time, recommendationId int64, menu *form.TrainingMenu) (*response.GetWorkoutSession, error)
+		UpdateWorkoutSession(userId int64, id int64, attrs map[string]interface{}) (*response.WorkoutSession, error)
+		StartWorkoutSession(userId int64, id int64, startedAt time.Time) (*response.WorkoutSession, error)
+		FinishWorkoutSession(userId int64, id int64, finishedAt time.Time, attrs map[string]interface{}) (*response.WorkoutSession, error)
+		ReopenWorkoutSession(userId int64, id int64) (*response.WorkoutSession, error)
+		UpdateExercise(userId int64, sessionId int64, exerciseId int64, attrs map[string]interface{}) (*response.Exercise, error)
+		UpdateSet(userId int64, sessionId int64, exerciseId int64, setId int64, attrs map[string]interface{}) (*response.Set, error)
+		ReplaceTargetSets(userId int64, sessionId int64, exerciseId int64, sets []form.TemplateSet) (*response.Exercise, error)
+		DeleteWorkoutSession(userId int64, id int64) error
+		DeleteExercise(userId int64, sessionId int64, exerciseId int64) error
+		DeleteSet(userId int64, sessionId int64, exerciseId int64, setId int64) error
 	}
 
 	// WorkoutImpl ワークアウトのサービスを表す
 	WorkoutImpl struct {
-		WorkoutSession model.WorkoutSession
-		Exercise       model.Exercise
-		Set            model.Set
+		WorkoutSession  model.WorkoutSession
+		Exercise        model.Exercise
+		Set             model.Set
+		TargetSet       model.TargetSet
+		ExerciseCatalog model.ExerciseCatalog
+		PersonalRecord  model.PersonalRecord
+		Recommendation  model.Recommendation
+		Transaction     db.Transactor
+		// 開始・終了日時の指定がない場合の現在日時を返却する。テストで差し替えるため
+		Now func() time.Time
 	}
 )
 
 func NewWorkout() Workout {
 	return &WorkoutImpl{
-		WorkoutSession: model.NewWorkoutSession(),
-		Exercise:       model.NewExercise(),
-		Set:            model.NewSet(),
+		WorkoutSession:  model.NewWorkoutSession(),
+		Exercise:        model.NewExercise(),
+		Set:             model.NewSet(),
+		TargetSet:       model.NewTargetSet(),
+		ExerciseCatalog: model.NewExerciseCatalog(),
+		PersonalRecord:  model.NewPersonalRecord(),
+		Recommendation:  model.NewRecommendation(),
+		Transaction:     db.NewTransactor("training_db"),
+		Now:             time.Now,
 	}
 }
 
-// List ワークアウトの一覧を取得
-func (s *WorkoutImpl) List(id int64, date time.Time) (response.WorkoutSessions, error) {
-	workoutSessions, err := s.WorkoutSession.LoadByIDAndDate(id, date)
+// List ユーザーのワークアウトの一覧を取得し、続きがあれば次ページのカーソルも返却
+// 推定1RMは指定の計算式で計算する
+func (s *WorkoutImpl) List(userId int64, filter model.WorkoutSessionFilter, cursor string, formula metrics.Formula) (response.WorkoutSessions, string, error) {
+	filter.UserID = userId
+	if cursor != "" {
+		afterDate, afterID, err := decodeCursor(filter, cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		filter.AfterDate = afterDate
+		filter.AfterID = afterID
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultListLimit
+	}
+	limit := filter.Limit
+	// 次ページの有無を判定するため1件多く読み込む
+	filter.Limit++
+
+	workoutSessions, err := s.WorkoutSession.LoadByFilter(filter)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	if len(*workoutSessions) == 0 {
+		return nil, "", nil
 	}
 
+	var nextCursor string
+	if uint64(len(*workoutSessions)) > limit {
+		*workoutSessions = (*workoutSessions)[:limit]
+		last := (*workoutSessions)[limit-1]
+		nextCursor = encodeCursor(filter, last.Date, last.ID)
+	}
+
+	// セッション数・エクササイズ数に関わらずクエリ数が一定になるようまとめて読み込む
+	exercises, err := s.Exercise.LoadBySessionIDs(workoutSessions.IDs())
+	if err != nil {
+		return nil, "", err
+	}
+	sets, err := s.Set.LoadByExerciseIDs(exercises.IDs())
+	if err != nil {
+		return nil, "", err
+	}
+	exercisesBySession := exercises.GroupBySessionID()
+	setsByExercise := sets.GroupByExerciseID()
+
 	var responseWorkoutSessions response.WorkoutSessions
 	for _, workoutSession := range *workoutSessions {
-		responseWorkoutSessions = append(responseWorkoutSessions, *response.NewWorkoutSession().WorkoutSessionFromModel(&workoutSession))
+		r := response.NewWorkoutSession().WorkoutSessionFromModel(&workoutSession)
+		if sessionExercises, ok := exercisesBySession[workoutSession.ID]; ok {
+			r.Exercises = exercisesFromModel(sessionExercises, setsByExercise)
+		}
+		responseWorkoutSessions = append(responseWorkoutSessions, *r)
 	}
 
-	return responseWorkoutSessions, nil
+	return responseWorkoutSessions.ApplyFormula(formula), nextCursor, nil
 }
 
-// Get ワークアウトの詳細を取得
-func (s *WorkoutImpl) Get(id int64) (*response.GetWorkoutSession, error) {
-	workoutSession, err := s.WorkoutSession.Load(id)
+// encodeCursor 一覧の最後のレコードからカーソルを生成
+// 別の並び順・絞り込みの一覧に渡されたカーソルを判別できるよう、並び順と絞り込み条件のハッシュも含める
+func encodeCursor(filter model.WorkoutSessionFilter, date time.Time, id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s,%d,%s,%s", date.Format("2006-01-02"), id, cursorOrder(filter), cursorFilterHash(filter))))
+}
+
+// decodeCursor カーソルから最後に返却したレコードの日付とIDを復元
+// カーソルを生成した一覧と並び順・絞り込み条件が異なる場合は不正なカーソルとする
+func decodeCursor(filter model.WorkoutSessionFilter, cursor string) (time.Time, int64, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
 	if err != nil {
-		return nil, err
+		return time.Time{}, 0, fmt.Errorf("invalid cursor: %w", ErrInvalidArgument)
 	}
-	if workoutSession.ID != id || workoutSession.ID == 0 {
-		return nil, fmt.Errorf("workout session not found. id %d", id)
+
+	parts := strings.SplitN(string(b), ",", 4)
+	if len(parts) != 4 {
+		return time.Time{}, 0, fmt.Errorf("invalid cursor: %w", ErrInvalidArgument)
+	}
+
+	date, err := time.Parse("2006-01-02", parts[0])
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid cursor: %w", ErrInvalidArgument)
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || id <= 0 {
+		return time.Time{}, 0, fmt.Errorf("invalid cursor: %w", ErrInvalidArgument)
+	}
+	if parts[2] != cursorOrder(filter) || parts[3] != cursorFilterHash(filter) {
+		return time.Time{}, 0, fmt.Errorf("cursor doesn't match the order or filter: %w", ErrInvalidArgument)
+	}
+	return date, id, nil
+}
+
+// cursorOrder カーソルに含める並び順を返却
+func cursorOrder(filter model.WorkoutSessionFilter) string {
+	if filter.Desc {
+		return "desc"
+	}
+	return "asc"
+}
+
+// cursorFilterHash カーソルに含める絞り込み条件のハッシュを返却
+// 件数とカーソル自身の位置は条件に含めないため、ページごとに件数を変えても同じカーソルを使える
+func cursorFilterHash(filter model.WorkoutSessionFilter) string {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%d|%s|%s|%s|%s|%d|%d|%s",
+		filter.ID,
+		filter.Date.Format("2006-01-02"),
+		filter.From.Format("2006-01-02"),
+		filter.To.Format("2006-01-02"),
+		filter.ExerciseName,
+		filter.TemplateID,
+		filter.EnrollmentID,
+		filter.Status,
+	)
+	return strconv.FormatUint(uint64(h.Sum32()), 16)
+}
+
+// Get ワークアウトの詳細を目標セット・目標との比較とあわせて取得。推定1RMは指定の計算式で計算する
+func (s *WorkoutImpl) Get(userId int64, id int64, formula metrics.Formula) (*response.GetWorkoutSession, error) {
+	workoutSession, err := s.loadWorkoutSession(userId, id)
+	if err != nil {
+		return nil, err
 	}
 
 	exercises, err := s.Exercise.LoadBySessionID(workoutSession.ID)
@@ -64,25 +219,440 @@ func (s *WorkoutImpl) Get(id int64) (*response.GetWorkoutSession, error) {
 		return nil, err
 	}
 
+	sets, err := s.Set.LoadByExerciseIDs(exercises.IDs())
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := s.TargetSet.LoadByExerciseIDs(exercises.IDs())
+	if err != nil {
+		return nil, err
+	}
+
+	setsByExercise := sets.GroupByExerciseID()
+	responseExercises := exercisesFromModel(exercises, setsByExercise)
+	targetsByExercise := targets.GroupByExerciseID()
+	for i, exercise := range *exercises {
+		exerciseTargets, ok := targetsByExercise[exercise.ID]
+		if !ok {
+			continue
+		}
+		responseExercises[i].TargetSets = response.TargetSetsFromModel(exerciseTargets.Targets())
+		responseExercises[i].Comparison = compareTargets(&exercise, exerciseTargets, setsByExercise[exercise.ID])
+	}
+
+	return response.NewGetWorkoutSession().GetWorkoutSessionFromModel(workoutSession, responseExercises).ApplyFormula(formula), nil
+}
+
+// compareTargets エクササイズの目標セットと実施したセットを比較してレスポンスに変換
+func compareTargets(exercise *model.ExerciseImpl, targets *model.TargetSets, sets *model.Sets) *response.TargetComparison {
+	if sets == nil {
+		sets = model.NewSets()
+	}
+	return response.TargetComparisonFromModel(model.CompareTargets(exercise.Modality, targets.Targets(), sets))
+}
+
+// exercisesFromModel まとめて読み込んだセットをエクササイズに紐づけてレスポンスに変換
+func exercisesFromModel(exercises *model.Exercises, setsByExercise map[int64]*model.Sets) response.Exercises {
 	var responseExercises response.Exercises
 	for _, exercise := range *exercises {
-		sets, err := s.Set.LoadByExerciseID(exercise.ID)
+		responseExercises = append(responseExercises, *response.NewExercise().ExerciseFromModel(&exercise, setsByExercise[exercise.ID]))
+	}
+	return responseExercises
+}
+
+// CreateWorkoutSession ワークアウトを下書きとして作成
+func (s *WorkoutImpl) CreateWorkoutSession(date time.Time, userId int64) (*response.WorkoutSession, error) {
+	workoutSession, err := s.WorkoutSession.Create(date, userId, model.SessionStatusDraft)
+	if err != nil {
+		return nil, err
+	}
+
+	workoutSession, err = s.WorkoutSession.Load(workoutSession.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.NewWorkoutSession().WorkoutSessionFromModel(workoutSession), nil
+}
+
+// CreateExercise エクササイズを作成。カタログIDか名前(別名を含む)からカタログの種目に紐づける
+// 記録方法の指定がない場合はカタログの種目の記録方法とする
+func (s *WorkoutImpl) CreateExercise(userId int64, sessionId int64, catalogId int64, exerciseName string, modality string) (*response.Exercise, error) {
+	if _, err := s.loadEditableWorkoutSession(userId, sessionId); err != nil {
+		return nil, err
+	}
+
+	exerciseName, catalogId, catalogModality, err := s.resolveCatalog(catalogId, exerciseName)
+	if err != nil {
+		return nil, err
+	}
+	if modality, err = exerciseModality(modality, catalogModality); err != nil {
+		return nil, err
+	}
+
+	exercise, err := s.Exercise.Create(sessionId, exerciseName, catalogId, modality)
+	if err != nil {
+		return nil, err
+	}
+
+	exercise, err = s.Exercise.Load(exercise.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.NewExercise().ExerciseFromModel(exercise, nil), nil
+}
+
+// CreateSet セットを作成し、更新された自己ベストもあわせて返却
+// 重量はフォームで指定された単位で受け取り、kgに変換して保存する
+func (s *WorkoutImpl) CreateSet(userId int64, sessionId int64, exerciseID int64, f form.CreateSet) (*response.Sets, response.PersonalRecords, error) {
+	detail, err := setDetailFromForm(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	exercise, err := s.loadExercise(userId, sessionId, exerciseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := validateSetMeasure(exercise.Modality, f.Weight, f.Reps, detail); err != nil {
+		return nil, nil, err
+	}
+
+	unit := enteredUnit(f.Unit)
+	var set *model.SetImpl
+	records := model.NewPersonalRecords()
+	err = s.Transaction(func(tx dbr.SessionRunner) error {
+		var err error
+		set, err = s.Set.CreateTx(tx, exerciseID, f.SetNumber, units.ToKilograms(f.Weight, unit), f.Reps, string(unit), detail)
 		if err != nil {
+			return err
+		}
+		if !countsForRecords(exercise.Modality, set) {
+			return nil
+		}
+		records, err = s.refreshPersonalRecordsTx(tx, userId, exercise.Key(), sessionId, set.ID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range *records {
+		(*records)[i].DisplayName = exercise.ExerciseName
+	}
+
+	sets, err := s.Set.LoadByExerciseID(set.ExerciseID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responseSets := response.NewExercise().SetFromModel(sets)
+	responseSets.ApplyModality(exercise.Modality)
+	return responseSets, response.PersonalRecordsFromModel(records), nil
+}
+
+// CreateWorkoutLog セッション・エクササイズ・セットを1トランザクションでまとめて作成
+// 実施後にまとめて記録するため、セッションは実施済みとする
+func (s *WorkoutImpl) CreateWorkoutLog(date time.Time, userId int64, exercises []form.CreateWorkoutLogExercise) (*response.GetWorkoutSession, error) {
+	var workoutSession *model.WorkoutSessionImpl
+	var responseExercises response.Exercises
+
+	// 名前の解決は読み込みのみのためトランザクションの外で済ませる
+	exerciseNames := make([]string, len(exercises))
+	catalogIds := make([]int64, len(exercises))
+	modalities := make([]string, len(exercises))
+	details := make([][]model.SetDetail, len(exercises))
+	for i, e := range exercises {
+		var catalogModality string
+		var err error
+		if exerciseNames[i], catalogIds[i], catalogModality, err = s.resolveCatalog(e.CatalogID, e.ExerciseName); err != nil {
+			return nil, err
+		}
+		if modalities[i], err = exerciseModality(e.Modality, catalogModality); err != nil {
 			return nil, err
 		}
-		responseExercises = append(responseExercises, *response.NewExercise().ExerciseFromModel(&exercise, sets))
+		for _, st := range e.Sets {
+			detail, err := setDetailFromForm(st)
+			if err != nil {
+				return nil, err
+			}
+			if err := validateSetMeasure(modalities[i], st.Weight, st.Reps, detail); err != nil {
+				return nil, fmt.Errorf("%s set %d: %w", exerciseNames[i], st.SetNumber, err)
+			}
+			details[i] = append(details[i], detail)
+		}
+	}
+
+	err := s.Transaction(func(tx dbr.SessionRunner) error {
+		var err error
+		workoutSession, err = s.WorkoutSession.CreateTx(tx, date, userId, model.SessionStatusCompleted)
+		if err != nil {
+			return err
+		}
+
+		for i, e := range exercises {
+			exercise, err := s.Exercise.CreateTx(tx, workoutSession.ID, exerciseNames[i], catalogIds[i], modalities[i])
+			if err != nil {
+				return err
+			}
+
+			sets := model.NewSets()
+			for j, st := range e.Sets {
+				unit := enteredUnit(st.Unit)
+				set, err := s.Set.CreateTx(tx, exercise.ID, st.SetNumber, units.ToKilograms(st.Weight, unit), st.Reps, string(unit), details[i][j])
+				if err != nil {
+					return err
+				}
+				*sets = append(*sets, *set)
+			}
+			responseExercises = append(responseExercises, *response.NewExercise().ExerciseFromModel(exercise, sets))
+		}
+
+		for _, key := range exerciseKeys(exerciseNames, catalogIds) {
+			if _, err := s.refreshPersonalRecordsTx(tx, userId, key, 0, 0); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return response.NewGetWorkoutSession().GetWorkoutSessionFromModel(workoutSession, responseExercises), nil
 }
 
-func (s *WorkoutImpl) CreateWorkoutSession(date time.Time, userId int64) (*response.WorkoutSession, error) {
-	workoutSession, err := s.WorkoutSession.Create(date, userId)
+// CreateFromRecommendation 提案したメニューから下書きのセッションを作成し、種目ごとに目標セットを入れておく
+// メニューは保存した提案のIDか内容のどちらかで指定し、種目名は名前・別名からカタログの種目に紐づける
+// 提案には重量がないため、目標セットは回数のみとする
+func (s *WorkoutImpl) CreateFromRecommendation(userId int64, date time.Time, recommendationId int64, menu *form.TrainingMenu) (*response.GetWorkoutSession, error) {
+	exercises, err := s.recommendedExercises(userId, recommendationId, menu)
 	if err != nil {
 		return nil, err
 	}
+	if err := validateMenuExercises(exercises); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidArgument)
+	}
 
-	workoutSession, err = s.WorkoutSession.Load(workoutSession.ID)
+	// 名前の解決は読み込みのみのためトランザクションの外で済ませる
+	entries := make([]templateEntry, 0, len(exercises))
+	for _, e := range exercises {
+		exerciseName, catalogId, catalogModality, err := s.resolveCatalog(0, e.Name)
+		if err != nil {
+			return nil, err
+		}
+		targets := make([]model.SetTarget, e.Sets)
+		for i := range targets {
+			targets[i] = model.SetTarget{
+				SetNumber: int64(i + 1),
+				SetType:   model.SetTypeWorking,
+				Unit:      string(units.DefaultUnit),
+				Reps:      int64(e.Reps),
+			}
+		}
+		entries = append(entries, templateEntry{
+			exerciseName: exerciseName,
+			catalogId:    catalogId,
+			modality:     model.NormalizeModality(catalogModality),
+			targets:      targets,
+		})
+	}
+
+	return createPlannedWorkout(s.Transaction, s.Exercise, s.TargetSet, entries, func(tx dbr.SessionRunner) (*model.WorkoutSessionImpl, error) {
+		return s.WorkoutSession.CreateTx(tx, date, userId, model.SessionStatusDraft)
+	})
+}
+
+// recommendedExercises 保存した提案、または指定の内容からメニューの種目を返却
+// 他人の提案も存在を知られないようErrNotFoundとする
+func (s *WorkoutImpl) recommendedExercises(userId int64, recommendationId int64, menu *form.TrainingMenu) ([]response.MenuExercise, error) {
+	if recommendationId != 0 && menu != nil {
+		return nil, fmt.Errorf("specify either recommendation_id or menu: %w", ErrInvalidArgument)
+	}
+
+	if recommendationId != 0 {
+		recommendation, err := s.Recommendation.Load(recommendationId)
+		if err != nil {
+			return nil, err
+		}
+		if recommendation.ID == 0 || recommendation.UserID != userId {
+			return nil, fmt.Errorf("recommendation %d: %w", recommendationId, ErrNotFound)
+		}
+		saved := response.NewTrainingMenu()
+		if err := json.Unmarshal([]byte(recommendation.Menu), saved); err != nil {
+			return nil, fmt.Errorf("couldn't read recommendation %d: %w", recommendationId, err)
+		}
+		return saved.Exercises, nil
+	}
+
+	if menu == nil {
+		return nil, fmt.Errorf("recommendation_id or menu is required: %w", ErrInvalidArgument)
+	}
+	exercises := make([]response.MenuExercise, 0, len(menu.Exercises))
+	for _, e := range menu.Exercises {
+		exercises = append(exercises, response.MenuExercise{
+			Name:        e.Name,
+			Sets:        e.Sets,
+			Reps:        e.Reps,
+			RestSeconds: e.RestSeconds,
+			Notes:       e.Notes,
+		})
+	}
+	return exercises, nil
+}
+
+// createPlannedWorkout 下書きのセッションとエクササイズ・目標セットを1トランザクションで作成
+// セッションはcreateSessionで作成し、テンプレートやプログラムの予定の日との紐づけは呼び出し元が決める
+func createPlannedWorkout(transaction db.Transactor, exerciseModel model.Exercise, targetSet model.TargetSet, entries []templateEntry, createSession func(tx dbr.SessionRunner) (*model.WorkoutSessionImpl, error)) (*response.GetWorkoutSession, error) {
+	var workoutSession *model.WorkoutSessionImpl
+	var responseExercises response.Exercises
+	err := transaction(func(tx dbr.SessionRunner) error {
+		var err error
+		workoutSession, err = createSession(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			exercise, err := exerciseModel.CreateTx(tx, workoutSession.ID, entry.exerciseName, entry.catalogId, entry.modality)
+			if err != nil {
+				return err
+			}
+			for _, target := range entry.targets {
+				if _, err := targetSet.CreateTx(tx, exercise.ID, target); err != nil {
+					return err
+				}
+			}
+			r := response.NewExercise().ExerciseFromModel(exercise, nil)
+			r.TargetSets = response.TargetSetsFromModel(entry.targets)
+			responseExercises = append(responseExercises, *r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.NewGetWorkoutSession().GetWorkoutSessionFromModel(workoutSession, responseExercises), nil
+}
+
+// enteredUnit 入力時の単位を返却。指定がない場合は既定の単位とする
+func enteredUnit(unit string) units.Unit {
+	if unit == "" {
+		return units.DefaultUnit
+	}
+	return units.Unit(unit)
+}
+
+// exerciseKeys 名前とカタログIDの組から重複のない種目の一覧を返却
+func exerciseKeys(exerciseNames []string, catalogIds []int64) []model.ExerciseKey {
+	var keys []model.ExerciseKey
+	seen := map[model.ExerciseKey]bool{}
+	for i := range exerciseNames {
+		key := model.ExerciseKey{ExerciseName: exerciseNames[i]}
+		if catalogIds[i] != 0 {
+			key = model.ExerciseKey{CatalogID: catalogIds[i]}
+		}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// UpdateWorkoutSession ワークアウトを更新
+// 実施済みのセッションはメモと評価のみ更新でき、それ以外は再開してから更新する
+func (s *WorkoutImpl) UpdateWorkoutSession(userId int64, id int64, attrs map[string]interface{}) (*response.WorkoutSession, error) {
+	workoutSession, err := s.loadWorkoutSession(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if workoutSession.IsLocked() {
+		for key := range attrs {
+			if !lockedSessionAttrs[key] {
+				return nil, fmt.Errorf("workout session %d is completed. reopen it to update %s: %w", id, key, ErrConflict)
+			}
+		}
+	}
+	if err := validateSessionAttrs(attrs); err != nil {
+		return nil, err
+	}
+
+	return s.updateWorkoutSession(workoutSession, attrs)
+}
+
+// StartWorkoutSession 下書きのセッションを開始する。開始日時の指定がない場合は現在日時とする
+func (s *WorkoutImpl) StartWorkoutSession(userId int64, id int64, startedAt time.Time) (*response.WorkoutSession, error) {
+	workoutSession, err := s.loadWorkoutSession(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if workoutSession.Status != model.SessionStatusDraft {
+		return nil, fmt.Errorf("workout session %d is %s, not %s: %w", id, workoutSession.Status, model.SessionStatusDraft, ErrConflict)
+	}
+	if startedAt.IsZero() {
+		startedAt = s.Now()
+	}
+
+	return s.updateWorkoutSession(workoutSession, map[string]interface{}{
+		"status":     model.SessionStatusInProgress,
+		"started_at": startedAt,
+	})
+}
+
+// FinishWorkoutSession 実施中のセッションを終了し、メモ・評価もあわせて記録する
+// 終了日時の指定がない場合は現在日時とし、開始日時より前は指定できない
+func (s *WorkoutImpl) FinishWorkoutSession(userId int64, id int64, finishedAt time.Time, attrs map[string]interface{}) (*response.WorkoutSession, error) {
+	workoutSession, err := s.loadWorkoutSession(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if workoutSession.Status != model.SessionStatusInProgress {
+		return nil, fmt.Errorf("workout session %d is %s, not %s: %w", id, workoutSession.Status, model.SessionStatusInProgress, ErrConflict)
+	}
+	if finishedAt.IsZero() {
+		finishedAt = s.Now()
+	}
+	if workoutSession.StartedAt.Valid && finishedAt.Before(workoutSession.StartedAt.Time) {
+		return nil, fmt.Errorf("finished_at must not be before started_at: %w", ErrInvalidArgument)
+	}
+	if err := validateSessionAttrs(attrs); err != nil {
+		return nil, err
+	}
+
+	if attrs == nil {
+		attrs = map[string]interface{}{}
+	}
+	attrs["status"] = model.SessionStatusCompleted
+	attrs["finished_at"] = finishedAt
+	return s.updateWorkoutSession(workoutSession, attrs)
+}
+
+// ReopenWorkoutSession 実施済みのセッションを実施中に戻して編集できるようにする
+func (s *WorkoutImpl) ReopenWorkoutSession(userId int64, id int64) (*response.WorkoutSession, error) {
+	workoutSession, err := s.loadWorkoutSession(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if !workoutSession.IsLocked() {
+		return nil, fmt.Errorf("workout session %d is %s, not %s: %w", id, workoutSession.Status, model.SessionStatusCompleted, ErrConflict)
+	}
+
+	return s.updateWorkoutSession(workoutSession, map[string]interface{}{
+		"status":      model.SessionStatusInProgress,
+		"finished_at": nil,
+	})
+}
+
+// updateWorkoutSession セッションを更新し、読み込み直してレスポンスに変換
+func (s *WorkoutImpl) updateWorkoutSession(workoutSession *model.WorkoutSessionImpl, attrs map[string]interface{}) (*response.WorkoutSession, error) {
+	if _, err := s.WorkoutSession.Update(workoutSession.ID, attrs); err != nil {
+		return nil, err
+	}
+
+	workoutSession, err := s.WorkoutSession.Load(workoutSession.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -90,30 +660,634 @@ func (s *WorkoutImpl) CreateWorkoutSession(date time.Time, userId int64) (*respo
 	return response.NewWorkoutSession().WorkoutSessionFromModel(workoutSession), nil
 }
 
-func (s *WorkoutImpl) CreateExercise(sessionId int64, exerciseName string) (*response.Exercise, error) {
-	exercise, err := s.Exercise.Create(sessionId, exerciseName)
+// validateSessionAttrs セッションのメモと評価を検証。評価はnilの指定で消去する
+func validateSessionAttrs(attrs map[string]interface{}) error {
+	if v, ok := attrs["notes"].(string); ok {
+		notes := strings.TrimSpace(v)
+		if utf8.RuneCountInString(notes) > maxSessionNotesLength {
+			return fmt.Errorf("notes must be at most %d characters: %w", maxSessionNotesLength, ErrInvalidArgument)
+		}
+		attrs["notes"] = notes
+	}
+	if v, ok := attrs["rating"].(int64); ok && (v < minSessionRating || v > maxSessionRating) {
+		return fmt.Errorf("rating must be between %d and %d: %w", minSessionRating, maxSessionRating, ErrInvalidArgument)
+	}
+	return nil
+}
+
+// UpdateExercise エクササイズを更新
+func (s *WorkoutImpl) UpdateExercise(userId int64, sessionId int64, exerciseId int64, attrs map[string]interface{}) (*response.Exercise, error) {
+	exercise, err := s.loadExercise(userId, sessionId, exerciseId)
 	if err != nil {
 		return nil, err
 	}
 
-	exercise, err = s.Exercise.Load(exercise.ID)
+	// 名前やカタログIDが変わる場合は紐づくカタログの種目も付け直す
+	previousKey, key := exercise.Key(), exercise.Key()
+	catalogId, hasCatalogId := attrs["catalog_id"].(int64)
+	exerciseName, hasExerciseName := attrs["exercise_name"].(string)
+	if hasCatalogId || hasExerciseName {
+		if !hasCatalogId && exerciseName == exercise.ExerciseName {
+			catalogId = exercise.CatalogID.Int64
+		}
+		if hasCatalogId && !hasExerciseName {
+			exerciseName = exercise.ExerciseName
+		}
+		if exerciseName, catalogId, _, err = s.resolveCatalog(catalogId, exerciseName); err != nil {
+			return nil, err
+		}
+		attrs["exercise_name"] = exerciseName
+		attrs["catalog_id"] = nil
+		key = model.ExerciseKey{ExerciseName: exerciseName}
+		if catalogId != 0 {
+			attrs["catalog_id"] = catalogId
+			key = model.ExerciseKey{CatalogID: catalogId}
+		}
+	}
+
+	// 記録方法によって自己ベストの集計対象が変わるため、記録方法の変更も集計し直す対象とする
+	modalityChanged := false
+	if v, ok := attrs["modality"].(string); ok {
+		if err := validateModality(v); err != nil {
+			return nil, err
+		}
+		modalityChanged = model.NormalizeModality(v) != model.NormalizeModality(exercise.Modality)
+	}
+
+	// 種目が変わった場合は変更前後の両方の自己ベストを、記録方法のみ変わった場合はその種目の自己ベストを集計し直す
+	var refreshKeys []model.ExerciseKey
+	if key != previousKey {
+		refreshKeys = []model.ExerciseKey{previousKey, key}
+	} else if modalityChanged {
+		refreshKeys = []model.ExerciseKey{key}
+	}
+
+	err = s.Transaction(func(tx dbr.SessionRunner) error {
+		if _, err := s.Exercise.UpdateTx(tx, exercise.ID, attrs); err != nil {
+			return err
+		}
+		for _, k := range refreshKeys {
+			if _, err := s.refreshPersonalRecordsTx(tx, userId, k, 0, 0); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return response.NewExercise().ExerciseFromModel(exercise, nil), nil
+	exercise, err = s.Exercise.Load(exerciseId)
+	if err != nil {
+		return nil, err
+	}
+
+	sets, err := s.Set.LoadByExerciseID(exercise.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.NewExercise().ExerciseFromModel(exercise, sets), nil
 }
 
-func (s *WorkoutImpl) CreateSet(exerciseID int64, setNumber int64, weight float64, reps int64) (*response.Sets, error) {
-	set, err := s.Set.Create(exerciseID, setNumber, weight, reps)
+// UpdateSet セットを更新
+func (s *WorkoutImpl) UpdateSet(userId int64, sessionId int64, exerciseId int64, setId int64, attrs map[string]interface{}) (*response.Set, error) {
+	exercise, set, err := s.loadSet(userId, sessionId, exerciseId, setId)
 	if err != nil {
 		return nil, err
 	}
 
-	sets, err := s.Set.LoadByExerciseID(set.ExerciseID)
+	if err := validateSetAttrs(attrs); err != nil {
+		return nil, err
+	}
+	weight, reps, detail := mergedSetMeasure(set, attrs)
+	if err := validateSetMeasure(exercise.Modality, weight, reps, detail); err != nil {
+		return nil, err
+	}
+
+	// 重量は"unit"で指定された単位からkgに変換し、入力時の単位とあわせて保存する
+	// 保存済みの重量の単位だけを変えると重量の意味が変わるため、単位は重量とあわせて指定させる
+	if _, ok := attrs["unit"]; ok {
+		if _, ok := attrs["weight"]; !ok {
+			return nil, fmt.Errorf("unit requires weight: %w", ErrInvalidArgument)
+		}
+	}
+	unit, _ := attrs["unit"].(string)
+	delete(attrs, "unit")
+	if weight, ok := attrs["weight"].(float64); ok {
+		attrs["weight"] = units.ToKilograms(weight, enteredUnit(unit))
+		attrs["unit"] = string(enteredUnit(unit))
+	}
+
+	// 変更前後のどちらかが自己ベストの集計対象で、集計に使う項目が変わる場合のみ集計し直す
+	updated := *set
+	updated.Weight, updated.Reps, updated.SetDetail = weight, reps, detail
+	if v, ok := attrs["set_type"].(string); ok {
+		updated.SetType = v
+	}
+	refresh := false
+	for key := range attrs {
+		if recordSetAttrs[key] {
+			refresh = countsForRecords(exercise.Modality, set) || countsForRecords(exercise.Modality, &updated)
+			break
+		}
+	}
+
+	err = s.Transaction(func(tx dbr.SessionRunner) error {
+		if _, err := s.Set.UpdateTx(tx, set.ID, attrs); err != nil {
+			return err
+		}
+		if !refresh {
+			return nil
+		}
+		_, err := s.refreshPersonalRecordsTx(tx, userId, exercise.Key(), 0, 0)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	set, err = s.Set.Load(setId)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.NewSet().SetFromModel(set).ApplyModality(exercise.Modality), nil
+}
+
+// ReplaceTargetSets エクササイズの目標セットをまとめて置き換え、目標との比較とあわせて返却
+// 重量はフォームで指定された単位で受け取り、kgに変換して保存する。空の場合は目標セットを削除する
+func (s *WorkoutImpl) ReplaceTargetSets(userId int64, sessionId int64, exerciseId int64, sets []form.TemplateSet) (*response.Exercise, error) {
+	exercise, err := s.loadExercise(userId, sessionId, exerciseId)
 	if err != nil {
 		return nil, err
 	}
 
-	return response.NewExercise().SetFromModel(sets), nil
+	targets := make([]model.SetTarget, 0, len(sets))
+	seen := map[int64]bool{}
+	for _, f := range sets {
+		target, err := targetFromForm(f)
+		if err != nil {
+			return nil, err
+		}
+		if seen[target.SetNumber] {
+			return nil, fmt.Errorf("set_number %d is duplicated: %w", target.SetNumber, ErrInvalidArgument)
+		}
+		seen[target.SetNumber] = true
+		targets = append(targets, target)
+	}
+
+	err = s.Transaction(func(tx dbr.SessionRunner) error {
+		if _, err := s.TargetSet.DeleteByExerciseIDTx(tx, exerciseId); err != nil {
+			return err
+		}
+		for _, target := range targets {
+			if _, err := s.TargetSet.CreateTx(tx, exerciseId, target); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exerciseSets, err := s.Set.LoadByExerciseID(exerciseId)
+	if err != nil {
+		return nil, err
+	}
+	exerciseTargets, err := s.TargetSet.LoadByExerciseIDs([]int64{exerciseId})
+	if err != nil {
+		return nil, err
+	}
+
+	r := response.NewExercise().ExerciseFromModel(exercise, exerciseSets)
+	if len(*exerciseTargets) > 0 {
+		r.TargetSets = response.TargetSetsFromModel(exerciseTargets.Targets())
+		r.Comparison = compareTargets(exercise, exerciseTargets, exerciseSets)
+	}
+	return r, nil
+}
+
+// DeleteWorkoutSession ワークアウトを配下のエクササイズ・セット・目標セットごと削除
+func (s *WorkoutImpl) DeleteWorkoutSession(userId int64, id int64) error {
+	if _, err := s.loadEditableWorkoutSession(userId, id); err != nil {
+		return err
+	}
+
+	exercises, err := s.Exercise.LoadBySessionID(id)
+	if err != nil {
+		return err
+	}
+
+	return s.Transaction(func(tx dbr.SessionRunner) error {
+		if _, err := s.Set.DeleteBySessionIDTx(tx, id); err != nil {
+			return err
+		}
+		if _, err := s.TargetSet.DeleteBySessionIDTx(tx, id); err != nil {
+			return err
+		}
+		if _, err := s.Exercise.DeleteBySessionIDTx(tx, id); err != nil {
+			return err
+		}
+		if _, err := s.WorkoutSession.DeleteTx(tx, id); err != nil {
+			return err
+		}
+
+		seen := map[model.ExerciseKey]bool{}
+		for _, exercise := range *exercises {
+			key := exercise.Key()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if _, err := s.refreshPersonalRecordsTx(tx, userId, key, 0, 0); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteExercise エクササイズを配下のセット・目標セットごと削除
+func (s *WorkoutImpl) DeleteExercise(userId int64, sessionId int64, exerciseId int64) error {
+	exercise, err := s.loadExercise(userId, sessionId, exerciseId)
+	if err != nil {
+		return err
+	}
+
+	return s.Transaction(func(tx dbr.SessionRunner) error {
+		if _, err := s.Set.DeleteByExerciseIDTx(tx, exerciseId); err != nil {
+			return err
+		}
+		if _, err := s.TargetSet.DeleteByExerciseIDTx(tx, exerciseId); err != nil {
+			return err
+		}
+		if _, err := s.Exercise.DeleteTx(tx, exerciseId); err != nil {
+			return err
+		}
+		_, err := s.refreshPersonalRecordsTx(tx, userId, exercise.Key(), 0, 0)
+		return err
+	})
+}
+
+// DeleteSet セットを削除
+func (s *WorkoutImpl) DeleteSet(userId int64, sessionId int64, exerciseId int64, setId int64) error {
+	exercise, set, err := s.loadSet(userId, sessionId, exerciseId, setId)
+	if err != nil {
+		return err
+	}
+
+	return s.Transaction(func(tx dbr.SessionRunner) error {
+		if _, err := s.Set.DeleteTx(tx, setId); err != nil {
+			return err
+		}
+		// 集計対象でないセットは自己ベストに影響しない
+		if !countsForRecords(exercise.Modality, set) {
+			return nil
+		}
+		_, err := s.refreshPersonalRecordsTx(tx, userId, exercise.Key(), 0, 0)
+		return err
+	})
+}
+
+// refreshPersonalRecordsTx トランザクション内で種目の自己ベストを集計し直し、指定のセットで更新された自己ベストを返却
+func (s *WorkoutImpl) refreshPersonalRecordsTx(tx dbr.SessionRunner, userId int64, key model.ExerciseKey, sessionId int64, setId int64) (*model.PersonalRecords, error) {
+	return refreshPersonalRecordsTx(s.Set, s.PersonalRecord, tx, userId, key, sessionId, setId)
+}
+
+// resolveCatalog エクササイズ名とカタログIDを解決
+// カタログIDの指定があればその種目に紐づけ、名前が空ならカタログの日本語名を使う。
+// カタログIDの指定がなければ名前・別名から種目を探し、見つからなければ自由入力の名前として扱う
+// 紐づいたカタログの記録方法もあわせて返却し、紐づかない場合は空文字とする
+func (s *WorkoutImpl) resolveCatalog(catalogId int64, exerciseName string) (string, int64, string, error) {
+	return resolveCatalog(s.ExerciseCatalog, catalogId, exerciseName)
+}
+
+// resolveCatalog テンプレートなどワークアウト以外のサービスからもカタログの種目を解決できるようにする
+func resolveCatalog(exerciseCatalog model.ExerciseCatalog, catalogId int64, exerciseName string) (string, int64, string, error) {
+	exerciseName = strings.TrimSpace(exerciseName)
+
+	if catalogId != 0 {
+		catalog, err := exerciseCatalog.Load(catalogId)
+		if err != nil {
+			return "", 0, "", err
+		}
+		if catalog.ID == 0 {
+			return "", 0, "", fmt.Errorf("catalog %d: %w", catalogId, ErrInvalidArgument)
+		}
+		if exerciseName == "" {
+			exerciseName = catalog.NameJa
+		}
+		return exerciseName, catalog.ID, catalog.Modality, nil
+	}
+
+	if exerciseName == "" {
+		return "", 0, "", fmt.Errorf("exercise_name or catalog_id is required: %w", ErrInvalidArgument)
+	}
+
+	catalog, err := exerciseCatalog.Resolve(exerciseName)
+	if err != nil {
+		return "", 0, "", err
+	}
+	return exerciseName, catalog.ID, catalog.Modality, nil
+}
+
+// exerciseModality エクササイズの記録方法を決める
+// 指定がなければカタログの記録方法とし、カタログにも紐づかない場合はウェイト種目とする
+func exerciseModality(requested string, catalogModality string) (string, error) {
+	if requested == "" {
+		return model.NormalizeModality(catalogModality), nil
+	}
+	if err := validateModality(requested); err != nil {
+		return "", err
+	}
+	return requested, nil
+}
+
+func validateModality(modality string) error {
+	for _, m := range model.Modalities {
+		if m == modality {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown modality %q: %w", modality, ErrInvalidArgument)
+}
+
+// loadWorkoutSession ユーザーのワークアウトを読み込み、存在しなければErrNotFoundを返却
+// 他人のワークアウトも存在を知られないようErrNotFoundとする
+func (s *WorkoutImpl) loadWorkoutSession(userId int64, id int64) (*model.WorkoutSessionImpl, error) {
+	workoutSession, err := s.WorkoutSession.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if workoutSession.ID != id || workoutSession.ID == 0 || workoutSession.UserID != userId {
+		return nil, fmt.Errorf("workout session not found. id %d: %w", id, ErrNotFound)
+	}
+	return workoutSession, nil
+}
+
+// loadEditableWorkoutSession 編集するワークアウトを読み込み、実施済みの場合はErrConflictを返却
+func (s *WorkoutImpl) loadEditableWorkoutSession(userId int64, id int64) (*model.WorkoutSessionImpl, error) {
+	workoutSession, err := s.loadWorkoutSession(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if workoutSession.IsLocked() {
+		return nil, fmt.Errorf("workout session %d is completed. reopen it to edit: %w", id, ErrConflict)
+	}
+	return workoutSession, nil
+}
+
+// loadExercise 編集するユーザーのセッション配下のエクササイズを読み込み
+func (s *WorkoutImpl) loadExercise(userId int64, sessionId int64, exerciseId int64) (*model.ExerciseImpl, error) {
+	if _, err := s.loadEditableWorkoutSession(userId, sessionId); err != nil {
+		return nil, err
+	}
+
+	exercise, err := s.Exercise.Load(exerciseId)
+	if err != nil {
+		return nil, err
+	}
+	if exercise.ID != exerciseId || exercise.ID == 0 || exercise.SessionID != sessionId {
+		return nil, fmt.Errorf("exercise not found. session_id %d exercise_id %d: %w", sessionId, exerciseId, ErrNotFound)
+	}
+	return exercise, nil
+}
+
+// loadSet エクササイズ配下のセットを読み込み、親のエクササイズとあわせて返却
+func (s *WorkoutImpl) loadSet(userId int64, sessionId int64, exerciseId int64, setId int64) (*model.ExerciseImpl, *model.SetImpl, error) {
+	exercise, err := s.loadExercise(userId, sessionId, exerciseId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	set, err := s.Set.Load(setId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if set.ID != setId || set.ID == 0 || set.ExerciseID != exerciseId {
+		return nil, nil, fmt.Errorf("set not found. exercise_id %d set_id %d: %w", exerciseId, setId, ErrNotFound)
+	}
+	return exercise, set, nil
+}
+
+// tempoPattern エキセントリック・ボトム・コンセントリック・トップの秒数を表す4桁のテンポ。Xは爆発的に行うことを表す
+var tempoPattern = regexp.MustCompile(`^[0-9X]{4}$`)
+
+// setDetailFromForm フォームで指定されたセットの記録を検証し、保存する形式に変換
+func setDetailFromForm(f form.CreateSet) (model.SetDetail, error) {
+	detail := model.SetDetail{
+		SetType: f.SetType,
+		Notes:   strings.TrimSpace(f.Notes),
+	}
+	if f.DurationSeconds != nil {
+		detail.DurationSeconds = dbr.NewNullInt64(*f.DurationSeconds)
+	}
+	if f.DistanceMeters != nil {
+		detail.DistanceMeters = dbr.NewNullFloat64(*f.DistanceMeters)
+	}
+	if detail.SetType == "" {
+		detail.SetType = model.SetTypeWorking
+	}
+	if err := validateSetType(detail.SetType); err != nil {
+		return detail, err
+	}
+
+	if f.RPE != nil && f.RIR != nil {
+		return detail, fmt.Errorf("specify either rpe or rir: %w", ErrInvalidArgument)
+	}
+	if f.RPE != nil {
+		if err := validateRPE(*f.RPE); err != nil {
+			return detail, err
+		}
+		detail.RPE = dbr.NewNullFloat64(*f.RPE)
+	}
+	if f.RIR != nil {
+		if err := validateRIR(*f.RIR); err != nil {
+			return detail, err
+		}
+		detail.RIR = dbr.NewNullInt64(*f.RIR)
+	}
+	if f.RestSeconds != nil {
+		if err := validateRestSeconds(*f.RestSeconds); err != nil {
+			return detail, err
+		}
+		detail.RestSeconds = dbr.NewNullInt64(*f.RestSeconds)
+	}
+
+	tempo, err := normalizeTempo(f.Tempo)
+	if err != nil {
+		return detail, err
+	}
+	detail.Tempo = tempo
+
+	if f.PerformedAt != "" {
+		performedAt, err := time.Parse(time.RFC3339, f.PerformedAt)
+		if err != nil {
+			return detail, fmt.Errorf("invalid performed_at %q: %w", f.PerformedAt, ErrInvalidArgument)
+		}
+		detail.PerformedAt = dbr.NewNullTime(performedAt)
+	}
+	return detail, nil
+}
+
+// validateSetAttrs セットの更新内容のうち、セットの記録を検証
+// RPEとRIRはどちらか一方のみを記録するため、一方を更新する場合はもう一方を消去する
+func validateSetAttrs(attrs map[string]interface{}) error {
+	if v, ok := attrs["set_type"].(string); ok {
+		if err := validateSetType(v); err != nil {
+			return err
+		}
+	}
+
+	rpe, hasRPE := attrs["rpe"].(float64)
+	rir, hasRIR := attrs["rir"].(int64)
+	if hasRPE && hasRIR {
+		return fmt.Errorf("specify either rpe or rir: %w", ErrInvalidArgument)
+	}
+	if hasRPE {
+		if err := validateRPE(rpe); err != nil {
+			return err
+		}
+		attrs["rir"] = nil
+	}
+	if hasRIR {
+		if err := validateRIR(rir); err != nil {
+			return err
+		}
+		attrs["rpe"] = nil
+	}
+
+	if v, ok := attrs["rest_seconds"].(int64); ok {
+		if err := validateRestSeconds(v); err != nil {
+			return err
+		}
+	}
+	if v, ok := attrs["tempo"].(string); ok {
+		tempo, err := normalizeTempo(v)
+		if err != nil {
+			return err
+		}
+		attrs["tempo"] = tempo
+	}
+	if v, ok := attrs["notes"].(string); ok {
+		notes := strings.TrimSpace(v)
+		if utf8.RuneCountInString(notes) > maxSetNotesLength {
+			return fmt.Errorf("notes must be at most %d characters: %w", maxSetNotesLength, ErrInvalidArgument)
+		}
+		attrs["notes"] = notes
+	}
+	return nil
+}
+
+// validateSetMeasure 重量・回数・時間・距離をエクササイズの記録方法に応じて検証
+// 自重種目・時間の種目の重量は加重のため0を許し、アシスト種目はアシスト重量を必須とする
+func validateSetMeasure(modality string, weight float64, reps int64, detail model.SetDetail) error {
+	if weight < 0 || reps < 0 {
+		return fmt.Errorf("weight and reps must not be negative: %w", ErrInvalidArgument)
+	}
+	if detail.DurationSeconds.Valid && detail.DurationSeconds.Int64 <= 0 {
+		return fmt.Errorf("duration_seconds must be positive: %w", ErrInvalidArgument)
+	}
+	if detail.DistanceMeters.Valid && detail.DistanceMeters.Float64 <= 0 {
+		return fmt.Errorf("distance_meters must be positive: %w", ErrInvalidArgument)
+	}
+
+	modality = model.NormalizeModality(modality)
+	if modality != model.ModalityDistance && detail.DistanceMeters.Valid {
+		return fmt.Errorf("distance_meters is only for %s exercises: %w", model.ModalityDistance, ErrInvalidArgument)
+	}
+	switch modality {
+	case model.ModalityWeighted, model.ModalityAssisted:
+		if weight == 0 || reps == 0 {
+			return fmt.Errorf("weight and reps are required for %s exercises: %w", modality, ErrInvalidArgument)
+		}
+	case model.ModalityBodyweight:
+		if reps == 0 {
+			return fmt.Errorf("reps is required for %s exercises: %w", modality, ErrInvalidArgument)
+		}
+	case model.ModalityTimed:
+		if !detail.DurationSeconds.Valid {
+			return fmt.Errorf("duration_seconds is required for %s exercises: %w", modality, ErrInvalidArgument)
+		}
+	case model.ModalityDistance:
+		if weight != 0 {
+			return fmt.Errorf("weight is not recorded for %s exercises: %w", modality, ErrInvalidArgument)
+		}
+		if !detail.DistanceMeters.Valid && !detail.DurationSeconds.Valid {
+			return fmt.Errorf("distance_meters or duration_seconds is required for %s exercises: %w", modality, ErrInvalidArgument)
+		}
+	default:
+		return validateModality(modality)
+	}
+	return nil
+}
+
+// mergedSetMeasure 更新内容を反映したセットの重量・回数・時間・距離を返却
+// 時間・距離はnilの指定で消去されるため未記録として扱う
+func mergedSetMeasure(set *model.SetImpl, attrs map[string]interface{}) (float64, int64, model.SetDetail) {
+	weight, reps, detail := set.Weight, set.Reps, set.SetDetail
+	if v, ok := attrs["weight"].(float64); ok {
+		weight = v
+	}
+	if v, ok := attrs["reps"].(int64); ok {
+		reps = v
+	}
+	if v, ok := attrs["duration_seconds"]; ok {
+		detail.DurationSeconds = dbr.NullInt64{}
+		if durationSeconds, ok := v.(int64); ok {
+			detail.DurationSeconds = dbr.NewNullInt64(durationSeconds)
+		}
+	}
+	if v, ok := attrs["distance_meters"]; ok {
+		detail.DistanceMeters = dbr.NullFloat64{}
+		if distanceMeters, ok := v.(float64); ok {
+			detail.DistanceMeters = dbr.NewNullFloat64(distanceMeters)
+		}
+	}
+	return weight, reps, detail
+}
+
+func validateSetType(setType string) error {
+	for _, t := range model.SetTypes {
+		if t == setType {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown set_type %q: %w", setType, ErrInvalidArgument)
+}
+
+// validateRPE RPEは1〜10を0.5刻みで記録する
+func validateRPE(rpe float64) error {
+	if rpe < 1 || rpe > 10 || rpe*2 != math.Trunc(rpe*2) {
+		return fmt.Errorf("rpe must be between 1 and 10 in steps of 0.5: %w", ErrInvalidArgument)
+	}
+	return nil
+}
+
+func validateRIR(rir int64) error {
+	if rir < 0 || rir > 10 {
+		return fmt.Errorf("rir must be between 0 and 10: %w", ErrInvalidArgument)
+	}
+	return nil
+}
+
+func validateRestSeconds(restSeconds int64) error {
+	if restSeconds < 0 {
+		return fmt.Errorf("rest_seconds must not be negative: %w", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// normalizeTempo 区切り文字を除いた大文字の4桁に揃える。空文字は未指定として扱う
+func normalizeTempo(tempo string) (string, error) {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", ":", "", "/", "", " ", "").Replace(tempo))
+	if normalized == "" {
+		return "", nil
+	}
+	if !tempoPattern.MatchString(normalized) {
+		return "", fmt.Errorf("invalid tempo %q: %w", tempo, ErrInvalidArgument)
+	}
+	return normalized, nil
 }
@@ -2,12 +2,17 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/form"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/metrics"
 	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model"
 	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/model/mock_model"
 	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/response"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+	"github.com/gocraft/dbr/v2"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -16,115 +21,231 @@ func TestWorkoutList(t *testing.T) {
 	t.Parallel()
 	type fields struct {
 		WorkoutSession model.WorkoutSession
+		Exercise       model.Exercise
+		Set            model.Set
 	}
 	type args struct {
-		id   int64
-		date time.Time
+		filter model.WorkoutSessionFilter
+		cursor string
 	}
 	tests := []struct {
 		testCase  string
 		args      args
 		fields    func(ctrl *gomock.Controller) fields
-		assertion func(r response.WorkoutSessions, err error)
+		assertion func(r response.WorkoutSessions, nextCursor string, err error)
 	}{
 		{
 			testCase: "正常系",
 			args: args{
-				id:   int64(0),
-				date: time.Time{},
+				filter: model.WorkoutSessionFilter{Desc: true},
 			},
 			fields: func(ctrl *gomock.Controller) fields {
 				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
-				WorkoutSession.EXPECT().LoadByIDAndDate(int64(0), time.Time{}).Return(&model.WorkoutSessions{
+				WorkoutSession.EXPECT().LoadByFilter(model.WorkoutSessionFilter{UserID: int64(1), Desc: true, Limit: 21}).Return(&model.WorkoutSessions{
 					{ID: int64(1), Date: time.Now(), UserID: int64(1)},
 					{ID: int64(2), Date: time.Now(), UserID: int64(1)},
 					{ID: int64(3), Date: time.Now(), UserID: int64(1)},
 				}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().LoadBySessionIDs([]int64{1, 2, 3}).Return(&model.Exercises{
+					{ID: int64(1), SessionID: int64(1), ExerciseName: "test"},
+					{ID: int64(2), SessionID: int64(3), ExerciseName: "test"},
+				}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadByExerciseIDs([]int64{1, 2}).Return(&model.Sets{
+					{ID: int64(1), ExerciseID: int64(1), SetNumber: int64(1), Weight: float64(10), Reps: int64(10)},
+					{ID: int64(2), ExerciseID: int64(2), SetNumber: int64(1), Weight: float64(10), Reps: int64(10)},
+					{ID: int64(3), ExerciseID: int64(2), SetNumber: int64(2), Weight: float64(10), Reps: int64(10)},
+				}, nil)
 				return fields{
 					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
 				}
 			},
-			assertion: func(r response.WorkoutSessions, err error) {
+			assertion: func(r response.WorkoutSessions, nextCursor string, err error) {
 				assert.NoError(t, err)
 				assert.NotNil(t, r)
 				assert.Len(t, r, 3)
+				assert.Len(t, r[0].Exercises, 1)
+				assert.Len(t, r[0].Exercises[0].Sets, 1)
+				assert.Len(t, r[1].Exercises, 0)
+				assert.Len(t, r[2].Exercises, 1)
+				assert.Len(t, r[2].Exercises[0].Sets, 2)
+				assert.Empty(t, nextCursor)
 			},
 		},
 		{
-			testCase: "正常系(1件取得id指定)",
+			testCase: "正常系(期間・ユーザー・種目指定)",
 			args: args{
-				id:   int64(1),
-				date: time.Time{},
+				filter: model.WorkoutSessionFilter{
+					From:         time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+					To:           time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC),
+					UserID:       int64(1),
+					ExerciseName: "ベンチプレス",
+				},
 			},
 			fields: func(ctrl *gomock.Controller) fields {
 				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
-				WorkoutSession.EXPECT().LoadByIDAndDate(int64(1), time.Time{}).Return(&model.WorkoutSessions{
-					{ID: int64(1), Date: time.Now(), UserID: int64(1)},
+				WorkoutSession.EXPECT().LoadByFilter(model.WorkoutSessionFilter{
+					From:         time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+					To:           time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC),
+					UserID:       int64(1),
+					ExerciseName: "ベンチプレス",
+					Limit:        21,
+				}).Return(&model.WorkoutSessions{
+					{ID: int64(1), Date: time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC), UserID: int64(1)},
 				}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().LoadBySessionIDs([]int64{1}).Return(&model.Exercises{}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadByExerciseIDs([]int64{}).Return(&model.Sets{}, nil)
 				return fields{
 					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
 				}
 			},
-			assertion: func(r response.WorkoutSessions, err error) {
+			assertion: func(r response.WorkoutSessions, nextCursor string, err error) {
 				assert.NoError(t, err)
-				assert.NotNil(t, r)
 				assert.Len(t, r, 1)
+				assert.Empty(t, nextCursor)
 			},
 		},
 		{
-			testCase: "正常系(1件取得日付指定)",
+			testCase: "正常系(次ページあり)",
 			args: args{
-				id:   int64(0),
-				date: time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC),
+				filter: model.WorkoutSessionFilter{Desc: true, Limit: 2},
 			},
 			fields: func(ctrl *gomock.Controller) fields {
 				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
-				WorkoutSession.EXPECT().LoadByIDAndDate(int64(0), time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC)).Return(&model.WorkoutSessions{
-					{ID: int64(1), Date: time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC), UserID: int64(1)},
+				WorkoutSession.EXPECT().LoadByFilter(model.WorkoutSessionFilter{UserID: int64(1), Desc: true, Limit: 3}).Return(&model.WorkoutSessions{
+					{ID: int64(3), Date: time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC), UserID: int64(1)},
+					{ID: int64(2), Date: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), UserID: int64(1)},
+					{ID: int64(1), Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), UserID: int64(1)},
 				}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().LoadBySessionIDs([]int64{3, 2}).Return(&model.Exercises{}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadByExerciseIDs([]int64{}).Return(&model.Sets{}, nil)
 				return fields{
 					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
 				}
 			},
-			assertion: func(r response.WorkoutSessions, err error) {
+			assertion: func(r response.WorkoutSessions, nextCursor string, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, r, 2)
+				assert.Equal(t, encodeCursor(model.WorkoutSessionFilter{Desc: true}, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), int64(2)), nextCursor)
+			},
+		},
+		{
+			testCase: "正常系(カーソル指定)",
+			args: args{
+				filter: model.WorkoutSessionFilter{Desc: true, Limit: 2},
+				cursor: encodeCursor(model.WorkoutSessionFilter{Desc: true}, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), int64(2)),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().LoadByFilter(model.WorkoutSessionFilter{
+					UserID:    int64(1),
+					Desc:      true,
+					Limit:     3,
+					AfterDate: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+					AfterID:   int64(2),
+				}).Return(&model.WorkoutSessions{
+					{ID: int64(1), Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), UserID: int64(1)},
+				}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().LoadBySessionIDs([]int64{1}).Return(&model.Exercises{}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadByExerciseIDs([]int64{}).Return(&model.Sets{}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
+				}
+			},
+			assertion: func(r response.WorkoutSessions, nextCursor string, err error) {
 				assert.NoError(t, err)
-				assert.NotNil(t, r)
 				assert.Len(t, r, 1)
+				assert.Empty(t, nextCursor)
 			},
 		},
 		{
 			testCase: "正常系(空)",
 			args: args{
-				id:   int64(100),
-				date: time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC),
+				filter: model.WorkoutSessionFilter{ID: int64(100)},
 			},
 			fields: func(ctrl *gomock.Controller) fields {
 				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
-				WorkoutSession.EXPECT().LoadByIDAndDate(int64(100), time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC)).Return(&model.WorkoutSessions{}, nil)
+				WorkoutSession.EXPECT().LoadByFilter(model.WorkoutSessionFilter{ID: int64(100), UserID: int64(1), Limit: 21}).Return(&model.WorkoutSessions{}, nil)
 				return fields{
 					WorkoutSession: WorkoutSession,
 				}
 			},
-			assertion: func(r response.WorkoutSessions, err error) {
+			assertion: func(r response.WorkoutSessions, nextCursor string, err error) {
 				assert.NoError(t, err)
 				assert.Nil(t, r)
 				assert.Len(t, r, 0)
+				assert.Empty(t, nextCursor)
+			},
+		},
+		{
+			testCase: "エラー(不正なカーソル)",
+			args: args{
+				cursor: "invalid",
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r response.WorkoutSessions, nextCursor string, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(並び順が異なる一覧のカーソル)",
+			args: args{
+				filter: model.WorkoutSessionFilter{Desc: false},
+				cursor: encodeCursor(model.WorkoutSessionFilter{Desc: true}, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), int64(2)),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r response.WorkoutSessions, nextCursor string, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(絞り込み条件が異なる一覧のカーソル)",
+			args: args{
+				filter: model.WorkoutSessionFilter{Desc: true, ExerciseName: "スクワット"},
+				cursor: encodeCursor(model.WorkoutSessionFilter{Desc: true, ExerciseName: "ベンチプレス"}, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), int64(2)),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r response.WorkoutSessions, nextCursor string, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
 			},
 		},
 		{
 			testCase: "エラー",
 			args: args{
-				id:   int64(100),
-				date: time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC),
+				filter: model.WorkoutSessionFilter{ID: int64(100)},
 			},
 			fields: func(ctrl *gomock.Controller) fields {
 				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
-				WorkoutSession.EXPECT().LoadByIDAndDate(int64(100), time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC)).Return(nil, errors.New("couldn't load workout_sessions"))
+				WorkoutSession.EXPECT().LoadByFilter(model.WorkoutSessionFilter{ID: int64(100), UserID: int64(1), Limit: 21}).Return(nil, errors.New("couldn't load workout_sessions"))
 				return fields{
 					WorkoutSession: WorkoutSession,
 				}
 			},
-			assertion: func(r response.WorkoutSessions, err error) {
+			assertion: func(r response.WorkoutSessions, nextCursor string, err error) {
 				assert.Error(t, err)
 				assert.Nil(t, r)
 			},
@@ -138,8 +259,10 @@ func TestWorkoutList(t *testing.T) {
 			fields := tt.fields(ctrl)
 			w := &WorkoutImpl{
 				WorkoutSession: fields.WorkoutSession,
+				Exercise:       fields.Exercise,
+				Set:            fields.Set,
 			}
-			tt.assertion(w.List(tt.args.id, tt.args.date))
+			tt.assertion(w.List(int64(1), tt.args.filter, tt.args.cursor, metrics.DefaultFormula))
 		})
 	}
 }
@@ -150,9 +273,11 @@ func TestWorkoutGet(t *testing.T) {
 		WorkoutSession model.WorkoutSession
 		Exercise       model.Exercise
 		Set            model.Set
+		TargetSet      model.TargetSet
 	}
 	type args struct {
-		id int64
+		id      int64
+		formula metrics.Formula
 	}
 	tests := []struct {
 		testCase  string
@@ -163,7 +288,8 @@ func TestWorkoutGet(t *testing.T) {
 		{
 			testCase: "正常系",
 			args: args{
-				id: int64(1),
+				id:      int64(1),
+				formula: metrics.Brzycki,
 			},
 			fields: func(ctrl *gomock.Controller) fields {
 				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
@@ -174,18 +300,21 @@ func TestWorkoutGet(t *testing.T) {
 					{ID: int64(2), SessionID: int64(1), ExerciseName: "test"},
 				}, nil)
 				Set := mock_model.NewMockSet(ctrl)
-				Set.EXPECT().LoadByExerciseID(int64(1)).Return(&model.Sets{
+				Set.EXPECT().LoadByExerciseIDs([]int64{1, 2}).Return(&model.Sets{
 					{ID: int64(1), ExerciseID: int64(1), SetNumber: int64(1), Weight: float64(10), Reps: int64(10)},
 					{ID: int64(2), ExerciseID: int64(1), SetNumber: int64(2), Weight: float64(10), Reps: int64(10)},
+					{ID: int64(3), ExerciseID: int64(2), SetNumber: int64(1), Weight: float64(10), Reps: int64(10)},
+					{ID: int64(4), ExerciseID: int64(2), SetNumber: int64(2), Weight: float64(10), Reps: int64(10)},
 				}, nil)
-				Set.EXPECT().LoadByExerciseID(int64(2)).Return(&model.Sets{
-					{ID: int64(1), ExerciseID: int64(2), SetNumber: int64(1), Weight: float64(10), Reps: int64(10)},
-					{ID: int64(2), ExerciseID: int64(2), SetNumber: int64(2), Weight: float64(10), Reps: int64(10)},
+				TargetSet := mock_model.NewMockTargetSet(ctrl)
+				TargetSet.EXPECT().LoadByExerciseIDs([]int64{1, 2}).Return(&model.TargetSets{
+					{ID: int64(1), ExerciseID: int64(1), SetTarget: model.SetTarget{SetNumber: int64(1), SetType: model.SetTypeWorking, Weight: float64(10), Unit: "kg", Reps: int64(10)}},
 				}, nil)
 				return fields{
 					WorkoutSession: WorkoutSession,
 					Exercise:       Exercise,
 					Set:            Set,
+					TargetSet:      TargetSet,
 				}
 			},
 			assertion: func(r *response.GetWorkoutSession, err error) {
@@ -196,6 +325,44 @@ func TestWorkoutGet(t *testing.T) {
 				assert.Len(t, r.Exercises, 2)
 				assert.Len(t, r.Exercises[0].Sets, 2)
 				assert.Len(t, r.Exercises[1].Sets, 2)
+				// 目標セットはエクササイズごとに紐づける
+				if assert.Len(t, r.Exercises[0].TargetSets, 1) {
+					assert.Equal(t, float64(10), r.Exercises[0].TargetSets[0].Weight)
+					assert.Equal(t, int64(10), r.Exercises[0].TargetSets[0].Reps)
+				}
+				assert.Empty(t, r.Exercises[1].TargetSets)
+				// 目標どおりの1セット目に加えて目標にない2セット目を実施したため目標を上回る
+				if assert.NotNil(t, r.Exercises[0].Comparison) {
+					assert.Equal(t, model.TargetExceeded, r.Exercises[0].Comparison.Status)
+					assert.Equal(t, int64(1), r.Exercises[0].Comparison.Completed)
+					assert.Equal(t, int64(1), r.Exercises[0].Comparison.ExtraSets)
+					assert.Equal(t, []response.SetComparison{{SetNumber: 1, Status: model.TargetCompleted, SetID: 1}}, r.Exercises[0].Comparison.Sets)
+				}
+				assert.Nil(t, r.Exercises[1].Comparison)
+				// 10kgx10回をBrzycki式で計算
+				assert.Equal(t, "brzycki", r.Formula)
+				assert.Equal(t, float64(13.33), r.Exercises[0].Sets[0].EstimatedOneRepMax)
+				assert.Equal(t, float64(13.33), r.Exercises[0].EstimatedOneRepMax)
+				assert.Equal(t, float64(100), r.Exercises[0].Sets[0].Volume)
+				assert.Equal(t, float64(200), r.Exercises[0].Volume)
+				assert.Equal(t, float64(400), r.Volume)
+			},
+		},
+		{
+			testCase: "エラー(他人のセッション)",
+			args: args{
+				id: int64(2),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(2)).Return(&model.WorkoutSessionImpl{ID: int64(2), Date: time.Now(), UserID: int64(2)}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+				}
+			},
+			assertion: func(r *response.GetWorkoutSession, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.Nil(t, r)
 			},
 		},
 		{
@@ -226,8 +393,9 @@ func TestWorkoutGet(t *testing.T) {
 				WorkoutSession: fields.WorkoutSession,
 				Exercise:       fields.Exercise,
 				Set:            fields.Set,
+				TargetSet:      fields.TargetSet,
 			}
-			tt.assertion(w.Get(tt.args.id))
+			tt.assertion(w.Get(int64(1), tt.args.id, tt.args.formula))
 		})
 	}
 }
@@ -255,7 +423,7 @@ func TestWorkoutCreateWorkoutSession(t *testing.T) {
 			},
 			fields: func(ctrl *gomock.Controller) fields {
 				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
-				WorkoutSession.EXPECT().Create(time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC), int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC), UserID: int64(1)}, nil)
+				WorkoutSession.EXPECT().Create(time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC), int64(1), model.SessionStatusDraft).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC), UserID: int64(1)}, nil)
 				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC), UserID: int64(1)}, nil)
 				return fields{
 					WorkoutSession: WorkoutSession,
@@ -277,7 +445,7 @@ func TestWorkoutCreateWorkoutSession(t *testing.T) {
 			},
 			fields: func(ctrl *gomock.Controller) fields {
 				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
-				WorkoutSession.EXPECT().Create(time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC), int64(0)).Return(nil, errors.New("couldn't create workout_session"))
+				WorkoutSession.EXPECT().Create(time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC), int64(0), model.SessionStatusDraft).Return(nil, errors.New("couldn't create workout_session"))
 				return fields{
 					WorkoutSession: WorkoutSession,
 				}
@@ -305,11 +473,16 @@ func TestWorkoutCreateWorkoutSession(t *testing.T) {
 func TestWorkoutCreateExercise(t *testing.T) {
 	t.Parallel()
 	type fields struct {
-		Exercise model.Exercise
+		WorkoutSession  model.WorkoutSession
+		Exercise        model.Exercise
+		ExerciseCatalog model.ExerciseCatalog
 	}
 	type args struct {
+		userId       int64
 		sessionId    int64
+		catalogId    int64
 		exerciseName string
+		modality     string
 	}
 	tests := []struct {
 		testCase  string
@@ -320,15 +493,22 @@ func TestWorkoutCreateExercise(t *testing.T) {
 		{
 			testCase: "正常系",
 			args: args{
+				userId:       int64(1),
 				sessionId:    int64(1),
 				exerciseName: "test",
 			},
 			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("test").Return(&model.ExerciseCatalogImpl{}, nil)
 				Exercise := mock_model.NewMockExercise(ctrl)
-				Exercise.EXPECT().Create(int64(1), "test").Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "test"}, nil)
+				Exercise.EXPECT().Create(int64(1), "test", int64(0), model.ModalityWeighted).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "test"}, nil)
 				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "test"}, nil)
 				return fields{
-					Exercise: Exercise,
+					WorkoutSession:  WorkoutSession,
+					Exercise:        Exercise,
+					ExerciseCatalog: ExerciseCatalog,
 				}
 			},
 			assertion: func(r *response.Exercise, err error) {
@@ -336,20 +516,194 @@ func TestWorkoutCreateExercise(t *testing.T) {
 				assert.NotNil(t, r)
 				assert.Equal(t, int64(1), r.ID)
 				assert.Equal(t, int64(1), r.SessionID)
+				assert.Equal(t, int64(0), r.CatalogID)
 				assert.Equal(t, "test", r.ExerciseName)
 			},
 		},
+		{
+			testCase: "正常系(別名からカタログに紐づける)",
+			args: args{
+				userId:       int64(1),
+				sessionId:    int64(1),
+				exerciseName: " ベンチ ",
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("ベンチ").Return(&model.ExerciseCatalogImpl{ID: int64(1), NameJa: "ベンチプレス"}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Create(int64(1), "ベンチ", int64(1), model.ModalityWeighted).Return(&model.ExerciseImpl{ID: int64(1)}, nil)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), CatalogID: dbr.NewNullInt64(1), ExerciseName: "ベンチ"}, nil)
+				return fields{
+					WorkoutSession:  WorkoutSession,
+					Exercise:        Exercise,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), r.CatalogID)
+				assert.Equal(t, "ベンチ", r.ExerciseName)
+			},
+		},
+		{
+			testCase: "正常系(カタログIDのみ指定)",
+			args: args{
+				userId:    int64(1),
+				sessionId: int64(1),
+				catalogId: int64(1),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(1)).Return(&model.ExerciseCatalogImpl{ID: int64(1), NameJa: "ベンチプレス"}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Create(int64(1), "ベンチプレス", int64(1), model.ModalityWeighted).Return(&model.ExerciseImpl{ID: int64(1)}, nil)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), CatalogID: dbr.NewNullInt64(1), ExerciseName: "ベンチプレス"}, nil)
+				return fields{
+					WorkoutSession:  WorkoutSession,
+					Exercise:        Exercise,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), r.CatalogID)
+				assert.Equal(t, "ベンチプレス", r.ExerciseName)
+			},
+		},
+		{
+			testCase: "正常系(記録方法の指定がない場合はカタログの記録方法)",
+			args: args{
+				userId:       int64(1),
+				sessionId:    int64(1),
+				exerciseName: "懸垂",
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("懸垂").Return(&model.ExerciseCatalogImpl{ID: int64(18), NameJa: "懸垂", Modality: model.ModalityBodyweight}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Create(int64(1), "懸垂", int64(18), model.ModalityBodyweight).Return(&model.ExerciseImpl{ID: int64(1)}, nil)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), CatalogID: dbr.NewNullInt64(18), ExerciseName: "懸垂", Modality: model.ModalityBodyweight}, nil)
+				return fields{
+					WorkoutSession:  WorkoutSession,
+					Exercise:        Exercise,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, model.ModalityBodyweight, r.Modality)
+			},
+		},
+		{
+			testCase: "正常系(指定した記録方法をカタログより優先)",
+			args: args{
+				userId:       int64(1),
+				sessionId:    int64(1),
+				exerciseName: "懸垂",
+				modality:     model.ModalityAssisted,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("懸垂").Return(&model.ExerciseCatalogImpl{ID: int64(18), NameJa: "懸垂", Modality: model.ModalityBodyweight}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Create(int64(1), "懸垂", int64(18), model.ModalityAssisted).Return(&model.ExerciseImpl{ID: int64(1)}, nil)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), CatalogID: dbr.NewNullInt64(18), ExerciseName: "懸垂", Modality: model.ModalityAssisted}, nil)
+				return fields{
+					WorkoutSession:  WorkoutSession,
+					Exercise:        Exercise,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, model.ModalityAssisted, r.Modality)
+			},
+		},
+		{
+			testCase: "エラー(存在しないカタログID)",
+			args: args{
+				userId:    int64(1),
+				sessionId: int64(1),
+				catalogId: int64(99),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Load(int64(99)).Return(&model.ExerciseCatalogImpl{}, nil)
+				return fields{
+					WorkoutSession:  WorkoutSession,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(名前もカタログIDも未指定)",
+			args: args{
+				userId:    int64(1),
+				sessionId: int64(1),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(他人のセッション)",
+			args: args{
+				userId:       int64(2),
+				sessionId:    int64(1),
+				exerciseName: "test",
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.Nil(t, r)
+			},
+		},
 		{
 			testCase: "エラー",
 			args: args{
-				sessionId:    int64(0),
-				exerciseName: "",
+				userId:       int64(1),
+				sessionId:    int64(1),
+				exerciseName: "test",
 			},
 			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("test").Return(&model.ExerciseCatalogImpl{}, nil)
 				Exercise := mock_model.NewMockExercise(ctrl)
-				Exercise.EXPECT().Create(int64(0), "").Return(nil, errors.New("couldn't create exercise"))
+				Exercise.EXPECT().Create(int64(1), "test", int64(0), model.ModalityWeighted).Return(nil, errors.New("couldn't create exercise"))
 				return fields{
-					Exercise: Exercise,
+					WorkoutSession:  WorkoutSession,
+					Exercise:        Exercise,
+					ExerciseCatalog: ExerciseCatalog,
 				}
 			},
 			assertion: func(r *response.Exercise, err error) {
@@ -365,9 +719,11 @@ func TestWorkoutCreateExercise(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			fields := tt.fields(ctrl)
 			w := &WorkoutImpl{
-				Exercise: fields.Exercise,
+				WorkoutSession:  fields.WorkoutSession,
+				Exercise:        fields.Exercise,
+				ExerciseCatalog: fields.ExerciseCatalog,
 			}
-			tt.assertion(w.CreateExercise(tt.args.sessionId, tt.args.exerciseName))
+			tt.assertion(w.CreateExercise(tt.args.userId, tt.args.sessionId, tt.args.catalogId, tt.args.exerciseName, tt.args.modality))
 		})
 	}
 }
@@ -375,62 +731,180 @@ func TestWorkoutCreateExercise(t *testing.T) {
 func TestWorkoutCreateSet(t *testing.T) {
 	t.Parallel()
 	type fields struct {
-		Set model.Set
+		WorkoutSession model.WorkoutSession
+		Exercise       model.Exercise
+		Set            model.Set
+		PersonalRecord model.PersonalRecord
 	}
 	type args struct {
+		userId     int64
+		sessionId  int64
 		exerciseID int64
 		setNumber  int64
 		weight     float64
 		reps       int64
+		unit       units.Unit
 	}
 	tests := []struct {
 		testCase  string
 		args      args
 		fields    func(ctrl *gomock.Controller) fields
-		assertion func(r *response.Sets, err error)
+		assertion func(r *response.Sets, records response.PersonalRecords, err error)
 	}{
 		{
 			testCase: "正常系",
 			args: args{
+				userId:     int64(1),
+				sessionId:  int64(1),
 				exerciseID: int64(1),
 				setNumber:  int64(1),
 				weight:     float64(10),
 				reps:       int64(10),
+				unit:       units.Kilogram,
 			},
 			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "test"}, nil)
 				Set := mock_model.NewMockSet(ctrl)
-				Set.EXPECT().Create(int64(1), int64(1), float64(10), int64(10)).Return(&model.SetImpl{
+				Set.EXPECT().CreateTx(gomock.Any(), int64(1), int64(1), float64(10), int64(10), "kg", model.SetDetail{SetType: model.SetTypeWorking}).Return(&model.SetImpl{
 					ID: int64(1), ExerciseID: int64(1), SetNumber: int64(1), Weight: float64(10), Reps: int64(10),
 				}, nil)
+				// 以前のセッションで10kgx8回を記録済み
+				key := model.ExerciseKey{ExerciseName: "test"}
+				previous := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+				PersonalRecord := mock_model.NewMockPersonalRecord(ctrl)
+				PersonalRecord.EXPECT().LoadByKeyTx(gomock.Any(), int64(1), key).Return(&model.PersonalRecords{
+					{RecordType: model.RecordTypeMaxWeight, Value: float64(10), Weight: float64(10), Reps: int64(8), SessionID: int64(2), SetID: int64(5), AchievedOn: previous},
+					{RecordType: model.RecordTypeEstimatedOneRepMax, Value: float64(12.67), Weight: float64(10), Reps: int64(8), SessionID: int64(2), SetID: int64(5), AchievedOn: previous},
+					{RecordType: model.RecordTypeRepsAtWeight, WeightClass: float64(10), Value: float64(8), Weight: float64(10), Reps: int64(8), SessionID: int64(2), SetID: int64(5), AchievedOn: previous},
+					{RecordType: model.RecordTypeSessionVolume, Value: float64(80), SessionID: int64(2), AchievedOn: previous},
+				}, nil)
+				Set.EXPECT().LoadHistoryTx(gomock.Any(), model.SetHistoryFilter{UserID: int64(1), Key: key}).Return(&model.SetHistories{
+					{SetID: int64(5), ExerciseID: int64(3), SessionID: int64(2), TrainingDate: previous, SetNumber: int64(1), Weight: float64(10), Reps: int64(8)},
+					{SetID: int64(1), ExerciseID: int64(1), SessionID: int64(1), TrainingDate: previous.AddDate(0, 0, 7), SetNumber: int64(1), Weight: float64(10), Reps: int64(10)},
+				}, nil)
+				PersonalRecord.EXPECT().ReplaceTx(gomock.Any(), int64(1), key, gomock.Any()).Return(nil)
 				Set.EXPECT().LoadByExerciseID(int64(1)).Return(&model.Sets{
 					{ID: int64(1), ExerciseID: int64(1), SetNumber: int64(1), Weight: float64(10), Reps: int64(10)},
-					{ID: int64(2), ExerciseID: int64(1), SetNumber: int64(2), Weight: float64(10), Reps: int64(10)},
 				}, nil)
 				return fields{
-					Set: Set,
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
+					PersonalRecord: PersonalRecord,
 				}
 			},
-			assertion: func(r *response.Sets, err error) {
+			assertion: func(r *response.Sets, records response.PersonalRecords, err error) {
 				assert.NoError(t, err)
 				assert.NotNil(t, r)
+				// 最大重量は同じ重量のため更新されない
+				var types []string
+				for _, record := range records {
+					types = append(types, record.RecordType)
+					assert.Equal(t, "test", record.ExerciseName)
+				}
+				assert.Equal(t, []string{model.RecordTypeEstimatedOneRepMax, model.RecordTypeRepsAtWeight, model.RecordTypeSessionVolume}, types)
+			},
+		},
+		{
+			testCase: "正常系(ポンドで入力した重量をkgで保存)",
+			args: args{
+				userId:     int64(1),
+				sessionId:  int64(1),
+				exerciseID: int64(1),
+				setNumber:  int64(1),
+				weight:     float64(225),
+				reps:       int64(5),
+				unit:       units.Pound,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "test"}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				set := &model.SetImpl{ID: int64(1), ExerciseID: int64(1), SetNumber: int64(1), Weight: float64(102.058), Unit: "lb", Reps: int64(5)}
+				Set.EXPECT().CreateTx(gomock.Any(), int64(1), int64(1), float64(102.058), int64(5), "lb", model.SetDetail{SetType: model.SetTypeWorking}).Return(set, nil)
+				key := model.ExerciseKey{ExerciseName: "test"}
+				PersonalRecord := mock_model.NewMockPersonalRecord(ctrl)
+				PersonalRecord.EXPECT().LoadByKeyTx(gomock.Any(), int64(1), key).Return(&model.PersonalRecords{}, nil)
+				Set.EXPECT().LoadHistoryTx(gomock.Any(), model.SetHistoryFilter{UserID: int64(1), Key: key}).Return(&model.SetHistories{
+					{SetID: int64(1), ExerciseID: int64(1), SessionID: int64(1), TrainingDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), SetNumber: int64(1), Weight: float64(102.058), Unit: "lb", Reps: int64(5)},
+				}, nil)
+				PersonalRecord.EXPECT().ReplaceTx(gomock.Any(), int64(1), key, gomock.Any()).Return(nil)
+				Set.EXPECT().LoadByExerciseID(int64(1)).Return(&model.Sets{*set}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
+					PersonalRecord: PersonalRecord,
+				}
+			},
+			assertion: func(r *response.Sets, records response.PersonalRecords, err error) {
+				assert.NoError(t, err)
+				// kgではプレートの刻みに丸め、入力時と同じポンドでは入力した重量に戻す
+				assert.Equal(t, float64(102), (*r)[0].Weight)
+				assert.Equal(t, float64(225), r.ApplyUnit(units.Pound)[0].Weight)
+				assert.Equal(t, "lb", (*r)[0].Unit)
+				assert.Equal(t, float64(1125), (*r)[0].Volume)
+				for _, record := range records.ApplyUnit(units.Pound) {
+					if record.RecordType == model.RecordTypeMaxWeight {
+						assert.Equal(t, float64(225), record.Value)
+					}
+				}
+			},
+		},
+		{
+			testCase: "エラー(他人のセッション)",
+			args: args{
+				userId:     int64(2),
+				sessionId:  int64(1),
+				exerciseID: int64(1),
+				setNumber:  int64(1),
+				weight:     float64(10),
+				reps:       int64(10),
+				unit:       units.Kilogram,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+				}
+			},
+			assertion: func(r *response.Sets, records response.PersonalRecords, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.Nil(t, r)
+				assert.Nil(t, records)
 			},
 		},
 		{
 			testCase: "エラー",
 			args: args{
+				userId:     int64(1),
+				sessionId:  int64(1),
 				exerciseID: int64(1),
 				setNumber:  int64(1),
 				weight:     float64(10),
 				reps:       int64(10),
+				unit:       units.Kilogram,
 			},
 			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "test"}, nil)
 				Set := mock_model.NewMockSet(ctrl)
-				Set.EXPECT().Create(int64(1), int64(1), float64(10), int64(10)).Return(nil, errors.New("couldn't create set"))
+				Set.EXPECT().CreateTx(gomock.Any(), int64(1), int64(1), float64(10), int64(10), "kg", model.SetDetail{SetType: model.SetTypeWorking}).Return(nil, errors.New("couldn't create set"))
 				return fields{
-					Set: Set,
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
 				}
 			},
-			assertion: func(r *response.Sets, err error) {
+			assertion: func(r *response.Sets, records response.PersonalRecords, err error) {
 				assert.Error(t, err)
 				assert.Nil(t, r)
 			},
@@ -443,9 +917,1466 @@ func TestWorkoutCreateSet(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			fields := tt.fields(ctrl)
 			w := &WorkoutImpl{
-				Set: fields.Set,
+				WorkoutSession: fields.WorkoutSession,
+				Exercise:       fields.Exercise,
+				Set:            fields.Set,
+				PersonalRecord: fields.PersonalRecord,
+				Transaction:    noTransaction,
+			}
+			tt.assertion(w.CreateSet(tt.args.userId, tt.args.sessionId, tt.args.exerciseID, form.CreateSet{
+				SetNumber: tt.args.setNumber,
+				Weight:    tt.args.weight,
+				Unit:      string(tt.args.unit),
+				Reps:      tt.args.reps,
+			}))
+		})
+	}
+}
+
+// noTransaction テスト用にトランザクションを張らずfnを実行する
+func noTransaction(fn func(tx dbr.SessionRunner) error) error {
+	return fn(nil)
+}
+
+func TestWorkoutUpdateExercise(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		Exercise        model.Exercise
+		Set             model.Set
+		ExerciseCatalog model.ExerciseCatalog
+		PersonalRecord  model.PersonalRecord
+	}
+	tests := []struct {
+		testCase  string
+		attrs     map[string]interface{}
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.Exercise, err error)
+	}{
+		{
+			testCase: "正常系(名前の変更でカタログに付け直し、変更前後の自己ベストを集計し直す)",
+			attrs:    map[string]interface{}{"exercise_name": "スクワット"},
+			fields: func(ctrl *gomock.Controller) fields {
+				previousKey := model.ExerciseKey{CatalogID: int64(3)}
+				key := model.ExerciseKey{CatalogID: int64(5)}
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), CatalogID: dbr.NewNullInt64(int64(3)), ExerciseName: "ベンチプレス"}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("スクワット").Return(&model.ExerciseCatalogImpl{ID: int64(5), NameJa: "スクワット", Modality: model.ModalityWeighted}, nil)
+				Exercise.EXPECT().UpdateTx(gomock.Any(), int64(1), map[string]interface{}{"exercise_name": "スクワット", "catalog_id": int64(5)}).Return(true, nil)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), CatalogID: dbr.NewNullInt64(int64(5)), ExerciseName: "スクワット"}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				PersonalRecord := mock_model.NewMockPersonalRecord(ctrl)
+				for _, k := range []model.ExerciseKey{previousKey, key} {
+					PersonalRecord.EXPECT().LoadByKeyTx(gomock.Any(), int64(1), k).Return(model.NewPersonalRecords(), nil)
+					Set.EXPECT().LoadHistoryTx(gomock.Any(), model.SetHistoryFilter{UserID: int64(1), Key: k}).Return(&model.SetHistories{}, nil)
+					PersonalRecord.EXPECT().ReplaceTx(gomock.Any(), int64(1), k, gomock.Any()).Return(nil)
+				}
+				Set.EXPECT().LoadByExerciseID(int64(1)).Return(&model.Sets{{ID: int64(1), ExerciseID: int64(1), SetNumber: int64(1), Weight: float64(100), Reps: int64(5)}}, nil)
+				return fields{
+					Exercise:        Exercise,
+					Set:             Set,
+					ExerciseCatalog: ExerciseCatalog,
+					PersonalRecord:  PersonalRecord,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "スクワット", r.ExerciseName)
+				assert.Equal(t, int64(5), r.CatalogID)
+				assert.Len(t, r.Sets, 1)
+			},
+		},
+		{
+			testCase: "正常系(記録方法の変更で自己ベストを集計し直す)",
+			attrs:    map[string]interface{}{"modality": model.ModalityBodyweight},
+			fields: func(ctrl *gomock.Controller) fields {
+				key := model.ExerciseKey{ExerciseName: "懸垂"}
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "懸垂"}, nil)
+				Exercise.EXPECT().UpdateTx(gomock.Any(), int64(1), map[string]interface{}{"modality": model.ModalityBodyweight}).Return(true, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				PersonalRecord := mock_model.NewMockPersonalRecord(ctrl)
+				PersonalRecord.EXPECT().LoadByKeyTx(gomock.Any(), int64(1), key).Return(model.NewPersonalRecords(), nil)
+				Set.EXPECT().LoadHistoryTx(gomock.Any(), model.SetHistoryFilter{UserID: int64(1), Key: key}).Return(&model.SetHistories{}, nil)
+				PersonalRecord.EXPECT().ReplaceTx(gomock.Any(), int64(1), key, gomock.Any()).Return(nil)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "懸垂", Modality: model.ModalityBodyweight}, nil)
+				Set.EXPECT().LoadByExerciseID(int64(1)).Return(&model.Sets{}, nil)
+				return fields{
+					Exercise:       Exercise,
+					Set:            Set,
+					PersonalRecord: PersonalRecord,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, model.ModalityBodyweight, r.Modality)
+			},
+		},
+		{
+			testCase: "正常系(種目も記録方法も変わらない場合は自己ベストを集計しない)",
+			attrs:    map[string]interface{}{"modality": model.ModalityWeighted},
+			fields: func(ctrl *gomock.Controller) fields {
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "懸垂"}, nil)
+				Exercise.EXPECT().UpdateTx(gomock.Any(), int64(1), map[string]interface{}{"modality": model.ModalityWeighted}).Return(true, nil)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "懸垂", Modality: model.ModalityWeighted}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadByExerciseID(int64(1)).Return(&model.Sets{}, nil)
+				return fields{
+					Exercise: Exercise,
+					Set:      Set,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, model.ModalityWeighted, r.Modality)
+			},
+		},
+		{
+			testCase: "エラー(不明な記録方法)",
+			attrs:    map[string]interface{}{"modality": "unknown"},
+			fields: func(ctrl *gomock.Controller) fields {
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "懸垂"}, nil)
+				return fields{
+					Exercise: Exercise,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+			WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+			w := &WorkoutImpl{
+				WorkoutSession:  WorkoutSession,
+				Exercise:        fields.Exercise,
+				Set:             fields.Set,
+				ExerciseCatalog: fields.ExerciseCatalog,
+				PersonalRecord:  fields.PersonalRecord,
+				Transaction:     noTransaction,
+			}
+			tt.assertion(w.UpdateExercise(int64(1), int64(1), int64(1), tt.attrs))
+		})
+	}
+}
+
+func TestWorkoutUpdateSet(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		WorkoutSession model.WorkoutSession
+		Exercise       model.Exercise
+		Set            model.Set
+		PersonalRecord model.PersonalRecord
+	}
+	type args struct {
+		sessionId  int64
+		exerciseId int64
+		setId      int64
+		attrs      map[string]interface{}
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.Set, err error)
+	}{
+		{
+			testCase: "正常系(重量をkgに変換して保存し、自己ベストを集計し直す)",
+			args: args{
+				sessionId:  int64(1),
+				exerciseId: int64(1),
+				setId:      int64(1),
+				attrs:      map[string]interface{}{"weight": float64(135), "unit": "lb", "reps": int64(8)},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				key := model.ExerciseKey{CatalogID: int64(3)}
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), CatalogID: dbr.NewNullInt64(int64(3)), ExerciseName: "ベンチプレス"}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().Load(int64(1)).Return(&model.SetImpl{ID: int64(1), ExerciseID: int64(1), SetNumber: int64(1), Weight: float64(60), Reps: int64(10)}, nil)
+				Set.EXPECT().UpdateTx(gomock.Any(), int64(1), map[string]interface{}{"weight": units.ToKilograms(float64(135), units.Pound), "unit": "lb", "reps": int64(8)}).Return(true, nil)
+				PersonalRecord := mock_model.NewMockPersonalRecord(ctrl)
+				PersonalRecord.EXPECT().LoadByKeyTx(gomock.Any(), int64(1), key).Return(model.NewPersonalRecords(), nil)
+				Set.EXPECT().LoadHistoryTx(gomock.Any(), model.SetHistoryFilter{UserID: int64(1), Key: key}).Return(&model.SetHistories{}, nil)
+				PersonalRecord.EXPECT().ReplaceTx(gomock.Any(), int64(1), key, gomock.Any()).Return(nil)
+				Set.EXPECT().Load(int64(1)).Return(&model.SetImpl{ID: int64(1), ExerciseID: int64(1), SetNumber: int64(1), Weight: units.ToKilograms(float64(135), units.Pound), Unit: "lb", Reps: int64(8)}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
+					PersonalRecord: PersonalRecord,
+				}
+			},
+			assertion: func(r *response.Set, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(8), r.Reps)
+				assert.Equal(t, units.ToKilograms(float64(135), units.Pound), r.Kilograms)
+			},
+		},
+		{
+			testCase: "正常系(メモのみの変更では自己ベストを集計し直さない)",
+			args: args{
+				sessionId:  int64(1),
+				exerciseId: int64(1),
+				setId:      int64(1),
+				attrs:      map[string]interface{}{"notes": "フォームを意識"},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "test"}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().Load(int64(1)).Return(&model.SetImpl{ID: int64(1), ExerciseID: int64(1), SetNumber: int64(1), Weight: float64(60), Reps: int64(10)}, nil)
+				Set.EXPECT().UpdateTx(gomock.Any(), int64(1), map[string]interface{}{"notes": "フォームを意識"}).Return(true, nil)
+				Set.EXPECT().Load(int64(1)).Return(&model.SetImpl{ID: int64(1), ExerciseID: int64(1), SetNumber: int64(1), Weight: float64(60), Reps: int64(10), SetDetail: model.SetDetail{Notes: "フォームを意識"}}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
+				}
+			},
+			assertion: func(r *response.Set, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "フォームを意識", r.Notes)
+			},
+		},
+		{
+			testCase: "エラー(重量を指定せずに単位のみ変更)",
+			args: args{
+				sessionId:  int64(1),
+				exerciseId: int64(1),
+				setId:      int64(1),
+				attrs:      map[string]interface{}{"unit": "lb"},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "test"}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().Load(int64(1)).Return(&model.SetImpl{ID: int64(1), ExerciseID: int64(1), SetNumber: int64(1), Weight: float64(60), Reps: int64(10)}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
+				}
+			},
+			assertion: func(r *response.Set, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(別セッションのエクササイズ)",
+			args: args{
+				sessionId:  int64(2),
+				exerciseId: int64(1),
+				setId:      int64(1),
+				attrs:      map[string]interface{}{"reps": int64(8)},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(2)).Return(&model.WorkoutSessionImpl{ID: int64(2), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "test"}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+				}
+			},
+			assertion: func(r *response.Set, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(別エクササイズのセット)",
+			args: args{
+				sessionId:  int64(1),
+				exerciseId: int64(1),
+				setId:      int64(3),
+				attrs:      map[string]interface{}{"reps": int64(8)},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(1)).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "test"}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().Load(int64(3)).Return(&model.SetImpl{ID: int64(3), ExerciseID: int64(2)}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
+				}
+			},
+			assertion: func(r *response.Set, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			w := &WorkoutImpl{
+				WorkoutSession: fields.WorkoutSession,
+				Exercise:       fields.Exercise,
+				Set:            fields.Set,
+				PersonalRecord: fields.PersonalRecord,
+				Transaction:    noTransaction,
+			}
+			tt.assertion(w.UpdateSet(int64(1), tt.args.sessionId, tt.args.exerciseId, tt.args.setId, tt.args.attrs))
+		})
+	}
+}
+
+func TestWorkoutReplaceTargetSets(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		WorkoutSession model.WorkoutSession
+		Exercise       model.Exercise
+		Set            model.Set
+		TargetSet      model.TargetSet
+	}
+	type args struct {
+		sessionId int64
+		sets      []form.TemplateSet
+	}
+	rpe, invalidRPE := float64(8), float64(8.3)
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.Exercise, err error)
+	}{
+		{
+			testCase: "正常系",
+			args: args{
+				sessionId: int64(1),
+				sets: []form.TemplateSet{
+					{SetNumber: 1, Weight: 100, Unit: "kg", Reps: 5, RPE: &rpe},
+					{SetNumber: 2, Weight: 100, Unit: "kg", Reps: 5},
+				},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(2)).Return(&model.ExerciseImpl{ID: int64(2), SessionID: int64(1), ExerciseName: "スクワット"}, nil)
+				TargetSet := mock_model.NewMockTargetSet(ctrl)
+				first := model.SetTarget{SetNumber: 1, SetType: model.SetTypeWorking, Weight: 100, Unit: "kg", Reps: 5, RPE: dbr.NewNullFloat64(8)}
+				second := model.SetTarget{SetNumber: 2, SetType: model.SetTypeWorking, Weight: 100, Unit: "kg", Reps: 5}
+				gomock.InOrder(
+					TargetSet.EXPECT().DeleteByExerciseIDTx(gomock.Any(), int64(2)).Return(int64(3), nil),
+					TargetSet.EXPECT().CreateTx(gomock.Any(), int64(2), first).Return(&model.TargetSetImpl{ID: 1}, nil),
+					TargetSet.EXPECT().CreateTx(gomock.Any(), int64(2), second).Return(&model.TargetSetImpl{ID: 2}, nil),
+				)
+				TargetSet.EXPECT().LoadByExerciseIDs([]int64{2}).Return(&model.TargetSets{
+					{ID: 1, ExerciseID: 2, SetTarget: first},
+					{ID: 2, ExerciseID: 2, SetTarget: second},
+				}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().LoadByExerciseID(int64(2)).Return(&model.Sets{
+					{ID: 5, ExerciseID: 2, SetNumber: 1, Weight: 100, Unit: "kg", Reps: 5},
+					{ID: 6, ExerciseID: 2, SetNumber: 2, Weight: 100, Unit: "kg", Reps: 3},
+				}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
+					TargetSet:      TargetSet,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.NoError(t, err)
+				if assert.Len(t, r.TargetSets, 2) && assert.NotNil(t, r.TargetSets[0].RPE) {
+					assert.Equal(t, float64(8), *r.TargetSets[0].RPE)
+				}
+				// 2セット目は回数が届かない
+				if assert.NotNil(t, r.Comparison) {
+					assert.Equal(t, model.TargetMissed, r.Comparison.Status)
+					assert.Equal(t, int64(1), r.Comparison.Completed)
+					assert.Equal(t, int64(1), r.Comparison.Missed)
+				}
+			},
+		},
+		{
+			testCase: "エラー(セット数の重複)",
+			args: args{
+				sessionId: int64(1),
+				sets: []form.TemplateSet{
+					{SetNumber: 1, Weight: 100, Unit: "kg", Reps: 5},
+					{SetNumber: 1, Weight: 100, Unit: "kg", Reps: 5},
+				},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(2)).Return(&model.ExerciseImpl{ID: int64(2), SessionID: int64(1), ExerciseName: "スクワット"}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(RPEの範囲外)",
+			args: args{
+				sessionId: int64(1),
+				sets:      []form.TemplateSet{{SetNumber: 1, Weight: 100, Unit: "kg", Reps: 5, RPE: &invalidRPE}},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(2)).Return(&model.ExerciseImpl{ID: int64(2), SessionID: int64(1), ExerciseName: "スクワット"}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(実施済みのセッション)",
+			args: args{
+				sessionId: int64(3),
+				sets:      []form.TemplateSet{{SetNumber: 1, Weight: 100, Unit: "kg", Reps: 5}},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(3)).Return(&model.WorkoutSessionImpl{ID: int64(3), Date: time.Now(), UserID: int64(1), Status: model.SessionStatusCompleted}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+				}
+			},
+			assertion: func(r *response.Exercise, err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			w := &WorkoutImpl{
+				WorkoutSession: fields.WorkoutSession,
+				Exercise:       fields.Exercise,
+				Set:            fields.Set,
+				TargetSet:      fields.TargetSet,
+				Transaction:    noTransaction,
+			}
+			tt.assertion(w.ReplaceTargetSets(int64(1), tt.args.sessionId, int64(2), tt.args.sets))
+		})
+	}
+}
+
+func TestWorkoutDeleteWorkoutSession(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		WorkoutSession model.WorkoutSession
+		Exercise       model.Exercise
+		Set            model.Set
+		TargetSet      model.TargetSet
+		PersonalRecord model.PersonalRecord
+	}
+	type args struct {
+		id int64
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(err error)
+	}{
+		{
+			testCase: "正常系",
+			args: args{
+				id: int64(1),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().LoadBySessionID(int64(1)).Return(&model.Exercises{
+					{ID: int64(1), SessionID: int64(1), ExerciseName: "test"},
+					{ID: int64(2), SessionID: int64(1), ExerciseName: "test"},
+				}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				PersonalRecord := mock_model.NewMockPersonalRecord(ctrl)
+				PersonalRecord.EXPECT().LoadByKeyTx(gomock.Any(), int64(1), model.ExerciseKey{ExerciseName: "test"}).Return(model.NewPersonalRecords(), nil)
+				PersonalRecord.EXPECT().ReplaceTx(gomock.Any(), int64(1), model.ExerciseKey{ExerciseName: "test"}, model.NewPersonalRecords()).Return(nil)
+				Set.EXPECT().LoadHistoryTx(gomock.Any(), model.SetHistoryFilter{UserID: int64(1), Key: model.ExerciseKey{ExerciseName: "test"}}).Return(&model.SetHistories{}, nil)
+				TargetSet := mock_model.NewMockTargetSet(ctrl)
+				gomock.InOrder(
+					Set.EXPECT().DeleteBySessionIDTx(gomock.Any(), int64(1)).Return(int64(4), nil),
+					TargetSet.EXPECT().DeleteBySessionIDTx(gomock.Any(), int64(1)).Return(int64(2), nil),
+					Exercise.EXPECT().DeleteBySessionIDTx(gomock.Any(), int64(1)).Return(int64(2), nil),
+					WorkoutSession.EXPECT().DeleteTx(gomock.Any(), int64(1)).Return(true, nil),
+				)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
+					TargetSet:      TargetSet,
+					PersonalRecord: PersonalRecord,
+				}
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "エラー(存在しない)",
+			args: args{
+				id: int64(100),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(100)).Return(&model.WorkoutSessionImpl{}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+				}
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+		{
+			testCase: "エラー(子の削除に失敗)",
+			args: args{
+				id: int64(1),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().LoadBySessionID(int64(1)).Return(&model.Exercises{}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().DeleteBySessionIDTx(gomock.Any(), int64(1)).Return(int64(0), errors.New("couldn't delete sets"))
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
+				}
+			},
+			assertion: func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			w := &WorkoutImpl{
+				WorkoutSession: fields.WorkoutSession,
+				Exercise:       fields.Exercise,
+				Set:            fields.Set,
+				TargetSet:      fields.TargetSet,
+				PersonalRecord: fields.PersonalRecord,
+				Transaction:    noTransaction,
+			}
+			tt.assertion(w.DeleteWorkoutSession(int64(1), tt.args.id))
+		})
+	}
+}
+
+func TestWorkoutDeleteExercise(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		WorkoutSession model.WorkoutSession
+		Exercise       model.Exercise
+		Set            model.Set
+		TargetSet      model.TargetSet
+		PersonalRecord model.PersonalRecord
+	}
+	type args struct {
+		sessionId  int64
+		exerciseId int64
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(err error)
+	}{
+		{
+			testCase: "正常系",
+			args: args{
+				sessionId:  int64(1),
+				exerciseId: int64(2),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(2)).Return(&model.ExerciseImpl{ID: int64(2), SessionID: int64(1), ExerciseName: "test"}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				PersonalRecord := mock_model.NewMockPersonalRecord(ctrl)
+				PersonalRecord.EXPECT().LoadByKeyTx(gomock.Any(), int64(1), model.ExerciseKey{ExerciseName: "test"}).Return(model.NewPersonalRecords(), nil)
+				PersonalRecord.EXPECT().ReplaceTx(gomock.Any(), int64(1), model.ExerciseKey{ExerciseName: "test"}, model.NewPersonalRecords()).Return(nil)
+				Set.EXPECT().LoadHistoryTx(gomock.Any(), model.SetHistoryFilter{UserID: int64(1), Key: model.ExerciseKey{ExerciseName: "test"}}).Return(&model.SetHistories{}, nil)
+				TargetSet := mock_model.NewMockTargetSet(ctrl)
+				gomock.InOrder(
+					Set.EXPECT().DeleteByExerciseIDTx(gomock.Any(), int64(2)).Return(int64(3), nil),
+					TargetSet.EXPECT().DeleteByExerciseIDTx(gomock.Any(), int64(2)).Return(int64(0), nil),
+					Exercise.EXPECT().DeleteTx(gomock.Any(), int64(2)).Return(true, nil),
+				)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
+					TargetSet:      TargetSet,
+					PersonalRecord: PersonalRecord,
+				}
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "エラー(別セッションのエクササイズ)",
+			args: args{
+				sessionId:  int64(5),
+				exerciseId: int64(2),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(5)).Return(&model.WorkoutSessionImpl{ID: int64(5), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(2)).Return(&model.ExerciseImpl{ID: int64(2), SessionID: int64(1), ExerciseName: "test"}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+				}
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			w := &WorkoutImpl{
+				WorkoutSession: fields.WorkoutSession,
+				Exercise:       fields.Exercise,
+				Set:            fields.Set,
+				TargetSet:      fields.TargetSet,
+				PersonalRecord: fields.PersonalRecord,
+				Transaction:    noTransaction,
+			}
+			tt.assertion(w.DeleteExercise(int64(1), tt.args.sessionId, tt.args.exerciseId))
+		})
+	}
+}
+
+func TestWorkoutDeleteSet(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		WorkoutSession model.WorkoutSession
+		Exercise       model.Exercise
+		Set            model.Set
+		PersonalRecord model.PersonalRecord
+	}
+	type args struct {
+		sessionId  int64
+		exerciseId int64
+		setId      int64
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(err error)
+	}{
+		{
+			testCase: "正常系",
+			args: args{
+				sessionId:  int64(1),
+				exerciseId: int64(2),
+				setId:      int64(3),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(2)).Return(&model.ExerciseImpl{ID: int64(2), SessionID: int64(1), ExerciseName: "test"}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().Load(int64(3)).Return(&model.SetImpl{ID: int64(3), ExerciseID: int64(2), Weight: float64(60), Reps: int64(10)}, nil)
+				Set.EXPECT().DeleteTx(gomock.Any(), int64(3)).Return(true, nil)
+				PersonalRecord := mock_model.NewMockPersonalRecord(ctrl)
+				PersonalRecord.EXPECT().LoadByKeyTx(gomock.Any(), int64(1), model.ExerciseKey{ExerciseName: "test"}).Return(model.NewPersonalRecords(), nil)
+				PersonalRecord.EXPECT().ReplaceTx(gomock.Any(), int64(1), model.ExerciseKey{ExerciseName: "test"}, model.NewPersonalRecords()).Return(nil)
+				Set.EXPECT().LoadHistoryTx(gomock.Any(), model.SetHistoryFilter{UserID: int64(1), Key: model.ExerciseKey{ExerciseName: "test"}}).Return(&model.SetHistories{}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
+					PersonalRecord: PersonalRecord,
+				}
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "正常系(ウォームアップのセットは自己ベストを集計し直さない)",
+			args: args{
+				sessionId:  int64(1),
+				exerciseId: int64(2),
+				setId:      int64(3),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(2)).Return(&model.ExerciseImpl{ID: int64(2), SessionID: int64(1), ExerciseName: "test"}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().Load(int64(3)).Return(&model.SetImpl{ID: int64(3), ExerciseID: int64(2), Weight: float64(40), Reps: int64(10), SetDetail: model.SetDetail{SetType: model.SetTypeWarmup}}, nil)
+				Set.EXPECT().DeleteTx(gomock.Any(), int64(3)).Return(true, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
+				}
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "エラー(存在しない)",
+			args: args{
+				sessionId:  int64(1),
+				exerciseId: int64(2),
+				setId:      int64(100),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: time.Now(), UserID: int64(1)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().Load(int64(2)).Return(&model.ExerciseImpl{ID: int64(2), SessionID: int64(1), ExerciseName: "test"}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().Load(int64(100)).Return(&model.SetImpl{}, nil)
+				return fields{
+					WorkoutSession: WorkoutSession,
+					Exercise:       Exercise,
+					Set:            Set,
+				}
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			w := &WorkoutImpl{
+				WorkoutSession: fields.WorkoutSession,
+				Exercise:       fields.Exercise,
+				Set:            fields.Set,
+				PersonalRecord: fields.PersonalRecord,
+				Transaction:    noTransaction,
+			}
+			tt.assertion(w.DeleteSet(int64(1), tt.args.sessionId, tt.args.exerciseId, tt.args.setId))
+		})
+	}
+}
+
+func TestWorkoutCreateWorkoutLog(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		WorkoutSession  model.WorkoutSession
+		Exercise        model.Exercise
+		Set             model.Set
+		ExerciseCatalog model.ExerciseCatalog
+		PersonalRecord  model.PersonalRecord
+	}
+	type args struct {
+		date      time.Time
+		userId    int64
+		exercises []form.CreateWorkoutLogExercise
+	}
+	date := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	exercises := []form.CreateWorkoutLogExercise{
+		{
+			ExerciseName: "ベンチプレス",
+			Sets: []form.CreateSet{
+				{SetNumber: int64(1), Weight: float64(60), Reps: int64(10)},
+				{SetNumber: int64(2), Weight: float64(60), Reps: int64(8)},
+			},
+		},
+		{
+			ExerciseName: "スクワット",
+			Sets: []form.CreateSet{
+				{SetNumber: int64(1), Weight: float64(80), Reps: int64(5)},
+			},
+		},
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.GetWorkoutSession, err error)
+	}{
+		{
+			testCase: "正常系",
+			args: args{
+				date:      date,
+				userId:    int64(1),
+				exercises: exercises,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().CreateTx(gomock.Any(), date, int64(1), model.SessionStatusCompleted).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: date, UserID: int64(1)}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("ベンチプレス").Return(&model.ExerciseCatalogImpl{ID: int64(1)}, nil)
+				ExerciseCatalog.EXPECT().Resolve("スクワット").Return(&model.ExerciseCatalogImpl{ID: int64(8)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().CreateTx(gomock.Any(), int64(1), "ベンチプレス", int64(1), model.ModalityWeighted).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "ベンチプレス"}, nil)
+				Exercise.EXPECT().CreateTx(gomock.Any(), int64(1), "スクワット", int64(8), model.ModalityWeighted).Return(&model.ExerciseImpl{ID: int64(2), SessionID: int64(1), ExerciseName: "スクワット"}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().CreateTx(gomock.Any(), int64(1), int64(1), float64(60), int64(10), "kg", model.SetDetail{SetType: model.SetTypeWorking}).Return(&model.SetImpl{ID: int64(1), ExerciseID: int64(1), SetNumber: int64(1), Weight: float64(60), Reps: int64(10)}, nil)
+				Set.EXPECT().CreateTx(gomock.Any(), int64(1), int64(2), float64(60), int64(8), "kg", model.SetDetail{SetType: model.SetTypeWorking}).Return(&model.SetImpl{ID: int64(2), ExerciseID: int64(1), SetNumber: int64(2), Weight: float64(60), Reps: int64(8)}, nil)
+				Set.EXPECT().CreateTx(gomock.Any(), int64(2), int64(1), float64(80), int64(5), "kg", model.SetDetail{SetType: model.SetTypeWorking}).Return(&model.SetImpl{ID: int64(3), ExerciseID: int64(2), SetNumber: int64(1), Weight: float64(80), Reps: int64(5)}, nil)
+				PersonalRecord := mock_model.NewMockPersonalRecord(ctrl)
+				for _, key := range []model.ExerciseKey{{CatalogID: int64(1)}, {CatalogID: int64(8)}} {
+					PersonalRecord.EXPECT().LoadByKeyTx(gomock.Any(), int64(1), key).Return(model.NewPersonalRecords(), nil)
+					Set.EXPECT().LoadHistoryTx(gomock.Any(), model.SetHistoryFilter{UserID: int64(1), Key: key}).Return(&model.SetHistories{}, nil)
+					PersonalRecord.EXPECT().ReplaceTx(gomock.Any(), int64(1), key, gomock.Any()).Return(nil)
+				}
+				return fields{
+					WorkoutSession:  WorkoutSession,
+					Exercise:        Exercise,
+					Set:             Set,
+					ExerciseCatalog: ExerciseCatalog,
+					PersonalRecord:  PersonalRecord,
+				}
+			},
+			assertion: func(r *response.GetWorkoutSession, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), r.ID)
+				assert.Len(t, r.Exercises, 2)
+				assert.Len(t, r.Exercises[0].Sets, 2)
+				assert.Len(t, r.Exercises[1].Sets, 1)
+			},
+		},
+		{
+			testCase: "エラー(途中のセット作成に失敗)",
+			args: args{
+				date:      date,
+				userId:    int64(1),
+				exercises: exercises,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().CreateTx(gomock.Any(), date, int64(1), model.SessionStatusCompleted).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: date, UserID: int64(1)}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("ベンチプレス").Return(&model.ExerciseCatalogImpl{ID: int64(1)}, nil)
+				ExerciseCatalog.EXPECT().Resolve("スクワット").Return(&model.ExerciseCatalogImpl{ID: int64(8)}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().CreateTx(gomock.Any(), int64(1), "ベンチプレス", int64(1), model.ModalityWeighted).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "ベンチプレス"}, nil)
+				Set := mock_model.NewMockSet(ctrl)
+				Set.EXPECT().CreateTx(gomock.Any(), int64(1), int64(1), float64(60), int64(10), "kg", model.SetDetail{SetType: model.SetTypeWorking}).Return(&model.SetImpl{ID: int64(1), ExerciseID: int64(1), SetNumber: int64(1), Weight: float64(60), Reps: int64(10)}, nil)
+				Set.EXPECT().CreateTx(gomock.Any(), int64(1), int64(2), float64(60), int64(8), "kg", model.SetDetail{SetType: model.SetTypeWorking}).Return(nil, errors.New("couldn't create sets"))
+				return fields{
+					WorkoutSession:  WorkoutSession,
+					Exercise:        Exercise,
+					Set:             Set,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.GetWorkoutSession, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			w := &WorkoutImpl{
+				WorkoutSession:  fields.WorkoutSession,
+				Exercise:        fields.Exercise,
+				Set:             fields.Set,
+				ExerciseCatalog: fields.ExerciseCatalog,
+				PersonalRecord:  fields.PersonalRecord,
+				Transaction:     noTransaction,
+			}
+			tt.assertion(w.CreateWorkoutLog(tt.args.date, tt.args.userId, tt.args.exercises))
+		})
+	}
+}
+
+func TestWorkoutCreateFromRecommendation(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		WorkoutSession  model.WorkoutSession
+		Exercise        model.Exercise
+		TargetSet       model.TargetSet
+		ExerciseCatalog model.ExerciseCatalog
+		Recommendation  model.Recommendation
+	}
+	type args struct {
+		userId           int64
+		date             time.Time
+		recommendationId int64
+		menu             *form.TrainingMenu
+	}
+	date := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	saved := `{"title":"胸の日","estimated_minutes":30,"exercises":[{"name":"ベンチプレス","sets":2,"reps":8,"rest_seconds":120,"estimated_minutes":10}]}`
+	target := func(setNumber int64, reps int64) model.SetTarget {
+		return model.SetTarget{SetNumber: setNumber, SetType: model.SetTypeWorking, Unit: "kg", Reps: reps}
+	}
+	tests := []struct {
+		testCase  string
+		args      args
+		fields    func(ctrl *gomock.Controller) fields
+		assertion func(r *response.GetWorkoutSession, err error)
+	}{
+		{
+			testCase: "正常系(保存した提案のIDを指定)",
+			args: args{
+				userId:           int64(1),
+				date:             date,
+				recommendationId: int64(7),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				Recommendation := mock_model.NewMockRecommendation(ctrl)
+				Recommendation.EXPECT().Load(int64(7)).Return(&model.RecommendationImpl{ID: int64(7), UserID: int64(1), Menu: saved}, nil)
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("ベンチプレス").Return(&model.ExerciseCatalogImpl{ID: int64(1), Modality: model.ModalityWeighted}, nil)
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().CreateTx(gomock.Any(), date, int64(1), model.SessionStatusDraft).Return(&model.WorkoutSessionImpl{ID: int64(1), Date: date, UserID: int64(1), Status: model.SessionStatusDraft}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().CreateTx(gomock.Any(), int64(1), "ベンチプレス", int64(1), model.ModalityWeighted).Return(&model.ExerciseImpl{ID: int64(1), SessionID: int64(1), ExerciseName: "ベンチプレス"}, nil)
+				TargetSet := mock_model.NewMockTargetSet(ctrl)
+				TargetSet.EXPECT().CreateTx(gomock.Any(), int64(1), target(1, 8)).Return(&model.TargetSetImpl{}, nil)
+				TargetSet.EXPECT().CreateTx(gomock.Any(), int64(1), target(2, 8)).Return(&model.TargetSetImpl{}, nil)
+				return fields{
+					WorkoutSession:  WorkoutSession,
+					Exercise:        Exercise,
+					TargetSet:       TargetSet,
+					ExerciseCatalog: ExerciseCatalog,
+					Recommendation:  Recommendation,
+				}
+			},
+			assertion: func(r *response.GetWorkoutSession, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), r.ID)
+				if assert.Len(t, r.Exercises, 1) {
+					assert.Len(t, r.Exercises[0].TargetSets, 2)
+				}
+			},
+		},
+		{
+			testCase: "正常系(メニューの内容を指定)",
+			args: args{
+				userId: int64(1),
+				date:   date,
+				menu: &form.TrainingMenu{
+					Title: "背中の日",
+					Exercises: []form.MenuExercise{
+						{Name: "懸垂", Sets: 1, Reps: 10, RestSeconds: 90},
+					},
+				},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				ExerciseCatalog := mock_model.NewMockExerciseCatalog(ctrl)
+				ExerciseCatalog.EXPECT().Resolve("懸垂").Return(&model.ExerciseCatalogImpl{ID: int64(18), Modality: model.ModalityBodyweight}, nil)
+				WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+				WorkoutSession.EXPECT().CreateTx(gomock.Any(), date, int64(1), model.SessionStatusDraft).Return(&model.WorkoutSessionImpl{ID: int64(2), Date: date, UserID: int64(1), Status: model.SessionStatusDraft}, nil)
+				Exercise := mock_model.NewMockExercise(ctrl)
+				Exercise.EXPECT().CreateTx(gomock.Any(), int64(2), "懸垂", int64(18), model.ModalityBodyweight).Return(&model.ExerciseImpl{ID: int64(3), SessionID: int64(2), ExerciseName: "懸垂"}, nil)
+				TargetSet := mock_model.NewMockTargetSet(ctrl)
+				TargetSet.EXPECT().CreateTx(gomock.Any(), int64(3), target(1, 10)).Return(&model.TargetSetImpl{}, nil)
+				return fields{
+					WorkoutSession:  WorkoutSession,
+					Exercise:        Exercise,
+					TargetSet:       TargetSet,
+					ExerciseCatalog: ExerciseCatalog,
+				}
+			},
+			assertion: func(r *response.GetWorkoutSession, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(2), r.ID)
+				assert.Len(t, r.Exercises, 1)
+			},
+		},
+		{
+			testCase: "エラー(他のユーザーの提案)",
+			args: args{
+				userId:           int64(2),
+				date:             date,
+				recommendationId: int64(7),
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				Recommendation := mock_model.NewMockRecommendation(ctrl)
+				Recommendation.EXPECT().Load(int64(7)).Return(&model.RecommendationImpl{ID: int64(7), UserID: int64(1), Menu: saved}, nil)
+				return fields{Recommendation: Recommendation}
+			},
+			assertion: func(r *response.GetWorkoutSession, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(IDと内容の両方を指定)",
+			args: args{
+				userId:           int64(1),
+				date:             date,
+				recommendationId: int64(7),
+				menu:             &form.TrainingMenu{Exercises: []form.MenuExercise{{Name: "懸垂", Sets: 1, Reps: 10}}},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r *response.GetWorkoutSession, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(IDも内容も指定なし)",
+			args: args{
+				userId: int64(1),
+				date:   date,
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r *response.GetWorkoutSession, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+		{
+			testCase: "エラー(セット数が範囲外)",
+			args: args{
+				userId: int64(1),
+				date:   date,
+				menu:   &form.TrainingMenu{Exercises: []form.MenuExercise{{Name: "懸垂", Sets: 0, Reps: 10}}},
+			},
+			fields: func(ctrl *gomock.Controller) fields {
+				return fields{}
+			},
+			assertion: func(r *response.GetWorkoutSession, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				assert.Nil(t, r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			fields := tt.fields(ctrl)
+			w := &WorkoutImpl{
+				WorkoutSession:  fields.WorkoutSession,
+				Exercise:        fields.Exercise,
+				TargetSet:       fields.TargetSet,
+				ExerciseCatalog: fields.ExerciseCatalog,
+				Recommendation:  fields.Recommendation,
+				Transaction:     noTransaction,
+			}
+			tt.assertion(w.CreateFromRecommendation(tt.args.userId, tt.args.date, tt.args.recommendationId, tt.args.menu))
+		})
+	}
+}
+
+// BenchmarkWorkoutGet エクササイズ数が増えてもクエリ数が一定であることを確認
+func BenchmarkWorkoutGet(b *testing.B) {
+	for _, n := range []int{1, 12, 48} {
+		n := n
+		b.Run(fmt.Sprintf("exercises=%d", n), func(b *testing.B) {
+			ctrl := gomock.NewController(b)
+			queries := 0
+
+			exercises := model.Exercises{}
+			sets := model.Sets{}
+			for i := 1; i <= n; i++ {
+				exercises = append(exercises, model.ExerciseImpl{ID: int64(i), SessionID: int64(1), ExerciseName: "test"})
+				for j := 1; j <= 3; j++ {
+					sets = append(sets, model.SetImpl{ID: int64(i*3 + j), ExerciseID: int64(i), SetNumber: int64(j), Weight: float64(10), Reps: int64(10)})
+				}
+			}
+
+			WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+			WorkoutSession.EXPECT().Load(int64(1)).DoAndReturn(func(id int64) (*model.WorkoutSessionImpl, error) {
+				queries++
+				return &model.WorkoutSessionImpl{ID: id, Date: time.Now(), UserID: int64(1)}, nil
+			}).AnyTimes()
+			Exercise := mock_model.NewMockExercise(ctrl)
+			Exercise.EXPECT().LoadBySessionID(int64(1)).DoAndReturn(func(sessionId int64) (*model.Exercises, error) {
+				queries++
+				return &exercises, nil
+			}).AnyTimes()
+			Set := mock_model.NewMockSet(ctrl)
+			Set.EXPECT().LoadByExerciseIDs(gomock.Any()).DoAndReturn(func(exerciseIds []int64) (*model.Sets, error) {
+				queries++
+				return &sets, nil
+			}).AnyTimes()
+			Set.EXPECT().LoadByExerciseID(gomock.Any()).Times(0)
+			TargetSet := mock_model.NewMockTargetSet(ctrl)
+			TargetSet.EXPECT().LoadByExerciseIDs(gomock.Any()).DoAndReturn(func(exerciseIds []int64) (*model.TargetSets, error) {
+				queries++
+				return model.NewTargetSets(), nil
+			}).AnyTimes()
+
+			w := &WorkoutImpl{
+				WorkoutSession: WorkoutSession,
+				Exercise:       Exercise,
+				Set:            Set,
+				TargetSet:      TargetSet,
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := w.Get(int64(1), int64(1), metrics.DefaultFormula); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			perOp := float64(queries) / float64(b.N)
+			b.ReportMetric(perOp, "queries/op")
+			if perOp != 4 {
+				b.Errorf("queries/op = %v, want 4 regardless of exercise count", perOp)
+			}
+		})
+	}
+}
+
+func TestSetDetailFromForm(t *testing.T) {
+	t.Parallel()
+	rpe := float64(8.5)
+	invalidRPE := float64(8.3)
+	rir := int64(2)
+	rest := int64(90)
+	tests := []struct {
+		testCase  string
+		set       form.CreateSet
+		assertion func(d model.SetDetail, err error)
+	}{
+		{
+			testCase: "正常系(種類の指定がない場合は本番セット)",
+			set:      form.CreateSet{},
+			assertion: func(d model.SetDetail, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, model.SetDetail{SetType: model.SetTypeWorking}, d)
+			},
+		},
+		{
+			testCase: "正常系(テンポの区切り文字を除いて大文字にする)",
+			set:      form.CreateSet{SetType: model.SetTypeWarmup, RPE: &rpe, RestSeconds: &rest, Tempo: "3-1-x-0", PerformedAt: "2024-10-01T19:30:00+09:00", Notes: " 軽め "},
+			assertion: func(d model.SetDetail, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, model.SetTypeWarmup, d.SetType)
+				assert.Equal(t, dbr.NewNullFloat64(rpe), d.RPE)
+				assert.False(t, d.RIR.Valid)
+				assert.Equal(t, dbr.NewNullInt64(rest), d.RestSeconds)
+				assert.Equal(t, "31X0", d.Tempo)
+				assert.Equal(t, time.Date(2024, 10, 1, 10, 30, 0, 0, time.UTC), d.PerformedAt.Time.UTC())
+				assert.Equal(t, "軽め", d.Notes)
+			},
+		},
+		{
+			testCase: "エラー(RPEとRIRの両方を指定)",
+			set:      form.CreateSet{RPE: &rpe, RIR: &rir},
+			assertion: func(d model.SetDetail, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+			},
+		},
+		{
+			testCase: "エラー(RPEが0.5刻みでない)",
+			set:      form.CreateSet{RPE: &invalidRPE},
+			assertion: func(d model.SetDetail, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+			},
+		},
+		{
+			testCase: "エラー(テンポが4桁でない)",
+			set:      form.CreateSet{Tempo: "31"},
+			assertion: func(d model.SetDetail, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			tt.assertion(setDetailFromForm(tt.set))
+		})
+	}
+}
+
+func TestValidateSetMeasure(t *testing.T) {
+	t.Parallel()
+	duration := model.SetDetail{DurationSeconds: dbr.NewNullInt64(60)}
+	distance := model.SetDetail{DistanceMeters: dbr.NewNullFloat64(5000)}
+	tests := []struct {
+		testCase string
+		modality string
+		weight   float64
+		reps     int64
+		detail   model.SetDetail
+		wantErr  bool
+	}{
+		{testCase: "正常系(ウェイト種目)", modality: model.ModalityWeighted, weight: float64(60), reps: int64(10)},
+		{testCase: "正常系(記録方法が未指定の場合はウェイト種目)", weight: float64(60), reps: int64(10)},
+		{testCase: "正常系(加重なしの自重種目)", modality: model.ModalityBodyweight, reps: int64(10)},
+		{testCase: "正常系(アシスト種目)", modality: model.ModalityAssisted, weight: float64(20), reps: int64(8)},
+		{testCase: "正常系(時間の種目)", modality: model.ModalityTimed, detail: duration},
+		{testCase: "正常系(距離のみの有酸素種目)", modality: model.ModalityDistance, detail: distance},
+		{testCase: "エラー(ウェイト種目の重量が0)", modality: model.ModalityWeighted, reps: int64(10), wantErr: true},
+		{testCase: "エラー(自重種目の回数が0)", modality: model.ModalityBodyweight, wantErr: true},
+		{testCase: "エラー(アシスト重量がない)", modality: model.ModalityAssisted, reps: int64(8), wantErr: true},
+		{testCase: "エラー(時間の種目の時間がない)", modality: model.ModalityTimed, reps: int64(1), wantErr: true},
+		{testCase: "エラー(有酸素種目の時間・距離がない)", modality: model.ModalityDistance, wantErr: true},
+		{testCase: "エラー(有酸素種目に重量を指定)", modality: model.ModalityDistance, weight: float64(10), detail: distance, wantErr: true},
+		{testCase: "エラー(有酸素種目以外に距離を指定)", modality: model.ModalityTimed, detail: model.SetDetail{DurationSeconds: dbr.NewNullInt64(60), DistanceMeters: dbr.NewNullFloat64(100)}, wantErr: true},
+		{testCase: "エラー(負の重量)", modality: model.ModalityBodyweight, weight: float64(-5), reps: int64(10), wantErr: true},
+		{testCase: "エラー(未知の記録方法)", modality: "swimming", reps: int64(10), wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			err := validateSetMeasure(tt.modality, tt.weight, tt.reps, tt.detail)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestWorkoutSessionLifecycle(t *testing.T) {
+	t.Parallel()
+	startedAt := time.Date(2024, 10, 1, 19, 0, 0, 0, time.UTC)
+	session := func(status string) *model.WorkoutSessionImpl {
+		m := &model.WorkoutSessionImpl{ID: int64(1), Date: startedAt, UserID: int64(1), Status: status}
+		if status != model.SessionStatusDraft {
+			m.StartedAt = dbr.NewNullTime(startedAt)
+		}
+		return m
+	}
+	tests := []struct {
+		testCase string
+		status   string
+		// 更新が成功する場合に保存される値
+		updated   map[string]interface{}
+		call      func(w *WorkoutImpl) error
+		assertion func(err error)
+	}{
+		{
+			testCase: "正常系(下書きのセッションを開始)",
+			status:   model.SessionStatusDraft,
+			updated:  map[string]interface{}{"status": model.SessionStatusInProgress, "started_at": startedAt.Add(time.Hour)},
+			call: func(w *WorkoutImpl) error {
+				_, err := w.StartWorkoutSession(int64(1), int64(1), time.Time{})
+				return err
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "正常系(実施中のセッションを評価とあわせて終了)",
+			status:   model.SessionStatusInProgress,
+			updated:  map[string]interface{}{"status": model.SessionStatusCompleted, "finished_at": startedAt.Add(time.Hour), "rating": int64(4)},
+			call: func(w *WorkoutImpl) error {
+				_, err := w.FinishWorkoutSession(int64(1), int64(1), time.Time{}, map[string]interface{}{"rating": int64(4)})
+				return err
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "正常系(実施済みのセッションを再開)",
+			status:   model.SessionStatusCompleted,
+			updated:  map[string]interface{}{"status": model.SessionStatusInProgress, "finished_at": nil},
+			call: func(w *WorkoutImpl) error {
+				_, err := w.ReopenWorkoutSession(int64(1), int64(1))
+				return err
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "正常系(実施済みのセッションのメモを更新)",
+			status:   model.SessionStatusCompleted,
+			updated:  map[string]interface{}{"notes": "良かった"},
+			call: func(w *WorkoutImpl) error {
+				_, err := w.UpdateWorkoutSession(int64(1), int64(1), map[string]interface{}{"notes": " 良かった "})
+				return err
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			testCase: "エラー(実施中のセッションは開始できない)",
+			status:   model.SessionStatusInProgress,
+			call: func(w *WorkoutImpl) error {
+				_, err := w.StartWorkoutSession(int64(1), int64(1), time.Time{})
+				return err
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+			},
+		},
+		{
+			testCase: "エラー(下書きのセッションは終了できない)",
+			status:   model.SessionStatusDraft,
+			call: func(w *WorkoutImpl) error {
+				_, err := w.FinishWorkoutSession(int64(1), int64(1), time.Time{}, map[string]interface{}{})
+				return err
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+			},
+		},
+		{
+			testCase: "エラー(終了日時が開始日時より前)",
+			status:   model.SessionStatusInProgress,
+			call: func(w *WorkoutImpl) error {
+				_, err := w.FinishWorkoutSession(int64(1), int64(1), startedAt.Add(-time.Minute), map[string]interface{}{})
+				return err
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+			},
+		},
+		{
+			testCase: "エラー(評価が範囲外)",
+			status:   model.SessionStatusInProgress,
+			call: func(w *WorkoutImpl) error {
+				_, err := w.FinishWorkoutSession(int64(1), int64(1), time.Time{}, map[string]interface{}{"rating": int64(6)})
+				return err
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+			},
+		},
+		{
+			testCase: "エラー(実施済みでないセッションは再開できない)",
+			status:   model.SessionStatusInProgress,
+			call: func(w *WorkoutImpl) error {
+				_, err := w.ReopenWorkoutSession(int64(1), int64(1))
+				return err
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+			},
+		},
+		{
+			testCase: "エラー(実施済みのセッションの日付は変更できない)",
+			status:   model.SessionStatusCompleted,
+			call: func(w *WorkoutImpl) error {
+				_, err := w.UpdateWorkoutSession(int64(1), int64(1), map[string]interface{}{"training_date": startedAt, "notes": "良かった"})
+				return err
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+			},
+		},
+		{
+			testCase: "エラー(実施済みのセッションにはエクササイズを追加できない)",
+			status:   model.SessionStatusCompleted,
+			call: func(w *WorkoutImpl) error {
+				_, err := w.CreateExercise(int64(1), int64(1), int64(0), "test", "")
+				return err
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+			},
+		},
+		{
+			testCase: "エラー(実施済みのセッションにはセットを追加できない)",
+			status:   model.SessionStatusCompleted,
+			call: func(w *WorkoutImpl) error {
+				_, _, err := w.CreateSet(int64(1), int64(1), int64(1), form.CreateSet{SetNumber: int64(1), Weight: float64(60), Reps: int64(10)})
+				return err
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+			},
+		},
+		{
+			testCase: "エラー(実施済みのセッションは削除できない)",
+			status:   model.SessionStatusCompleted,
+			call: func(w *WorkoutImpl) error {
+				return w.DeleteWorkoutSession(int64(1), int64(1))
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrConflict)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			WorkoutSession := mock_model.NewMockWorkoutSession(ctrl)
+			WorkoutSession.EXPECT().Load(int64(1)).Return(session(tt.status), nil)
+			if tt.updated != nil {
+				WorkoutSession.EXPECT().Update(int64(1), tt.updated).Return(true, nil)
+				WorkoutSession.EXPECT().Load(int64(1)).Return(session(tt.status), nil)
+			}
+			w := &WorkoutImpl{
+				WorkoutSession: WorkoutSession,
+				Now:            func() time.Time { return startedAt.Add(time.Hour) },
 			}
-			tt.assertion(w.CreateSet(tt.args.exerciseID, tt.args.setNumber, tt.args.weight, tt.args.reps))
+			tt.assertion(tt.call(w))
 		})
 	}
 }
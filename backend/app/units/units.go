@@ -0,0 +1,87 @@
+package units
+
+import (
+	"fmt"
+	"math"
+)
+
+// Unit 重量の単位
+type Unit string
+
+const (
+	Kilogram Unit = "kg"
+	Pound    Unit = "lb"
+
+	// DefaultUnit 指定がない場合の単位。重量はこの単位で保存する
+	DefaultUnit = Kilogram
+
+	// kilogramsPerPound 1lbあたりのkg
+	kilogramsPerPound = 0.45359237
+)
+
+// plateIncrements 単位ごとに付け外しできる重量の最小の刻み
+var plateIncrements = map[Unit]float64{
+	Kilogram: 0.5,
+	Pound:    1,
+}
+
+// ParseUnit 文字列から単位を取得。空文字の場合は既定の単位を返却
+func ParseUnit(s string) (Unit, error) {
+	switch u := Unit(s); u {
+	case "":
+		return DefaultUnit, nil
+	case Kilogram, Pound:
+		return u, nil
+	default:
+		return "", fmt.Errorf("unknown unit %q", s)
+	}
+}
+
+// orDefault 空の単位を既定の単位として扱う。単位を記録する前のデータは既定の単位とみなす
+func (u Unit) orDefault() Unit {
+	if u == "" {
+		return DefaultUnit
+	}
+	return u
+}
+
+// ToKilograms 指定の単位の重量を保存用にkgへ変換。ポンドからの変換でも元に戻せるよう小数点以下3桁に丸める
+func ToKilograms(weight float64, unit Unit) float64 {
+	if unit.orDefault() == Pound {
+		return round(weight*kilogramsPerPound, 1000)
+	}
+	return weight
+}
+
+// Convert ボリューム・推定1RMなどの重量から求めた値を単位間で変換し、小数点以下2桁に丸める
+func Convert(value float64, from Unit, to Unit) float64 {
+	from, to = from.orDefault(), to.orDefault()
+	if from == to {
+		return value
+	}
+	if from == Pound {
+		return round(value*kilogramsPerPound, 100)
+	}
+	return round(value/kilogramsPerPound, 100)
+}
+
+// Weight 保存したkgの重量を表示する単位に変換
+// 入力時と同じ単位ではその重量に戻し、異なる単位ではプレートで組める重量に丸める
+func Weight(kilograms float64, entered Unit, unit Unit) float64 {
+	entered, unit = entered.orDefault(), unit.orDefault()
+	weight := Convert(kilograms, Kilogram, unit)
+	if entered == unit {
+		return weight
+	}
+	return RoundToPlate(weight, unit)
+}
+
+// RoundToPlate 重量をその単位のプレートの刻みに丸める
+func RoundToPlate(weight float64, unit Unit) float64 {
+	increment := plateIncrements[unit.orDefault()]
+	return math.Round(weight/increment) * increment
+}
+
+func round(v float64, scale float64) float64 {
+	return math.Round(v*scale) / scale
+}
@@ -0,0 +1,53 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeight(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		testCase string
+		weight   float64
+		entered  Unit
+		unit     Unit
+		want     float64
+	}{
+		{testCase: "kgで入力しkgで表示", weight: 62.3, entered: Kilogram, unit: Kilogram, want: 62.3},
+		{testCase: "lbで入力しlbで表示", weight: ToKilograms(225, Pound), entered: Pound, unit: Pound, want: 225},
+		{testCase: "lbで入力しkgで表示", weight: ToKilograms(225, Pound), entered: Pound, unit: Kilogram, want: 102},
+		{testCase: "kgで入力しlbで表示", weight: 100, entered: Kilogram, unit: Pound, want: 220},
+		{testCase: "単位の記録がない場合はkg", weight: 100, entered: "", unit: "", want: 100},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testCase, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, Weight(tt.weight, tt.entered, tt.unit))
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, float64(1102.31), Convert(500, Kilogram, Pound))
+	assert.Equal(t, float64(500), Convert(1102.31, Pound, Kilogram))
+	assert.Equal(t, float64(500), Convert(500, Kilogram, Kilogram))
+	assert.Equal(t, float64(102.058), ToKilograms(225, Pound))
+}
+
+func TestParseUnit(t *testing.T) {
+	t.Parallel()
+	u, err := ParseUnit("")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultUnit, u)
+
+	u, err = ParseUnit("lb")
+	assert.NoError(t, err)
+	assert.Equal(t, Pound, u)
+
+	_, err = ParseUnit("stone")
+	assert.Error(t, err)
+}
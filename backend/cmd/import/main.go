@@ -0,0 +1,102 @@
+// importはStrong・Hevy・FitNotesのCSVをユーザーのワークアウトとして取り込む
+//
+// backendディレクトリで実行する:
+//
+//	go run ./cmd/import -user 1 -file strong.csv -dry-run
+//	go run ./cmd/import -user 1 -file strong.csv -map "Bench Press (Barbell)=ベンチプレス"
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/service"
+	"github.com/everytv/pre-employment-training-2024/final/ikuma.esaki/backend/app/units"
+)
+
+// mappingFlag "取り込み元の種目名=種目名"の形式で複数回指定できる対応づけ
+type mappingFlag map[string]string
+
+func (m mappingFlag) String() string {
+	return fmt.Sprint(map[string]string(m))
+}
+
+func (m mappingFlag) Set(s string) error {
+	source, target, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("mapping must be SOURCE=TARGET: %q", s)
+	}
+	m[strings.TrimSpace(source)] = strings.TrimSpace(target)
+	return nil
+}
+
+func main() {
+	mapping := mappingFlag{}
+	userID := flag.Int64("user", 0, "取り込み先のユーザーID")
+	file := flag.String("file", "", "取り込むCSVのパス。-の場合は標準入力")
+	format := flag.String("format", "", "CSVの形式(strong, hevy, fitnotes)。未指定の場合はヘッダから判別")
+	unit := flag.String("unit", "", "重量の単位を記録しない形式での単位(kg, lb)。未指定の場合はユーザーの設定")
+	dryRun := flag.Bool("dry-run", false, "保存せずに取り込む内容を表示")
+	mappingFile := flag.String("mapping-file", "", "取り込み元の種目名から種目名への対応づけのJSONファイル")
+	flag.Var(mapping, "map", "取り込み元の種目名=種目名・別名・カタログID(複数指定可)")
+	flag.Parse()
+
+	if *userID == 0 || *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *mappingFile != "" {
+		b, err := os.ReadFile(*mappingFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fromFile := map[string]string{}
+		if err := json.Unmarshal(b, &fromFile); err != nil {
+			log.Fatalf("invalid mapping file: %v", err)
+		}
+		// -mapの指定を優先する
+		for source, target := range fromFile {
+			if _, ok := mapping[source]; !ok {
+				mapping[source] = target
+			}
+		}
+	}
+
+	weightUnit, err := units.ParseUnit(*unit)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *unit == "" {
+		if weightUnit, err = service.NewUser().WeightUnit(*userID); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var r io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	result, err := service.NewImport().Workouts(*userID, r, *format, weightUnit, mapping, *dryRun)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(result); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,4 @@
+-- +migrate Up
+CREATE INDEX idx_workout_sessions_user_date ON workout_sessions (user_id, training_date, session_id);
+CREATE INDEX idx_workout_sessions_date ON workout_sessions (training_date, session_id);
+CREATE INDEX idx_exercises_name ON exercises (exercise_name);
@@ -0,0 +1,106 @@
+-- +migrate Up
+CREATE TABLE exercise_catalog (
+    catalog_id INT AUTO_INCREMENT PRIMARY KEY,
+    name_ja VARCHAR(255) NOT NULL,
+    name_en VARCHAR(255) NOT NULL,
+    primary_muscle VARCHAR(64) NOT NULL,
+    secondary_muscles VARCHAR(255) NOT NULL DEFAULT '',
+    equipment VARCHAR(64) NOT NULL DEFAULT '',
+    UNIQUE KEY uq_exercise_catalog_name_ja (name_ja),
+    UNIQUE KEY uq_exercise_catalog_name_en (name_en)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE exercise_aliases (
+    alias_id INT AUTO_INCREMENT PRIMARY KEY,
+    catalog_id INT NOT NULL,
+    alias VARCHAR(255) NOT NULL,
+    locale VARCHAR(8) NOT NULL DEFAULT '',
+    UNIQUE KEY uq_exercise_aliases_alias (alias),
+    FOREIGN KEY (catalog_id) REFERENCES exercise_catalog(catalog_id)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+ALTER TABLE exercises ADD COLUMN catalog_id INT NULL AFTER session_id;
+ALTER TABLE exercises ADD FOREIGN KEY (catalog_id) REFERENCES exercise_catalog(catalog_id);
+
+INSERT INTO exercise_catalog (catalog_id, name_ja, name_en, primary_muscle, secondary_muscles, equipment) VALUES
+    (1, 'ベンチプレス', 'Bench Press', 'chest', 'triceps,shoulders', 'barbell'),
+    (2, 'インクラインベンチプレス', 'Incline Bench Press', 'chest', 'shoulders,triceps', 'barbell'),
+    (3, 'ダンベルプレス', 'Dumbbell Bench Press', 'chest', 'triceps,shoulders', 'dumbbell'),
+    (4, 'チェストプレス', 'Chest Press', 'chest', 'triceps,shoulders', 'machine'),
+    (5, 'ダンベルフライ', 'Dumbbell Fly', 'chest', 'shoulders', 'dumbbell'),
+    (6, '腕立て伏せ', 'Push-up', 'chest', 'triceps,shoulders,core', 'bodyweight'),
+    (7, 'ディップス', 'Dip', 'chest', 'triceps,shoulders', 'bodyweight'),
+    (8, 'スクワット', 'Back Squat', 'quads', 'glutes,hamstrings,core', 'barbell'),
+    (9, 'フロントスクワット', 'Front Squat', 'quads', 'glutes,core', 'barbell'),
+    (10, 'レッグプレス', 'Leg Press', 'quads', 'glutes,hamstrings', 'machine'),
+    (11, 'レッグエクステンション', 'Leg Extension', 'quads', '', 'machine'),
+    (12, 'ブルガリアンスクワット', 'Bulgarian Split Squat', 'quads', 'glutes,hamstrings', 'dumbbell'),
+    (13, 'デッドリフト', 'Deadlift', 'back', 'hamstrings,glutes,traps,forearms', 'barbell'),
+    (14, 'ルーマニアンデッドリフト', 'Romanian Deadlift', 'hamstrings', 'glutes,back', 'barbell'),
+    (15, 'レッグカール', 'Leg Curl', 'hamstrings', '', 'machine'),
+    (16, 'ヒップスラスト', 'Hip Thrust', 'glutes', 'hamstrings', 'barbell'),
+    (17, 'カーフレイズ', 'Calf Raise', 'calves', '', 'machine'),
+    (18, '懸垂', 'Pull-up', 'back', 'biceps,forearms', 'bodyweight'),
+    (19, 'ラットプルダウン', 'Lat Pulldown', 'back', 'biceps', 'machine'),
+    (20, 'ベントオーバーロウ', 'Barbell Row', 'back', 'biceps,traps', 'barbell'),
+    (21, 'シーテッドロウ', 'Seated Cable Row', 'back', 'biceps', 'cable'),
+    (22, 'ワンハンドロウ', 'One-arm Dumbbell Row', 'back', 'biceps', 'dumbbell'),
+    (23, 'ショルダープレス', 'Overhead Press', 'shoulders', 'triceps', 'barbell'),
+    (24, 'ダンベルショルダープレス', 'Dumbbell Shoulder Press', 'shoulders', 'triceps', 'dumbbell'),
+    (25, 'サイドレイズ', 'Lateral Raise', 'shoulders', '', 'dumbbell'),
+    (26, 'リアレイズ', 'Rear Delt Fly', 'shoulders', 'back', 'dumbbell'),
+    (27, 'シュラッグ', 'Shrug', 'traps', 'forearms', 'dumbbell'),
+    (28, 'バーベルカール', 'Barbell Curl', 'biceps', 'forearms', 'barbell'),
+    (29, 'ダンベルカール', 'Dumbbell Curl', 'biceps', 'forearms', 'dumbbell'),
+    (30, 'ハンマーカール', 'Hammer Curl', 'biceps', 'forearms', 'dumbbell'),
+    (31, 'トライセプスプッシュダウン', 'Triceps Pushdown', 'triceps', '', 'cable'),
+    (32, 'スカルクラッシャー', 'Skull Crusher', 'triceps', '', 'barbell'),
+    (33, 'プランク', 'Plank', 'core', 'shoulders', 'bodyweight'),
+    (34, 'クランチ', 'Crunch', 'core', '', 'bodyweight'),
+    (35, 'ハンギングレッグレイズ', 'Hanging Leg Raise', 'core', 'forearms', 'bodyweight');
+
+INSERT INTO exercise_aliases (catalog_id, alias, locale) VALUES
+    (1, 'ベンチ', 'ja'),
+    (1, 'bench', 'en'),
+    (1, 'BP', 'en'),
+    (1, 'Flat Bench Press', 'en'),
+    (2, 'インクラインベンチ', 'ja'),
+    (2, 'Incline Bench', 'en'),
+    (3, 'DBプレス', 'ja'),
+    (3, 'DB Bench Press', 'en'),
+    (6, 'プッシュアップ', 'ja'),
+    (6, 'Push Up', 'en'),
+    (6, 'Pushup', 'en'),
+    (7, 'Dips', 'en'),
+    (8, 'バックスクワット', 'ja'),
+    (8, 'Squat', 'en'),
+    (8, 'SQ', 'en'),
+    (12, 'ブルガリアンスプリットスクワット', 'ja'),
+    (13, 'デッド', 'ja'),
+    (13, 'DL', 'en'),
+    (13, 'Conventional Deadlift', 'en'),
+    (14, 'RDL', 'en'),
+    (18, 'チンニング', 'ja'),
+    (18, 'Chin-up', 'en'),
+    (18, 'Pull Up', 'en'),
+    (19, 'Lat Pull Down', 'en'),
+    (20, 'バーベルロウ', 'ja'),
+    (20, 'Bent Over Row', 'en'),
+    (23, 'オーバーヘッドプレス', 'ja'),
+    (23, 'OHP', 'en'),
+    (23, 'Military Press', 'en'),
+    (25, 'サイドレイズ(ダンベル)', 'ja'),
+    (25, 'Side Raise', 'en'),
+    (28, 'アームカール', 'ja'),
+    (31, 'プッシュダウン', 'ja'),
+    (31, 'Pushdown', 'en');
+
+-- 既存の記録を名前・別名からカタログに紐づける
+UPDATE exercises e
+    JOIN exercise_catalog c ON e.exercise_name IN (c.name_ja, c.name_en)
+    SET e.catalog_id = c.catalog_id
+    WHERE e.catalog_id IS NULL;
+UPDATE exercises e
+    JOIN exercise_aliases a ON e.exercise_name = a.alias
+    SET e.catalog_id = a.catalog_id
+    WHERE e.catalog_id IS NULL;
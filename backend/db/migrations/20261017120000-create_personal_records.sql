@@ -0,0 +1,17 @@
+-- +migrate Up
+-- 種目ごとの自己ベスト。カタログに紐づく種目はcatalog_id、紐づかない種目はexercise_nameで区別する
+CREATE TABLE personal_records (
+    record_id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    catalog_id INT NOT NULL DEFAULT 0,
+    exercise_name VARCHAR(255) NOT NULL DEFAULT '',
+    record_type VARCHAR(32) NOT NULL,
+    weight_class DECIMAL(7,2) NOT NULL DEFAULT 0,
+    value DOUBLE NOT NULL,
+    weight DECIMAL(7,2) NOT NULL DEFAULT 0,
+    reps INT NOT NULL DEFAULT 0,
+    session_id INT NOT NULL,
+    set_id INT NOT NULL DEFAULT 0,
+    achieved_on DATE NOT NULL,
+    UNIQUE KEY uq_personal_records (user_id, catalog_id, exercise_name, record_type, weight_class)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
@@ -0,0 +1,14 @@
+-- +migrate Up
+-- 重量はkgに揃えて保存し、入力時の単位をあわせて記録する。ポンドからの変換で精度が落ちないよう小数点以下3桁とする
+ALTER TABLE sets
+    MODIFY weight DECIMAL(8,3) NOT NULL,
+    ADD COLUMN unit VARCHAR(2) NOT NULL DEFAULT 'kg' AFTER weight;
+
+ALTER TABLE personal_records
+    MODIFY weight_class DECIMAL(8,3) NOT NULL DEFAULT 0,
+    MODIFY weight DECIMAL(8,3) NOT NULL DEFAULT 0,
+    ADD COLUMN unit VARCHAR(2) NOT NULL DEFAULT 'kg' AFTER weight;
+
+-- 重量を表示する単位の設定
+ALTER TABLE users
+    ADD COLUMN weight_unit VARCHAR(2) NOT NULL DEFAULT 'kg' AFTER name;
@@ -0,0 +1,10 @@
+-- +migrate Up
+-- セットの種類・主観的運動強度(RPEまたはRIR)・休憩時間・テンポ・実施日時・メモ
+ALTER TABLE sets
+    ADD COLUMN set_type VARCHAR(16) NOT NULL DEFAULT 'working' AFTER reps,
+    ADD COLUMN rpe DECIMAL(3,1) NULL AFTER set_type,
+    ADD COLUMN rir INT NULL AFTER rpe,
+    ADD COLUMN rest_seconds INT NULL AFTER rir,
+    ADD COLUMN tempo VARCHAR(16) NOT NULL DEFAULT '' AFTER rest_seconds,
+    ADD COLUMN performed_at DATETIME NULL AFTER tempo,
+    ADD COLUMN notes VARCHAR(1000) NOT NULL DEFAULT '' AFTER performed_at;
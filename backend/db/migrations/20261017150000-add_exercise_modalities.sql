@@ -0,0 +1,33 @@
+-- +migrate Up
+-- 種目の記録方法(weighted, bodyweight, assisted, timed, distance)
+ALTER TABLE exercise_catalog ADD COLUMN modality VARCHAR(16) NOT NULL DEFAULT 'weighted' AFTER equipment;
+ALTER TABLE exercises ADD COLUMN modality VARCHAR(16) NOT NULL DEFAULT 'weighted' AFTER exercise_name;
+
+-- セットの時間と距離。自重種目の加重・アシスト種目のアシスト重量はweightに記録する
+ALTER TABLE sets
+    ADD COLUMN duration_seconds INT NULL AFTER reps,
+    ADD COLUMN distance_meters DECIMAL(10,2) NULL AFTER duration_seconds;
+
+UPDATE exercise_catalog SET modality = 'bodyweight' WHERE catalog_id IN (6, 7, 18, 34, 35);
+UPDATE exercise_catalog SET modality = 'timed' WHERE catalog_id = 33;
+
+INSERT INTO exercise_catalog (catalog_id, name_ja, name_en, primary_muscle, secondary_muscles, equipment, modality) VALUES
+    (36, 'アシスト懸垂', 'Assisted Pull-up', 'back', 'biceps,forearms', 'machine', 'assisted'),
+    (37, 'アシストディップス', 'Assisted Dip', 'chest', 'triceps,shoulders', 'machine', 'assisted'),
+    (38, 'ランニング', 'Running', 'cardio', 'quads,hamstrings,calves', 'bodyweight', 'distance'),
+    (39, 'サイクリング', 'Cycling', 'cardio', 'quads,glutes', 'machine', 'distance'),
+    (40, 'ローイングマシン', 'Rowing Machine', 'cardio', 'back,quads', 'machine', 'distance');
+
+INSERT INTO exercise_aliases (catalog_id, alias, locale) VALUES
+    (36, 'アシストチンニング', 'ja'),
+    (38, 'ジョギング', 'ja'),
+    (38, 'Treadmill Run', 'en'),
+    (38, 'Jogging', 'en'),
+    (39, 'エアロバイク', 'ja'),
+    (39, 'Stationary Bike', 'en'),
+    (40, 'Rower', 'en');
+
+-- 既存のエクササイズは紐づくカタログの記録方法に揃える
+UPDATE exercises e
+    JOIN exercise_catalog c ON c.catalog_id = e.catalog_id
+    SET e.modality = c.modality;
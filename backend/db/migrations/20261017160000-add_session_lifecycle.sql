@@ -0,0 +1,11 @@
+-- +migrate Up
+-- セッションの状態(draft, in_progress, completed)・開始/終了日時・メモ・評価
+ALTER TABLE workout_sessions
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'draft' AFTER user_id,
+    ADD COLUMN started_at DATETIME NULL AFTER status,
+    ADD COLUMN finished_at DATETIME NULL AFTER started_at,
+    ADD COLUMN notes VARCHAR(2000) NOT NULL DEFAULT '' AFTER finished_at,
+    ADD COLUMN rating TINYINT NULL AFTER notes;
+
+-- 既存の記録は実施済みのセッションとして扱う
+UPDATE workout_sessions SET status = 'completed';
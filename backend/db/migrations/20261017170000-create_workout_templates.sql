@@ -0,0 +1,54 @@
+-- +migrate Up
+-- ワークアウトのテンプレート。ユーザーごとに名前で区別する
+CREATE TABLE workout_templates (
+    template_id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    UNIQUE KEY uq_workout_templates (user_id, name)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- テンプレートの種目。positionの順に並べる
+CREATE TABLE template_exercises (
+    template_exercise_id INT AUTO_INCREMENT PRIMARY KEY,
+    template_id INT NOT NULL,
+    position INT NOT NULL,
+    catalog_id INT NULL,
+    exercise_name VARCHAR(255) NOT NULL,
+    modality VARCHAR(16) NOT NULL DEFAULT 'weighted',
+    FOREIGN KEY (template_id) REFERENCES workout_templates(template_id),
+    FOREIGN KEY (catalog_id) REFERENCES exercise_catalog(catalog_id)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- テンプレートの種目ごとの目標セット。重量はsetsと同じくkgで保存する
+CREATE TABLE template_sets (
+    template_set_id INT AUTO_INCREMENT PRIMARY KEY,
+    template_exercise_id INT NOT NULL,
+    set_number INT NOT NULL,
+    set_type VARCHAR(16) NOT NULL DEFAULT 'working',
+    weight DECIMAL(8,3) NOT NULL DEFAULT 0,
+    unit VARCHAR(2) NOT NULL DEFAULT 'kg',
+    reps INT NOT NULL DEFAULT 0,
+    duration_seconds INT NULL,
+    distance_meters DECIMAL(10,2) NULL,
+    FOREIGN KEY (template_exercise_id) REFERENCES template_exercises(template_exercise_id)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- セッションのエクササイズごとの目標セット。前回の重量・回数を目安として事前に入れておく
+CREATE TABLE target_sets (
+    target_set_id INT AUTO_INCREMENT PRIMARY KEY,
+    exercise_id INT NOT NULL,
+    set_number INT NOT NULL,
+    set_type VARCHAR(16) NOT NULL DEFAULT 'working',
+    weight DECIMAL(8,3) NOT NULL DEFAULT 0,
+    unit VARCHAR(2) NOT NULL DEFAULT 'kg',
+    reps INT NOT NULL DEFAULT 0,
+    duration_seconds INT NULL,
+    distance_meters DECIMAL(10,2) NULL,
+    FOREIGN KEY (exercise_id) REFERENCES exercises(exercise_id)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- テンプレートから作成したセッション。テンプレートを削除しても記録は残す
+ALTER TABLE workout_sessions
+    ADD COLUMN template_id INT NULL AFTER user_id,
+    ADD CONSTRAINT fk_workout_sessions_template FOREIGN KEY (template_id) REFERENCES workout_templates(template_id) ON DELETE SET NULL,
+    ADD INDEX idx_workout_sessions_template (user_id, template_id, training_date);
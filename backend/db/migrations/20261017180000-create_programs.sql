@@ -0,0 +1,73 @@
+-- +migrate Up
+-- 複数週のトレーニングプログラム。weeks週の各曜日に予定の日を割り当てる
+CREATE TABLE programs (
+    program_id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    weeks INT NOT NULL,
+    UNIQUE KEY uq_programs (user_id, name)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- プログラムの予定の日。weekは1始まりの週、dayは週の開始日から数えた1〜7日目
+CREATE TABLE program_days (
+    program_day_id INT AUTO_INCREMENT PRIMARY KEY,
+    program_id INT NOT NULL,
+    week INT NOT NULL,
+    day INT NOT NULL,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    UNIQUE KEY uq_program_days (program_id, week, day),
+    FOREIGN KEY (program_id) REFERENCES programs(program_id)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- 予定の日の種目。positionの順に並べる
+CREATE TABLE program_exercises (
+    program_exercise_id INT AUTO_INCREMENT PRIMARY KEY,
+    program_day_id INT NOT NULL,
+    position INT NOT NULL,
+    catalog_id INT NULL,
+    exercise_name VARCHAR(255) NOT NULL,
+    modality VARCHAR(16) NOT NULL DEFAULT 'weighted',
+    FOREIGN KEY (program_day_id) REFERENCES program_days(program_day_id),
+    FOREIGN KEY (catalog_id) REFERENCES exercise_catalog(catalog_id)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- 予定のセット。重量はトレーニングマックスに対する割合(%)で指定し、割合がない場合は重量を指定しない
+CREATE TABLE program_sets (
+    program_set_id INT AUTO_INCREMENT PRIMARY KEY,
+    program_exercise_id INT NOT NULL,
+    set_number INT NOT NULL,
+    set_type VARCHAR(16) NOT NULL DEFAULT 'working',
+    percentage DECIMAL(5,2) NULL,
+    reps INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (program_exercise_id) REFERENCES program_exercises(program_exercise_id)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- プログラムへの参加。start_dateを1週目の1日目とする
+CREATE TABLE program_enrollments (
+    enrollment_id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    program_id INT NOT NULL,
+    start_date DATE NOT NULL,
+    FOREIGN KEY (program_id) REFERENCES programs(program_id),
+    INDEX idx_program_enrollments_user (user_id, start_date)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- 参加時の種目ごとのトレーニングマックス。personal_recordsと同じくcatalog_idかexercise_nameで種目を区別する
+CREATE TABLE training_maxes (
+    training_max_id INT AUTO_INCREMENT PRIMARY KEY,
+    enrollment_id INT NOT NULL,
+    catalog_id INT NOT NULL DEFAULT 0,
+    exercise_name VARCHAR(255) NOT NULL DEFAULT '',
+    weight DECIMAL(8,3) NOT NULL,
+    unit VARCHAR(2) NOT NULL DEFAULT 'kg',
+    UNIQUE KEY uq_training_maxes (enrollment_id, catalog_id, exercise_name),
+    FOREIGN KEY (enrollment_id) REFERENCES program_enrollments(enrollment_id)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- プログラムの予定の日として実施したセッション
+ALTER TABLE workout_sessions
+    ADD COLUMN enrollment_id INT NULL AFTER template_id,
+    ADD COLUMN program_day_id INT NULL AFTER enrollment_id,
+    ADD CONSTRAINT fk_workout_sessions_enrollment FOREIGN KEY (enrollment_id) REFERENCES program_enrollments(enrollment_id),
+    ADD CONSTRAINT fk_workout_sessions_program_day FOREIGN KEY (program_day_id) REFERENCES program_days(program_day_id),
+    ADD INDEX idx_workout_sessions_enrollment (enrollment_id, program_day_id);
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- 目標セットに目標のRPEを記録する。テンプレートの目標セットも同じ項目を持つ
+ALTER TABLE target_sets
+    ADD COLUMN rpe DECIMAL(3,1) NULL AFTER distance_meters;
+
+ALTER TABLE template_sets
+    ADD COLUMN rpe DECIMAL(3,1) NULL AFTER distance_meters;
@@ -0,0 +1,19 @@
+-- +migrate Up
+-- 種目ごとの重量・回数の進め方。personal_recordsと同じくcatalog_idかexercise_nameで種目を区別する
+-- incrementはkgで保存し、unitに入力時の単位を記録する。deload_afterが0の場合はディロードしない
+CREATE TABLE progression_rules (
+    rule_id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    catalog_id INT NOT NULL DEFAULT 0,
+    exercise_name VARCHAR(255) NOT NULL DEFAULT '',
+    strategy VARCHAR(16) NOT NULL,
+    increment DECIMAL(8,3) NOT NULL,
+    unit VARCHAR(2) NOT NULL DEFAULT 'kg',
+    sets INT NOT NULL,
+    min_reps INT NOT NULL,
+    max_reps INT NOT NULL,
+    target_rpe DECIMAL(3,1) NULL,
+    deload_after INT NOT NULL DEFAULT 3,
+    deload_percentage DECIMAL(5,2) NOT NULL DEFAULT 10,
+    UNIQUE KEY uq_progression_rules (user_id, catalog_id, exercise_name)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
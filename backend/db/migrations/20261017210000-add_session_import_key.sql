@@ -0,0 +1,5 @@
+-- +migrate Up
+-- 他のアプリから取り込んだセッションの取り込み元を表すキー。再取り込み時の重複の判定に使う
+ALTER TABLE workout_sessions
+    ADD COLUMN import_key VARCHAR(64) NULL AFTER rating,
+    ADD UNIQUE KEY uq_workout_sessions_import_key (user_id, import_key);
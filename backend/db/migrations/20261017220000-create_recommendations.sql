@@ -0,0 +1,9 @@
+-- +migrate Up
+-- 提案したトレーニングメニュー。menuに検証済みのメニューをJSONで保存し、IDを指定してセッションを作成できるようにする
+CREATE TABLE recommendations (
+    recommendation_id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    menu TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_recommendations_user (user_id, recommendation_id)
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
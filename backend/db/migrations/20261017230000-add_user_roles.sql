@@ -0,0 +1,4 @@
+-- +migrate Up
+-- ユーザーの権限。全ユーザーで共有する種目カタログはadminのみ編集できる
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'member' AFTER weight_unit;
@@ -9,6 +9,7 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/gocraft/dbr/v2"
 	"github.com/kylelemons/go-gypsy/yaml"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -16,6 +17,11 @@ const (
 	dbMaxConnections = 50
 )
 
+type (
+	// Transactor fnを1つのトランザクション内で実行する
+	Transactor func(fn func(tx dbr.SessionRunner) error) error
+)
+
 var (
 	mutex    = sync.RWMutex{}
 	sessions = make(map[string]*dbr.Session)
@@ -35,6 +41,31 @@ func GetSession(hint string) *dbr.Session {
 	return sessions[hint]
 }
 
+// NewTransactor hintのセッションでトランザクションを張るTransactorを返却
+func NewTransactor(hint string) Transactor {
+	return func(fn func(tx dbr.SessionRunner) error) error {
+		return Transaction(hint, fn)
+	}
+}
+
+// Transaction fnをトランザクション内で実行し、エラーが返った場合はロールバック
+func Transaction(hint string, fn func(tx dbr.SessionRunner) error) error {
+	tx, err := GetSession(hint).Begin()
+	if err != nil {
+		return errors.Wrapf(err, "couldn't begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrapf(err, "couldn't commit transaction")
+	}
+	return nil
+}
+
 func newSession() *dbr.Session {
 	// err := godotenv.Load(".env")
 	// if err != nil {
@@ -3,24 +3,44 @@ import {
   Card,
   CardHeader,
   CardContent,
+  List,
+  ListItem,
+  ListItemText,
   Typography
 } from "@mui/material";
 
 import { RecommendationResponse } from '@/features/recommendations/types/index';
 
-export default function RecommendationDisplay({ recommendation }: RecommendationResponse) {
-  if (!recommendation) return null;
+export default function RecommendationDisplay({ menu }: RecommendationResponse) {
+  if (!menu) return null;
 
   return (
     <Card sx={{ width: "100%", mt: 2 }}>
       <CardHeader
-        title="おすすめトレーニングメニュー"
-        subheader="あなたの目標と条件に合わせたトレーニングメニューです"
+        title={menu.title || "おすすめトレーニングメニュー"}
+        subheader={`所要時間の目安: ${menu.estimated_minutes}分`}
       />
       <CardContent>
-        <Typography sx={{ whiteSpace: 'pre-wrap' }}>
-          {recommendation}
-        </Typography>
+        <List>
+          {menu.exercises.map((exercise, i) => (
+            <ListItem key={i} divider alignItems="flex-start">
+              <ListItemText
+                primary={`${exercise.name}　${exercise.sets}セット × ${exercise.reps}回`}
+                secondary={
+                  <>
+                    {`休憩 ${exercise.rest_seconds}秒 / 約${exercise.estimated_minutes}分`}
+                    {exercise.notes && <><br />{exercise.notes}</>}
+                  </>
+                }
+              />
+            </ListItem>
+          ))}
+        </List>
+        {menu.notes && (
+          <Typography sx={{ whiteSpace: 'pre-wrap', mt: 1 }}>
+            {menu.notes}
+          </Typography>
+        )}
       </CardContent>
     </Card>
   );
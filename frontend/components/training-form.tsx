@@ -19,7 +19,7 @@ import {
   CircularProgress,
 } from "@mui/material"
 import RecommendationsAPI from "@/features/recommendations/api"
-import type { RecommendationRequest } from "@/features/recommendations/types"
+import type { RecommendationRequest, TrainingMenu } from "@/features/recommendations/types"
 import RecommendationDisplay from "@/components/recommendation-display"
 
 const trainingGoals = [
@@ -51,7 +51,7 @@ export default function TrainingForm() {
   const [experienceLevel, setExperienceLevel] = useState<string>("")
   const [availableTime, setAvailableTime] = useState<number>(30)
   const [isLoading, setIsLoading] = useState(false)
-  const [recommendations, setRecommendations] = useState<string>("")
+  const [menu, setMenu] = useState<TrainingMenu | null>(null)
 
   const handlePartChange = (value: string) => {
     setSelectedParts((prev) => {
@@ -78,7 +78,7 @@ export default function TrainingForm() {
 
       const response = await RecommendationsAPI.proposeTrainingMenu(request)
       console.log(response)
-      setRecommendations(response.recommendation)
+      setMenu(response.menu)
     } catch (error) {
       console.error("トレーニングメニューの取得に失敗しました", error)
       alert("トレーニングメニューの取得に失敗しました。もう一度お試しください。")
@@ -192,9 +192,9 @@ export default function TrainingForm() {
         </Box>
       )}
 
-      {!isLoading && recommendations.length > 0 && (
+      {!isLoading && menu && (
         <Box sx={{ mt: 2 }}>
-          <RecommendationDisplay recommendation={recommendations} />
+          <RecommendationDisplay menu={menu} />
         </Box>
       )}
     </Box>
@@ -7,6 +7,22 @@ export type Recommendation = {
 
 export type RecommendationRequest = Pick<Recommendation, 'training_goal' | 'target_parts' | 'experience_level' | 'available_time'>;
 
+export type MenuExercise = {
+    name: string;
+    sets: number;
+    reps: number;
+    rest_seconds: number;
+    estimated_minutes: number;
+    notes: string;
+};
+
+export type TrainingMenu = {
+    title: string;
+    estimated_minutes: number;
+    exercises: MenuExercise[];
+    notes: string;
+};
+
 export type RecommendationResponse = {
-    recommendation: string;
+    menu: TrainingMenu;
 };